@@ -0,0 +1,119 @@
+// Command addressdata imports districts, subdistricts and postcodes into the
+// embedded Thai address dataset from a CSV with the columns
+//
+//	province_th,province_en,district_th,district_en,subdistrict_th,subdistrict_en,zip
+//
+// such as the one published by the Department of Provincial Administration.
+// Provinces must already be in the dataset. Running it again with the same
+// CSV leaves the dataset as it is.
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/thaiaddress"
+)
+
+var columns = []string{"province_th", "province_en", "district_th", "district_en", "subdistrict_th", "subdistrict_en", "zip"}
+
+func main() {
+	in := flag.String("in", "", "CSV to import")
+	out := flag.String("out", "pkg/utils/thaiaddress/data/provinces.json", "dataset to update")
+	flag.Parse()
+	if *in == "" {
+		log.Fatal("-in is required")
+	}
+
+	data, err := os.ReadFile(*out)
+	if err != nil {
+		log.Fatalf("failed to read dataset: %v", err)
+	}
+	dataset, err := thaiaddress.Load(data)
+	if err != nil {
+		log.Fatalf("failed to parse dataset: %v", err)
+	}
+
+	file, err := os.Open(*in)
+	if err != nil {
+		log.Fatalf("failed to open CSV: %v", err)
+	}
+	defer file.Close()
+
+	imported, err := importCSV(dataset, file)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(dataset.Provinces); err != nil {
+		log.Fatalf("failed to encode dataset: %v", err)
+	}
+	if err := os.WriteFile(*out, buf.Bytes(), 0o644); err != nil {
+		log.Fatalf("failed to write dataset: %v", err)
+	}
+	log.Printf("imported %d subdistricts into %s", imported, *out)
+}
+
+func importCSV(dataset *thaiaddress.Dataset, r io.Reader) (int, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read header: %w", err)
+	}
+	index := make(map[string]int)
+	for i, name := range header {
+		index[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+	for _, column := range columns {
+		if _, ok := index[column]; !ok {
+			return 0, fmt.Errorf("missing column %s", column)
+		}
+	}
+
+	imported := 0
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return imported, nil
+		}
+		if err != nil {
+			return imported, fmt.Errorf("line %d: %w", line, err)
+		}
+		field := func(column string) string {
+			return strings.TrimSpace(record[index[column]])
+		}
+
+		province, ok := dataset.Province(field("province_th"))
+		if !ok {
+			return imported, fmt.Errorf("line %d: unknown province %q", line, field("province_th"))
+		}
+		district, ok := province.District(field("district_th"))
+		if !ok {
+			province.Districts = append(province.Districts, thaiaddress.District{
+				NameTH: field("district_th"),
+				NameEN: field("district_en"),
+			})
+			district = &province.Districts[len(province.Districts)-1]
+		}
+		if _, ok := district.Subdistrict(field("subdistrict_th")); ok {
+			continue
+		}
+		district.Subdistricts = append(district.Subdistricts, thaiaddress.Subdistrict{
+			NameTH: field("subdistrict_th"),
+			NameEN: field("subdistrict_en"),
+			Zip:    field("zip"),
+		})
+		imported++
+	}
+}
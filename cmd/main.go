@@ -6,6 +6,7 @@ import (
 	"github.com/Dongy-s-Advanture/back-end/internal/config"
 	"github.com/Dongy-s-Advanture/back-end/internal/database"
 	routes "github.com/Dongy-s-Advanture/back-end/internal/router"
+	"github.com/Dongy-s-Advanture/back-end/internal/storage"
 	"github.com/gin-gonic/gin"
 )
 
@@ -28,12 +29,12 @@ func main() {
 		panic(fmt.Sprintf("Error connecting redis: %v", err))
 	}
 
-	s3Client, err := database.InitS3Client(&conf.AWS)
+	store, err := storage.New(&conf.Storage, &conf.AWS)
 	if err != nil {
-		panic(fmt.Sprintf("Error initializing S3 client: %v", err))
+		panic(fmt.Sprintf("Error initializing storage: %v", err))
 	}
 
 	r := routes.NewRouter(gin.Default(), conf)
 
-	r.Run(mongoDB, redisDB, s3Client)
+	r.Run(mongoDB, redisDB, store)
 }
@@ -0,0 +1,226 @@
+// Command migrate converts amounts stored as float64 baht into money
+// documents of integer satang, and works out the rating of sellers reviewed
+// before ratings were kept. It only touches numeric fields and sellers
+// without a rating, so it is safe to run more than once.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/config"
+	"github.com/Dongy-s-Advanture/back-end/internal/database"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/coupontype"
+	"github.com/Dongy-s-Advanture/back-end/internal/repository"
+	"github.com/Dongy-s-Advanture/back-end/internal/service"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/money"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type migration struct {
+	collection string
+	filter     bson.M
+	pipeline   mongo.Pipeline
+}
+
+func main() {
+	conf, err := config.LoadConfig()
+	if err != nil {
+		panic(fmt.Sprintf("Error loading config: %v", err))
+	}
+
+	mongoDB, err := database.InitMongoDatabase(&conf.Db)
+	if err != nil {
+		panic(fmt.Sprintf("Error connecting mongo: %v", err))
+	}
+
+	for _, m := range migrations() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		res, err := mongoDB.Collection(m.collection).UpdateMany(ctx, m.filter, m.pipeline)
+		cancel()
+		if err != nil {
+			log.Fatalf("failed to migrate %s: %v", m.collection, err)
+		}
+		log.Printf("migrated %d of %d documents in %s", res.ModifiedCount, res.MatchedCount, m.collection)
+	}
+
+	if err := migrateRatings(mongoDB, &conf.Rating); err != nil {
+		log.Fatalf("failed to migrate seller ratings: %v", err)
+	}
+}
+
+func migrateRatings(db *mongo.Database, ratingCfg *config.RatingConfig) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	cursor, err := db.Collection("sellers").Find(ctx,
+		bson.M{"rating": bson.M{"$exists": false}},
+		options.Find().SetProjection(bson.M{"_id": 1}),
+	)
+	if err != nil {
+		return err
+	}
+	var sellers []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &sellers); err != nil {
+		return err
+	}
+
+	sellerRepo := repository.NewSellerRepository(db, "sellers", "reviews")
+	for _, seller := range sellers {
+		rating, err := sellerRepo.RecalculateRating(seller.ID)
+		if err != nil {
+			return err
+		}
+		if _, err := sellerRepo.SetSellerScore(seller.ID, rating, service.SellerScore(rating, ratingCfg)); err != nil {
+			return err
+		}
+	}
+	log.Printf("migrated the rating of %d sellers", len(sellers))
+	return nil
+}
+
+func migrations() []migration {
+	orderProduct := func(v string) bson.M {
+		return bson.M{
+			"unitPrice": toMoney(v + ".unitPrice"),
+			"subtotal":  toMoney(v + ".subtotal"),
+		}
+	}
+
+	return []migration{
+		{
+			collection: "products",
+			filter:     isNumber("price"),
+			pipeline:   set(bson.M{"price": toMoney("$price")}),
+		},
+		{
+			collection: "orders",
+			filter: isNumber(
+				"totalPrice", "subtotal", "discount",
+				"products.unitPrice", "products.subtotal",
+				"fees.sellerGross", "fees.commission", "fees.processingFee", "fees.couponSubsidy", "fees.sellerNet",
+			),
+			pipeline: set(bson.M{
+				"totalPrice": toMoney("$totalPrice"),
+				"subtotal":   toMoney("$subtotal"),
+				"discount":   toMoney("$discount"),
+				"products":   mapArray("$products", orderProduct("$$item")),
+				"fees": mergeObject("$fees", bson.M{
+					"sellerGross":   toMoney("$fees.sellerGross"),
+					"commission":    toMoney("$fees.commission"),
+					"processingFee": toMoney("$fees.processingFee"),
+					"couponSubsidy": toMoney("$fees.couponSubsidy"),
+					"sellerNet":     toMoney("$fees.sellerNet"),
+				}),
+			}),
+		},
+		{
+			collection: "sellers",
+			filter:     isNumber("balance", "transaction.amount"),
+			pipeline: set(bson.M{
+				"balance":     toMoney("$balance"),
+				"transaction": mapArray("$transaction", bson.M{"amount": toMoney("$$item.amount")}),
+			}),
+		},
+		{
+			collection: "buyers",
+			filter:     isNumber("cart.unitPrice", "cart.subtotal"),
+			pipeline:   set(bson.M{"cart": mapArray("$cart", orderProduct("$$item"))}),
+		},
+		{
+			collection: "advertisements",
+			filter:     isNumber("amount"),
+			pipeline:   set(bson.M{"amount": toMoney("$amount")}),
+		},
+		{
+			// Coupons kept a single value, a percentage or an amount off
+			// depending on the type
+			collection: "coupons",
+			filter:     bson.M{"value": bson.M{"$exists": true}},
+			pipeline: mongo.Pipeline{
+				{{Key: "$set", Value: bson.M{
+					"percent": bson.M{"$cond": bson.A{
+						bson.M{"$eq": bson.A{"$type", coupontype.PERCENTAGE}}, "$value", "$$REMOVE",
+					}},
+					"amount": bson.M{"$cond": bson.A{
+						bson.M{"$eq": bson.A{"$type", coupontype.FIXED}}, toMoney("$value"), "$$REMOVE",
+					}},
+				}}},
+				{{Key: "$unset", Value: "value"}},
+			},
+		},
+		{
+			collection: "coupons",
+			filter:     isNumber("minSpend", "maxDiscount"),
+			pipeline: set(bson.M{
+				"minSpend":    toMoney("$minSpend"),
+				"maxDiscount": toMoney("$maxDiscount"),
+			}),
+		},
+		{
+			collection: "coupon_redemptions",
+			filter:     isNumber("discount"),
+			pipeline:   set(bson.M{"discount": toMoney("$discount")}),
+		},
+		{
+			collection: "platform_ledger",
+			filter:     isNumber("amount"),
+			pipeline:   set(bson.M{"amount": toMoney("$amount")}),
+		},
+	}
+}
+
+// isNumber matches documents with any of the fields still stored as a number
+func isNumber(fields ...string) bson.M {
+	or := bson.A{}
+	for _, field := range fields {
+		or = append(or, bson.M{field: bson.M{"$type": "number"}})
+	}
+	return bson.M{"$or": or}
+}
+
+func set(fields bson.M) mongo.Pipeline {
+	return mongo.Pipeline{{{Key: "$set", Value: fields}}}
+}
+
+// toMoney converts a number of baht at path into satang, leaving anything
+// else, including missing fields, as it is
+func toMoney(path string) bson.M {
+	return bson.M{"$cond": bson.A{
+		bson.M{"$isNumber": path},
+		bson.M{
+			"amount":   bson.M{"$toLong": bson.M{"$round": bson.A{bson.M{"$multiply": bson.A{path, 100}}, 0}}},
+			"currency": money.DefaultCurrency,
+		},
+		path,
+	}}
+}
+
+// mapArray merges fields into every element of the array at path
+func mapArray(path string, fields bson.M) bson.M {
+	return bson.M{"$cond": bson.A{
+		bson.M{"$isArray": path},
+		bson.M{"$map": bson.M{
+			"input": path,
+			"as":    "item",
+			"in":    bson.M{"$mergeObjects": bson.A{"$$item", fields}},
+		}},
+		path,
+	}}
+}
+
+// mergeObject merges fields into the embedded document at path, if there is one
+func mergeObject(path string, fields bson.M) bson.M {
+	return bson.M{"$cond": bson.A{
+		bson.M{"$eq": bson.A{bson.M{"$type": path}, "object"}},
+		bson.M{"$mergeObjects": bson.A{path, fields}},
+		path,
+	}}
+}
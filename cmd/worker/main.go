@@ -0,0 +1,91 @@
+// Command worker runs the background jobs: it claims jobs from the Redis
+// queue, retries the ones that fail and enqueues the scheduled ones. Any
+// number of workers can run side by side, each scheduled run is enqueued by
+// only one of them.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/config"
+	"github.com/Dongy-s-Advanture/back-end/internal/database"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/jobtype"
+	"github.com/Dongy-s-Advanture/back-end/internal/job"
+	routes "github.com/Dongy-s-Advanture/back-end/internal/router"
+	"github.com/Dongy-s-Advanture/back-end/internal/storage"
+	"github.com/Dongy-s-Advanture/back-end/pkg/redis"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/cron"
+)
+
+func main() {
+	conf, err := config.LoadConfig()
+	if err != nil {
+		panic(fmt.Sprintf("Error loading config: %v", err))
+	}
+
+	mongoDB, err := database.InitMongoDatabase(&conf.Db)
+	if err != nil {
+		panic(fmt.Sprintf("Error connecting mongo: %v", err))
+	}
+
+	redisDB, err := database.InitRedis(&conf.Db)
+	if err != nil {
+		panic(fmt.Sprintf("Error connecting redis: %v", err))
+	}
+
+	store, err := storage.New(&conf.Storage, &conf.AWS)
+	if err != nil {
+		panic(fmt.Sprintf("Error initializing storage: %v", err))
+	}
+
+	deps := routes.NewDependencies(mongoDB, redis.NewGoRedisAdapter(redisDB), store, conf)
+	worker := job.NewWorker(job.NewQueue(redisDB, &conf.Worker), &conf.Worker)
+
+	grace := time.Duration(conf.Storage.OrphanGraceHours) * time.Hour
+	worker.Handle(jobtype.SWEEP_ORPHAN_UPLOADS, func(ctx context.Context, j *job.Job) error {
+		deleted, err := deps.UploadService.SweepOrphans(grace)
+		if deleted > 0 {
+			log.Printf("orphan sweep deleted %d objects", deleted)
+		}
+		return err
+	})
+	worker.Handle(jobtype.SEND_APPOINTMENT_REMINDERS, func(ctx context.Context, j *job.Job) error {
+		sent, err := deps.AppointmentService.SendDueReminders(time.Now())
+		if sent > 0 {
+			log.Printf("sent %d appointment reminders", sent)
+		}
+		return err
+	})
+	worker.Handle(jobtype.RELAY_EVENTS, func(ctx context.Context, j *job.Job) error {
+		published, err := deps.EventRelay.RelayEvents(time.Now())
+		if published > 0 {
+			log.Printf("published %d events", published)
+		}
+		return err
+	})
+
+	worker.Schedule("sweep-orphan-uploads",
+		cron.Every(time.Duration(conf.Storage.SweepIntervalMinutes)*time.Minute),
+		jobtype.SWEEP_ORPHAN_UPLOADS,
+	)
+	worker.Schedule("send-appointment-reminders",
+		cron.Every(time.Duration(conf.Appointment.ReminderIntervalMinutes)*time.Minute),
+		jobtype.SEND_APPOINTMENT_REMINDERS,
+	)
+	worker.Schedule("relay-events",
+		cron.Every(time.Duration(conf.Outbox.RelayIntervalSeconds)*time.Second),
+		jobtype.RELAY_EVENTS,
+	)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	log.Printf("worker started with %d slots", conf.Worker.Concurrency)
+	worker.Run(ctx)
+	log.Printf("worker stopped")
+}
@@ -3,6 +3,7 @@ module github.com/Dongy-s-Advanture/back-end
 go 1.22.3
 
 require (
+	github.com/HugoSmits86/nativewebp v1.1.0
 	github.com/aws/aws-sdk-go-v2 v1.36.3
 	github.com/aws/aws-sdk-go-v2/config v1.29.13
 	github.com/aws/aws-sdk-go-v2/credentials v1.17.66
@@ -25,6 +26,7 @@ require (
 	go.mongodb.org/mongo-driver v1.17.3
 	go.uber.org/mock v0.5.1
 	golang.org/x/crypto v0.32.0
+	golang.org/x/image v0.24.0
 )
 
 require (
@@ -90,9 +92,9 @@ require (
 	github.com/youmark/pkcs8 v0.0.0-20240726163527-a2c0da244d78 // indirect
 	golang.org/x/arch v0.13.0 // indirect
 	golang.org/x/net v0.34.0 // indirect
-	golang.org/x/sync v0.10.0 // indirect
+	golang.org/x/sync v0.11.0 // indirect
 	golang.org/x/sys v0.29.0 // indirect
-	golang.org/x/text v0.21.0 // indirect
+	golang.org/x/text v0.22.0 // indirect
 	golang.org/x/tools v0.29.0 // indirect
 	google.golang.org/protobuf v1.36.4 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
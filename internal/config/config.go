@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -11,6 +13,79 @@ import (
 type PaymentConfig struct {
 	Public  string
 	Private string
+	// Omise processing fee charged on card payments, VAT is charged on the fee
+	FeePercent    float64
+	FeeVATPercent float64
+}
+
+type CommissionConfig struct {
+	// Used when no commission rule matches
+	DefaultPercent float64
+}
+
+type CalendarConfig struct {
+	// Signs the secret URLs of users' appointment calendar feeds
+	Secret string
+}
+
+type AppointmentConfig struct {
+	// How long before an appointment reminders are sent
+	ReminderLeads           []time.Duration
+	ReminderIntervalMinutes int
+	// How long after the start of an appointment a no-show can be reported
+	NoShowGraceMinutes int
+	// Cancel and refund the order when someone doesn't show up
+	NoShowCancel bool
+	// Share of a card payment refunded when the buyer is the one who didn't
+	// show up. An absent seller always refunds it all.
+	NoShowBuyerRefundPercent float64
+	// Count the no-show against the absent party's account
+	NoShowStrike bool
+}
+
+// RatingConfig weighs seller scores towards PriorMean, as if every seller
+// had PriorWeight extra reviews scoring it, so a few reviews don't rank a
+// seller above ones with many
+type RatingConfig struct {
+	PriorMean   float64
+	PriorWeight float64
+}
+
+// NotificationConfig sets up the channels notifications are sent over.
+// Email is only offered to users when SMTPHost is set, LINE when
+// LineChannelAccessToken is.
+type NotificationConfig struct {
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	EmailFrom    string
+	// Messaging API channel of our LINE Official Account
+	LineChannelAccessToken string
+	LineAPIURL             string
+	// Signs the body of webhook notifications
+	WebhookSecret  string
+	TimeoutSeconds int
+}
+
+// OutboxConfig sets how the relay publishes domain events. A failed event is
+// retried after RetryBaseSeconds, doubling every attempt, and moved to the
+// dead letter after MaxAttempts.
+type OutboxConfig struct {
+	RelayIntervalSeconds int
+	MaxAttempts          int
+	RetryBaseSeconds     int
+}
+
+// WorkerConfig sets up the background job runner. A failed job is retried
+// after RetryBaseSeconds, doubling every attempt, and parked with the dead
+// jobs after MaxAttempts.
+type WorkerConfig struct {
+	Concurrency        int
+	PollIntervalMillis int
+	JobTimeoutSeconds  int
+	MaxAttempts        int
+	RetryBaseSeconds   int
 }
 
 type AppConfig struct {
@@ -32,6 +107,24 @@ type AWSConfig struct {
 	BucketName string
 }
 
+type StorageConfig struct {
+	// s3 (default), s3-compatible or local
+	Driver               string
+	Endpoint             string
+	PublicURL            string
+	LocalDir             string
+	SigningSecret        string
+	PresignExpiryMinutes int
+	// Unreferenced uploads older than this are deleted by the orphan sweeper
+	OrphanGraceHours     int
+	SweepIntervalMinutes int
+}
+
+type ImageConfig struct {
+	Format      string
+	JPEGQuality int
+}
+
 type AuthConfig struct {
 	AccessTokenSecret           string
 	RefreshTokenSecret          string
@@ -39,12 +132,40 @@ type AuthConfig struct {
 	RefreshTokenLifespanMinutes int32
 }
 
+type AdminConfig struct {
+	// IDs of the users allowed to manage platform-wide resources
+	UserIDs []string
+}
+
+func (c AdminConfig) IsAdmin(userID string) bool {
+	for _, id := range c.UserIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
 type Config struct {
-	App     AppConfig
-	Auth    AuthConfig
-	Db      DbConfig
-	AWS     AWSConfig
-	Payment PaymentConfig
+	App        AppConfig
+	Auth       AuthConfig
+	Db         DbConfig
+	AWS        AWSConfig
+	Storage    StorageConfig
+	Image      ImageConfig
+	Payment    PaymentConfig
+	Admin      AdminConfig
+	Commission CommissionConfig
+	Calendar   CalendarConfig
+	// Reminders and no-shows
+	Appointment AppointmentConfig
+	Rating      RatingConfig
+	// Email, LINE and webhook channels
+	Notification NotificationConfig
+	// Relay of domain events to their subscribers
+	Outbox OutboxConfig
+	// Background jobs run by cmd/worker
+	Worker WorkerConfig
 }
 
 func LoadConfig() (*Config, error) {
@@ -80,6 +201,53 @@ func LoadConfig() (*Config, error) {
 		BucketName: os.Getenv("AWS_BUCKET_NAME"),
 	}
 
+	storageConfig := StorageConfig{
+		Driver:               os.Getenv("STORAGE_DRIVER"),
+		Endpoint:             os.Getenv("STORAGE_ENDPOINT"),
+		PublicURL:            os.Getenv("STORAGE_PUBLIC_URL"),
+		LocalDir:             os.Getenv("STORAGE_LOCAL_DIR"),
+		SigningSecret:        os.Getenv("STORAGE_SIGNING_SECRET"),
+		PresignExpiryMinutes: 15,
+		OrphanGraceHours:     24,
+		SweepIntervalMinutes: 60,
+	}
+	if storageConfig.Driver == "" {
+		storageConfig.Driver = "s3"
+	}
+	if storageConfig.LocalDir == "" {
+		storageConfig.LocalDir = "uploads"
+	}
+	if expiry := os.Getenv("STORAGE_PRESIGN_EXPIRY_MINUTES"); expiry != "" {
+		storageConfig.PresignExpiryMinutes, err = strconv.Atoi(expiry)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if grace := os.Getenv("STORAGE_ORPHAN_GRACE_HOURS"); grace != "" {
+		storageConfig.OrphanGraceHours, err = strconv.Atoi(grace)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if interval := os.Getenv("STORAGE_SWEEP_INTERVAL_MINUTES"); interval != "" {
+		storageConfig.SweepIntervalMinutes, err = strconv.Atoi(interval)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	imageConfig := ImageConfig{
+		Format:      os.Getenv("IMAGE_FORMAT"),
+		JPEGQuality: 82,
+	}
+	if quality := os.Getenv("IMAGE_JPEG_QUALITY"); quality != "" {
+		imageConfig.JPEGQuality, err = strconv.Atoi(quality)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	authConfig := AuthConfig{
 		AccessTokenSecret:           os.Getenv("ACCESS_TOKEN_SECRET"),
 		RefreshTokenSecret:          os.Getenv("REFRESH_TOKEN_SECRET"),
@@ -98,15 +266,241 @@ func LoadConfig() (*Config, error) {
 	}
 
 	paymentConfig := PaymentConfig{
-		Public:  os.Getenv("OMISE_PUBLIC_KEY"),
-		Private: os.Getenv("OMISE_PRIVATE_KEY"),
+		Public:        os.Getenv("OMISE_PUBLIC_KEY"),
+		Private:       os.Getenv("OMISE_PRIVATE_KEY"),
+		FeePercent:    3.65,
+		FeeVATPercent: 7,
+	}
+	if fee := os.Getenv("OMISE_FEE_PERCENT"); fee != "" {
+		paymentConfig.FeePercent, err = strconv.ParseFloat(fee, 64)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if vat := os.Getenv("OMISE_FEE_VAT_PERCENT"); vat != "" {
+		paymentConfig.FeeVATPercent, err = strconv.ParseFloat(vat, 64)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	commissionConfig := CommissionConfig{}
+	if commission := os.Getenv("COMMISSION_DEFAULT_PERCENT"); commission != "" {
+		commissionConfig.DefaultPercent, err = strconv.ParseFloat(commission, 64)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	adminConfig := AdminConfig{}
+	for _, id := range strings.Split(os.Getenv("ADMIN_IDS"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			adminConfig.UserIDs = append(adminConfig.UserIDs, id)
+		}
+	}
+
+	calendarConfig := CalendarConfig{
+		Secret: os.Getenv("CALENDAR_SECRET"),
+	}
+
+	appointmentConfig := AppointmentConfig{
+		ReminderLeads:            []time.Duration{24 * time.Hour, time.Hour},
+		ReminderIntervalMinutes:  5,
+		NoShowGraceMinutes:       30,
+		NoShowCancel:             true,
+		NoShowBuyerRefundPercent: 50,
+		NoShowStrike:             true,
+	}
+	if leads := os.Getenv("APPOINTMENT_REMINDER_LEAD_MINUTES"); leads != "" {
+		appointmentConfig.ReminderLeads = nil
+		for _, lead := range strings.Split(leads, ",") {
+			minutes, err := strconv.Atoi(strings.TrimSpace(lead))
+			if err != nil {
+				return nil, err
+			}
+			appointmentConfig.ReminderLeads = append(appointmentConfig.ReminderLeads, time.Duration(minutes)*time.Minute)
+		}
+	}
+	if interval := os.Getenv("APPOINTMENT_REMINDER_INTERVAL_MINUTES"); interval != "" {
+		appointmentConfig.ReminderIntervalMinutes, err = strconv.Atoi(interval)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if grace := os.Getenv("NO_SHOW_GRACE_MINUTES"); grace != "" {
+		appointmentConfig.NoShowGraceMinutes, err = strconv.Atoi(grace)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cancel := os.Getenv("NO_SHOW_CANCEL"); cancel != "" {
+		appointmentConfig.NoShowCancel, err = strconv.ParseBool(cancel)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if refund := os.Getenv("NO_SHOW_BUYER_REFUND_PERCENT"); refund != "" {
+		appointmentConfig.NoShowBuyerRefundPercent, err = strconv.ParseFloat(refund, 64)
+		if err != nil {
+			return nil, err
+		}
+		if appointmentConfig.NoShowBuyerRefundPercent < 0 || appointmentConfig.NoShowBuyerRefundPercent > 100 {
+			return nil, fmt.Errorf("NO_SHOW_BUYER_REFUND_PERCENT must be between 0 and 100")
+		}
+	}
+	if strike := os.Getenv("NO_SHOW_STRIKE"); strike != "" {
+		appointmentConfig.NoShowStrike, err = strconv.ParseBool(strike)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ratingConfig := RatingConfig{
+		PriorMean:   7,
+		PriorWeight: 5,
+	}
+	if mean := os.Getenv("RATING_PRIOR_MEAN"); mean != "" {
+		ratingConfig.PriorMean, err = strconv.ParseFloat(mean, 64)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if weight := os.Getenv("RATING_PRIOR_WEIGHT"); weight != "" {
+		ratingConfig.PriorWeight, err = strconv.ParseFloat(weight, 64)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	notificationConfig := NotificationConfig{
+		SMTPHost:               os.Getenv("SMTP_HOST"),
+		SMTPPort:               587,
+		SMTPUsername:           os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:           os.Getenv("SMTP_PASSWORD"),
+		EmailFrom:              os.Getenv("SMTP_FROM"),
+		LineChannelAccessToken: os.Getenv("LINE_CHANNEL_ACCESS_TOKEN"),
+		LineAPIURL:             os.Getenv("LINE_API_URL"),
+		WebhookSecret:          os.Getenv("NOTIFICATION_WEBHOOK_SECRET"),
+		TimeoutSeconds:         10,
+	}
+	if port := os.Getenv("SMTP_PORT"); port != "" {
+		notificationConfig.SMTPPort, err = strconv.Atoi(port)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if notificationConfig.LineAPIURL == "" {
+		notificationConfig.LineAPIURL = "https://api.line.me"
+	}
+	if timeout := os.Getenv("NOTIFICATION_TIMEOUT_SECONDS"); timeout != "" {
+		notificationConfig.TimeoutSeconds, err = strconv.Atoi(timeout)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	outboxConfig := OutboxConfig{
+		RelayIntervalSeconds: 5,
+		MaxAttempts:          8,
+		RetryBaseSeconds:     30,
+	}
+	if interval := os.Getenv("OUTBOX_RELAY_INTERVAL_SECONDS"); interval != "" {
+		outboxConfig.RelayIntervalSeconds, err = strconv.Atoi(interval)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if attempts := os.Getenv("OUTBOX_MAX_ATTEMPTS"); attempts != "" {
+		outboxConfig.MaxAttempts, err = strconv.Atoi(attempts)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if base := os.Getenv("OUTBOX_RETRY_BASE_SECONDS"); base != "" {
+		outboxConfig.RetryBaseSeconds, err = strconv.Atoi(base)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	workerConfig := WorkerConfig{
+		Concurrency:        4,
+		PollIntervalMillis: 1000,
+		JobTimeoutSeconds:  300,
+		MaxAttempts:        5,
+		RetryBaseSeconds:   10,
+	}
+	if concurrency := os.Getenv("WORKER_CONCURRENCY"); concurrency != "" {
+		workerConfig.Concurrency, err = strconv.Atoi(concurrency)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if poll := os.Getenv("WORKER_POLL_INTERVAL_MS"); poll != "" {
+		workerConfig.PollIntervalMillis, err = strconv.Atoi(poll)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if timeout := os.Getenv("WORKER_JOB_TIMEOUT_SECONDS"); timeout != "" {
+		workerConfig.JobTimeoutSeconds, err = strconv.Atoi(timeout)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if attempts := os.Getenv("WORKER_MAX_ATTEMPTS"); attempts != "" {
+		workerConfig.MaxAttempts, err = strconv.Atoi(attempts)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if base := os.Getenv("WORKER_RETRY_BASE_SECONDS"); base != "" {
+		workerConfig.RetryBaseSeconds, err = strconv.Atoi(base)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Zero or less would stop the loops these drive or have them spin
+	for _, setting := range []struct {
+		name  string
+		value int
+	}{
+		{"STORAGE_SWEEP_INTERVAL_MINUTES", storageConfig.SweepIntervalMinutes},
+		{"APPOINTMENT_REMINDER_INTERVAL_MINUTES", appointmentConfig.ReminderIntervalMinutes},
+		{"OUTBOX_RELAY_INTERVAL_SECONDS", outboxConfig.RelayIntervalSeconds},
+		{"OUTBOX_MAX_ATTEMPTS", outboxConfig.MaxAttempts},
+		{"OUTBOX_RETRY_BASE_SECONDS", outboxConfig.RetryBaseSeconds},
+		{"WORKER_CONCURRENCY", workerConfig.Concurrency},
+		{"WORKER_POLL_INTERVAL_MS", workerConfig.PollIntervalMillis},
+		{"WORKER_JOB_TIMEOUT_SECONDS", workerConfig.JobTimeoutSeconds},
+		{"WORKER_MAX_ATTEMPTS", workerConfig.MaxAttempts},
+		{"WORKER_RETRY_BASE_SECONDS", workerConfig.RetryBaseSeconds},
+	} {
+		if setting.value <= 0 {
+			return nil, fmt.Errorf("%s must be positive, got %d", setting.name, setting.value)
+		}
 	}
 
 	return &Config{
-		App:     appConfig,
-		Auth:    authConfig,
-		Db:      dbConfig,
-		AWS: 	 awsConfig,
-		Payment: paymentConfig,
+		App:        appConfig,
+		Auth:       authConfig,
+		Db:         dbConfig,
+		AWS:        awsConfig,
+		Storage:    storageConfig,
+		Image:      imageConfig,
+		Payment:    paymentConfig,
+		Admin:      adminConfig,
+		Commission: commissionConfig,
+		Calendar:   calendarConfig,
+		// Reminders and no-shows
+		Appointment: appointmentConfig,
+		Rating:      ratingConfig,
+		// Email, LINE and webhook channels
+		Notification: notificationConfig,
+		// Relay of domain events to their subscribers
+		Outbox: outboxConfig,
+		// Background jobs run by cmd/worker
+		Worker: workerConfig,
 	}, nil
 }
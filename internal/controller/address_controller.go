@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type IAddressController interface {
+	GetProvinces(c *gin.Context)
+	GetDistricts(c *gin.Context)
+	GetSubdistricts(c *gin.Context)
+	LookupZip(c *gin.Context)
+}
+
+type AddressController struct {
+	addressService service.IAddressService
+}
+
+func NewAddressController(s service.IAddressService) IAddressController {
+	return AddressController{
+		addressService: s,
+	}
+}
+
+// GetProvinces godoc
+//
+//	@Summary		List provinces
+//	@Description	Returns every Thai province, for the first step of an address picker
+//	@Tags			address
+//	@Produce		json
+//	@Success		200	{object}	dto.SuccessResponse{data=[]dto.AddressArea}
+//	@Router			/address/province [get]
+func (s AddressController) GetProvinces(c *gin.Context) {
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Get provinces success",
+		Data:    s.addressService.GetProvinces(),
+	})
+}
+
+// GetDistricts godoc
+//
+//	@Summary		List the districts of a province
+//	@Description	Returns the districts of a province, named in Thai or English
+//	@Tags			address
+//	@Produce		json
+//	@Param			province	path		string	true	"Province name"
+//	@Success		200			{object}	dto.SuccessResponse{data=[]dto.AddressArea}
+//	@Failure		404			{object}	dto.ErrorResponse
+//	@Router			/address/province/{province}/district [get]
+func (s AddressController) GetDistricts(c *gin.Context) {
+	res, err := s.addressService.GetDistricts(c.Param("province"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusNotFound,
+			Error:   "Failed to get districts",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Get districts success",
+		Data:    res,
+	})
+}
+
+// GetSubdistricts godoc
+//
+//	@Summary		List the subdistricts of a district
+//	@Description	Returns the subdistricts of a district with their postcodes
+//	@Tags			address
+//	@Produce		json
+//	@Param			province	path		string	true	"Province name"
+//	@Param			district	path		string	true	"District name"
+//	@Success		200			{object}	dto.SuccessResponse{data=[]dto.AddressArea}
+//	@Failure		404			{object}	dto.ErrorResponse
+//	@Router			/address/province/{province}/district/{district}/subdistrict [get]
+func (s AddressController) GetSubdistricts(c *gin.Context) {
+	res, err := s.addressService.GetSubdistricts(c.Param("province"), c.Param("district"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusNotFound,
+			Error:   "Failed to get subdistricts",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Get subdistricts success",
+		Data:    res,
+	})
+}
+
+// LookupZip godoc
+//
+//	@Summary		Find the places with a postcode
+//	@Description	Returns the provinces, and the districts and subdistricts where known, that a postcode belongs to
+//	@Tags			address
+//	@Produce		json
+//	@Param			zip	path		string	true	"Postcode"
+//	@Success		200	{object}	dto.SuccessResponse{data=[]dto.AddressMatch}
+//	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		404	{object}	dto.ErrorResponse
+//	@Router			/address/zip/{zip} [get]
+func (s AddressController) LookupZip(c *gin.Context) {
+	res, err := s.addressService.LookupZip(c.Param("zip"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid zip",
+			Message: err.Error(),
+		})
+		return
+	}
+	if len(res) == 0 {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusNotFound,
+			Error:   "Failed to find zip",
+			Message: "no place has zip " + c.Param("zip"),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Lookup zip success",
+		Data:    res,
+	})
+}
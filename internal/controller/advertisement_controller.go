@@ -7,6 +7,7 @@ import (
 	"github.com/Dongy-s-Advanture/back-end/internal/dto"
 	"github.com/Dongy-s-Advanture/back-end/internal/model"
 	"github.com/Dongy-s-Advanture/back-end/internal/service"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/converter"
 	"github.com/gin-gonic/gin"
 	"github.com/jinzhu/copier"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -250,9 +251,9 @@ func (s AdvertisementController) CreateAdvertisement(c *gin.Context) {
 
 	fmt.Printf("newAdvertisement: %#v\n", newAdvertisement)
 
-	var imageURL string
+	var images *model.ImageVariants
 	if newAdvertisement.ImageURL != nil {
-		fileUrl, err := s.s3Service.UploadFile(newAdvertisement.ImageURL, "advertisements")
+		variants, err := s.s3Service.UploadImage(newAdvertisement.ImageURL, "advertisements")
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 				Success: false,
@@ -262,7 +263,16 @@ func (s AdvertisementController) CreateAdvertisement(c *gin.Context) {
 			})
 			return
 		}
-		imageURL = fileUrl
+		images, err = converter.ImageVariantsDTOToModel(variants)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Success: false,
+				Status:  http.StatusInternalServerError,
+				Error:   "Failed to convert image data",
+				Message: err.Error(),
+			})
+			return
+		}
 	}
 
 	var newAdvertisementData model.Advertisement
@@ -301,7 +311,10 @@ func (s AdvertisementController) CreateAdvertisement(c *gin.Context) {
 	newAdvertisementData.SellerID = sellerID
 	newAdvertisementData.ProductID = productID
 
-	newAdvertisementData.ImageURL = imageURL
+	if images != nil {
+		newAdvertisementData.ImageURL = images.Large
+		newAdvertisementData.Images = images
+	}
 	res, err := s.advertisementService.CreateAdvertisement(&newAdvertisementData)
 
 	if err != nil {
@@ -358,9 +371,9 @@ func (s AdvertisementController) UpdateAdvertisement(c *gin.Context) {
 		return
 	}
 
-	var imageURL string
+	var images *model.ImageVariants
 	if updatedAdvertisement.ImageURL != nil {
-		fileUrl, err := s.s3Service.UploadFile(updatedAdvertisement.ImageURL, "advertisements")
+		variants, err := s.s3Service.UploadImage(updatedAdvertisement.ImageURL, "advertisements")
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 				Success: false,
@@ -370,7 +383,16 @@ func (s AdvertisementController) UpdateAdvertisement(c *gin.Context) {
 			})
 			return
 		}
-		imageURL = fileUrl
+		images, err = converter.ImageVariantsDTOToModel(variants)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Success: false,
+				Status:  http.StatusInternalServerError,
+				Error:   "Failed to convert image data",
+				Message: err.Error(),
+			})
+			return
+		}
 	}
 
 	var updatedAdvertisementData model.Advertisement
@@ -383,7 +405,10 @@ func (s AdvertisementController) UpdateAdvertisement(c *gin.Context) {
 		})
 		return
 	}
-	updatedAdvertisementData.ImageURL = imageURL
+	if images != nil {
+		updatedAdvertisementData.ImageURL = images.Large
+		updatedAdvertisementData.Images = images
+	}
 
 	res, err := s.advertisementService.UpdateAdvertisement(advertisementID, &updatedAdvertisementData)
 	if err != nil {
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/Dongy-s-Advanture/back-end/internal/service"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type IAppointmentController interface {
@@ -16,8 +18,12 @@ type IAppointmentController interface {
 	GetAppointmentByID(c *gin.Context)
 	GetAppointmentByOrderID(c *gin.Context)
 	CreateAppointment(c *gin.Context)
-	UpdateAppointmentDate(c *gin.Context)
-	UpdateAppointmentPlace(c *gin.Context)
+	SetAvailability(c *gin.Context)
+	GetOpenSlots(c *gin.Context)
+	Propose(c *gin.Context)
+	AcceptProposal(c *gin.Context)
+	RejectProposal(c *gin.Context)
+	ReportNoShow(c *gin.Context)
 }
 
 type AppointmentController struct {
@@ -185,33 +191,44 @@ func (s AppointmentController) CreateAppointment(c *gin.Context) {
 	})
 }
 
-// UpdateAppointment godoc
-//	@Summary		Update an appointment date by ID
-//	@Description	Updates an existing appointment's date by its ID
-//	@Tags			appointment
+// SetAvailability godoc
+//
+//	@Summary		Set a seller's availability
+//	@Description	Replaces the seller's weekly opening hours, slot length and blackout dates. Times are in Thai time.
+//	@Tags			seller
 //	@Accept			json
 //	@Produce		json
-//	@Param			appointment_id	path		string						true	"Appointment ID"
-//	@Param			appointment		body		dto.AppointmentDateRequest	true	"Appointment date to update"
-//	@Success		200				{object}	dto.SuccessResponse{data=dto.Appointment}
+//	@Param			seller_id		path		string					true	"Seller ID"
+//	@Param			availability	body		dto.AvailabilityRequest	true	"Availability"
+//	@Success		200				{object}	dto.SuccessResponse{data=dto.SellerAvailability}
 //	@Failure		400				{object}	dto.ErrorResponse
+//	@Failure		401				{object}	dto.ErrorResponse
 //	@Failure		500				{object}	dto.ErrorResponse
-//	@Router			/appointment/{appointment_id}/date [put]
-func (s AppointmentController) UpdateAppointmentDate(c *gin.Context) {
-	appointmentIDstr := c.Param("appointment_id")
-	appointmentID, err := primitive.ObjectIDFromHex(appointmentIDstr)
+//	@Router			/seller/{seller_id}/availability [put]
+func (s AppointmentController) SetAvailability(c *gin.Context) {
+	sellerIDstr := c.Param("seller_id")
+	userID, exists := c.Get("userID")
+	if userID != sellerIDstr || !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusUnauthorized,
+			Error:   "ID not match or not exists",
+			Message: "param ID doesn't match with callerID"})
+		return
+	}
+	sellerID, err := primitive.ObjectIDFromHex(sellerIDstr)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Success: false,
 			Status:  http.StatusBadRequest,
-			Error:   "Invalid appointmentID format",
+			Error:   "Invalid sellerID format",
 			Message: err.Error(),
 		})
 		return
 	}
 
-	var dateRequest dto.AppointmentDateRequest
-	if err := c.ShouldBindJSON(&dateRequest); err != nil {
+	var req dto.AvailabilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Success: false,
 			Status:  http.StatusBadRequest,
@@ -221,29 +238,92 @@ func (s AppointmentController) UpdateAppointmentDate(c *gin.Context) {
 		return
 	}
 
-	// Parse the date from string to time.Time
-	parsedDate, err := time.Parse("2006-01-02", dateRequest.Date) // Assuming input format is "YYYY-MM-DD"
+	res, err := s.appointmentService.SetAvailability(sellerID, &req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrInvalidAvailability) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, dto.ErrorResponse{
+			Success: false,
+			Status:  status,
+			Error:   "Failed to set availability",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Set availability success",
+		Data:    res,
+	})
+}
+
+// GetOpenSlots godoc
+//
+//	@Summary		Get a seller's open slots
+//	@Description	Lists the seller's free appointment slots between two dates, inclusive. Defaults to the next 14 days.
+//	@Tags			seller
+//	@Produce		json
+//	@Param			seller_id	path		string	true	"Seller ID"
+//	@Param			from		query		string	false	"First date, 2006-01-02"
+//	@Param			to			query		string	false	"Last date, 2006-01-02"
+//	@Success		200			{object}	dto.SuccessResponse{data=dto.OpenSlots}
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/seller/{seller_id}/availability [get]
+func (s AppointmentController) GetOpenSlots(c *gin.Context) {
+	sellerID, err := primitive.ObjectIDFromHex(c.Param("seller_id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Success: false,
 			Status:  http.StatusBadRequest,
-			Error:   "Invalid date format, expected YYYY-MM-DD",
+			Error:   "Invalid sellerID format",
 			Message: err.Error(),
 		})
 		return
 	}
 
-	updatedAppointment := model.Appointment{
-		Date:     parsedDate,
-		TimeSlot: dateRequest.TimeSlot,
+	year, month, day := time.Now().In(service.AppointmentZone).Date()
+	from := time.Date(year, month, day, 0, 0, 0, 0, service.AppointmentZone)
+	if fromStr := c.Query("from"); fromStr != "" {
+		if from, err = time.ParseInLocation(time.DateOnly, fromStr, service.AppointmentZone); err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Success: false,
+				Status:  http.StatusBadRequest,
+				Error:   "Invalid from date",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+	to := from.AddDate(0, 0, 14)
+	if toStr := c.Query("to"); toStr != "" {
+		last, err := time.ParseInLocation(time.DateOnly, toStr, service.AppointmentZone)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Success: false,
+				Status:  http.StatusBadRequest,
+				Error:   "Invalid to date",
+				Message: err.Error(),
+			})
+			return
+		}
+		to = last.AddDate(0, 0, 1)
 	}
 
-	res, err := s.appointmentService.UpdateAppointmentDate(appointmentID, &updatedAppointment)
+	res, err := s.appointmentService.GetOpenSlots(sellerID, from, to)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrInvalidTimeSlot) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, dto.ErrorResponse{
 			Success: false,
-			Status:  http.StatusInternalServerError,
-			Error:   "Failed to update appointment date",
+			Status:  status,
+			Error:   "Failed to get open slots",
 			Message: err.Error(),
 		})
 		return
@@ -252,38 +332,44 @@ func (s AppointmentController) UpdateAppointmentDate(c *gin.Context) {
 	c.JSON(http.StatusOK, dto.SuccessResponse{
 		Success: true,
 		Status:  http.StatusOK,
-		Message: "Appointment date updated successfully",
+		Message: "Get open slots success",
 		Data:    res,
 	})
 }
 
-
-// UpdateAppointment godoc
-//	@Summary		Update an appointment place by ID
-//	@Description	Updates an existing appointment's place by its ID
+// Propose godoc
+//
+//	@Summary		Propose a place or time for an appointment
+//	@Description	The buyer or the seller proposes a place (kind 0) or one of the seller's open slots (kind 1). A proposal while the other party's one of the same kind is pending counters it.
 //	@Tags			appointment
 //	@Accept			json
 //	@Produce		json
-//	@Param			appointment_id	path		string						true	"Appointment ID"
-//	@Param			appointment		body		dto.AppointmentPlaceRequest	true	"Appointment place to update"
-//	@Success		200				{object}	dto.SuccessResponse{data=dto.Appointment}
+//	@Param			appointment_id	path		string							true	"Appointment ID"
+//	@Param			proposal		body		dto.AppointmentProposalRequest	true	"Proposal"
+//	@Success		201				{object}	dto.SuccessResponse{data=dto.Appointment}
 //	@Failure		400				{object}	dto.ErrorResponse
+//	@Failure		401				{object}	dto.ErrorResponse
+//	@Failure		403				{object}	dto.ErrorResponse
 //	@Failure		500				{object}	dto.ErrorResponse
-//	@Router			/appointment/{appointment_id}/place [put]
-func (s AppointmentController) UpdateAppointmentPlace(c *gin.Context) {
-	appointmentIDstr := c.Param("appointment_id")
-	appointmentID, err := primitive.ObjectIDFromHex(appointmentIDstr)
+//	@Router			/appointment/{appointment_id}/proposal [post]
+func (s AppointmentController) Propose(c *gin.Context) {
+	appointmentID, err := primitive.ObjectIDFromHex(c.Param("appointment_id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Success: false,
-			Status:  http.StatusInternalServerError,
+			Status:  http.StatusBadRequest,
 			Error:   "Invalid appointmentID format",
 			Message: err.Error(),
 		})
 		return
 	}
-	var updatedAppointment model.Appointment
-	if err := c.BindJSON(&updatedAppointment); err != nil {
+	callerID, ok := caller(c)
+	if !ok {
+		return
+	}
+
+	var req dto.AppointmentProposalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Success: false,
 			Status:  http.StatusBadRequest,
@@ -293,21 +379,190 @@ func (s AppointmentController) UpdateAppointmentPlace(c *gin.Context) {
 		return
 	}
 
-	res, err := s.appointmentService.UpdateAppointmentPlace(appointmentID, &updatedAppointment)
+	res, err := s.appointmentService.Propose(appointmentID, callerID, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+		proposalError(c, err, "Failed to propose")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusCreated,
+		Message: "Proposal created",
+		Data:    res,
+	})
+}
+
+// AcceptProposal godoc
+//
+//	@Summary		Accept an appointment proposal
+//	@Description	Applies the other party's pending proposal to the appointment. An accepted place moves the order to waiting for a time, an accepted time books the slot and moves it to appointed.
+//	@Tags			appointment
+//	@Produce		json
+//	@Param			appointment_id	path		string	true	"Appointment ID"
+//	@Param			proposal_id		path		string	true	"Proposal ID"
+//	@Success		200				{object}	dto.SuccessResponse{data=dto.Appointment}
+//	@Failure		400				{object}	dto.ErrorResponse
+//	@Failure		403				{object}	dto.ErrorResponse
+//	@Failure		404				{object}	dto.ErrorResponse
+//	@Failure		409				{object}	dto.ErrorResponse
+//	@Failure		500				{object}	dto.ErrorResponse
+//	@Router			/appointment/{appointment_id}/proposal/{proposal_id}/accept [post]
+func (s AppointmentController) AcceptProposal(c *gin.Context) {
+	s.answerProposal(c, s.appointmentService.AcceptProposal, "Proposal accepted")
+}
+
+// RejectProposal godoc
+//
+//	@Summary		Reject an appointment proposal
+//	@Description	Rejects the other party's pending proposal, leaving the appointment and the order as they are
+//	@Tags			appointment
+//	@Produce		json
+//	@Param			appointment_id	path		string	true	"Appointment ID"
+//	@Param			proposal_id		path		string	true	"Proposal ID"
+//	@Success		200				{object}	dto.SuccessResponse{data=dto.Appointment}
+//	@Failure		400				{object}	dto.ErrorResponse
+//	@Failure		403				{object}	dto.ErrorResponse
+//	@Failure		404				{object}	dto.ErrorResponse
+//	@Failure		409				{object}	dto.ErrorResponse
+//	@Failure		500				{object}	dto.ErrorResponse
+//	@Router			/appointment/{appointment_id}/proposal/{proposal_id}/reject [post]
+func (s AppointmentController) RejectProposal(c *gin.Context) {
+	s.answerProposal(c, s.appointmentService.RejectProposal, "Proposal rejected")
+}
+
+func (s AppointmentController) answerProposal(c *gin.Context, answer func(primitive.ObjectID, primitive.ObjectID, primitive.ObjectID) (*dto.Appointment, error), message string) {
+	appointmentID, err := primitive.ObjectIDFromHex(c.Param("appointment_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Success: false,
-			Status:  http.StatusInternalServerError,
-			Error:   "Failed to update appointment place",
-			Message: err.Error()})
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid appointmentID format",
+			Message: err.Error(),
+		})
+		return
+	}
+	proposalID, err := primitive.ObjectIDFromHex(c.Param("proposal_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid proposalID format",
+			Message: err.Error(),
+		})
+		return
+	}
+	callerID, ok := caller(c)
+	if !ok {
+		return
+	}
+
+	res, err := answer(appointmentID, proposalID, callerID)
+	if err != nil {
+		proposalError(c, err, "Failed to answer proposal")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: message,
+		Data:    res,
+	})
+}
+
+// ReportNoShow godoc
+//
+//	@Summary		Report a no-show
+//	@Description	The buyer or the seller reports that the other party missed the appointment. Depending on the config the order is cancelled and refunded and the absent party gets a strike.
+//	@Tags			appointment
+//	@Produce		json
+//	@Param			appointment_id	path		string	true	"Appointment ID"
+//	@Success		200				{object}	dto.SuccessResponse{data=dto.Appointment}
+//	@Failure		400				{object}	dto.ErrorResponse
+//	@Failure		403				{object}	dto.ErrorResponse
+//	@Failure		404				{object}	dto.ErrorResponse
+//	@Failure		409				{object}	dto.ErrorResponse
+//	@Failure		500				{object}	dto.ErrorResponse
+//	@Router			/appointment/{appointment_id}/no-show [post]
+func (s AppointmentController) ReportNoShow(c *gin.Context) {
+	appointmentID, err := primitive.ObjectIDFromHex(c.Param("appointment_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid appointmentID format",
+			Message: err.Error(),
+		})
+		return
+	}
+	callerID, ok := caller(c)
+	if !ok {
+		return
+	}
+
+	res, err := s.appointmentService.ReportNoShow(appointmentID, callerID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, service.ErrAppointmentUnscheduled), errors.Is(err, service.ErrNoShowTooEarly):
+			status = http.StatusBadRequest
+		case errors.Is(err, service.ErrNoShowForbidden):
+			status = http.StatusForbidden
+		case errors.Is(err, mongo.ErrNoDocuments):
+			status = http.StatusNotFound
+		case errors.Is(err, service.ErrNoShowReported), errors.Is(err, service.ErrOrderClosed):
+			status = http.StatusConflict
+		}
+		c.JSON(status, dto.ErrorResponse{
+			Success: false,
+			Status:  status,
+			Error:   "Failed to report no-show",
+			Message: err.Error(),
+		})
 		return
 	}
 
 	c.JSON(http.StatusOK, dto.SuccessResponse{
 		Success: true,
 		Status:  http.StatusOK,
-		Message: "Update appointment place success",
+		Message: "No-show reported",
 		Data:    res,
 	})
 }
 
+func proposalError(c *gin.Context, err error, message string) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, service.ErrInvalidProposal), errors.Is(err, service.ErrInvalidTimeSlot), errors.Is(err, service.ErrSlotUnavailable):
+		status = http.StatusBadRequest
+	case errors.Is(err, service.ErrProposalForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, service.ErrProposalNotFound), errors.Is(err, mongo.ErrNoDocuments):
+		status = http.StatusNotFound
+	case errors.Is(err, service.ErrProposalNotPending), errors.Is(err, service.ErrSlotTaken), errors.Is(err, service.ErrAppointmentClosed):
+		status = http.StatusConflict
+	}
+	c.JSON(status, dto.ErrorResponse{
+		Success: false,
+		Status:  status,
+		Error:   message,
+		Message: err.Error(),
+	})
+}
+
+// caller is the authenticated user, answering 401 when there is none
+func caller(c *gin.Context) (primitive.ObjectID, bool) {
+	userID, exists := c.Get("userID")
+	userIDStr, _ := userID.(string)
+	callerID, err := primitive.ObjectIDFromHex(userIDStr)
+	if !exists || err != nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusUnauthorized,
+			Error:   "Unauthorized",
+			Message: "caller ID is missing or invalid"})
+		return primitive.NilObjectID, false
+	}
+	return callerID, true
+}
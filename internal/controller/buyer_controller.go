@@ -1,11 +1,13 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/Dongy-s-Advanture/back-end/internal/dto"
 	"github.com/Dongy-s-Advanture/back-end/internal/model"
 	"github.com/Dongy-s-Advanture/back-end/internal/service"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/converter"
 	"github.com/gin-gonic/gin"
 	"github.com/jinzhu/copier"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -18,6 +20,12 @@ type IBuyerController interface {
 	UpdateBuyer(c *gin.Context)
 	UpdateProductInCart(c *gin.Context)
 	DeleteProductFromCart(c *gin.Context)
+	GetCart(c *gin.Context)
+	GetWishlist(c *gin.Context)
+	AddToWishlist(c *gin.Context)
+	RemoveFromWishlist(c *gin.Context)
+	MoveToCart(c *gin.Context)
+	SaveForLater(c *gin.Context)
 }
 
 type BuyerController struct {
@@ -56,9 +64,9 @@ func (s BuyerController) CreateBuyer(c *gin.Context) {
 		})
 		return
 	}
-	var profilePicUrl string
+	var profilePics *model.ImageVariants
 	if newBuyer.ProfilePic != nil {
-		fileUrl, err := s.s3Service.UploadFile(newBuyer.ProfilePic, "buyers")
+		variants, err := s.s3Service.UploadImage(newBuyer.ProfilePic, "buyers")
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 				Success: false,
@@ -68,7 +76,16 @@ func (s BuyerController) CreateBuyer(c *gin.Context) {
 			})
 			return
 		}
-		profilePicUrl = fileUrl
+		profilePics, err = converter.ImageVariantsDTOToModel(variants)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Success: false,
+				Status:  http.StatusInternalServerError,
+				Error:   "Failed to convert image data",
+				Message: err.Error(),
+			})
+			return
+		}
 	}
 	var newBuyerData model.Buyer
 	if err := copier.Copy(&newBuyerData, &newBuyer); err != nil {
@@ -82,11 +99,23 @@ func (s BuyerController) CreateBuyer(c *gin.Context) {
 	}
 
 	newBuyerData.Password = newBuyer.Password
-	newBuyerData.ProfilePic = profilePicUrl
+	if profilePics != nil {
+		newBuyerData.ProfilePic = profilePics.Medium
+		newBuyerData.ProfilePics = profilePics
+	}
 
 	res, err := s.buyerService.CreateBuyerData(&newBuyerData)
 
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidAddress) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Success: false,
+				Status:  http.StatusBadRequest,
+				Error:   "Invalid address",
+				Message: err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Success: false,
 			Status:  http.StatusInternalServerError,
@@ -224,9 +253,13 @@ func (s BuyerController) UpdateBuyer(c *gin.Context) {
 
 	res, err := s.buyerService.UpdateBuyerData(buyerID, &updatedBuyer)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrInvalidLocation) || errors.Is(err, service.ErrInvalidAddress) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, dto.ErrorResponse{
 			Success: false,
-			Status:  http.StatusInternalServerError,
+			Status:  status,
 			Error:   "Failed to update buyer data",
 			Message: err.Error(),
 		})
@@ -249,7 +282,7 @@ func (s BuyerController) UpdateBuyer(c *gin.Context) {
 // @Param buyer_id path string true "Buyer ID"
 // @Param orderProduct body dto.OrderProduct true "Product to update in cart"
 // @Success 200 {object} dto.SuccessResponse{data=[]dto.OrderProduct}
-// @Failure 400 {object} dto.ErrorResponse
+// @Failure 400 {object} dto.ErrorResponse "Invalid amount, product unavailable or not enough stock"
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /buyer/{buyer_id}/cart [post]
 func (s BuyerController) UpdateProductInCart(c *gin.Context) {
@@ -289,6 +322,15 @@ func (s BuyerController) UpdateProductInCart(c *gin.Context) {
 	}
 
 	updatedCart, err := s.buyerService.UpdateProductInCart(buyerID, request)
+	if errors.Is(err, service.ErrInvalidCartAmount) || errors.Is(err, service.ErrProductUnavailable) || errors.Is(err, service.ErrNotEnoughStock) {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Failed to update cart",
+			Message: err.Error(),
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Success: false,
@@ -373,3 +415,322 @@ func (s BuyerController) DeleteProductFromCart(c *gin.Context) {
 		Message: "Product deleted from cart successfully",
 	})
 }
+
+// GetCart godoc
+// @Summary Get buyer's cart
+// @Description Returns the cart with current product details. Items that were deleted, are out of stock or changed price since being added are flagged
+// @Tags buyer
+// @Accept json
+// @Produce json
+// @Param buyer_id path string true "Buyer ID"
+// @Success 200 {object} dto.SuccessResponse{data=dto.Cart}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /buyer/{buyer_id}/cart [get]
+func (s BuyerController) GetCart(c *gin.Context) {
+	buyerIDStr := c.Param("buyer_id")
+	userID, exists := c.Get("userID")
+
+	if userID != buyerIDStr || !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusUnauthorized,
+			Error:   "Unauthorized",
+			Message: "You are not allowed to view this cart",
+		})
+		return
+	}
+
+	buyerID, err := primitive.ObjectIDFromHex(buyerIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid buyerID format",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	cart, err := s.buyerService.GetCart(buyerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusInternalServerError,
+			Error:   "Failed to get cart",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Get cart success",
+		Data:    cart,
+	})
+}
+
+// GetWishlist godoc
+// @Summary Get buyer's wishlist
+// @Description Returns the wishlist with current product details
+// @Tags buyer
+// @Accept json
+// @Produce json
+// @Param buyer_id path string true "Buyer ID"
+// @Success 200 {object} dto.SuccessResponse{data=[]dto.WishlistProduct}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /buyer/{buyer_id}/wishlist [get]
+func (s BuyerController) GetWishlist(c *gin.Context) {
+	buyerID, ok := authorizeBuyer(c)
+	if !ok {
+		return
+	}
+
+	wishlist, err := s.buyerService.GetWishlist(buyerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusInternalServerError,
+			Error:   "Failed to get wishlist",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Get wishlist success",
+		Data:    wishlist,
+	})
+}
+
+// AddToWishlist godoc
+// @Summary Add a product to buyer's wishlist
+// @Description Adds the product to the wishlist. The buyer is alerted when it drops in price or comes back in stock
+// @Tags buyer
+// @Accept json
+// @Produce json
+// @Param buyer_id path string true "Buyer ID"
+// @Param product body dto.WishlistAddRequest true "Product to add"
+// @Success 200 {object} dto.SuccessResponse{data=[]dto.WishlistItem}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /buyer/{buyer_id}/wishlist [post]
+func (s BuyerController) AddToWishlist(c *gin.Context) {
+	buyerID, ok := authorizeBuyer(c)
+	if !ok {
+		return
+	}
+
+	var request dto.WishlistAddRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	wishlist, err := s.buyerService.AddToWishlist(buyerID, request.ProductID)
+	if errors.Is(err, service.ErrProductUnavailable) {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Failed to update wishlist",
+			Message: err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusInternalServerError,
+			Error:   "Failed to update wishlist",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Wishlist updated successfully",
+		Data:    wishlist,
+	})
+}
+
+// RemoveFromWishlist godoc
+// @Summary Remove a product from buyer's wishlist
+// @Tags buyer
+// @Accept json
+// @Produce json
+// @Param buyer_id path string true "Buyer ID"
+// @Param product_id path string true "Product ID"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /buyer/{buyer_id}/wishlist/{product_id} [delete]
+func (s BuyerController) RemoveFromWishlist(c *gin.Context) {
+	buyerID, productID, ok := authorizeBuyerProduct(c)
+	if !ok {
+		return
+	}
+
+	if err := s.buyerService.RemoveFromWishlist(buyerID, productID); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusInternalServerError,
+			Error:   "Failed to remove product from wishlist",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Product removed from wishlist",
+	})
+}
+
+// MoveToCart godoc
+// @Summary Move a wishlist product to the cart
+// @Description Adds the product to the cart, with its saved amount if it was saved for later, and removes it from the wishlist
+// @Tags buyer
+// @Accept json
+// @Produce json
+// @Param buyer_id path string true "Buyer ID"
+// @Param product_id path string true "Product ID"
+// @Success 200 {object} dto.SuccessResponse{data=[]dto.OrderProduct}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /buyer/{buyer_id}/wishlist/{product_id}/move-to-cart [post]
+func (s BuyerController) MoveToCart(c *gin.Context) {
+	buyerID, productID, ok := authorizeBuyerProduct(c)
+	if !ok {
+		return
+	}
+
+	updatedCart, err := s.buyerService.MoveToCart(buyerID, productID)
+	if errors.Is(err, service.ErrProductUnavailable) || errors.Is(err, service.ErrNotEnoughStock) {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Failed to move product to cart",
+			Message: err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusInternalServerError,
+			Error:   "Failed to move product to cart",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Product moved to cart",
+		Data:    updatedCart,
+	})
+}
+
+// SaveForLater godoc
+// @Summary Save a cart product for later
+// @Description Moves the product from the cart to the wishlist, keeping its amount
+// @Tags buyer
+// @Accept json
+// @Produce json
+// @Param buyer_id path string true "Buyer ID"
+// @Param product_id path string true "Product ID"
+// @Success 200 {object} dto.SuccessResponse{data=[]dto.WishlistItem}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /buyer/{buyer_id}/cart/{product_id}/save-for-later [post]
+func (s BuyerController) SaveForLater(c *gin.Context) {
+	buyerID, productID, ok := authorizeBuyerProduct(c)
+	if !ok {
+		return
+	}
+
+	wishlist, err := s.buyerService.SaveForLater(buyerID, productID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusInternalServerError,
+			Error:   "Failed to save product for later",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Product saved for later",
+		Data:    wishlist,
+	})
+}
+
+// authorizeBuyer checks the buyer_id param belongs to the caller, writing
+// the error response if not
+func authorizeBuyer(c *gin.Context) (primitive.ObjectID, bool) {
+	buyerIDStr := c.Param("buyer_id")
+	userID, exists := c.Get("userID")
+
+	if userID != buyerIDStr || !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusUnauthorized,
+			Error:   "Unauthorized",
+			Message: "You are not allowed to access this buyer",
+		})
+		return primitive.NilObjectID, false
+	}
+
+	buyerID, err := primitive.ObjectIDFromHex(buyerIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid buyerID format",
+			Message: err.Error(),
+		})
+		return primitive.NilObjectID, false
+	}
+	return buyerID, true
+}
+
+func authorizeBuyerProduct(c *gin.Context) (primitive.ObjectID, primitive.ObjectID, bool) {
+	buyerID, ok := authorizeBuyer(c)
+	if !ok {
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+
+	productID, err := primitive.ObjectIDFromHex(c.Param("product_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid productID format",
+			Message: err.Error(),
+		})
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+	return buyerID, productID, true
+}
@@ -0,0 +1,220 @@
+package controller
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/service"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/ical"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type ICalendarController interface {
+	GetCalendarSubscription(c *gin.Context)
+	RotateCalendarSubscription(c *gin.Context)
+	GetCalendarFeed(c *gin.Context)
+	GetAppointmentCalendar(c *gin.Context)
+}
+
+type CalendarController struct {
+	calendarService service.ICalendarService
+}
+
+func NewCalendarController(s service.ICalendarService) ICalendarController {
+	return CalendarController{
+		calendarService: s,
+	}
+}
+
+// GetCalendarSubscription godoc
+//
+//	@Summary		Get the caller's calendar feed URL
+//	@Description	Returns the secret URL calendar apps subscribe to for the caller's appointments
+//	@Tags			appointment
+//	@Produce		json
+//	@Success		200	{object}	dto.SuccessResponse{data=dto.CalendarSubscription}
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/appointment/calendar/token [get]
+func (s CalendarController) GetCalendarSubscription(c *gin.Context) {
+	callerID, ok := caller(c)
+	if !ok {
+		return
+	}
+
+	token, err := s.calendarService.CalendarToken(callerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusInternalServerError,
+			Error:   "Failed to create calendar token",
+			Message: err.Error(),
+		})
+		return
+	}
+	writeCalendarSubscription(c, token, "Get calendar subscription success")
+}
+
+// RotateCalendarSubscription godoc
+//
+//	@Summary		Rotate the caller's calendar feed URL
+//	@Description	Replaces the caller's calendar token, calendar apps subscribed with the old URL stop getting updates
+//	@Tags			appointment
+//	@Produce		json
+//	@Success		200	{object}	dto.SuccessResponse{data=dto.CalendarSubscription}
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/appointment/calendar/token [post]
+func (s CalendarController) RotateCalendarSubscription(c *gin.Context) {
+	callerID, ok := caller(c)
+	if !ok {
+		return
+	}
+
+	token, err := s.calendarService.RotateCalendarToken(callerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusInternalServerError,
+			Error:   "Failed to rotate calendar token",
+			Message: err.Error(),
+		})
+		return
+	}
+	writeCalendarSubscription(c, token, "Rotate calendar subscription success")
+}
+
+// writeCalendarSubscription sends the token with the feed URL built from it
+func writeCalendarSubscription(c *gin.Context, token string, message string) {
+	scheme := "https"
+	if c.Request.TLS == nil && c.GetHeader("X-Forwarded-Proto") != "https" {
+		scheme = "http"
+	}
+	feedPath := strings.TrimSuffix(c.FullPath(), "/calendar/token") + "/calendar.ics"
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: message,
+		Data: dto.CalendarSubscription{
+			Token: token,
+			URL:   fmt.Sprintf("%s://%s%s?token=%s", scheme, c.Request.Host, feedPath, url.QueryEscape(token)),
+		},
+	})
+}
+
+// GetCalendarFeed godoc
+//
+//	@Summary		Get a calendar feed of appointments
+//	@Description	iCalendar feed of the appointments of the user the token belongs to, for calendar apps to subscribe to
+//	@Tags			appointment
+//	@Produce		text/calendar
+//	@Param			token	query		string	true	"Calendar token"
+//	@Success		200		{string}	string
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/appointment/calendar.ics [get]
+func (s CalendarController) GetCalendarFeed(c *gin.Context) {
+	userID, err := s.calendarService.VerifyCalendarToken(c.Query("token"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrInvalidCalendarToken) {
+			status = http.StatusUnauthorized
+		}
+		c.JSON(status, dto.ErrorResponse{
+			Success: false,
+			Status:  status,
+			Error:   "Invalid calendar token",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	calendar, err := s.calendarService.GetCalendar(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusInternalServerError,
+			Error:   "Failed to get calendar",
+			Message: err.Error(),
+		})
+		return
+	}
+	writeCalendar(c, calendar, "")
+}
+
+// GetAppointmentCalendar godoc
+//
+//	@Summary		Download an appointment as an iCalendar event
+//	@Description	Single event .ics file for the appointment, for the buyer or the seller
+//	@Tags			appointment
+//	@Produce		text/calendar
+//	@Param			appointment_id	path		string	true	"Appointment ID"
+//	@Success		200				{string}	string
+//	@Failure		400				{object}	dto.ErrorResponse
+//	@Failure		403				{object}	dto.ErrorResponse
+//	@Failure		404				{object}	dto.ErrorResponse
+//	@Failure		500				{object}	dto.ErrorResponse
+//	@Router			/appointment/{appointment_id}/calendar.ics [get]
+func (s CalendarController) GetAppointmentCalendar(c *gin.Context) {
+	appointmentIDstr := c.Param("appointment_id")
+	appointmentID, err := primitive.ObjectIDFromHex(appointmentIDstr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid appointmentID format",
+			Message: err.Error(),
+		})
+		return
+	}
+	callerID, ok := caller(c)
+	if !ok {
+		return
+	}
+
+	calendar, err := s.calendarService.GetAppointmentCalendar(appointmentID, callerID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, service.ErrAppointmentUnscheduled):
+			status = http.StatusBadRequest
+		case errors.Is(err, service.ErrAppointmentForbidden):
+			status = http.StatusForbidden
+		case errors.Is(err, mongo.ErrNoDocuments):
+			status = http.StatusNotFound
+		}
+		c.JSON(status, dto.ErrorResponse{
+			Success: false,
+			Status:  status,
+			Error:   "Failed to get appointment calendar",
+			Message: err.Error(),
+		})
+		return
+	}
+	writeCalendar(c, calendar, "appointment-"+appointmentIDstr+".ics")
+}
+
+// writeCalendar sends the calendar, as a download when filename is set
+func writeCalendar(c *gin.Context, calendar *ical.Calendar, filename string) {
+	var body bytes.Buffer
+	if _, err := calendar.WriteTo(&body); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusInternalServerError,
+			Error:   "Failed to write calendar",
+			Message: err.Error(),
+		})
+		return
+	}
+	if filename != "" {
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	}
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", body.Bytes())
+}
@@ -0,0 +1,212 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ICommissionController interface {
+	GetRules(c *gin.Context)
+	SetRule(c *gin.Context)
+	DeleteRule(c *gin.Context)
+	GetLedger(c *gin.Context)
+}
+
+type CommissionController struct {
+	commissionService service.ICommissionService
+}
+
+func NewCommissionController(s service.ICommissionService) ICommissionController {
+	return CommissionController{
+		commissionService: s,
+	}
+}
+
+// GetRules godoc
+//
+//	@Summary		Get commission rules
+//	@Description	Lists the global, category and seller commission rules. Admin only.
+//	@Tags			commission
+//	@Produce		json
+//	@Success		200	{object}	dto.SuccessResponse{data=[]dto.CommissionRule}
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/commission/rules [get]
+func (s CommissionController) GetRules(c *gin.Context) {
+	rules, err := s.commissionService.GetRules()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusInternalServerError,
+			Error:   "Failed to get commission rules",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Get commission rules success",
+		Data:    rules,
+	})
+}
+
+// SetRule godoc
+//
+//	@Summary		Set a commission rule
+//	@Description	Creates the rule for the global scope, a category (product tag) or a seller, replacing the rate if it already exists. Admin only.
+//	@Tags			commission
+//	@Accept			json
+//	@Produce		json
+//	@Param			rule	body		dto.CommissionRuleRequest	true	"Rule to set"
+//	@Success		200		{object}	dto.SuccessResponse{data=dto.CommissionRule}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/commission/rules [put]
+func (s CommissionController) SetRule(c *gin.Context) {
+	var req dto.CommissionRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid request body, failed to bind JSON",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	rule, err := s.commissionService.SetRule(&req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrInvalidCommissionRule) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, dto.ErrorResponse{
+			Success: false,
+			Status:  status,
+			Error:   "Failed to set commission rule",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Commission rule set",
+		Data:    rule,
+	})
+}
+
+// DeleteRule godoc
+//
+//	@Summary		Delete a commission rule
+//	@Description	Orders fall back to the next matching rule. Admin only.
+//	@Tags			commission
+//	@Produce		json
+//	@Param			rule_id	path		string	true	"Rule ID"
+//	@Success		200		{object}	dto.SuccessResponse
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/commission/rules/{rule_id} [delete]
+func (s CommissionController) DeleteRule(c *gin.Context) {
+	ruleID, err := primitive.ObjectIDFromHex(c.Param("rule_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid ruleID format",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := s.commissionService.DeleteRule(ruleID); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrCommissionRuleNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, dto.ErrorResponse{
+			Success: false,
+			Status:  status,
+			Error:   "Failed to delete commission rule",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Commission rule deleted",
+	})
+}
+
+// GetLedger godoc
+//
+//	@Summary		Get platform revenue
+//	@Description	Returns the platform ledger entries and totals between from (inclusive) and to (exclusive), dates as YYYY-MM-DD. Defaults to the last 30 days. Admin only.
+//	@Tags			commission
+//	@Produce		json
+//	@Param			from	query		string	false	"Start date"
+//	@Param			to		query		string	false	"End date"
+//	@Success		200		{object}	dto.SuccessResponse{data=dto.LedgerSummary}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/commission/ledger [get]
+func (s CommissionController) GetLedger(c *gin.Context) {
+	to := time.Now()
+	from := to.AddDate(0, 0, -30)
+	var err error
+	if fromStr := c.Query("from"); fromStr != "" {
+		if from, err = time.Parse(time.DateOnly, fromStr); err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Success: false,
+				Status:  http.StatusBadRequest,
+				Error:   "Invalid from date",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+	if toStr := c.Query("to"); toStr != "" {
+		if to, err = time.Parse(time.DateOnly, toStr); err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Success: false,
+				Status:  http.StatusBadRequest,
+				Error:   "Invalid to date",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	summary, err := s.commissionService.GetLedger(from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusInternalServerError,
+			Error:   "Failed to get ledger",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Get ledger success",
+		Data:    summary,
+	})
+}
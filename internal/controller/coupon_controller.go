@@ -0,0 +1,211 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/config"
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ICouponController interface {
+	CreateCoupon(c *gin.Context)
+	GetCouponsBySellerID(c *gin.Context)
+	DeactivateCoupon(c *gin.Context)
+}
+
+type CouponController struct {
+	couponService service.ICouponService
+	adminConfig   *config.AdminConfig
+}
+
+func NewCouponController(s service.ICouponService, adminConfig *config.AdminConfig) ICouponController {
+	return CouponController{
+		couponService: s,
+		adminConfig:   adminConfig,
+	}
+}
+
+// CreateCoupon godoc
+//
+//	@Summary		Create a coupon
+//	@Description	Creates a coupon for the calling seller's products, or a platform-wide coupon when the caller is an admin
+//	@Tags			coupon
+//	@Accept			json
+//	@Produce		json
+//	@Param			coupon	body		dto.CouponCreateRequest	true	"Coupon to create"
+//	@Success		201		{object}	dto.SuccessResponse{data=dto.Coupon}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/coupon/ [post]
+func (s CouponController) CreateCoupon(c *gin.Context) {
+	callerID, isAdmin, ok := s.caller(c)
+	if !ok {
+		return
+	}
+
+	var req dto.CouponCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid request body, failed to bind JSON",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	coupon, err := s.couponService.CreateCoupon(callerID, isAdmin, &req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, service.ErrInvalidCoupon):
+			status = http.StatusBadRequest
+		case errors.Is(err, service.ErrCouponForbidden):
+			status = http.StatusForbidden
+		case errors.Is(err, service.ErrCouponCodeTaken):
+			status = http.StatusConflict
+		}
+		c.JSON(status, dto.ErrorResponse{
+			Success: false,
+			Status:  status,
+			Error:   "Failed to create coupon",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusCreated,
+		Message: "Coupon created",
+		Data:    coupon,
+	})
+}
+
+// GetCouponsBySellerID godoc
+//
+//	@Summary		Get a seller's coupons
+//	@Description	Lists every coupon the seller has created, newest first
+//	@Tags			coupon
+//	@Produce		json
+//	@Param			seller_id	path		string	true	"Seller ID"
+//	@Success		200			{object}	dto.SuccessResponse{data=[]dto.Coupon}
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/coupon/seller/{seller_id} [get]
+func (s CouponController) GetCouponsBySellerID(c *gin.Context) {
+	sellerIDStr := c.Param("seller_id")
+	userID, exists := c.Get("userID")
+	if userID != sellerIDStr || !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusUnauthorized,
+			Error:   "ID not match or not exists",
+			Message: "param ID doesn't match with callerID"})
+		return
+	}
+	sellerID, err := primitive.ObjectIDFromHex(sellerIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid sellerID format",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	coupons, err := s.couponService.GetCouponsBySellerID(sellerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusInternalServerError,
+			Error:   "Failed to get coupons",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Get coupons success",
+		Data:    coupons,
+	})
+}
+
+// DeactivateCoupon godoc
+//
+//	@Summary		Deactivate a coupon
+//	@Description	Stops the coupon from being applied to new orders. Only its seller or an admin can deactivate it.
+//	@Tags			coupon
+//	@Produce		json
+//	@Param			coupon_id	path		string	true	"Coupon ID"
+//	@Success		200			{object}	dto.SuccessResponse
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		403			{object}	dto.ErrorResponse
+//	@Failure		404			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/coupon/{coupon_id} [delete]
+func (s CouponController) DeactivateCoupon(c *gin.Context) {
+	callerID, isAdmin, ok := s.caller(c)
+	if !ok {
+		return
+	}
+	couponID, err := primitive.ObjectIDFromHex(c.Param("coupon_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid couponID format",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := s.couponService.DeactivateCoupon(callerID, isAdmin, couponID); err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, service.ErrCouponNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, service.ErrCouponForbidden):
+			status = http.StatusForbidden
+		}
+		c.JSON(status, dto.ErrorResponse{
+			Success: false,
+			Status:  status,
+			Error:   "Failed to deactivate coupon",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Coupon deactivated",
+	})
+}
+
+// caller reads the authenticated user, writing a 401 when it is missing
+func (s CouponController) caller(c *gin.Context) (primitive.ObjectID, bool, bool) {
+	userID, exists := c.Get("userID")
+	userIDStr, _ := userID.(string)
+	callerID, err := primitive.ObjectIDFromHex(userIDStr)
+	if !exists || err != nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusUnauthorized,
+			Error:   "Unauthorized",
+			Message: "caller ID is missing or invalid"})
+		return primitive.NilObjectID, false, false
+	}
+	return callerID, s.adminConfig.IsAdmin(userIDStr), true
+}
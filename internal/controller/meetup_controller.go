@@ -0,0 +1,262 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/service"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/geo"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type IMeetupController interface {
+	CreateMeetupPoint(c *gin.Context)
+	DeleteMeetupPoint(c *gin.Context)
+	GetMeetupPoints(c *gin.Context)
+	SuggestMeetupPoints(c *gin.Context)
+}
+
+type MeetupController struct {
+	meetupService service.IMeetupService
+}
+
+func NewMeetupController(s service.IMeetupService) IMeetupController {
+	return MeetupController{
+		meetupService: s,
+	}
+}
+
+// CreateMeetupPoint godoc
+//
+//	@Summary		Add a public meet-up point
+//	@Description	Adds a public place buyers and sellers are suggested to meet at. Admin only.
+//	@Tags			meetup-point
+//	@Accept			json
+//	@Produce		json
+//	@Param			meetupPoint	body		dto.MeetupPointRequest	true	"Meet-up point"
+//	@Success		201			{object}	dto.SuccessResponse{data=dto.MeetupPoint}
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		403			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/meetup-point/ [post]
+func (s MeetupController) CreateMeetupPoint(c *gin.Context) {
+	var req dto.MeetupPointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid request body, failed to bind JSON",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	res, err := s.meetupService.CreateMeetupPoint(&req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrInvalidLocation) || errors.Is(err, service.ErrInvalidAddress) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, dto.ErrorResponse{
+			Success: false,
+			Status:  status,
+			Error:   "Failed to create meet-up point",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusCreated,
+		Message: "Meet-up point created",
+		Data:    res,
+	})
+}
+
+// DeleteMeetupPoint godoc
+//
+//	@Summary		Delete a meet-up point
+//	@Description	Admin only
+//	@Tags			meetup-point
+//	@Produce		json
+//	@Param			meetup_point_id	path		string	true	"Meet-up point ID"
+//	@Success		200				{object}	dto.SuccessResponse
+//	@Failure		400				{object}	dto.ErrorResponse
+//	@Failure		403				{object}	dto.ErrorResponse
+//	@Failure		404				{object}	dto.ErrorResponse
+//	@Failure		500				{object}	dto.ErrorResponse
+//	@Router			/meetup-point/{meetup_point_id} [delete]
+func (s MeetupController) DeleteMeetupPoint(c *gin.Context) {
+	meetupPointID, err := primitive.ObjectIDFromHex(c.Param("meetup_point_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid meetupPointID format",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := s.meetupService.DeleteMeetupPoint(meetupPointID); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, dto.ErrorResponse{
+			Success: false,
+			Status:  status,
+			Error:   "Failed to delete meet-up point",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Meet-up point deleted",
+	})
+}
+
+// GetMeetupPoints godoc
+//
+//	@Summary		Find meet-up points near a location
+//	@Description	Returns the meet-up points within radius meters of near, closest first
+//	@Tags			meetup-point
+//	@Produce		json
+//	@Param			near	query		string	true	"Latitude and longitude, lat,lng"
+//	@Param			radius	query		number	false	"Radius in meters, defaults to 5000"
+//	@Success		200		{object}	dto.SuccessResponse{data=[]dto.MeetupPoint}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/meetup-point/ [get]
+func (s MeetupController) GetMeetupPoints(c *gin.Context) {
+	near, radius, ok := nearQuery(c)
+	if !ok {
+		return
+	}
+	if near == nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid query",
+			Message: "near is required",
+		})
+		return
+	}
+
+	res, err := s.meetupService.GetMeetupPointsNear(*near, radius)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusInternalServerError,
+			Error:   "Failed to get meet-up points",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Get meet-up points success",
+		Data:    res,
+	})
+}
+
+// SuggestMeetupPoints godoc
+//
+//	@Summary		Suggest where to meet
+//	@Description	Returns the midpoint between the buyer and the seller and the public meet-up points around it. Both need a location on their profile.
+//	@Tags			appointment
+//	@Produce		json
+//	@Param			appointment_id	path		string	true	"Appointment ID"
+//	@Success		200				{object}	dto.SuccessResponse{data=dto.MeetupSuggestions}
+//	@Failure		400				{object}	dto.ErrorResponse
+//	@Failure		403				{object}	dto.ErrorResponse
+//	@Failure		404				{object}	dto.ErrorResponse
+//	@Failure		500				{object}	dto.ErrorResponse
+//	@Router			/appointment/{appointment_id}/meetup-points [get]
+func (s MeetupController) SuggestMeetupPoints(c *gin.Context) {
+	appointmentID, err := primitive.ObjectIDFromHex(c.Param("appointment_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid appointmentID format",
+			Message: err.Error(),
+		})
+		return
+	}
+	callerID, ok := caller(c)
+	if !ok {
+		return
+	}
+
+	res, err := s.meetupService.SuggestMeetupPoints(appointmentID, callerID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, service.ErrLocationMissing):
+			status = http.StatusBadRequest
+		case errors.Is(err, service.ErrAppointmentForbidden):
+			status = http.StatusForbidden
+		case errors.Is(err, mongo.ErrNoDocuments):
+			status = http.StatusNotFound
+		}
+		c.JSON(status, dto.ErrorResponse{
+			Success: false,
+			Status:  status,
+			Error:   "Failed to suggest meet-up points",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Suggest meet-up points success",
+		Data:    res,
+	})
+}
+
+// nearQuery reads the near=lat,lng and radius query parameters, answering
+// 400 when they are invalid. near is nil when it wasn't given.
+func nearQuery(c *gin.Context) (*geo.Point, float64, bool) {
+	nearStr := c.Query("near")
+	if nearStr == "" {
+		return nil, 0, true
+	}
+	near, err := geo.Parse(nearStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid near query",
+			Message: err.Error(),
+		})
+		return nil, 0, false
+	}
+
+	radius := float64(service.DefaultSearchRadius)
+	if radiusStr := c.Query("radius"); radiusStr != "" {
+		radius, err = strconv.ParseFloat(radiusStr, 64)
+		if err != nil || radius <= 0 || radius > service.MaxSearchRadius {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Success: false,
+				Status:  http.StatusBadRequest,
+				Error:   "Invalid radius query",
+				Message: "radius must be a number of meters up to " + strconv.Itoa(service.MaxSearchRadius),
+			})
+			return nil, 0, false
+		}
+	}
+	return &near, radius, true
+}
@@ -0,0 +1,452 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// streamKeepAlive is how often an idle message stream is written to, so
+// proxies don't close it
+const streamKeepAlive = 30 * time.Second
+
+type IMessageController interface {
+	StartConversation(c *gin.Context)
+	GetConversations(c *gin.Context)
+	GetConversation(c *gin.Context)
+	GetMessages(c *gin.Context)
+	SendMessage(c *gin.Context)
+	MarkRead(c *gin.Context)
+	GetUnreadCount(c *gin.Context)
+	UploadMessageImage(c *gin.Context)
+	StreamMessages(c *gin.Context)
+}
+
+type MessageController struct {
+	messageService service.IMessageService
+	s3Service      service.IS3Service
+}
+
+func NewMessageController(s service.IMessageService, s3 service.IS3Service) IMessageController {
+	return MessageController{
+		messageService: s,
+		s3Service:      s3,
+	}
+}
+
+// StartConversation godoc
+//
+//	@Summary		Start a conversation
+//	@Description	Opens a conversation with the seller of a product, or with the other party of an order. The existing conversation is returned if there already is one.
+//	@Tags			conversation
+//	@Accept			json
+//	@Produce		json
+//	@Param			conversation	body		dto.ConversationRequest	true	"A productID or an orderID"
+//	@Success		200				{object}	dto.SuccessResponse{data=dto.Conversation}
+//	@Failure		400				{object}	dto.ErrorResponse
+//	@Failure		401				{object}	dto.ErrorResponse
+//	@Failure		403				{object}	dto.ErrorResponse
+//	@Failure		404				{object}	dto.ErrorResponse
+//	@Failure		500				{object}	dto.ErrorResponse
+//	@Router			/conversation/ [post]
+func (s MessageController) StartConversation(c *gin.Context) {
+	callerID, ok := caller(c)
+	if !ok {
+		return
+	}
+	var req dto.ConversationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid request body, failed to bind JSON",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	res, err := s.messageService.StartConversation(callerID, &req)
+	if err != nil {
+		messageError(c, err, "Failed to start conversation")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Conversation started",
+		Data:    res,
+	})
+}
+
+// GetConversations godoc
+//
+//	@Summary		Get my conversations
+//	@Description	Returns the caller's conversations, the most recently active first
+//	@Tags			conversation
+//	@Produce		json
+//	@Success		200	{object}	dto.SuccessResponse{data=[]dto.Conversation}
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/conversation/ [get]
+func (s MessageController) GetConversations(c *gin.Context) {
+	callerID, ok := caller(c)
+	if !ok {
+		return
+	}
+
+	res, err := s.messageService.GetConversations(callerID)
+	if err != nil {
+		messageError(c, err, "Failed to get conversations")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Get conversations success",
+		Data:    res,
+	})
+}
+
+// GetConversation godoc
+//
+//	@Summary		Get a conversation
+//	@Tags			conversation
+//	@Produce		json
+//	@Param			conversation_id	path		string	true	"Conversation ID"
+//	@Success		200				{object}	dto.SuccessResponse{data=dto.Conversation}
+//	@Failure		400				{object}	dto.ErrorResponse
+//	@Failure		401				{object}	dto.ErrorResponse
+//	@Failure		403				{object}	dto.ErrorResponse
+//	@Failure		404				{object}	dto.ErrorResponse
+//	@Failure		500				{object}	dto.ErrorResponse
+//	@Router			/conversation/{conversation_id} [get]
+func (s MessageController) GetConversation(c *gin.Context) {
+	callerID, ok := caller(c)
+	if !ok {
+		return
+	}
+	conversationID, ok := conversationIDParam(c)
+	if !ok {
+		return
+	}
+
+	res, err := s.messageService.GetConversation(callerID, conversationID)
+	if err != nil {
+		messageError(c, err, "Failed to get conversation")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Get conversation success",
+		Data:    res,
+	})
+}
+
+// GetMessages godoc
+//
+//	@Summary		Get the messages of a conversation
+//	@Description	Returns the messages newest first. Pass the ID of the oldest message received as "before" to load earlier ones.
+//	@Tags			conversation
+//	@Produce		json
+//	@Param			conversation_id	path		string	true	"Conversation ID"
+//	@Param			before			query		string	false	"Only messages sent before this message ID"
+//	@Param			limit			query		int		false	"Messages to return, 50 by default and at most 100"
+//	@Success		200				{object}	dto.SuccessResponse{data=[]dto.Message}
+//	@Failure		400				{object}	dto.ErrorResponse
+//	@Failure		401				{object}	dto.ErrorResponse
+//	@Failure		403				{object}	dto.ErrorResponse
+//	@Failure		404				{object}	dto.ErrorResponse
+//	@Failure		500				{object}	dto.ErrorResponse
+//	@Router			/conversation/{conversation_id}/message [get]
+func (s MessageController) GetMessages(c *gin.Context) {
+	callerID, ok := caller(c)
+	if !ok {
+		return
+	}
+	conversationID, ok := conversationIDParam(c)
+	if !ok {
+		return
+	}
+
+	var before primitive.ObjectID
+	if c.Query("before") != "" {
+		var err error
+		if before, err = primitive.ObjectIDFromHex(c.Query("before")); err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Success: false,
+				Status:  http.StatusBadRequest,
+				Error:   "Invalid before format",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid limit",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	res, err := s.messageService.GetMessages(callerID, conversationID, before, limit)
+	if err != nil {
+		messageError(c, err, "Failed to get messages")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Get messages success",
+		Data:    res,
+	})
+}
+
+// SendMessage godoc
+//
+//	@Summary		Send a message
+//	@Description	Sends text and images, uploaded with POST /conversation/image, to the other party of the conversation
+//	@Tags			conversation
+//	@Accept			json
+//	@Produce		json
+//	@Param			conversation_id	path		string				true	"Conversation ID"
+//	@Param			message			body		dto.MessageRequest	true	"Message"
+//	@Success		201				{object}	dto.SuccessResponse{data=dto.Message}
+//	@Failure		400				{object}	dto.ErrorResponse
+//	@Failure		401				{object}	dto.ErrorResponse
+//	@Failure		403				{object}	dto.ErrorResponse
+//	@Failure		404				{object}	dto.ErrorResponse
+//	@Failure		500				{object}	dto.ErrorResponse
+//	@Router			/conversation/{conversation_id}/message [post]
+func (s MessageController) SendMessage(c *gin.Context) {
+	callerID, ok := caller(c)
+	if !ok {
+		return
+	}
+	conversationID, ok := conversationIDParam(c)
+	if !ok {
+		return
+	}
+	var req dto.MessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid request body, failed to bind JSON",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	res, err := s.messageService.SendMessage(callerID, conversationID, &req)
+	if err != nil {
+		messageError(c, err, "Failed to send message")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusCreated,
+		Message: "Message sent",
+		Data:    res,
+	})
+}
+
+// MarkRead godoc
+//
+//	@Summary		Mark a conversation read
+//	@Description	Clears the caller's unread messages in the conversation and tells the other party they were read
+//	@Tags			conversation
+//	@Produce		json
+//	@Param			conversation_id	path		string	true	"Conversation ID"
+//	@Success		200				{object}	dto.SuccessResponse
+//	@Failure		400				{object}	dto.ErrorResponse
+//	@Failure		401				{object}	dto.ErrorResponse
+//	@Failure		403				{object}	dto.ErrorResponse
+//	@Failure		404				{object}	dto.ErrorResponse
+//	@Failure		500				{object}	dto.ErrorResponse
+//	@Router			/conversation/{conversation_id}/read [post]
+func (s MessageController) MarkRead(c *gin.Context) {
+	callerID, ok := caller(c)
+	if !ok {
+		return
+	}
+	conversationID, ok := conversationIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := s.messageService.MarkRead(callerID, conversationID); err != nil {
+		messageError(c, err, "Failed to mark conversation read")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Conversation marked read",
+	})
+}
+
+// GetUnreadCount godoc
+//
+//	@Summary		Count my unread messages
+//	@Description	Returns how many messages the caller hasn't read across all their conversations
+//	@Tags			conversation
+//	@Produce		json
+//	@Success		200	{object}	dto.SuccessResponse{data=dto.UnreadCount}
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/conversation/unread [get]
+func (s MessageController) GetUnreadCount(c *gin.Context) {
+	callerID, ok := caller(c)
+	if !ok {
+		return
+	}
+
+	res, err := s.messageService.GetUnreadCount(callerID)
+	if err != nil {
+		messageError(c, err, "Failed to count unread messages")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Get unread count success",
+		Data:    res,
+	})
+}
+
+// UploadMessageImage godoc
+//
+//	@Summary		Upload a message image
+//	@Description	Processes an image and returns the URLs of its renditions, to be sent in "images" with a message
+//	@Tags			conversation
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Param			image	formData	file	true	"Message image"
+//	@Success		201		{object}	dto.SuccessResponse{data=dto.ImageVariants}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/conversation/image [post]
+func (s MessageController) UploadMessageImage(c *gin.Context) {
+	image, err := c.FormFile("image")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid request body, image is required",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	res, err := s.s3Service.UploadImage(image, "messages")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusInternalServerError,
+			Error:   "Failed to upload image to S3",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusCreated,
+		Message: "Message image uploaded",
+		Data:    res,
+	})
+}
+
+// StreamMessages godoc
+//
+//	@Summary		Stream my messages
+//	@Description	Opens an SSE connection delivering a "message" event for each message sent to the caller's conversations and a "read" event when a conversation is read. Events sent while disconnected are not replayed, reload the conversations after reconnecting.
+//	@Tags			conversation
+//	@Produce		text/event-stream
+//	@Success		200	{object}	dto.MessageEvent
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Router			/conversation/stream [get]
+func (s MessageController) StreamMessages(c *gin.Context) {
+	callerID, ok := caller(c)
+	if !ok {
+		return
+	}
+
+	c.Writer.Header().Set("Content-Type", "text/event-stream")
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	c.Writer.Header().Set("Connection", "keep-alive")
+
+	events, unsubscribe := s.messageService.Subscribe(callerID)
+	defer unsubscribe()
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			c.SSEvent(event.Type, event)
+			return true
+		case <-keepAlive.C:
+			_, err := io.WriteString(w, ": keep-alive\n\n")
+			return err == nil
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+func conversationIDParam(c *gin.Context) (primitive.ObjectID, bool) {
+	conversationID, err := primitive.ObjectIDFromHex(c.Param("conversation_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid conversationID format",
+			Message: err.Error(),
+		})
+		return primitive.NilObjectID, false
+	}
+	return conversationID, true
+}
+
+// messageError responds with the status matching a message service error
+func messageError(c *gin.Context, err error, message string) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		status = http.StatusNotFound
+	case errors.Is(err, service.ErrConversationForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, service.ErrInvalidConversation), errors.Is(err, service.ErrEmptyMessage):
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, dto.ErrorResponse{
+		Success: false,
+		Status:  status,
+		Error:   message,
+		Message: err.Error(),
+	})
+}
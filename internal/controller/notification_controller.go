@@ -0,0 +1,231 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type INotificationController interface {
+	GetNotifications(c *gin.Context)
+	MarkRead(c *gin.Context)
+	MarkAllRead(c *gin.Context)
+	GetPreferences(c *gin.Context)
+	UpdatePreferences(c *gin.Context)
+}
+
+type NotificationController struct {
+	notificationService service.INotificationService
+}
+
+func NewNotificationController(s service.INotificationService) INotificationController {
+	return NotificationController{
+		notificationService: s,
+	}
+}
+
+// GetNotifications godoc
+//
+//	@Summary		Get my notifications
+//	@Description	Returns the caller's latest 50 notifications, newest first
+//	@Tags			notification
+//	@Produce		json
+//	@Param			unread	query		bool	false	"Only unread notifications"
+//	@Success		200		{object}	dto.SuccessResponse{data=[]dto.Notification}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/notifications [get]
+func (s NotificationController) GetNotifications(c *gin.Context) {
+	callerID, ok := caller(c)
+	if !ok {
+		return
+	}
+	unreadOnly, err := strconv.ParseBool(c.DefaultQuery("unread", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid unread",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	res, err := s.notificationService.GetNotifications(callerID, unreadOnly)
+	if err != nil {
+		notificationError(c, err, "Failed to get notifications")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Get notifications success",
+		Data:    res,
+	})
+}
+
+// MarkRead godoc
+//
+//	@Summary		Mark a notification read
+//	@Tags			notification
+//	@Produce		json
+//	@Param			notification_id	path		string	true	"Notification ID"
+//	@Success		200				{object}	dto.SuccessResponse
+//	@Failure		400				{object}	dto.ErrorResponse
+//	@Failure		401				{object}	dto.ErrorResponse
+//	@Failure		404				{object}	dto.ErrorResponse
+//	@Failure		500				{object}	dto.ErrorResponse
+//	@Router			/notifications/{notification_id}/read [post]
+func (s NotificationController) MarkRead(c *gin.Context) {
+	callerID, ok := caller(c)
+	if !ok {
+		return
+	}
+	notificationID, err := primitive.ObjectIDFromHex(c.Param("notification_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid notificationID format",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := s.notificationService.MarkRead(callerID, notificationID); err != nil {
+		notificationError(c, err, "Failed to mark notification read")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Notification marked read",
+	})
+}
+
+// MarkAllRead godoc
+//
+//	@Summary		Mark all my notifications read
+//	@Tags			notification
+//	@Produce		json
+//	@Success		200	{object}	dto.SuccessResponse
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/notifications/read [post]
+func (s NotificationController) MarkAllRead(c *gin.Context) {
+	callerID, ok := caller(c)
+	if !ok {
+		return
+	}
+
+	if err := s.notificationService.MarkAllRead(callerID); err != nil {
+		notificationError(c, err, "Failed to mark notifications read")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Notifications marked read",
+	})
+}
+
+// GetPreferences godoc
+//
+//	@Summary		Get my notification preferences
+//	@Description	Returns the channels the caller is notified over. Users who never set them are notified in the app only.
+//	@Tags			notification
+//	@Produce		json
+//	@Success		200	{object}	dto.SuccessResponse{data=dto.NotificationPreference}
+//	@Failure		401	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/notifications/preferences [get]
+func (s NotificationController) GetPreferences(c *gin.Context) {
+	callerID, ok := caller(c)
+	if !ok {
+		return
+	}
+
+	res, err := s.notificationService.GetPreferences(callerID)
+	if err != nil {
+		notificationError(c, err, "Failed to get notification preferences")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Get notification preferences success",
+		Data:    res,
+	})
+}
+
+// UpdatePreferences godoc
+//
+//	@Summary		Set my notification preferences
+//	@Description	Chooses the channels (inbox, email, line, webhook) the caller is notified over and the notification types they don't want. Each channel needs its address: an email, the LINE user ID our LINE Official Account knows the caller by or an https webhook URL.
+//	@Tags			notification
+//	@Accept			json
+//	@Produce		json
+//	@Param			preferences	body		dto.NotificationPreferenceRequest	true	"Notification preferences"
+//	@Success		200			{object}	dto.SuccessResponse{data=dto.NotificationPreference}
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/notifications/preferences [put]
+func (s NotificationController) UpdatePreferences(c *gin.Context) {
+	callerID, ok := caller(c)
+	if !ok {
+		return
+	}
+	var req dto.NotificationPreferenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid request body, failed to bind JSON",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	res, err := s.notificationService.UpdatePreferences(callerID, &req)
+	if err != nil {
+		notificationError(c, err, "Failed to update notification preferences")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Notification preferences updated",
+		Data:    res,
+	})
+}
+
+// notificationError responds with the status matching a notification
+// service error
+func notificationError(c *gin.Context, err error, message string) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		status = http.StatusNotFound
+	case errors.Is(err, service.ErrChannelUnavailable), errors.Is(err, service.ErrInvalidPreference):
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, dto.ErrorResponse{
+		Success: false,
+		Status:  status,
+		Error:   message,
+		Message: err.Error(),
+	})
+}
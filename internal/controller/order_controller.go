@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/Dongy-s-Advanture/back-end/internal/dto"
@@ -17,6 +18,8 @@ type IOrderController interface {
 	DeleteOrderByOrderID(c *gin.Context)
 	UpdateOrderByOrderID(c *gin.Context)
 	UpdateOrderStatusByOrderID(c *gin.Context)
+	Checkout(c *gin.Context)
+	GetQuote(c *gin.Context)
 }
 type OrderController struct {
 	orderService   service.IOrderService
@@ -52,6 +55,25 @@ func (o OrderController) CreateOrder(c *gin.Context) {
 	}
 	newOrder, err := o.orderService.CreateOrder(&req)
 	if err != nil {
+		if errors.Is(err, service.ErrCouponNotFound) || errors.Is(err, service.ErrCouponNotApplicable) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Success: false,
+				Status:  http.StatusBadRequest,
+				Error:   "Invalid coupon",
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, service.ErrProductUnavailable) || errors.Is(err, service.ErrNotEnoughStock) ||
+			errors.Is(err, service.ErrMixedSellers) || errors.Is(err, service.ErrSellerMismatch) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Success: false,
+				Status:  http.StatusBadRequest,
+				Error:   "Product can't be ordered",
+				Message: err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Success: false,
 			Status:  http.StatusInternalServerError,
@@ -300,3 +322,152 @@ func (o OrderController) UpdateOrderStatusByOrderID(c *gin.Context) {
 		Data:    updatedStatus,
 	})
 }
+
+// Checkout godoc
+//
+//	@Summary		Check out the buyer's cart
+//	@Description	Places one order and appointment per seller for everything in the buyer's cart under a single payment
+//	@Tags			order
+//	@Accept			json
+//	@Produce		json
+//	@Param			buyer_id	path		string					true	"Buyer ID"
+//	@Param			checkout	body		dto.CheckoutRequest		true	"Payment details"
+//	@Success		201			{object}	dto.SuccessResponse{data=dto.CheckoutResponse}
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/buyer/{buyer_id}/checkout [post]
+func (o OrderController) Checkout(c *gin.Context) {
+	buyerIDStr := c.Param("buyer_id")
+	userID, exists := c.Get("userID")
+	if userID != buyerIDStr || !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusUnauthorized,
+			Error:   "ID not match or not exists",
+			Message: "param ID doesn't match with callerID"})
+		return
+	}
+	buyerID, err := primitive.ObjectIDFromHex(buyerIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid buyerID format",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var req dto.CheckoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid request body, failed to bind JSON",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	res, err := o.orderService.Checkout(buyerID, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrCouponNotFound) || errors.Is(err, service.ErrCouponNotApplicable) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Success: false,
+				Status:  http.StatusBadRequest,
+				Error:   "Invalid coupon",
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, service.ErrProductUnavailable) || errors.Is(err, service.ErrNotEnoughStock) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Success: false,
+				Status:  http.StatusBadRequest,
+				Error:   "Product can't be ordered",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusInternalServerError,
+			Error:   "Failed to check out",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusCreated,
+		Message: "Checkout success",
+		Data:    res,
+	})
+}
+
+// GetQuote godoc
+//
+//	@Summary		Price products with a coupon
+//	@Description	Returns the subtotal, discount and total the caller would pay for the products. The coupon is checked but not redeemed.
+//	@Tags			order
+//	@Accept			json
+//	@Produce		json
+//	@Param			quote	body		dto.PriceQuoteRequest	true	"Products and coupon code"
+//	@Success		200		{object}	dto.SuccessResponse{data=dto.PriceQuote}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		401		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/order/quote [post]
+func (o OrderController) GetQuote(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	userIDStr, _ := userID.(string)
+	buyerID, err := primitive.ObjectIDFromHex(userIDStr)
+	if !exists || err != nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusUnauthorized,
+			Error:   "Unauthorized",
+			Message: "caller ID is missing or invalid"})
+		return
+	}
+
+	var req dto.PriceQuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid request body, failed to bind JSON",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	quote, err := o.orderService.GetTotalPrice(buyerID, req.Products, req.CouponCode)
+	if err != nil {
+		if errors.Is(err, service.ErrCouponNotFound) || errors.Is(err, service.ErrCouponNotApplicable) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Success: false,
+				Status:  http.StatusBadRequest,
+				Error:   "Invalid coupon",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusInternalServerError,
+			Error:   "Failed to price products",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Get quote success",
+		Data:    quote,
+	})
+}
@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type IOutboxController interface {
+	GetDeadEvents(c *gin.Context)
+	RetryDeadEvent(c *gin.Context)
+}
+
+type OutboxController struct {
+	eventRelay service.IEventRelay
+}
+
+func NewOutboxController(r service.IEventRelay) IOutboxController {
+	return OutboxController{
+		eventRelay: r,
+	}
+}
+
+// GetDeadEvents godoc
+//
+//	@Summary		Get dead events
+//	@Description	Lists the latest 100 domain events whose subscribers kept failing until they ran out of attempts, newest first. Admin only.
+//	@Tags			outbox
+//	@Produce		json
+//	@Success		200	{object}	dto.SuccessResponse{data=[]dto.OutboxEvent}
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/outbox/dead [get]
+func (s OutboxController) GetDeadEvents(c *gin.Context) {
+	events, err := s.eventRelay.GetDeadEvents()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusInternalServerError,
+			Error:   "Failed to get dead events",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Get dead events success",
+		Data:    events,
+	})
+}
+
+// RetryDeadEvent godoc
+//
+//	@Summary		Retry a dead event
+//	@Description	Gives a dead event a fresh round of attempts. Subscribers that already handled it are skipped. Admin only.
+//	@Tags			outbox
+//	@Produce		json
+//	@Param			event_id	path		string	true	"Event ID"
+//	@Success		200			{object}	dto.SuccessResponse
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		403			{object}	dto.ErrorResponse
+//	@Failure		404			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/outbox/{event_id}/retry [post]
+func (s OutboxController) RetryDeadEvent(c *gin.Context) {
+	eventID, err := primitive.ObjectIDFromHex(c.Param("event_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid eventID format",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := s.eventRelay.RetryDeadEvent(eventID); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrEventNotDead) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, dto.ErrorResponse{
+			Success: false,
+			Status:  status,
+			Error:   "Failed to retry event",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Event queued for retry",
+	})
+}
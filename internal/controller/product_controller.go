@@ -3,10 +3,13 @@ package controller
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/productstatus"
 	"github.com/Dongy-s-Advanture/back-end/internal/model"
 	"github.com/Dongy-s-Advanture/back-end/internal/service"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/converter"
 	"github.com/gin-gonic/gin"
 	"github.com/jinzhu/copier"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -19,6 +22,10 @@ type IProductController interface {
 	GetProductsBySellerID(c *gin.Context)
 	UpdateProduct(c *gin.Context)
 	DeleteProduct(c *gin.Context)
+	GetSellerListings(c *gin.Context)
+	PublishProduct(c *gin.Context)
+	ArchiveProduct(c *gin.Context)
+	RestoreProduct(c *gin.Context)
 }
 
 type ProductController struct {
@@ -59,9 +66,9 @@ func (s ProductController) CreateProduct(c *gin.Context) {
 		})
 		return
 	}
-	var imageURL string
+	var images *model.ImageVariants
 	if newProduct.Image != nil {
-		fileUrl, err := s.s3Service.UploadFile(newProduct.Image, "products")
+		variants, err := s.s3Service.UploadImage(newProduct.Image, "products")
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 				Success: false,
@@ -71,7 +78,16 @@ func (s ProductController) CreateProduct(c *gin.Context) {
 			})
 			return
 		}
-		imageURL = fileUrl
+		images, err = converter.ImageVariantsDTOToModel(variants)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Success: false,
+				Status:  http.StatusInternalServerError,
+				Error:   "Failed to convert image data",
+				Message: err.Error(),
+			})
+			return
+		}
 	}
 
 	var newProductData model.Product
@@ -95,8 +111,14 @@ func (s ProductController) CreateProduct(c *gin.Context) {
 		return
 	}
 
-	newProductData.Image = imageURL
+	if images != nil {
+		newProductData.Image = images.Large
+		newProductData.Images = images
+	}
 	newProductData.SellerID = sellerID
+	if newProduct.Draft {
+		newProductData.Status = productstatus.DRAFT
+	}
 
 	res, err := s.productService.CreateProduct(&newProductData)
 
@@ -151,6 +173,16 @@ func (s ProductController) GetProductByID(c *gin.Context) {
 		})
 		return
 	}
+	// Archived and deleted products stay readable for order history, drafts don't
+	if res.Status == productstatus.DRAFT {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusNotFound,
+			Error:   "No product with this productID",
+			Message: "product is not published",
+		})
+		return
+	}
 
 	c.JSON(http.StatusOK, dto.SuccessResponse{
 		Success: true,
@@ -205,15 +237,29 @@ func (s ProductController) GetProductsBySellerID(c *gin.Context) {
 // GetProducts godoc
 //
 //	@Summary		Get all products
-//	@Description	Retrieves all products
+//	@Description	Retrieves all products, or with near only those of sellers within radius meters, closest seller first
 //	@Tags			product
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	{object}	dto.SuccessResponse{data=[]dto.Product}
-//	@Failure		500	{object}	dto.ErrorResponse
+//	@Param			near	query		string	false	"Latitude and longitude, lat,lng"
+//	@Param			radius	query		number	false	"Radius in meters, defaults to 5000"
+//	@Success		200		{object}	dto.SuccessResponse{data=[]dto.Product}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
 //	@Router			/product/ [get]
 func (s ProductController) GetProducts(c *gin.Context) {
-	res, err := s.productService.GetProducts()
+	near, radius, ok := nearQuery(c)
+	if !ok {
+		return
+	}
+
+	var res []dto.Product
+	var err error
+	if near != nil {
+		res, err = s.productService.GetProductsNear(*near, radius)
+	} else {
+		res, err = s.productService.GetProducts()
+	}
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
@@ -244,18 +290,13 @@ func (s ProductController) GetProducts(c *gin.Context) {
 //	@Param			updatedProduct		body		dto.UpdateProductRequest	true	"Product data to update"
 //	@Success		200			{object}	dto.SuccessResponse{data=dto.Product}
 //	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		404			{object}	dto.ErrorResponse
 //	@Failure		500			{object}	dto.ErrorResponse
 //	@Router			/product/{product_id} [put]
 func (s ProductController) UpdateProduct(c *gin.Context) {
-	productIDstr := c.Param("product_id")
-	productID, err := primitive.ObjectIDFromHex(productIDstr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Success: false,
-			Status:  http.StatusInternalServerError,
-			Error:   "Invalid productID format",
-			Message: err.Error(),
-		})
+	productID, ok := s.authorizeProductOwner(c)
+	if !ok {
 		return
 	}
 	var updatedProduct dto.UpdateProductRequest
@@ -268,16 +309,8 @@ func (s ProductController) UpdateProduct(c *gin.Context) {
 		})
 		return
 	}
-	sellerID, err := primitive.ObjectIDFromHex(updatedProduct.SellerID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Success: false,
-			Status:  http.StatusInternalServerError,
-			Error:   "Invalid productID format",
-			Message: err.Error(),
-		})
-		return
-	}
+	// The caller owns the product, so it stays theirs whatever the body says
+	sellerID, _ := primitive.ObjectIDFromHex(c.GetString("userID"))
 	res, err := s.productService.UpdateProduct(productID, &model.Product{
 		ProductID:   productID,
 		ProductName: updatedProduct.ProductName,
@@ -311,40 +344,215 @@ func (s ProductController) UpdateProduct(c *gin.Context) {
 // DeleteProduct godoc
 //
 //	@Summary		Delete a product by ID
-//	@Description	Delete a product's data by its ID
+//	@Description	Soft deletes a product. It is hidden from buyers but kept for order history and can be restored by its seller
 //	@Tags			product
 //	@Accept			json
 //	@Produce		json
 //	@Param			product_id	path		string	true	"Product ID"
 //	@Success		200			{object}	dto.SuccessResponse
+//	@Failure		401			{object}	dto.ErrorResponse
 //	@Failure		500			{object}	dto.ErrorResponse
 //	@Router			/product/{product_id} [delete]
 func (s ProductController) DeleteProduct(c *gin.Context) {
-	productIDstr := c.Param("product_id")
-	productID, err := primitive.ObjectIDFromHex(productIDstr)
+	productID, ok := s.authorizeProductOwner(c)
+	if !ok {
+		return
+	}
+	err := s.productService.DeleteProduct(productID)
+
 	if err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Success: false,
 			Status:  http.StatusInternalServerError,
-			Error:   "Invalid productID format",
+			Error:   "No product with this productID",
+			Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Delete product success",
+	})
+}
+
+// GetSellerListings godoc
+//
+//	@Summary		Get a seller's own listings
+//	@Description	Retrieves all of the caller's products including drafts, sold out, archived and deleted ones
+//	@Tags			product
+//	@Accept			json
+//	@Produce		json
+//	@Param			seller_id	path		string	true	"Seller ID"
+//	@Param			status		query		int		false	"Product status (0 active, 1 draft, 2 sold out, 3 archived)"
+//	@Success		200			{object}	dto.SuccessResponse{data=[]dto.Product}
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/product/seller/{seller_id}/listings [get]
+func (s ProductController) GetSellerListings(c *gin.Context) {
+	sellerIDstr := c.Param("seller_id")
+	userID, exists := c.Get("userID")
+	if userID != sellerIDstr || !exists {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusUnauthorized,
+			Error:   "ID not match or not exists",
+			Message: "param ID doesn't match with callerID"})
+		return
+	}
+	sellerID, err := primitive.ObjectIDFromHex(sellerIDstr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid sellerID format",
 			Message: err.Error(),
 		})
 		return
 	}
-	err = s.productService.DeleteProduct(productID)
 
+	var status *int
+	if statusStr := c.Query("status"); statusStr != "" {
+		parsed, err := strconv.Atoi(statusStr)
+		if err != nil || parsed < productstatus.ACTIVE || parsed > productstatus.ARCHIVED {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Success: false,
+				Status:  http.StatusBadRequest,
+				Error:   "Invalid status",
+				Message: "status must be between 0 and 3",
+			})
+			return
+		}
+		status = &parsed
+	}
+
+	res, err := s.productService.GetSellerListings(sellerID, status)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Success: false,
 			Status:  http.StatusInternalServerError,
-			Error:   "No product with this productID",
-			Message: err.Error()})
+			Error:   "Failed to get listings",
+			Message: err.Error(),
+		})
 		return
 	}
 
 	c.JSON(http.StatusOK, dto.SuccessResponse{
 		Success: true,
 		Status:  http.StatusOK,
-		Message: "Delete product success",
+		Message: "Get listings success",
+		Data:    res,
+	})
+}
+
+// PublishProduct godoc
+//
+//	@Summary		Publish a draft product
+//	@Description	Makes a draft product visible to buyers
+//	@Tags			product
+//	@Accept			json
+//	@Produce		json
+//	@Param			product_id	path		string	true	"Product ID"
+//	@Success		200			{object}	dto.SuccessResponse{data=dto.Product}
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Router			/product/{product_id}/publish [post]
+func (s ProductController) PublishProduct(c *gin.Context) {
+	s.changeProductStatus(c, s.productService.PublishProduct, "Publish product success")
+}
+
+// ArchiveProduct godoc
+//
+//	@Summary		Archive a product
+//	@Description	Hides a product from buyers without deleting it
+//	@Tags			product
+//	@Accept			json
+//	@Produce		json
+//	@Param			product_id	path		string	true	"Product ID"
+//	@Success		200			{object}	dto.SuccessResponse{data=dto.Product}
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Router			/product/{product_id}/archive [post]
+func (s ProductController) ArchiveProduct(c *gin.Context) {
+	s.changeProductStatus(c, s.productService.ArchiveProduct, "Archive product success")
+}
+
+// RestoreProduct godoc
+//
+//	@Summary		Restore an archived product
+//	@Description	Puts an archived or deleted product back on sale
+//	@Tags			product
+//	@Accept			json
+//	@Produce		json
+//	@Param			product_id	path		string	true	"Product ID"
+//	@Success		200			{object}	dto.SuccessResponse{data=dto.Product}
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Router			/product/{product_id}/restore [post]
+func (s ProductController) RestoreProduct(c *gin.Context) {
+	s.changeProductStatus(c, s.productService.RestoreProduct, "Restore product success")
+}
+
+func (s ProductController) changeProductStatus(c *gin.Context, change func(primitive.ObjectID) (*dto.Product, error), message string) {
+	productID, ok := s.authorizeProductOwner(c)
+	if !ok {
+		return
+	}
+
+	res, err := change(productID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Failed to update product status",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: message,
+		Data:    res,
 	})
 }
+
+// authorizeProductOwner parses the product_id param and checks the caller is
+// the product's seller, writing the error response if not
+func (s ProductController) authorizeProductOwner(c *gin.Context) (primitive.ObjectID, bool) {
+	productID, err := primitive.ObjectIDFromHex(c.Param("product_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid productID format",
+			Message: err.Error(),
+		})
+		return primitive.NilObjectID, false
+	}
+
+	product, err := s.productService.GetProductByID(productID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusNotFound,
+			Error:   "No product with this productID",
+			Message: err.Error(),
+		})
+		return primitive.NilObjectID, false
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists || userID != product.SellerID.Hex() {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusUnauthorized,
+			Error:   "ID not match or not exists",
+			Message: "product doesn't belong to the caller",
+		})
+		return primitive.NilObjectID, false
+	}
+	return productID, true
+}
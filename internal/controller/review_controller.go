@@ -1,13 +1,16 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 
+	"github.com/Dongy-s-Advanture/back-end/internal/config"
 	"github.com/Dongy-s-Advanture/back-end/internal/dto"
 	"github.com/Dongy-s-Advanture/back-end/internal/model"
 	"github.com/Dongy-s-Advanture/back-end/internal/service"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type IReviewController interface {
@@ -18,15 +21,28 @@ type IReviewController interface {
 	CreateReview(c *gin.Context)
 	UpdateReview(c *gin.Context)
 	DeleteReview(c *gin.Context)
+	UploadReviewImage(c *gin.Context)
+	GetSellerRating(c *gin.Context)
+	ReplyToReview(c *gin.Context)
+	ReportReview(c *gin.Context)
+	GetModerationQueue(c *gin.Context)
+	HideReview(c *gin.Context)
+	RemoveReview(c *gin.Context)
+	RestoreReview(c *gin.Context)
+	GetReviewsByProductID(c *gin.Context)
 }
 
 type ReviewController struct {
 	reviewService service.IReviewService
+	s3Service     service.IS3Service
+	adminConfig   *config.AdminConfig
 }
 
-func NewReviewController(s service.IReviewService) IReviewController {
+func NewReviewController(s service.IReviewService, s3 service.IS3Service, adminConfig *config.AdminConfig) IReviewController {
 	return ReviewController{
 		reviewService: s,
+		s3Service:     s3,
+		adminConfig:   adminConfig,
 	}
 }
 
@@ -187,13 +203,16 @@ func (s ReviewController) GetReviewsByBuyerID(c *gin.Context) {
 
 // CreateReview godoc
 //	@Summary		Create a new review
-//	@Description	Creates a new review in the database
+//	@Description	Reviews a completed order of the caller. Each order can be reviewed once, and the review is marked as a verified purchase.
 //	@Tags			review
 //	@Accept			json
 //	@Produce		json
 //	@Param			review	body		dto.ReviewCreateRequest	true	"Review to create"
 //	@Success		201		{object}	dto.SuccessResponse{data=dto.Review}
 //	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		403		{object}	dto.ErrorResponse
+//	@Failure		404		{object}	dto.ErrorResponse
+//	@Failure		409		{object}	dto.ErrorResponse
 //	@Failure		500		{object}	dto.ErrorResponse
 //	@Router			/review/ [post]
 func (s ReviewController) CreateReview(c *gin.Context) {
@@ -208,14 +227,38 @@ func (s ReviewController) CreateReview(c *gin.Context) {
 		})
 		return
 	}
+	if newReview.OrderID.IsZero() {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid request body",
+			Message: "orderID is required",
+		})
+		return
+	}
+	callerID, ok := caller(c)
+	if !ok {
+		return
+	}
 
-	res, err := s.reviewService.CreateReview(&newReview)
+	res, err := s.reviewService.CreateReview(callerID, &newReview)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, mongo.ErrNoDocuments):
+			status = http.StatusNotFound
+		case errors.Is(err, service.ErrReviewForbidden):
+			status = http.StatusForbidden
+		case errors.Is(err, service.ErrOrderNotCompleted), errors.Is(err, service.ErrInvalidProductReview):
+			status = http.StatusBadRequest
+		case errors.Is(err, service.ErrOrderReviewed):
+			status = http.StatusConflict
+		}
+		c.JSON(status, dto.ErrorResponse{
 			Success: false,
-			Status:  http.StatusInternalServerError,
-			Error:   "Failed to insert to database",
+			Status:  status,
+			Error:   "Failed to create review",
 			Message: err.Error(),
 		})
 		return
@@ -238,6 +281,8 @@ func (s ReviewController) CreateReview(c *gin.Context) {
 //	@Param			review		body		dto.ReviewUpdateRequest	true	"Review data to update"
 //	@Success		200			{object}	dto.SuccessResponse{data=dto.Review}
 //	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		403			{object}	dto.ErrorResponse
 //	@Failure		500			{object}	dto.ErrorResponse
 //	@Router			/review/{review_id} [put]
 func (s ReviewController) UpdateReview(c *gin.Context) {
@@ -263,11 +308,20 @@ func (s ReviewController) UpdateReview(c *gin.Context) {
 		return
 	}
 
-	res, err := s.reviewService.UpdateReview(reviewID, &updatedReview)
+	callerID, ok := caller(c)
+	if !ok {
+		return
+	}
+
+	res, err := s.reviewService.UpdateReview(reviewID, callerID, s.isAdmin(callerID), &updatedReview)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrReviewNotOwned) {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, dto.ErrorResponse{
 			Success: false,
-			Status:  http.StatusInternalServerError,
+			Status:  status,
 			Error:   "Failed to update review data",
 			Message: err.Error()})
 		return
@@ -289,6 +343,8 @@ func (s ReviewController) UpdateReview(c *gin.Context) {
 //	@Produce		json
 //	@Param			review_id	path		string	true	"Review ID"
 //	@Success		200			{object}	dto.SuccessResponse
+//	@Failure		401			{object}	dto.ErrorResponse
+//	@Failure		403			{object}	dto.ErrorResponse
 //	@Failure		500			{object}	dto.ErrorResponse
 //	@Router			/review/{review_id} [delete]
 func (s ReviewController) DeleteReview(c *gin.Context) {
@@ -303,9 +359,21 @@ func (s ReviewController) DeleteReview(c *gin.Context) {
 		})
 		return
 	}
-	err = s.reviewService.DeleteReview(reviewID)
+	callerID, ok := caller(c)
+	if !ok {
+		return
+	}
+	err = s.reviewService.DeleteReview(reviewID, callerID, s.isAdmin(callerID))
 
 	if err != nil {
+		if errors.Is(err, service.ErrReviewNotOwned) {
+			c.JSON(http.StatusForbidden, dto.ErrorResponse{
+				Success: false,
+				Status:  http.StatusForbidden,
+				Error:   "Not allowed to delete this review",
+				Message: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Success: false,
 			Status:  http.StatusInternalServerError,
@@ -320,3 +388,382 @@ func (s ReviewController) DeleteReview(c *gin.Context) {
 		Message: "Delete review success",
 	})
 }
+
+// UploadReviewImage godoc
+//	@Summary		Upload a review image
+//	@Description	Processes an image and returns the URLs of its renditions, to be sent as "images" when creating or updating a review
+//	@Tags			review
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Param			image	formData	file	true	"Review image"
+//	@Success		201		{object}	dto.SuccessResponse{data=dto.ImageVariants}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Failure		500		{object}	dto.ErrorResponse
+//	@Router			/review/image [post]
+func (s ReviewController) UploadReviewImage(c *gin.Context) {
+	image, err := c.FormFile("image")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid request body, image is required",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	res, err := s.s3Service.UploadImage(image, "reviews")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusInternalServerError,
+			Error:   "Failed to upload image to S3",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusCreated,
+		Message: "Review image uploaded",
+		Data:    res,
+	})
+}
+
+// GetSellerRating godoc
+//
+//	@Summary		Get a seller's rating
+//	@Description	Returns how many reviews a seller has, their average, the weighted score sellers are shown with and how many reviews gave each score
+//	@Tags			seller
+//	@Produce		json
+//	@Param			seller_id	path		string	true	"Seller ID"
+//	@Success		200			{object}	dto.SuccessResponse{data=dto.SellerRating}
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		404			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/seller/{seller_id}/rating [get]
+func (s ReviewController) GetSellerRating(c *gin.Context) {
+	sellerID, err := primitive.ObjectIDFromHex(c.Param("seller_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid sellerID format",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	res, err := s.reviewService.GetSellerRating(sellerID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, dto.ErrorResponse{
+			Success: false,
+			Status:  status,
+			Error:   "Failed to get seller rating",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Get seller rating success",
+		Data:    res,
+	})
+}
+
+// ReplyToReview godoc
+//
+//	@Summary		Reply to a review
+//	@Description	Posts the reviewed seller's public reply. A review gets one reply.
+//	@Tags			review
+//	@Accept			json
+//	@Produce		json
+//	@Param			review_id	path		string					true	"Review ID"
+//	@Param			reply		body		dto.ReviewReplyRequest	true	"Reply"
+//	@Success		201			{object}	dto.SuccessResponse{data=dto.Review}
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		403			{object}	dto.ErrorResponse
+//	@Failure		404			{object}	dto.ErrorResponse
+//	@Failure		409			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/review/{review_id}/reply [post]
+func (s ReviewController) ReplyToReview(c *gin.Context) {
+	reviewID, ok := reviewIDParam(c)
+	if !ok {
+		return
+	}
+	var req dto.ReviewReplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid request body, failed to bind JSON",
+			Message: err.Error(),
+		})
+		return
+	}
+	callerID, ok := caller(c)
+	if !ok {
+		return
+	}
+
+	res, err := s.reviewService.ReplyToReview(reviewID, callerID, req.Message)
+	if err != nil {
+		reviewError(c, err, "Failed to reply to review")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusCreated,
+		Message: "Reply posted",
+		Data:    res,
+	})
+}
+
+// ReportReview godoc
+//
+//	@Summary		Report a review
+//	@Description	Flags a review as abusive for an admin to look into. Each user reports a review once.
+//	@Tags			review
+//	@Accept			json
+//	@Produce		json
+//	@Param			review_id	path		string					true	"Review ID"
+//	@Param			report		body		dto.ReviewReportRequest	true	"Report"
+//	@Success		201			{object}	dto.SuccessResponse{data=dto.ReviewReport}
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		404			{object}	dto.ErrorResponse
+//	@Failure		409			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/review/{review_id}/report [post]
+func (s ReviewController) ReportReview(c *gin.Context) {
+	reviewID, ok := reviewIDParam(c)
+	if !ok {
+		return
+	}
+	var req dto.ReviewReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid request body, failed to bind JSON",
+			Message: err.Error(),
+		})
+		return
+	}
+	callerID, ok := caller(c)
+	if !ok {
+		return
+	}
+
+	res, err := s.reviewService.ReportReview(reviewID, callerID, req.Reason)
+	if err != nil {
+		reviewError(c, err, "Failed to report review")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusCreated,
+		Message: "Review reported",
+		Data:    res,
+	})
+}
+
+// GetModerationQueue godoc
+//
+//	@Summary		List reported reviews
+//	@Description	Returns the reviews with reports waiting on a decision, longest waiting first. Admin only.
+//	@Tags			review
+//	@Produce		json
+//	@Success		200	{object}	dto.SuccessResponse{data=[]dto.ModerationItem}
+//	@Failure		403	{object}	dto.ErrorResponse
+//	@Failure		500	{object}	dto.ErrorResponse
+//	@Router			/review/moderation [get]
+func (s ReviewController) GetModerationQueue(c *gin.Context) {
+	res, err := s.reviewService.GetModerationQueue()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusInternalServerError,
+			Error:   "Failed to get moderation queue",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Get moderation queue success",
+		Data:    res,
+	})
+}
+
+// HideReview godoc
+//
+//	@Summary		Hide a review
+//	@Description	Takes a review down, and out of the seller's rating, until it is removed or restored. Admin only.
+//	@Tags			review
+//	@Produce		json
+//	@Param			review_id	path		string	true	"Review ID"
+//	@Success		200			{object}	dto.SuccessResponse{data=dto.Review}
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		403			{object}	dto.ErrorResponse
+//	@Failure		404			{object}	dto.ErrorResponse
+//	@Failure		409			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/review/{review_id}/hide [post]
+func (s ReviewController) HideReview(c *gin.Context) {
+	s.moderate(c, s.reviewService.HideReview, "Review hidden")
+}
+
+// RemoveReview godoc
+//
+//	@Summary		Remove a review
+//	@Description	Upholds the reports about a review and keeps it down for good. Admin only.
+//	@Tags			review
+//	@Produce		json
+//	@Param			review_id	path		string	true	"Review ID"
+//	@Success		200			{object}	dto.SuccessResponse{data=dto.Review}
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		403			{object}	dto.ErrorResponse
+//	@Failure		404			{object}	dto.ErrorResponse
+//	@Failure		409			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/review/{review_id}/remove [post]
+func (s ReviewController) RemoveReview(c *gin.Context) {
+	s.moderate(c, s.reviewService.RemoveReview, "Review removed")
+}
+
+// RestoreReview godoc
+//
+//	@Summary		Restore a review
+//	@Description	Dismisses the reports about a review and shows it again. Admin only.
+//	@Tags			review
+//	@Produce		json
+//	@Param			review_id	path		string	true	"Review ID"
+//	@Success		200			{object}	dto.SuccessResponse{data=dto.Review}
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		403			{object}	dto.ErrorResponse
+//	@Failure		404			{object}	dto.ErrorResponse
+//	@Failure		409			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/review/{review_id}/restore [post]
+func (s ReviewController) RestoreReview(c *gin.Context) {
+	s.moderate(c, s.reviewService.RestoreReview, "Review restored")
+}
+
+// GetReviewsByProductID godoc
+//
+//	@Summary		Get the reviews of a product
+//	@Description	Returns what buyers said about a product in their reviews
+//	@Tags			product
+//	@Produce		json
+//	@Param			product_id	path		string	true	"Product ID"
+//	@Param			sort		query		string	false	"newest (default), highest or lowest"
+//	@Success		200			{object}	dto.SuccessResponse{data=[]dto.ProductReview}
+//	@Failure		400			{object}	dto.ErrorResponse
+//	@Failure		500			{object}	dto.ErrorResponse
+//	@Router			/product/{product_id}/reviews [get]
+func (s ReviewController) GetReviewsByProductID(c *gin.Context) {
+	productID, err := primitive.ObjectIDFromHex(c.Param("product_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid productID format",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	res, err := s.reviewService.GetReviewsByProductID(productID, c.Query("sort"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrInvalidReviewSort) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, dto.ErrorResponse{
+			Success: false,
+			Status:  status,
+			Error:   "Failed to get product reviews",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "Get product reviews success",
+		Data:    res,
+	})
+}
+
+func (s ReviewController) moderate(c *gin.Context, action func(primitive.ObjectID) (*dto.Review, error), message string) {
+	reviewID, ok := reviewIDParam(c)
+	if !ok {
+		return
+	}
+
+	res, err := action(reviewID)
+	if err != nil {
+		reviewError(c, err, "Failed to moderate review")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: message,
+		Data:    res,
+	})
+}
+
+func reviewIDParam(c *gin.Context) (primitive.ObjectID, bool) {
+	reviewID, err := primitive.ObjectIDFromHex(c.Param("review_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid reviewID format",
+			Message: err.Error(),
+		})
+		return primitive.NilObjectID, false
+	}
+	return reviewID, true
+}
+
+// reviewError responds with the status matching a review service error
+func reviewError(c *gin.Context, err error, message string) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		status = http.StatusNotFound
+	case errors.Is(err, service.ErrReplyForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, service.ErrReportOwnReview), errors.Is(err, service.ErrInvalidModeration):
+		status = http.StatusBadRequest
+	case errors.Is(err, service.ErrReviewReplied), errors.Is(err, service.ErrReviewReported), errors.Is(err, service.ErrReviewChanged):
+		status = http.StatusConflict
+	}
+	c.JSON(status, dto.ErrorResponse{
+		Success: false,
+		Status:  status,
+		Error:   message,
+		Message: err.Error(),
+	})
+}
+
+// isAdmin reports whether the caller may change other buyers' reviews
+func (s ReviewController) isAdmin(callerID primitive.ObjectID) bool {
+	return s.adminConfig.IsAdmin(callerID.Hex())
+}
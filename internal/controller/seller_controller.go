@@ -1,11 +1,13 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/Dongy-s-Advanture/back-end/internal/dto"
 	"github.com/Dongy-s-Advanture/back-end/internal/model"
 	"github.com/Dongy-s-Advanture/back-end/internal/service"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/converter"
 	"github.com/gin-gonic/gin"
 	"github.com/jinzhu/copier"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -57,9 +59,9 @@ func (s SellerController) CreateSeller(c *gin.Context) {
 		return
 	}
 
-	var profilePicUrl string
+	var profilePics *model.ImageVariants
 	if newSeller.ProfilePic != nil {
-		fileUrl, err := s.s3Service.UploadFile(newSeller.ProfilePic, "sellers")
+		variants, err := s.s3Service.UploadImage(newSeller.ProfilePic, "sellers")
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 				Success: false,
@@ -69,7 +71,16 @@ func (s SellerController) CreateSeller(c *gin.Context) {
 			})
 			return
 		}
-		profilePicUrl = fileUrl
+		profilePics, err = converter.ImageVariantsDTOToModel(variants)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Success: false,
+				Status:  http.StatusInternalServerError,
+				Error:   "Failed to convert image data",
+				Message: err.Error(),
+			})
+			return
+		}
 	}
 	var newSellerData model.Seller
 	if err := copier.Copy(&newSellerData, &newSeller); err != nil {
@@ -82,11 +93,23 @@ func (s SellerController) CreateSeller(c *gin.Context) {
 		return
 	}
 	newSellerData.Password = newSeller.Password
-	newSellerData.ProfilePic = profilePicUrl
+	if profilePics != nil {
+		newSellerData.ProfilePic = profilePics.Medium
+		newSellerData.ProfilePics = profilePics
+	}
 
 	res, err := s.sellerService.CreateSellerData(&newSellerData)
 
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidAddress) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Success: false,
+				Status:  http.StatusBadRequest,
+				Error:   "Invalid address",
+				Message: err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Success: false,
 			Status:  http.StatusInternalServerError,
@@ -224,9 +247,13 @@ func (s SellerController) UpdateSeller(c *gin.Context) {
 
 	res, err := s.sellerService.UpdateSeller(sellerID, &updatedSeller)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrInvalidLocation) || errors.Is(err, service.ErrInvalidAddress) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, dto.ErrorResponse{
 			Success: false,
-			Status:  http.StatusInternalServerError,
+			Status:  status,
 			Error:   "Failed to update seller data",
 			Message: err.Error()})
 		return
@@ -308,7 +335,7 @@ func (s SellerController) WithdrawSellerBalance(c *gin.Context) {
 //	@Accept			json
 //	@Produce		json
 //	@Param			seller_id	path		string	true	"Seller ID"
-//	@Success		200			{object}	dto.SuccessResponse{data=float64}
+//	@Success		200			{object}	dto.SuccessResponse{data=money.Money}
 //	@Failure		400			{object}	dto.ErrorResponse
 //	@Failure		500			{object}	dto.ErrorResponse
 //	@Router			/seller/{seller_id}/balance [get]
@@ -0,0 +1,170 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/service"
+	"github.com/Dongy-s-Advanture/back-end/internal/storage"
+	"github.com/gin-gonic/gin"
+)
+
+// Presigned uploads go straight to storage, so cap what the local driver accepts
+const maxLocalUploadBytes = 20 << 20
+
+type IStorageController interface {
+	PresignUpload(c *gin.Context)
+	CompleteUpload(c *gin.Context)
+	LocalUpload(c *gin.Context)
+}
+
+type StorageController struct {
+	s3Service service.IS3Service
+	local     *storage.LocalStorage
+}
+
+// local is nil unless files are stored on disk
+func NewStorageController(s3 service.IS3Service, local *storage.LocalStorage) IStorageController {
+	return StorageController{
+		s3Service: s3,
+		local:     local,
+	}
+}
+
+// PresignUpload godoc
+//
+//	@Summary		Create a presigned upload URL
+//	@Description	Returns a URL the client can PUT an image to directly, bypassing the API server
+//	@Tags			storage
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		dto.PresignUploadRequest	true	"File to upload"
+//	@Success		201		{object}	dto.SuccessResponse{data=dto.PresignUploadResponse}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Router			/storage/presign [post]
+func (s StorageController) PresignUpload(c *gin.Context) {
+	var req dto.PresignUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid request body, failed to bind JSON",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	res, err := s.s3Service.PresignUpload(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Failed to create upload URL",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusCreated,
+		Message: "Upload URL created",
+		Data:    res,
+	})
+}
+
+// CompleteUpload godoc
+//
+//	@Summary		Complete a presigned upload
+//	@Description	Strips the metadata of an image uploaded to a presigned URL and stores its resized renditions. The original upload is deleted.
+//	@Tags			storage
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		dto.CompleteUploadRequest	true	"Key returned by /storage/presign"
+//	@Success		201		{object}	dto.SuccessResponse{data=dto.ImageVariants}
+//	@Failure		400		{object}	dto.ErrorResponse
+//	@Router			/storage/complete [post]
+func (s StorageController) CompleteUpload(c *gin.Context) {
+	var req dto.CompleteUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid request body, failed to bind JSON",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	res, err := s.s3Service.CompleteUpload(req.Key)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Failed to process upload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusCreated,
+		Message: "Upload processed",
+		Data:    res,
+	})
+}
+
+// LocalUpload receives PUT requests made to presigned URLs of the local storage driver
+func (s StorageController) LocalUpload(c *gin.Context) {
+	if s.local == nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusNotFound,
+			Error:   "Not found",
+			Message: "local storage is not enabled",
+		})
+		return
+	}
+
+	key := strings.TrimPrefix(c.Param("filepath"), "/")
+	contentType := c.Query("contentType")
+	if c.ContentType() != contentType {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusBadRequest,
+			Error:   "Invalid upload",
+			Message: "Content-Type header doesn't match the signed content type",
+		})
+		return
+	}
+	if err := s.local.VerifyPresigned(key, contentType, c.Query("expires"), c.Query("signature")); err != nil {
+		c.JSON(http.StatusForbidden, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusForbidden,
+			Error:   "Invalid upload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxLocalUploadBytes)
+	fileURL, err := s.local.Put(c.Request.Context(), key, body, contentType)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Success: false,
+			Status:  http.StatusInternalServerError,
+			Error:   "Failed to store file",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Success: true,
+		Status:  http.StatusOK,
+		Message: "File uploaded",
+		Data:    fileURL,
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
@@ -11,8 +12,7 @@ import (
 	"github.com/Dongy-s-Advanture/back-end/internal/config"
 )
 
-
-func InitS3Client(cfg *config.AWSConfig) (*s3.Client, error) {
+func InitS3Client(cfg *config.AWSConfig, storageCfg *config.StorageConfig) (*s3.Client, error) {
 	awsCfg, err := awsconfig.LoadDefaultConfig(context.TODO(),
 		awsconfig.WithRegion(cfg.Region),
 		awsconfig.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(cfg.AccessKey, cfg.SecretKey, "")),
@@ -21,6 +21,12 @@ func InitS3Client(cfg *config.AWSConfig) (*s3.Client, error) {
 		return nil, fmt.Errorf("unable to load AWS SDK config: %v", err)
 	}
 
-	return s3.NewFromConfig(awsCfg), nil
+	return s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		// S3-compatible stores (MinIO, R2, ...) live behind a custom endpoint
+		// and usually only support path-style bucket addressing
+		if storageCfg.Endpoint != "" {
+			o.BaseEndpoint = aws.String(storageCfg.Endpoint)
+			o.UsePathStyle = true
+		}
+	}), nil
 }
-
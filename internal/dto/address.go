@@ -0,0 +1,19 @@
+package dto
+
+// AddressArea is an entry of the address pickers, a province, a district or
+// a subdistrict
+type AddressArea struct {
+	Code   string `json:"code,omitempty"`
+	NameTH string `json:"nameTH"`
+	NameEN string `json:"nameEN"`
+	Zip    string `json:"zip,omitempty"`
+}
+
+// AddressMatch is a place a postcode belongs to, named as the city,
+// province and zip fields of an address expect
+type AddressMatch struct {
+	Province    string `json:"province"`
+	City        string `json:"city,omitempty"`
+	Subdistrict string `json:"subdistrict,omitempty"`
+	Zip         string `json:"zip"`
+}
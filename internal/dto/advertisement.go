@@ -4,6 +4,7 @@ import (
 	"mime/multipart"
 	"time"
 
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/money"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -12,7 +13,8 @@ type Advertisement struct {
 	SellerID        primitive.ObjectID `json:"sellerID"`
 	ProductID       primitive.ObjectID `json:"productID"`
 	ImageURL        string             `json:"imageURL,omitempty"`
-	Amount          float64            `json:"amount"`
+	Images          *ImageVariants     `json:"images,omitempty"`
+	Amount          money.Money        `json:"amount"`
 	Payment         string             `json:"payment"`
 	CreatedAt       time.Time          `json:"createdAt"`
 }
@@ -21,13 +23,13 @@ type AdvertisementCreateRequest struct {
 	SellerID  string                `json:"sellerID" form:"sellerID"`
 	ProductID string                `json:"productID" form:"productID"`
 	ImageURL  *multipart.FileHeader `json:"imageURL,omitempty" form:"imageURL" swaggerignore:"true"`
-	Amount    float64               `json:"amount" form:"amount"`
+	Amount    money.Money           `json:"amount" form:"amount"`
 	Payment   string                `json:"payment" form:"payment"`
 }
 
 type AdvertisementUpdateRequest struct {
 	ProductID primitive.ObjectID    `json:"productID,omitempty" form:"productID"`
 	ImageURL  *multipart.FileHeader `json:"imageURL,omitempty" form:"imageURL" swaggerignore:"true"`
-	Amount    float64               `json:"amount" form:"amount"`
+	Amount    money.Money           `json:"amount" form:"amount"`
 	Payment   string                `json:"payment" form:"payment"`
 }
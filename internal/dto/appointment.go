@@ -3,6 +3,7 @@ package dto
 import (
 	"time"
 
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/geo"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -15,9 +16,49 @@ type Appointment struct {
 	City          string             `json:"city"`
 	Province      string             `json:"province"`
 	Zip           string             `json:"zip"`
+	Location      *geo.Point         `json:"location,omitempty"`
 	Date          time.Time          `json:"date"`
 	TimeSlot      string             `json:"timeSlot"`
+	SlotStart     time.Time          `json:"slotStart"`
+	SlotEnd       time.Time          `json:"slotEnd"`
 	CreatedAt     time.Time          `json:"createdAt"`
+	// Oldest first
+	Proposals []AppointmentProposal `json:"proposals"`
+	Reminders []int                 `json:"reminders,omitempty"`
+	NoShow    *NoShowReport         `json:"noShow,omitempty"`
+	// Set when the order of the appointment is cancelled
+	Cancellation *AppointmentCancellation `json:"cancellation,omitempty"`
+}
+
+type AppointmentCancellation struct {
+	CancelledAt time.Time `json:"cancelledAt"`
+	// The slot the appointment had booked before it was cancelled
+	SlotStart time.Time `json:"slotStart,omitempty"`
+	SlotEnd   time.Time `json:"slotEnd,omitempty"`
+}
+
+type NoShowReport struct {
+	ReportedBy primitive.ObjectID `json:"reportedBy"`
+	AbsentID   primitive.ObjectID `json:"absentID"`
+	ReportedAt time.Time          `json:"reportedAt"`
+}
+
+type AppointmentProposal struct {
+	ProposalID  primitive.ObjectID `json:"proposalID"`
+	Kind        int                `json:"kind"`
+	Status      int                `json:"status"`
+	ProposedBy  primitive.ObjectID `json:"proposedBy"`
+	Address     string             `json:"address,omitempty"`
+	City        string             `json:"city,omitempty"`
+	Province    string             `json:"province,omitempty"`
+	Zip         string             `json:"zip,omitempty"`
+	Location    *geo.Point         `json:"location,omitempty"`
+	Date        time.Time          `json:"date,omitempty"`
+	TimeSlot    string             `json:"timeSlot,omitempty"`
+	SlotStart   time.Time          `json:"slotStart,omitempty"`
+	SlotEnd     time.Time          `json:"slotEnd,omitempty"`
+	CreatedAt   time.Time          `json:"createdAt"`
+	RespondedAt *time.Time         `json:"respondedAt,omitempty"`
 }
 
 type AppointmentCreateRequest struct {
@@ -26,14 +67,24 @@ type AppointmentCreateRequest struct {
 	SellerID primitive.ObjectID `json:"sellerID"`
 }
 
-type AppointmentPlaceRequest struct {
+// AppointmentProposalRequest proposes a place or a time, countering the
+// other party's pending proposal of the same kind if there is one
+type AppointmentProposalRequest struct {
+	// 0 place, 1 time
+	Kind     int    `json:"kind" binding:"gte=0,lte=1"`
 	Address  string `json:"address"`
 	City     string `json:"city"`
 	Province string `json:"province"`
 	Zip      string `json:"zip"`
-}
-
-type AppointmentDateRequest struct {
+	// Optional GeoJSON point of the place
+	Location *geo.Point `json:"location"`
+	// "2006-01-02" and the start of one of the seller's open slots, "15:04"
 	Date     string `json:"date"`
 	TimeSlot string `json:"timeSlot"`
 }
+
+type CalendarSubscription struct {
+	Token string `json:"token"`
+	// Feed URL for calendar apps, keep it secret
+	URL string `json:"url"`
+}
@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type SellerAvailability struct {
+	SellerID    primitive.ObjectID `json:"sellerID"`
+	SlotMinutes int                `json:"slotMinutes"`
+	Weekly      []WeeklyWindow     `json:"weekly"`
+	Blackouts   []time.Time        `json:"blackouts"`
+	UpdatedAt   time.Time          `json:"updatedAt"`
+}
+
+type WeeklyWindow struct {
+	// 0 Sunday to 6 Saturday
+	Weekday time.Weekday `json:"weekday" binding:"gte=0,lte=6"`
+	// "15:04" in Thai time
+	Start string `json:"start" binding:"required"`
+	End   string `json:"end" binding:"required"`
+}
+
+type AvailabilityRequest struct {
+	SlotMinutes int            `json:"slotMinutes" binding:"gte=15,lte=480"`
+	Weekly      []WeeklyWindow `json:"weekly" binding:"dive"`
+	// Dates the seller is away, "2006-01-02"
+	Blackouts []string `json:"blackouts"`
+}
+
+type TimeSlot struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+type OpenSlots struct {
+	Availability *SellerAvailability `json:"availability"`
+	Slots        []TimeSlot          `json:"slots"`
+}
@@ -2,7 +2,10 @@ package dto
 
 import (
 	"mime/multipart"
+	"time"
 
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/geo"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/money"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -19,7 +22,13 @@ type Buyer struct {
 	Province    string             `json:"province"`
 	Zip         string             `json:"zip"`
 	Cart        []OrderProduct     `json:"cart"`
+	Wishlist    []WishlistItem     `json:"wishlist,omitempty"`
 	ProfilePic  string             `json:"profilePic"`
+	ProfilePics *ImageVariants     `json:"profilePics,omitempty"`
+	// Appointments the buyer was reported missing from
+	NoShowStrikes int `json:"noShowStrikes"`
+	// GeoJSON point, longitude first
+	Location *geo.Point `json:"location,omitempty"`
 }
 
 type BuyerRegisterRequest struct {
@@ -39,3 +48,26 @@ type BuyerRegisterRequest struct {
 type UpdateCartRequest struct {
 	Product Product `json:"product"`
 }
+
+type WishlistItem struct {
+	ProductID primitive.ObjectID `json:"productID"`
+	Amount    int                `json:"amount,omitempty"`
+	AddedAt   time.Time          `json:"addedAt"`
+}
+
+type WishlistAddRequest struct {
+	ProductID primitive.ObjectID `json:"productID" binding:"required"`
+}
+
+// WishlistProduct is a wishlist item joined with the product's current state
+type WishlistProduct struct {
+	ProductID   primitive.ObjectID `json:"productID"`
+	Amount      int                `json:"amount,omitempty"`
+	AddedAt     time.Time          `json:"addedAt"`
+	ProductName string             `json:"productName,omitempty"`
+	Image       string             `json:"image,omitempty"`
+	SellerID    primitive.ObjectID `json:"sellerID,omitempty"`
+	Price       money.Money        `json:"price"`
+	InStock     bool               `json:"inStock"`
+	Deleted     bool               `json:"deleted"`
+}
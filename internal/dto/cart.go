@@ -0,0 +1,28 @@
+package dto
+
+import (
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/money"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CartItem is a cart line joined with the product's current state
+type CartItem struct {
+	ProductID       primitive.ObjectID `json:"productID"`
+	Amount          int                `json:"amount"`
+	ProductName     string             `json:"productName,omitempty"`
+	Image           string             `json:"image,omitempty"`
+	SellerID        primitive.ObjectID `json:"sellerID,omitempty"`
+	UnitPrice       money.Money        `json:"unitPrice"`
+	AddedPrice      money.Money        `json:"addedPrice"`
+	AvailableAmount int                `json:"availableAmount"`
+	Subtotal        money.Money        `json:"subtotal"`
+	Deleted         bool               `json:"deleted"`
+	OutOfStock      bool               `json:"outOfStock"`
+	Repriced        bool               `json:"repriced"`
+}
+
+type Cart struct {
+	Items []CartItem `json:"items"`
+	// TotalPrice only counts items that can be checked out
+	TotalPrice money.Money `json:"totalPrice"`
+}
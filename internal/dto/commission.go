@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/money"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CommissionRule struct {
+	RuleID    primitive.ObjectID  `json:"ruleID"`
+	Scope     int                 `json:"scope"`
+	Category  string              `json:"category,omitempty"`
+	SellerID  *primitive.ObjectID `json:"sellerID,omitempty"`
+	Rate      float64             `json:"rate"`
+	UpdatedAt time.Time           `json:"updatedAt"`
+}
+
+type CommissionRuleRequest struct {
+	// 0 global, 1 category, 2 seller
+	Scope    int    `json:"scope" binding:"gte=0,lte=2"`
+	Category string `json:"category,omitempty"`
+	SellerID string `json:"sellerID,omitempty"`
+	// Percentage of the seller's revenue kept by the platform
+	Rate float64 `json:"rate" binding:"gte=0,lte=100"`
+}
+
+type OrderFees struct {
+	SellerGross   money.Money `json:"sellerGross"`
+	Commission    money.Money `json:"commission"`
+	ProcessingFee money.Money `json:"processingFee"`
+	CouponSubsidy money.Money `json:"couponSubsidy"`
+	SellerNet     money.Money `json:"sellerNet"`
+}
+
+// CommissionLine is an order line as seen by the commission rules
+type CommissionLine struct {
+	Tags     []string
+	Subtotal money.Money
+}
+
+type LedgerEntry struct {
+	EntryID   primitive.ObjectID `json:"entryID"`
+	Type      int                `json:"type"`
+	Amount    money.Money        `json:"amount"`
+	OrderID   primitive.ObjectID `json:"orderID"`
+	SellerID  primitive.ObjectID `json:"sellerID"`
+	Reversal  bool               `json:"reversal"`
+	CreatedAt time.Time          `json:"createdAt"`
+}
+
+type LedgerSummary struct {
+	From          time.Time     `json:"from"`
+	To            time.Time     `json:"to"`
+	Commission    money.Money   `json:"commission"`
+	ProcessingFee money.Money   `json:"processingFee"`
+	FeeRecovery   money.Money   `json:"feeRecovery"`
+	CouponSubsidy money.Money   `json:"couponSubsidy"`
+	Net           money.Money   `json:"net"`
+	Entries       []LedgerEntry `json:"entries"`
+}
@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Conversation struct {
+	ConversationID primitive.ObjectID `json:"conversationID"`
+	BuyerID        primitive.ObjectID `json:"buyerID"`
+	SellerID       primitive.ObjectID `json:"sellerID"`
+	ProductID      primitive.ObjectID `json:"productID,omitempty"`
+	OrderID        primitive.ObjectID `json:"orderID,omitempty"`
+	LastMessage    *Message           `json:"lastMessage,omitempty"`
+	BuyerUnread    int                `json:"buyerUnread"`
+	SellerUnread   int                `json:"sellerUnread"`
+	CreatedAt      time.Time          `json:"createdAt"`
+	UpdatedAt      time.Time          `json:"updatedAt"`
+}
+
+type Message struct {
+	MessageID      primitive.ObjectID `json:"messageID"`
+	ConversationID primitive.ObjectID `json:"conversationID"`
+	SenderID       primitive.ObjectID `json:"senderID"`
+	Text           string             `json:"text,omitempty"`
+	Images         []ImageVariants    `json:"images,omitempty"`
+	CreatedAt      time.Time          `json:"createdAt"`
+}
+
+// ConversationRequest starts a conversation about either a product or an order
+type ConversationRequest struct {
+	ProductID primitive.ObjectID `json:"productID,omitempty"`
+	OrderID   primitive.ObjectID `json:"orderID,omitempty"`
+}
+
+type MessageRequest struct {
+	Text   string          `json:"text" binding:"max=2000"`
+	Images []ImageVariants `json:"images" binding:"max=10"`
+}
+
+type UnreadCount struct {
+	Unread int `json:"unread"`
+}
+
+// MessageEvent is sent over a user's message stream when a message is sent
+// to one of their conversations or the other party reads it
+type MessageEvent struct {
+	Type           string             `json:"type"`
+	ConversationID primitive.ObjectID `json:"conversationID"`
+	Message        *Message           `json:"message,omitempty"`
+	ReaderID       primitive.ObjectID `json:"readerID,omitempty"`
+}
@@ -0,0 +1,66 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/money"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Coupon struct {
+	CouponID      primitive.ObjectID  `json:"couponID"`
+	Code          string              `json:"code"`
+	Type          int                 `json:"type"`
+	Percent       float64             `json:"percent,omitempty"`
+	Amount        money.Money         `json:"amount"`
+	SellerID      *primitive.ObjectID `json:"sellerID,omitempty"`
+	MinSpend      money.Money         `json:"minSpend"`
+	MaxDiscount   money.Money         `json:"maxDiscount"`
+	UsageLimit    int                 `json:"usageLimit"`
+	PerBuyerLimit int                 `json:"perBuyerLimit"`
+	UsedCount     int                 `json:"usedCount"`
+	StartsAt      time.Time           `json:"startsAt"`
+	ExpiresAt     time.Time           `json:"expiresAt"`
+	Active        bool                `json:"active"`
+	CreatedAt     time.Time           `json:"createdAt"`
+}
+
+type CouponCreateRequest struct {
+	Code string `json:"code" binding:"required,min=3,max=32,alphanum"`
+	// 0 percentage, 1 fixed amount
+	Type int `json:"type" binding:"gte=0,lte=1"`
+	// Percent off for percentage coupons, Amount off for fixed ones
+	Percent float64     `json:"percent,omitempty" binding:"gte=0,lte=100"`
+	Amount  money.Money `json:"amount"`
+	// Platform coupons apply to every seller and can only be created by admins
+	Platform      bool        `json:"platform,omitempty"`
+	MinSpend      money.Money `json:"minSpend"`
+	MaxDiscount   money.Money `json:"maxDiscount"`
+	UsageLimit    int         `json:"usageLimit" binding:"gte=0"`
+	PerBuyerLimit int         `json:"perBuyerLimit" binding:"gte=0"`
+	StartsAt      time.Time   `json:"startsAt"`
+	ExpiresAt     time.Time   `json:"expiresAt" binding:"required"`
+}
+
+// CouponLine is the part of a purchase a coupon is checked against
+type CouponLine struct {
+	SellerID primitive.ObjectID
+	Subtotal money.Money
+}
+
+type AppliedCoupon struct {
+	CouponID primitive.ObjectID  `json:"couponID"`
+	Code     string              `json:"code"`
+	SellerID *primitive.ObjectID `json:"sellerID,omitempty"`
+	Discount money.Money         `json:"discount"`
+	// Checked again when the coupon is redeemed
+	PerBuyerLimit int `json:"-"`
+}
+
+// PriceQuote is the price of a set of order products with a coupon applied
+type PriceQuote struct {
+	Subtotal   money.Money    `json:"subtotal"`
+	Discount   money.Money    `json:"discount"`
+	TotalPrice money.Money    `json:"totalPrice"`
+	Coupon     *AppliedCoupon `json:"coupon,omitempty"`
+}
@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/geo"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MeetupPoint struct {
+	MeetupPointID primitive.ObjectID `json:"meetupPointID"`
+	Name          string             `json:"name"`
+	Address       string             `json:"address"`
+	City          string             `json:"city"`
+	Province      string             `json:"province"`
+	Zip           string             `json:"zip"`
+	Location      geo.Point          `json:"location"`
+	CreatedAt     time.Time          `json:"createdAt"`
+}
+
+type MeetupPointRequest struct {
+	Name     string `json:"name" binding:"required"`
+	Address  string `json:"address" binding:"required"`
+	City     string `json:"city"`
+	Province string `json:"province" binding:"required"`
+	Zip      string `json:"zip"`
+	// GeoJSON point, longitude first
+	Location geo.Point `json:"location" binding:"required"`
+}
+
+// MeetupSuggestion is a public place near the middle of the buyer and the
+// seller, with how far each of them has to travel in meters
+type MeetupSuggestion struct {
+	MeetupPoint
+	BuyerDistance  float64 `json:"buyerDistance"`
+	SellerDistance float64 `json:"sellerDistance"`
+}
+
+type MeetupSuggestions struct {
+	Midpoint geo.Point          `json:"midpoint"`
+	Points   []MeetupSuggestion `json:"points"`
+}
@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Notification struct {
+	NotificationID primitive.ObjectID `json:"notificationID"`
+	UserID         primitive.ObjectID `json:"userID"`
+	Type           string             `json:"type"`
+	Title          string             `json:"title"`
+	Body           string             `json:"body"`
+	Data           map[string]string  `json:"data,omitempty"`
+	ReadAt         *time.Time         `json:"readAt,omitempty"`
+	CreatedAt      time.Time          `json:"createdAt"`
+}
+
+type NotificationPreference struct {
+	UserID     primitive.ObjectID `json:"userID"`
+	Channels   []string           `json:"channels"`
+	Muted      []string           `json:"muted,omitempty"`
+	Email      string             `json:"email,omitempty"`
+	LineUserID string             `json:"lineUserID,omitempty"`
+	WebhookURL string             `json:"webhookURL,omitempty"`
+	UpdatedAt  time.Time          `json:"updatedAt"`
+}
+
+// NotificationPreferenceRequest replaces the user's preferences. LineUserID
+// is the ID LINE gives our Official Account for the user once they add it as
+// a friend.
+type NotificationPreferenceRequest struct {
+	Channels   []string `json:"channels" binding:"required,dive,oneof=inbox email line webhook"`
+	Muted      []string `json:"muted" binding:"dive,oneof=order_status payment appointment_proposal appointment_reminder no_show review wishlist"`
+	Email      string   `json:"email" binding:"omitempty,email"`
+	LineUserID string   `json:"lineUserID"`
+	WebhookURL string   `json:"webhookURL" binding:"omitempty,url"`
+}
+
+// NotificationEvent is what domain services emit, the dispatcher turns it
+// into a notification for each channel the user has enabled
+type NotificationEvent struct {
+	Type  string
+	Title string
+	Body  string
+	Data  map[string]string
+	// What raised it, such as an outbox event ID, so raising it again
+	// doesn't add another inbox notification
+	Key string
+}
@@ -3,6 +3,7 @@ package dto
 import (
 	"time"
 
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/money"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -15,18 +16,50 @@ type Order struct {
 	SellerName    string             `json:"sellerName"`
 	BuyerID       primitive.ObjectID `json:"buyerID"`
 	BuyerName     string             `json:"buyerName"`
-	TotalPrice    float64            `json:"totalPrice"`
+	TotalPrice    money.Money        `json:"totalPrice"`
 	CreatedAt     time.Time          `json:"createdAt"`
 	Payment       string             `json:"payment"`
+	ChargeID      string             `json:"chargeID,omitempty"`
+	// Price before the coupon discount, TotalPrice is what the buyer paid
+	Subtotal   money.Money `json:"subtotal"`
+	Discount   money.Money `json:"discount"`
+	CouponCode string      `json:"couponCode,omitempty"`
+	// Platform coupons are paid for by the platform, not out of the seller payout
+	PlatformDiscount bool `json:"platformDiscount,omitempty"`
+	// How the revenue was split between seller and platform
+	Fees *OrderFees `json:"fees,omitempty"`
 }
 type OrderCreateRequest struct {
-	Products    []OrderProduct     `json:"products"`
-	BuyerID     primitive.ObjectID `json:"buyerID"`
-	SellerID    primitive.ObjectID `json:"sellerID"`
-	BuyerName   string             `json:"buyerName"`
-	SellerName  string             `json:"sellerName"`
-	Payment		string			   `json:"payment"`
-	CreatedAt	time.Time          `json:"createdAt"`				
+	Products   []OrderProduct     `json:"products"`
+	BuyerID    primitive.ObjectID `json:"buyerID"`
+	SellerID   primitive.ObjectID `json:"sellerID"`
+	BuyerName  string             `json:"buyerName"`
+	SellerName string             `json:"sellerName"`
+	Payment    string             `json:"payment"`
+	CreatedAt  time.Time          `json:"createdAt"`
+	// Set by Checkout once the card is charged, never bound from a request
+	ChargeID   string `json:"-"`
+	CouponCode string `json:"couponCode,omitempty"`
+}
+
+type CheckoutRequest struct {
+	Payment string `json:"payment" binding:"required"`
+	// Omise card token, the whole cart is charged once when it is set
+	Token      string `json:"token,omitempty"`
+	CouponCode string `json:"couponCode,omitempty"`
+}
+
+type CheckoutResponse struct {
+	Orders     []Order     `json:"orders"`
+	Subtotal   money.Money `json:"subtotal"`
+	Discount   money.Money `json:"discount"`
+	TotalPrice money.Money `json:"totalPrice"`
+	ChargeID   string      `json:"chargeID,omitempty"`
+}
+
+type PriceQuoteRequest struct {
+	Products   []OrderProduct `json:"products" binding:"required,min=1"`
+	CouponCode string         `json:"couponCode,omitempty"`
 }
 
 type OrderStatusRequest struct {
@@ -34,17 +67,22 @@ type OrderStatusRequest struct {
 }
 
 type OrderProduct struct {
-	ProductID primitive.ObjectID `json:"productID"`
-	Amount    int                `json:"amount"`
+	ProductID   primitive.ObjectID `json:"productID"`
+	Amount      int                `json:"amount"`
+	ProductName string             `json:"productName,omitempty"`
+	Color       string             `json:"color,omitempty"`
+	UnitPrice   money.Money        `json:"unitPrice"`
+	Image       string             `json:"image,omitempty"`
+	Subtotal    money.Money        `json:"subtotal"`
 }
 type PaymentRequest struct {
-	BuyerID       string    `json:"buyerID" binding:"required"`
-	PaymentMethod string    `json:"paymentMethod" binding:"required"`
-	Amount        int64     `json:"amount" binding:"required"`
-	Address       string    `json:"address,omitempty"`
-	City          string    `json:"city,omitempty"`
-	Province      string    `json:"province,omitempty"`
-	Zip           string    `json:"zip,omitempty"`
-	Token         string    `json:"token" binding:"required"`
-	CreatedAt     time.Time `json:"createdAt" binding:"required"`
+	BuyerID       string      `json:"buyerID" binding:"required"`
+	PaymentMethod string      `json:"paymentMethod" binding:"required"`
+	Amount        money.Money `json:"amount"`
+	Address       string      `json:"address,omitempty"`
+	City          string      `json:"city,omitempty"`
+	Province      string      `json:"province,omitempty"`
+	Zip           string      `json:"zip,omitempty"`
+	Token         string      `json:"token" binding:"required"`
+	CreatedAt     time.Time   `json:"createdAt" binding:"required"`
 }
@@ -0,0 +1,71 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/money"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type OutboxEvent struct {
+	EventID       primitive.ObjectID `json:"eventID"`
+	Type          string             `json:"type"`
+	AggregateID   primitive.ObjectID `json:"aggregateID"`
+	Payload       bson.Raw           `json:"-"`
+	OccurredAt    time.Time          `json:"occurredAt"`
+	Status        int16              `json:"status"`
+	Attempts      int                `json:"attempts"`
+	NextAttemptAt time.Time          `json:"nextAttemptAt"`
+	Delivered     []string           `json:"delivered,omitempty"`
+	LastError     string             `json:"lastError,omitempty"`
+	PublishedAt   *time.Time         `json:"publishedAt,omitempty"`
+}
+
+// OrderCreatedEvent is the payload of OrderCreated
+type OrderCreatedEvent struct {
+	OrderID  primitive.ObjectID `bson:"order_id"`
+	BuyerID  primitive.ObjectID `bson:"buyer_id"`
+	SellerID primitive.ObjectID `bson:"seller_id"`
+}
+
+// OrderStatusChangedEvent is the payload of OrderStatusChanged
+type OrderStatusChangedEvent struct {
+	OrderID primitive.ObjectID `bson:"order_id"`
+	// Unknown when the status was set without checking the old one
+	From *int `bson:"from,omitempty"`
+	To   int  `bson:"to"`
+}
+
+// PaymentSucceededEvent is the payload of PaymentSucceeded, raised for every
+// order paid by card
+type PaymentSucceededEvent struct {
+	OrderID  primitive.ObjectID `bson:"order_id"`
+	BuyerID  primitive.ObjectID `bson:"buyer_id"`
+	ChargeID string             `bson:"charge_id"`
+	Amount   money.Money        `bson:"amount"`
+}
+
+// OrderRefundedEvent is the payload of OrderRefunded, raised when a cancelled
+// order is given back to the buyer. Refund is the part of the total returned,
+// all of it for an order paid in cash.
+type OrderRefundedEvent struct {
+	OrderID primitive.ObjectID `bson:"order_id"`
+	Refund  money.Money        `bson:"refund"`
+}
+
+// ReviewCreatedEvent is the payload of ReviewCreated. It carries the scores
+// as they were created so the ratings stay right if the review is edited
+// before the event is handled.
+type ReviewCreatedEvent struct {
+	ReviewID  primitive.ObjectID `bson:"review_id"`
+	SellerID  primitive.ObjectID `bson:"seller_id"`
+	BuyerName string             `bson:"buyer_name"`
+	Score     int                `bson:"score"`
+	Products  []ProductScore     `bson:"products,omitempty"`
+}
+
+type ProductScore struct {
+	ProductID primitive.ObjectID `bson:"product_id"`
+	Score     int                `bson:"score"`
+}
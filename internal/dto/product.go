@@ -4,24 +4,32 @@ import (
 	"mime/multipart"
 	"time"
 
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/money"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type Product struct {
 	ProductID   primitive.ObjectID `json:"productID,omitempty"`
 	ProductName string             `json:"productName"`
-	Price       float64            `json:"price,omitempty"`
+	Price       money.Money        `json:"price"`
 	Description string             `json:"description,omitempty"`
 	Image       string             `json:"image,omitempty"`
+	Images      *ImageVariants     `json:"images,omitempty"`
 	Tag         []string           `json:"tag,omitempty"`
 	Color       string             `json:"color,omitempty"`
 	SellerID    primitive.ObjectID `json:"sellerID,omitempty"`
 	CreatedAt   time.Time          `json:"createdAt,omitempty"`
 	Amount      int                `json:"amount"`
+	Status      int                `json:"status"`
+	DeletedAt   *time.Time         `json:"deletedAt,omitempty"`
+	// Status the product had before it was archived or deleted
+	ArchivedFrom *int `json:"-"`
+	// Scores given to the product in reviews of orders it was bought in
+	Rating *Rating `json:"rating,omitempty"`
 }
 type ProductCreateRequest struct {
 	ProductName string                `json:"productName" binding:"required" form:"productName"`
-	Price       float64               `json:"price,omitempty" binding:"required,gte=1" form:"price"`
+	Price       money.Money           `json:"price" form:"price"`
 	Description string                `json:"description,omitempty" form:"description"`
 	Tag         []string              `json:"tag,omitempty" form:"tag[]"`
 	Color       string                `json:"color,omitempty" form:"color"`
@@ -29,16 +37,18 @@ type ProductCreateRequest struct {
 	CreatedAt   time.Time             `json:"createdAt,omitempty" form:"createdAt"`
 	Amount      int                   `json:"amount" binding:"required,gte=0" form:"amount"`
 	Image       *multipart.FileHeader `json:"image,omitempty" form:"image" swaggerignore:"true"`
+	// Draft products are only visible to their seller until published
+	Draft bool `json:"draft,omitempty" form:"draft"`
 }
 
 type UpdateProductRequest struct {
-	ProductName string    `json:"productName" binding:"required"`
-	Price       float64   `json:"price,omitempty" binding:"required,gte=1"`
-	Description string    `json:"description,omitempty"`
-	Image       string    `json:"image,omitempty"`
-	Tag         []string  `json:"tag,omitempty"`
-	Color       string    `json:"color,omitempty"`
-	SellerID    string    `json:"sellerID,omitempty"`
-	Amount      int       `json:"amount" binding:"required,gte=0"`
-	CreatedAt   time.Time `json:"createdAt,omitempty"`
+	ProductName string      `json:"productName" binding:"required"`
+	Price       money.Money `json:"price"`
+	Description string      `json:"description,omitempty"`
+	Image       string      `json:"image,omitempty"`
+	Tag         []string    `json:"tag,omitempty"`
+	Color       string      `json:"color,omitempty"`
+	SellerID    string      `json:"sellerID,omitempty"`
+	Amount      int         `json:"amount" binding:"required,gte=0"`
+	CreatedAt   time.Time   `json:"createdAt,omitempty"`
 }
@@ -0,0 +1,26 @@
+package dto
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+type Rating struct {
+	Count     int            `json:"count"`
+	Sum       int            `json:"sum"`
+	Average   float64        `json:"average"`
+	Histogram map[string]int `json:"histogram"`
+}
+
+// SellerRating is the breakdown of a seller's reviews
+type SellerRating struct {
+	SellerID primitive.ObjectID `json:"sellerID"`
+	Count    int                `json:"count"`
+	Average  float64            `json:"average"`
+	// The average weighted towards the platform prior, what sellers are
+	// shown and ranked with
+	Score     float64        `json:"score"`
+	Histogram []RatingBucket `json:"histogram"`
+}
+
+type RatingBucket struct {
+	Score int `json:"score"`
+	Count int `json:"count"`
+}
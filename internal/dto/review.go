@@ -13,23 +13,71 @@ type Review struct {
 	SellerID primitive.ObjectID `json:"sellerID"`
 	SellerName string 			`json:"sellerName"`
 	Image     string            `json:"image,omitempty"`
+	Images   *ImageVariants     `json:"images,omitempty"`
 	Message  string             `json:"message"`
 	Score    int                `json:"score"`
 	Date     time.Time          `json:"date"`
+	// Verified is set on reviews of a completed order, with the products
+	// bought in it
+	OrderID  primitive.ObjectID `json:"orderID,omitempty"`
+	Verified bool               `json:"verified"`
+	Products []ReviewProduct    `json:"products,omitempty"`
+	Reply    *ReviewReply       `json:"reply,omitempty"`
+	Status   int                `json:"status"`
+}
+
+type ReviewReply struct {
+	Message string    `json:"message"`
+	Date    time.Time `json:"date"`
+}
+
+type ReviewReplyRequest struct {
+	Message string `json:"message" binding:"required,max=500"`
+}
+
+type ReviewProduct struct {
+	ProductID   primitive.ObjectID `json:"productID"`
+	ProductName string             `json:"productName,omitempty"`
+	Color       string             `json:"color,omitempty"`
+	Image       string             `json:"image,omitempty"`
+	Amount      int                `json:"amount"`
+	Score       *int               `json:"score,omitempty"`
+	Comment     string             `json:"comment,omitempty"`
+}
+
+// ProductReviewRequest reviews one of the products bought in the order
+type ProductReviewRequest struct {
+	ProductID primitive.ObjectID `json:"productID"`
+	Score     *int               `json:"score"`
+	Comment   string             `json:"comment,omitempty"`
+}
+
+// ProductReview is what a review says about one of the products bought
+type ProductReview struct {
+	ReviewID  primitive.ObjectID `json:"reviewID"`
+	ProductID primitive.ObjectID `json:"productID"`
+	BuyerName string             `json:"buyerName"`
+	Color     string             `json:"color,omitempty"`
+	Score     int                `json:"score"`
+	Comment   string             `json:"comment,omitempty"`
+	Reply     *ReviewReply       `json:"reply,omitempty"`
+	Date      time.Time          `json:"date"`
 }
 
 type ReviewCreateRequest struct {
-	BuyerID  primitive.ObjectID `json:"buyerID"`
-	BuyerName string			`json:"buyerName"`
-	SellerID primitive.ObjectID `json:"sellerID"`
-	SellerName string 			`json:"sellerName"`
+	// The buyer, the seller and what was bought are taken from the order
+	OrderID  primitive.ObjectID `json:"orderID"`
+	// Optional reviews of the products bought
+	Products []ProductReviewRequest `json:"products,omitempty"`
 	Image     string            `json:"image,omitempty"`
+	Images   *ImageVariants     `json:"images,omitempty"`
 	Message  string             `json:"message"`
 	Score    int                `json:"score"`
 }
 
 type ReviewUpdateRequest struct {
 	Image     string            `json:"image,omitempty"`
+	Images   *ImageVariants     `json:"images,omitempty"`
 	Message  string             `json:"message"`
 	Score    int                `json:"score"`
 }
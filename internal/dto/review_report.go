@@ -0,0 +1,27 @@
+package dto
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ReviewReport struct {
+	ReportID   primitive.ObjectID `json:"reportID"`
+	ReviewID   primitive.ObjectID `json:"reviewID"`
+	ReporterID primitive.ObjectID `json:"reporterID"`
+	Reason     string             `json:"reason"`
+	Status     int                `json:"status"`
+	CreatedAt  time.Time          `json:"createdAt"`
+	ResolvedAt *time.Time         `json:"resolvedAt,omitempty"`
+}
+
+type ReviewReportRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// ModerationItem is a reported review with the reports waiting on a decision
+type ModerationItem struct {
+	Review  Review         `json:"review"`
+	Reports []ReviewReport `json:"reports"`
+}
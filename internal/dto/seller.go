@@ -3,6 +3,8 @@ package dto
 import (
 	"mime/multipart"
 
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/geo"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/money"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -19,8 +21,15 @@ type Seller struct {
 	City        string             `json:"city"`
 	Zip         string             `json:"zip"`
 	Transaction []Transaction      `json:"transaction"`
-	Balance     float64            `json:"balance"`
+	Balance     money.Money        `json:"balance"`
 	ProfilePic  string             `json:"profilePic"`
+	ProfilePics *ImageVariants     `json:"profilePics,omitempty"`
+	// Appointments the seller was reported missing from
+	NoShowStrikes int `json:"noShowStrikes"`
+	// GeoJSON point, longitude first
+	Location *geo.Point `json:"location,omitempty"`
+	// Review aggregates, Score is worked out from them
+	Rating *Rating `json:"rating,omitempty"`
 }
 
 type SellerRegisterRequest struct {
@@ -39,6 +48,6 @@ type SellerRegisterRequest struct {
 }
 
 type SellerWithdrawRequest struct {
-	Payment string  `json:"payment"`
-	Amount  float64 `json:"amount"`
+	Payment string      `json:"payment"`
+	Amount  money.Money `json:"amount"`
 }
@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+type PresignUploadRequest struct {
+	Folder      string `json:"folder" binding:"required"`
+	FileName    string `json:"fileName" binding:"required"`
+	ContentType string `json:"contentType" binding:"required"`
+}
+
+type PresignUploadResponse struct {
+	UploadURL string            `json:"uploadURL"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers"`
+	// Passed to /storage/complete once the upload is done
+	Key       string    `json:"key"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type CompleteUploadRequest struct {
+	Key string `json:"key" binding:"required"`
+}
@@ -3,12 +3,13 @@ package dto
 import (
 	"time"
 
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/money"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type Transaction struct {
 	Type    int16              `json:"type"`
-	Amount  float64            `json:"amount"`
+	Amount  money.Money        `json:"amount"`
 	OrderID primitive.ObjectID `json:"orderID,omitempty"`
 	Payment string             `json:"payment"`
 	Date    time.Time          `json:"date"`
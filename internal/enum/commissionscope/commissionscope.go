@@ -0,0 +1,8 @@
+package commissionscope
+
+// A seller rule beats a category rule, which beats the global rule
+const (
+	GLOBAL = iota
+	CATEGORY
+	SELLER
+)
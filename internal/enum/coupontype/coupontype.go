@@ -0,0 +1,6 @@
+package coupontype
+
+const (
+	PERCENTAGE = iota
+	FIXED
+)
@@ -0,0 +1,10 @@
+package eventtype
+
+// Domain events published through the outbox
+const (
+	ORDER_CREATED        = "OrderCreated"
+	ORDER_STATUS_CHANGED = "OrderStatusChanged"
+	REVIEW_CREATED       = "ReviewCreated"
+	PAYMENT_SUCCEEDED    = "PaymentSucceeded"
+	ORDER_REFUNDED       = "OrderRefunded"
+)
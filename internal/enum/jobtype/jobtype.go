@@ -0,0 +1,8 @@
+package jobtype
+
+// Background jobs run by cmd/worker
+const (
+	SWEEP_ORPHAN_UPLOADS       = "uploads.sweep_orphans"
+	SEND_APPOINTMENT_REMINDERS = "appointments.send_reminders"
+	RELAY_EVENTS               = "outbox.relay_events"
+)
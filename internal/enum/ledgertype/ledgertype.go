@@ -0,0 +1,8 @@
+package ledgertype
+
+const (
+	COMMISSION = iota
+	PROCESSING_FEE
+	FEE_RECOVERY
+	COUPON_SUBSIDY
+)
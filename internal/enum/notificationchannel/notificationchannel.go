@@ -0,0 +1,8 @@
+package notificationchannel
+
+const (
+	INBOX   = "inbox"
+	EMAIL   = "email"
+	LINE    = "line"
+	WEBHOOK = "webhook"
+)
@@ -0,0 +1,11 @@
+package notificationtype
+
+const (
+	ORDER_STATUS         = "order_status"
+	PAYMENT              = "payment"
+	APPOINTMENT_PROPOSAL = "appointment_proposal"
+	APPOINTMENT_REMINDER = "appointment_reminder"
+	NO_SHOW              = "no_show"
+	REVIEW               = "review"
+	WISHLIST             = "wishlist"
+)
@@ -5,4 +5,5 @@ const (
 	WAITFORTIME
 	APPOINTED
 	DONE
+	CANCELLED
 )
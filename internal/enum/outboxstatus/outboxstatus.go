@@ -0,0 +1,9 @@
+package outboxstatus
+
+const (
+	PENDING = iota
+	// Every subscriber handled the event
+	PUBLISHED
+	// A subscriber kept failing until the event ran out of attempts
+	DEAD
+)
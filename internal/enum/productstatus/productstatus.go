@@ -0,0 +1,9 @@
+package productstatus
+
+// ACTIVE is the zero value so products created before statuses existed stay listed
+const (
+	ACTIVE = iota
+	DRAFT
+	SOLDOUT
+	ARCHIVED
+)
@@ -0,0 +1,6 @@
+package proposalkind
+
+const (
+	PLACE = iota
+	TIME
+)
@@ -0,0 +1,11 @@
+package proposalstatus
+
+const (
+	PENDING = iota
+	ACCEPTED
+	REJECTED
+	// The other party answered with a proposal of their own
+	COUNTERED
+	// The proposer replaced it with a new one
+	WITHDRAWN
+)
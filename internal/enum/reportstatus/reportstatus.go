@@ -0,0 +1,9 @@
+package reportstatus
+
+const (
+	PENDING = iota
+	// The review was removed
+	UPHELD
+	// The review was kept
+	DISMISSED
+)
@@ -0,0 +1,9 @@
+package reviewstatus
+
+const (
+	VISIBLE = iota
+	// Taken down while an admin looks into reports about it
+	HIDDEN
+	// An admin upheld the reports
+	REMOVED
+)
@@ -0,0 +1,10 @@
+package uploadowner
+
+const (
+	PRODUCT       = "product"
+	BUYER         = "buyer"
+	SELLER        = "seller"
+	REVIEW        = "review"
+	ADVERTISEMENT = "advertisement"
+	MESSAGE       = "message"
+)
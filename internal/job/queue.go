@@ -0,0 +1,268 @@
+// Package job runs background work from a job queue kept in Redis. Jobs can
+// be delayed, are retried with backoff when their handler fails and are
+// parked with the dead jobs after their last attempt. Cron schedules are
+// enqueued by every worker, but a lock lets only one of them enqueue each run.
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/config"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrLocked = errors.New("lock is held by someone else")
+
+const (
+	keyPrefix = "jobs:"
+	// The longest a failed job waits for its next attempt
+	maxRetryDelay = time.Hour
+	// How many due or expired jobs are moved to the ready list at once
+	promoteBatch = 100
+)
+
+// Job is a unit of background work
+type Job struct {
+	ID      string          `json:"id"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+	// The cron schedule that enqueued the job, if any
+	Schedule    string    `json:"schedule,omitempty"`
+	MaxAttempts int       `json:"maxAttempts"`
+	EnqueuedAt  time.Time `json:"enqueuedAt"`
+	LastError   string    `json:"lastError,omitempty"`
+	// Attempts so far, counting the current one. Kept apart from the job so
+	// a claim can count it atomically.
+	Attempts int `json:"-"`
+}
+
+// Decode reads the job's payload into v
+func (j *Job) Decode(v interface{}) error {
+	return json.Unmarshal(j.Payload, v)
+}
+
+// Queue keeps jobs in Redis. Job bodies live in a hash by ID. Delayed jobs
+// and jobs waiting for a retry are in a sorted set by when they are due,
+// jobs due now in a list, and claimed jobs in a sorted set by when their
+// lease runs out, after which they are handed out again.
+type Queue struct {
+	client      *redis.Client
+	maxAttempts int
+	retryBase   time.Duration
+	lease       time.Duration
+}
+
+func NewQueue(client *redis.Client, workerCfg *config.WorkerConfig) *Queue {
+	return &Queue{
+		client:      client,
+		maxAttempts: workerCfg.MaxAttempts,
+		retryBase:   time.Duration(workerCfg.RetryBaseSeconds) * time.Second,
+		// Leased a little longer than a job may run so a slow job isn't
+		// handed out twice
+		lease: time.Duration(workerCfg.JobTimeoutSeconds)*time.Second + time.Minute,
+	}
+}
+
+func key(name string) string {
+	return keyPrefix + name
+}
+
+var (
+	scheduledKey = key("scheduled")
+	readyKey     = key("ready")
+	activeKey    = key("active")
+	dataKey      = key("data")
+	attemptsKey  = key("attempts")
+	deadKey      = key("dead")
+)
+
+// Enqueue adds a job of jobType with payload encoded as JSON, due after delay
+func (q *Queue) Enqueue(ctx context.Context, jobType string, payload interface{}, delay time.Duration) (*Job, error) {
+	return q.enqueue(ctx, &Job{Type: jobType}, payload, delay)
+}
+
+func (q *Queue) enqueue(ctx context.Context, job *Job, payload interface{}, delay time.Duration) (*Job, error) {
+	if payload != nil {
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		job.Payload = raw
+	}
+	job.ID = primitive.NewObjectID().Hex()
+	job.MaxAttempts = q.maxAttempts
+	job.EnqueuedAt = time.Now()
+	body, err := json.Marshal(job)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, dataKey, job.ID, body)
+		if delay > 0 {
+			pipe.ZAdd(ctx, scheduledKey, redis.Z{Score: score(job.EnqueuedAt.Add(delay)), Member: job.ID})
+		} else {
+			pipe.LPush(ctx, readyKey, job.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to enqueue %s job: %w", job.Type, err)
+	}
+	return job, nil
+}
+
+func score(t time.Time) float64 {
+	return float64(t.UnixMilli())
+}
+
+// claimScript moves due jobs and jobs whose lease ran out to the ready list,
+// then claims the oldest ready job, counting the attempt
+var claimScript = redis.NewScript(`
+for _, set in ipairs({KEYS[1], KEYS[3]}) do
+	local due = redis.call('ZRANGEBYSCORE', set, '-inf', ARGV[1], 'LIMIT', 0, ARGV[3])
+	for _, id in ipairs(due) do
+		redis.call('ZREM', set, id)
+		redis.call('LPUSH', KEYS[2], id)
+	end
+end
+local id = redis.call('RPOP', KEYS[2])
+if not id then
+	return false
+end
+redis.call('ZADD', KEYS[3], ARGV[2], id)
+local attempts = redis.call('HINCRBY', KEYS[5], id, 1)
+return {id, redis.call('HGET', KEYS[4], id), attempts}
+`)
+
+// Claim takes the next job that is due at now, or returns nil when there is
+// none. The job must be settled with Complete or Fail before its lease runs
+// out.
+func (q *Queue) Claim(ctx context.Context, now time.Time) (*Job, error) {
+	for {
+		res, err := claimScript.Run(ctx, q.client,
+			[]string{scheduledKey, readyKey, activeKey, dataKey, attemptsKey},
+			score(now), score(now.Add(q.lease)), promoteBatch,
+		).Slice()
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		id, job, err := decodeClaim(res)
+		if job == nil {
+			// Nothing can run it, drop it rather than hand it out forever
+			q.client.ZRem(ctx, activeKey, id)
+			q.client.HDel(ctx, attemptsKey, id)
+			return nil, err
+		}
+
+		// The lease of its last attempt ran out, most likely because it
+		// took the worker down with it
+		if job.Attempts > job.MaxAttempts {
+			job.Attempts = job.MaxAttempts
+			if _, err := q.Fail(ctx, job, errors.New("lease ran out"), now); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		return job, nil
+	}
+}
+
+// decodeClaim reads the reply of claimScript. The body is nil rather than
+// missing when the job's data is gone, which leaves an orphaned id with no
+// job and no error. A body that can't be read gives no job and an error.
+func decodeClaim(res []interface{}) (string, *Job, error) {
+	id, _ := res[0].(string)
+	body, ok := res[1].(string)
+	if !ok {
+		return id, nil, nil
+	}
+	var job Job
+	if err := json.Unmarshal([]byte(body), &job); err != nil {
+		return id, nil, fmt.Errorf("dropped unreadable job %s: %w", id, err)
+	}
+	attempts, _ := res[2].(int64)
+	job.Attempts = int(attempts)
+	return id, &job, nil
+}
+
+// Complete removes a finished job
+func (q *Queue) Complete(ctx context.Context, job *Job) error {
+	_, err := q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, activeKey, job.ID)
+		pipe.HDel(ctx, dataKey, job.ID)
+		pipe.HDel(ctx, attemptsKey, job.ID)
+		return nil
+	})
+	return err
+}
+
+// Fail schedules the job's next attempt after a backoff, or parks it with
+// the dead jobs when it has none left. It reports whether the job is dead.
+func (q *Queue) Fail(ctx context.Context, job *Job, cause error, now time.Time) (bool, error) {
+	job.LastError = cause.Error()
+	body, err := json.Marshal(job)
+	if err != nil {
+		return false, err
+	}
+
+	dead := job.Attempts >= job.MaxAttempts
+	_, err = q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, activeKey, job.ID)
+		pipe.HSet(ctx, dataKey, job.ID, body)
+		if dead {
+			pipe.LPush(ctx, deadKey, job.ID)
+		} else {
+			pipe.ZAdd(ctx, scheduledKey, redis.Z{Score: score(now.Add(backoff(job.Attempts, q.retryBase))), Member: job.ID})
+		}
+		return nil
+	})
+	return dead, err
+}
+
+// backoff doubles base for every attempt after the first, up to
+// maxRetryDelay
+func backoff(attempts int, base time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= maxRetryDelay {
+			return maxRetryDelay
+		}
+	}
+	return delay
+}
+
+// unlockScript deletes the lock only if it still holds our token
+var unlockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// Lock takes the named lock for ttl, or returns ErrLocked if another worker
+// holds it. Unlock gives it back early, a lock left alone expires after ttl.
+func (q *Queue) Lock(ctx context.Context, name string, ttl time.Duration) (func(ctx context.Context) error, error) {
+	lockKey := key("lock:" + name)
+	token := primitive.NewObjectID().Hex()
+	ok, err := q.client.SetNX(ctx, lockKey, token, ttl).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrLocked
+	}
+	return func(ctx context.Context) error {
+		return unlockScript.Run(ctx, q.client, []string{lockKey}, token).Err()
+	}, nil
+}
@@ -0,0 +1,209 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/config"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/cron"
+)
+
+// Handler does the work of a job. Jobs are run at least once, returning an
+// error has the job retried.
+type Handler func(ctx context.Context, job *Job) error
+
+type schedule struct {
+	name    string
+	spec    cron.Schedule
+	jobType string
+	next    time.Time
+}
+
+// Worker claims jobs from the queue and runs them with their handler
+type Worker struct {
+	queue        *Queue
+	handlers     map[string]Handler
+	schedules    []*schedule
+	concurrency  int
+	pollInterval time.Duration
+	timeout      time.Duration
+}
+
+func NewWorker(q *Queue, workerCfg *config.WorkerConfig) *Worker {
+	return &Worker{
+		queue:        q,
+		handlers:     make(map[string]Handler),
+		concurrency:  workerCfg.Concurrency,
+		pollInterval: time.Duration(workerCfg.PollIntervalMillis) * time.Millisecond,
+		timeout:      time.Duration(workerCfg.JobTimeoutSeconds) * time.Second,
+	}
+}
+
+// Handle runs jobs of jobType with handler. Handlers are registered before
+// Run.
+func (w *Worker) Handle(jobType string, handler Handler) {
+	w.handlers[jobType] = handler
+}
+
+// Schedule enqueues a jobType job whenever spec is due. The name must be
+// unique, it keys the locks that keep replicas from enqueueing the same run
+// twice or running it while the previous one is still going.
+func (w *Worker) Schedule(name string, spec cron.Schedule, jobType string) {
+	w.schedules = append(w.schedules, &schedule{name: name, spec: spec, jobType: jobType})
+}
+
+// Run claims and runs jobs and enqueues scheduled ones until ctx is done,
+// then waits for the running jobs to finish
+func (w *Worker) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		w.runScheduler(ctx)
+	}()
+	for i := 0; i < w.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.runJobs(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+func (w *Worker) runJobs(ctx context.Context) {
+	for ctx.Err() == nil {
+		job, err := w.queue.Claim(ctx, time.Now())
+		if err != nil && ctx.Err() == nil {
+			log.Printf("failed to claim job: %v", err)
+		}
+		if job == nil {
+			select {
+			case <-ctx.Done():
+			case <-time.After(w.pollInterval):
+			}
+			continue
+		}
+		w.process(job)
+	}
+}
+
+// process runs the job and settles it. Jobs aren't cut short when the worker
+// stops, only by their timeout.
+func (w *Worker) process(job *Job) {
+	ctx := context.Background()
+	if job.Schedule != "" {
+		// A slow run of a schedule may still be going when the next is due
+		unlock, err := w.queue.Lock(ctx, "running:"+job.Schedule, w.queue.lease)
+		switch {
+		case errors.Is(err, ErrLocked):
+			log.Printf("skipped %s job %s, the previous run is still going", job.Type, job.ID)
+			if err := w.queue.Complete(ctx, job); err != nil {
+				log.Printf("failed to complete %s job %s: %v", job.Type, job.ID, err)
+			}
+			return
+		case err != nil:
+			// Handed out again once its lease runs out
+			log.Printf("failed to lock %s job %s: %v", job.Type, job.ID, err)
+			return
+		}
+		defer func() {
+			if err := unlock(ctx); err != nil {
+				log.Printf("failed to unlock schedule %s: %v", job.Schedule, err)
+			}
+		}()
+	}
+
+	err := w.run(ctx, job)
+	if err == nil {
+		if err := w.queue.Complete(ctx, job); err != nil {
+			log.Printf("failed to complete %s job %s: %v", job.Type, job.ID, err)
+		}
+		return
+	}
+
+	dead, failErr := w.queue.Fail(ctx, job, err, time.Now())
+	if failErr != nil {
+		log.Printf("failed to settle %s job %s: %v", job.Type, job.ID, failErr)
+		return
+	}
+	if dead {
+		log.Printf("%s job %s failed %d times and is dead: %v", job.Type, job.ID, job.Attempts, err)
+	} else {
+		log.Printf("%s job %s failed, retrying: %v", job.Type, job.ID, err)
+	}
+}
+
+// run calls the job's handler within the job timeout, turning a panic into
+// an error so one bad job can't take the worker down
+func (w *Worker) run(ctx context.Context, job *Job) (err error) {
+	handler, ok := w.handlers[job.Type]
+	if !ok {
+		// Possibly enqueued by a newer release, retried until one runs it
+		return fmt.Errorf("no handler for %s jobs", job.Type)
+	}
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return handler(ctx, job)
+}
+
+// runScheduler enqueues the schedules' jobs as they come due, checking
+// every poll interval
+func (w *Worker) runScheduler(ctx context.Context) {
+	if len(w.schedules) == 0 {
+		return
+	}
+	now := time.Now()
+	for _, s := range w.schedules {
+		s.next = s.spec.Next(now)
+	}
+
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for _, s := range w.schedules {
+				w.enqueueDue(ctx, s, now)
+			}
+		}
+	}
+}
+
+// enqueueDue enqueues the schedule's job if a run is due at now. Every
+// replica tries, the run's lock lets one of them through. A run missed while
+// no worker was up is skipped.
+func (w *Worker) enqueueDue(ctx context.Context, s *schedule, now time.Time) {
+	if s.next.IsZero() || now.Before(s.next) {
+		return
+	}
+	run := s.next
+	s.next = s.spec.Next(now)
+
+	// Held until the run can't come up again, so a replica whose clock is
+	// a little behind doesn't enqueue it once more
+	ttl := s.next.Sub(run) + time.Minute
+	_, err := w.queue.Lock(ctx, "cron:"+s.name+":"+strconv.FormatInt(run.Unix(), 10), ttl)
+	if errors.Is(err, ErrLocked) {
+		return
+	}
+	if err != nil {
+		log.Printf("failed to lock schedule %s: %v", s.name, err)
+		return
+	}
+	if _, err := w.queue.enqueue(ctx, &Job{Type: s.jobType, Schedule: s.name}, nil, 0); err != nil {
+		log.Printf("failed to enqueue schedule %s: %v", s.name, err)
+	}
+}
@@ -0,0 +1,81 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/config"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/cron"
+	"github.com/stretchr/testify/assert"
+)
+
+func testWorker() *Worker {
+	cfg := &config.WorkerConfig{Concurrency: 1, PollIntervalMillis: 10, JobTimeoutSeconds: 1, MaxAttempts: 3, RetryBaseSeconds: 10}
+	return NewWorker(NewQueue(nil, cfg), cfg)
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, 10*time.Second, backoff(1, 10*time.Second))
+	assert.Equal(t, 40*time.Second, backoff(3, 10*time.Second))
+	assert.Equal(t, maxRetryDelay, backoff(30, 10*time.Second))
+}
+
+func TestWorkerRun(t *testing.T) {
+	w := testWorker()
+	w.Handle("ok", func(ctx context.Context, job *Job) error {
+		var payload struct{ Name string }
+		if err := job.Decode(&payload); err != nil {
+			return err
+		}
+		if payload.Name != "sweep" {
+			return errors.New("wrong payload")
+		}
+		_, hasDeadline := ctx.Deadline()
+		assert.True(t, hasDeadline)
+		return nil
+	})
+	w.Handle("panics", func(ctx context.Context, job *Job) error {
+		panic("boom")
+	})
+
+	ctx := context.Background()
+	assert.NoError(t, w.run(ctx, &Job{Type: "ok", Payload: []byte(`{"Name":"sweep"}`)}))
+	assert.EqualError(t, w.run(ctx, &Job{Type: "panics"}), "panic: boom")
+	assert.EqualError(t, w.run(ctx, &Job{Type: "unknown"}), "no handler for unknown jobs")
+}
+
+func TestEnqueueDueWaitsForRun(t *testing.T) {
+	w := testWorker()
+	now := time.Date(2025, 1, 31, 10, 7, 30, 0, time.UTC)
+	s := &schedule{name: "sweep", spec: cron.Every(time.Minute), jobType: "sweep"}
+	s.next = s.spec.Next(now)
+
+	// Not due yet, so Redis isn't touched
+	w.enqueueDue(context.Background(), s, now.Add(20*time.Second))
+	assert.Equal(t, time.Date(2025, 1, 31, 10, 8, 0, 0, time.UTC), s.next)
+
+	// Never due again
+	s.next = time.Time{}
+	w.enqueueDue(context.Background(), s, now.Add(time.Hour))
+	assert.True(t, s.next.IsZero())
+}
+
+func TestDecodeClaim(t *testing.T) {
+	id, job, err := decodeClaim([]interface{}{"job-1", `{"id":"job-1","type":"sweep"}`, int64(2)})
+	assert.NoError(t, err)
+	assert.Equal(t, "job-1", id)
+	assert.Equal(t, "sweep", job.Type)
+	assert.Equal(t, 2, job.Attempts)
+
+	// The job's data is gone, so the id is dropped without an error
+	id, job, err = decodeClaim([]interface{}{"job-2", nil, int64(1)})
+	assert.NoError(t, err)
+	assert.Equal(t, "job-2", id)
+	assert.Nil(t, job)
+
+	_, job, err = decodeClaim([]interface{}{"job-3", "{", int64(1)})
+	assert.Error(t, err)
+	assert.Nil(t, job)
+}
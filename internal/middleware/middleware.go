@@ -32,6 +32,20 @@ func JWTAuthMiddleWare(tokenType int, redisClient redis.IRedisClient, conf *conf
 	}
 }
 
+// AdminOnly must run after JWTAuthMiddleWare
+func AdminOnly(adminConfig *config.AdminConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := c.Get("userID")
+		userIDStr, _ := userID.(string)
+		if !adminConfig.IsAdmin(userIDStr) {
+			c.JSON(http.StatusForbidden, dto.ErrorResponse{Success: false, Status: http.StatusForbidden, Error: "Forbidden", Message: "admin only"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
 func CORS() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
@@ -3,6 +3,7 @@ package model
 import (
 	"time"
 
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/money"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -11,7 +12,8 @@ type Advertisement struct {
 	SellerID        primitive.ObjectID `json:"sellerID" bson:"seller_id"`
 	ProductID       primitive.ObjectID `json:"productID" bson:"product_id"`
 	ImageURL        string             `json:"imageURL,omitempty" bson:"imageURL"`
-	Amount          float64            `json:"amount" bson:"amount" binding:"gte=0"`
+	Images          *ImageVariants     `json:"images,omitempty" bson:"images,omitempty"`
+	Amount          money.Money        `json:"amount" bson:"amount"`
 	Payment         string             `json:"payment" bson:"payment"`
 	CreatedAt       time.Time          `json:"createdAt" bson:"createdAt"`
 }
@@ -3,6 +3,7 @@ package model
 import (
 	"time"
 
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/geo"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -15,7 +16,54 @@ type Appointment struct {
 	City          string             `json:"city" bson:"city"`
 	Province      string             `json:"province" bson:"province"`
 	Zip           string             `json:"zip" bson:"zip"`
+	Location      *geo.Point         `json:"location,omitempty" bson:"location,omitempty"`
 	Date          time.Time          `json:"date" bson:"date"`
 	TimeSlot      string             `json:"timeSlot" bson:"time_slot"`
 	CreatedAt     time.Time          `json:"createdAt" bson:"created_at"`
+	// The booked slot, unique per seller
+	SlotStart time.Time `json:"slotStart" bson:"slot_start,omitempty"`
+	SlotEnd   time.Time `json:"slotEnd" bson:"slot_end,omitempty"`
+	// Every place and time proposed for the appointment, oldest first
+	Proposals []AppointmentProposal `json:"proposals" bson:"proposals,omitempty"`
+	// Lead times in minutes of the reminders already sent
+	Reminders []int         `json:"reminders,omitempty" bson:"reminders,omitempty"`
+	NoShow    *NoShowReport `json:"noShow,omitempty" bson:"no_show,omitempty"`
+	// Set when the order of the appointment is cancelled
+	Cancellation *AppointmentCancellation `json:"cancellation,omitempty" bson:"cancellation,omitempty"`
+}
+
+// AppointmentCancellation is when the appointment was called off. The slot
+// it had booked is moved here, out of the seller's unique slot index, so the
+// seller can book it again.
+type AppointmentCancellation struct {
+	CancelledAt time.Time `json:"cancelledAt" bson:"cancelled_at"`
+	SlotStart   time.Time `json:"slotStart,omitempty" bson:"slot_start,omitempty"`
+	SlotEnd     time.Time `json:"slotEnd,omitempty" bson:"slot_end,omitempty"`
+}
+
+// NoShowReport is one party reporting the other missed the appointment
+type NoShowReport struct {
+	ReportedBy primitive.ObjectID `json:"reportedBy" bson:"reported_by"`
+	AbsentID   primitive.ObjectID `json:"absentID" bson:"absent_id"`
+	ReportedAt time.Time          `json:"reportedAt" bson:"reported_at"`
+}
+
+// AppointmentProposal is a place or a time suggested by the buyer or the
+// seller, which the other party accepts, rejects or counters
+type AppointmentProposal struct {
+	ProposalID  primitive.ObjectID `json:"proposalID" bson:"proposal_id"`
+	Kind        int                `json:"kind" bson:"kind"`
+	Status      int                `json:"status" bson:"status"`
+	ProposedBy  primitive.ObjectID `json:"proposedBy" bson:"proposed_by"`
+	Address     string             `json:"address,omitempty" bson:"address,omitempty"`
+	City        string             `json:"city,omitempty" bson:"city,omitempty"`
+	Province    string             `json:"province,omitempty" bson:"province,omitempty"`
+	Zip         string             `json:"zip,omitempty" bson:"zip,omitempty"`
+	Location    *geo.Point         `json:"location,omitempty" bson:"location,omitempty"`
+	Date        time.Time          `json:"date,omitempty" bson:"date,omitempty"`
+	TimeSlot    string             `json:"timeSlot,omitempty" bson:"time_slot,omitempty"`
+	SlotStart   time.Time          `json:"slotStart,omitempty" bson:"slot_start,omitempty"`
+	SlotEnd     time.Time          `json:"slotEnd,omitempty" bson:"slot_end,omitempty"`
+	CreatedAt   time.Time          `json:"createdAt" bson:"created_at"`
+	RespondedAt *time.Time         `json:"respondedAt,omitempty" bson:"responded_at,omitempty"`
 }
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SellerAvailability is when a seller meets buyers, one document per seller
+type SellerAvailability struct {
+	SellerID    primitive.ObjectID `json:"sellerID" bson:"_id"`
+	SlotMinutes int                `json:"slotMinutes" bson:"slot_minutes"`
+	Weekly      []WeeklyWindow     `json:"weekly" bson:"weekly"`
+	Blackouts   []time.Time        `json:"blackouts" bson:"blackouts"`
+	UpdatedAt   time.Time          `json:"updatedAt" bson:"updated_at"`
+}
+
+// WeeklyWindow is a repeating window of opening hours, Start and End are
+// "15:04" in Thai time
+type WeeklyWindow struct {
+	Weekday time.Weekday `json:"weekday" bson:"weekday"`
+	Start   string       `json:"start" bson:"start"`
+	End     string       `json:"end" bson:"end"`
+}
@@ -1,6 +1,9 @@
 package model
 
 import (
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/geo"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -17,5 +20,19 @@ type Buyer struct {
 	Province    string             `json:"province" bson:"province"`
 	Zip         string             `json:"zip" bson:"zip"`
 	Cart        []OrderProduct     `json:"cart" bson:"cart"`
+	Wishlist    []WishlistItem     `json:"wishlist,omitempty" bson:"wishlist,omitempty"`
 	ProfilePic  string             `json:"profilePic" bson:"profilePic"`
+	ProfilePics *ImageVariants     `json:"profilePics,omitempty" bson:"profilePics,omitempty"`
+	// Appointments the buyer was reported missing from
+	NoShowStrikes int `json:"noShowStrikes" bson:"noShowStrikes,omitempty"`
+	// Where the buyer usually meets, used to suggest meet-up points
+	Location *geo.Point `json:"location,omitempty" bson:"location,omitempty"`
+}
+
+// WishlistItem is a product the buyer is watching. Amount is only set for
+// items saved for later from the cart so moving them back keeps the quantity.
+type WishlistItem struct {
+	ProductID primitive.ObjectID `json:"productID" bson:"productID"`
+	Amount    int                `json:"amount,omitempty" bson:"amount,omitempty"`
+	AddedAt   time.Time          `json:"addedAt" bson:"addedAt"`
 }
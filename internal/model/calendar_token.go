@@ -0,0 +1,15 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CalendarToken holds the nonce signed into a user's calendar feed token,
+// one document per user. Rotating it revokes the feed URLs handed out so far.
+type CalendarToken struct {
+	UserID    primitive.ObjectID `json:"userID" bson:"_id"`
+	Nonce     string             `json:"nonce" bson:"nonce"`
+	RotatedAt time.Time          `json:"rotatedAt" bson:"rotated_at"`
+}
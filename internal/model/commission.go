@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/money"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CommissionRule is the percentage the platform keeps from a seller's
+// revenue. Category rules match products tagged with Category.
+type CommissionRule struct {
+	RuleID    primitive.ObjectID  `json:"ruleID,omitempty" bson:"_id,omitempty"`
+	Scope     int                 `json:"scope" bson:"scope"`
+	Category  string              `json:"category,omitempty" bson:"category,omitempty"`
+	SellerID  *primitive.ObjectID `json:"sellerID,omitempty" bson:"sellerID,omitempty"`
+	Rate      float64             `json:"rate" bson:"rate"`
+	UpdatedAt time.Time           `json:"updatedAt" bson:"updatedAt"`
+}
+
+// OrderFees is how an order's revenue was split between seller and platform
+type OrderFees struct {
+	// What the seller earned before fees, the subtotal less any seller coupon
+	SellerGross   money.Money `json:"sellerGross" bson:"sellerGross"`
+	Commission    money.Money `json:"commission" bson:"commission"`
+	ProcessingFee money.Money `json:"processingFee" bson:"processingFee"`
+	// Platform coupon discount the platform covers
+	CouponSubsidy money.Money `json:"couponSubsidy" bson:"couponSubsidy"`
+	SellerNet     money.Money `json:"sellerNet" bson:"sellerNet"`
+}
+
+// LedgerEntry is one movement of platform revenue. Income is positive and
+// costs are negative.
+type LedgerEntry struct {
+	EntryID  primitive.ObjectID `json:"entryID,omitempty" bson:"_id,omitempty"`
+	Type     int                `json:"type" bson:"type"`
+	Amount   money.Money        `json:"amount" bson:"amount"`
+	OrderID  primitive.ObjectID `json:"orderID" bson:"orderID"`
+	SellerID primitive.ObjectID `json:"sellerID" bson:"sellerID"`
+	// Set on entries that undo those of a refunded order
+	Reversal  bool      `json:"reversal" bson:"reversal"`
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Conversation is a buyer and a seller talking about a product or an order
+type Conversation struct {
+	ConversationID primitive.ObjectID `json:"conversationID,omitempty" bson:"_id"`
+	BuyerID        primitive.ObjectID `json:"buyerID" bson:"buyer_id"`
+	SellerID       primitive.ObjectID `json:"sellerID" bson:"seller_id"`
+	ProductID      primitive.ObjectID `json:"productID,omitempty" bson:"product_id,omitempty"`
+	OrderID        primitive.ObjectID `json:"orderID,omitempty" bson:"order_id,omitempty"`
+	LastMessage    *Message           `json:"lastMessage,omitempty" bson:"last_message,omitempty"`
+	// Messages each party hasn't read yet
+	BuyerUnread  int       `json:"buyerUnread" bson:"buyer_unread"`
+	SellerUnread int       `json:"sellerUnread" bson:"seller_unread"`
+	CreatedAt    time.Time `json:"createdAt" bson:"created_at"`
+	UpdatedAt    time.Time `json:"updatedAt" bson:"updated_at"`
+}
+
+type Message struct {
+	MessageID      primitive.ObjectID `json:"messageID,omitempty" bson:"_id"`
+	ConversationID primitive.ObjectID `json:"conversationID" bson:"conversation_id"`
+	SenderID       primitive.ObjectID `json:"senderID" bson:"sender_id"`
+	Text           string             `json:"text,omitempty" bson:"text,omitempty"`
+	Images         []ImageVariants    `json:"images,omitempty" bson:"images,omitempty"`
+	CreatedAt      time.Time          `json:"createdAt" bson:"created_at"`
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/money"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Coupon is a discount code. Coupons without a SellerID are platform-wide and
+// funded by the platform, seller coupons only discount that seller's products
+// and come out of the seller's payout.
+type Coupon struct {
+	CouponID primitive.ObjectID `json:"couponID,omitempty" bson:"_id,omitempty"`
+	Code     string             `json:"code" bson:"code"`
+	Type     int                `json:"type" bson:"type"`
+	// Percent off for percentage coupons, Amount off for fixed ones
+	Percent       float64             `json:"percent,omitempty" bson:"percent,omitempty"`
+	Amount        money.Money         `json:"amount" bson:"amount,omitempty"`
+	SellerID      *primitive.ObjectID `json:"sellerID,omitempty" bson:"sellerID,omitempty"`
+	MinSpend      money.Money         `json:"minSpend" bson:"minSpend"`
+	MaxDiscount   money.Money         `json:"maxDiscount" bson:"maxDiscount"`
+	UsageLimit    int                 `json:"usageLimit" bson:"usageLimit"`
+	PerBuyerLimit int                 `json:"perBuyerLimit" bson:"perBuyerLimit"`
+	UsedCount     int                 `json:"usedCount" bson:"usedCount"`
+	StartsAt      time.Time           `json:"startsAt" bson:"startsAt"`
+	ExpiresAt     time.Time           `json:"expiresAt" bson:"expiresAt"`
+	Active        bool                `json:"active" bson:"active"`
+	CreatedAt     time.Time           `json:"createdAt" bson:"createdAt"`
+}
+
+type CouponRedemption struct {
+	RedemptionID primitive.ObjectID `json:"redemptionID,omitempty" bson:"_id,omitempty"`
+	CouponID     primitive.ObjectID `json:"couponID" bson:"couponID"`
+	Code         string             `json:"code" bson:"code"`
+	BuyerID      primitive.ObjectID `json:"buyerID" bson:"buyerID"`
+	Discount     money.Money        `json:"discount" bson:"discount"`
+	CreatedAt    time.Time          `json:"createdAt" bson:"createdAt"`
+	// Which of the buyer's uses of a coupon with a per buyer limit this is
+	Seq int `json:"seq,omitempty" bson:"seq,omitempty"`
+}
@@ -0,0 +1,7 @@
+package model
+
+type ImageVariants struct {
+	Thumbnail string `json:"thumbnail" bson:"thumbnail"`
+	Medium    string `json:"medium" bson:"medium"`
+	Large     string `json:"large" bson:"large"`
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/geo"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MeetupPoint is a public place such as a mall or a station where buyers and
+// sellers can safely meet
+type MeetupPoint struct {
+	MeetupPointID primitive.ObjectID `json:"meetupPointID" bson:"_id"`
+	Name          string             `json:"name" bson:"name"`
+	Address       string             `json:"address" bson:"address"`
+	City          string             `json:"city" bson:"city"`
+	Province      string             `json:"province" bson:"province"`
+	Zip           string             `json:"zip" bson:"zip"`
+	Location      geo.Point          `json:"location" bson:"location"`
+	CreatedAt     time.Time          `json:"createdAt" bson:"created_at"`
+}
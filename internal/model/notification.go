@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Notification is an entry in a user's in-app inbox
+type Notification struct {
+	NotificationID primitive.ObjectID `json:"notificationID,omitempty" bson:"_id"`
+	UserID         primitive.ObjectID `json:"userID" bson:"user_id"`
+	Type           string             `json:"type" bson:"type"`
+	Title          string             `json:"title" bson:"title"`
+	Body           string             `json:"body" bson:"body"`
+	// IDs of what the notification is about, e.g. "orderID"
+	Data      map[string]string `json:"data,omitempty" bson:"data,omitempty"`
+	ReadAt    *time.Time        `json:"readAt,omitempty" bson:"read_at,omitempty"`
+	CreatedAt time.Time         `json:"createdAt" bson:"created_at"`
+}
+
+// NotificationPreference is where a user wants to be notified
+type NotificationPreference struct {
+	UserID   primitive.ObjectID `json:"userID" bson:"_id"`
+	Channels []string           `json:"channels" bson:"channels"`
+	// Notification types the user doesn't want at all
+	Muted      []string  `json:"muted,omitempty" bson:"muted,omitempty"`
+	Email      string    `json:"email,omitempty" bson:"email,omitempty"`
+	LineUserID string    `json:"lineUserID,omitempty" bson:"line_user_id,omitempty"`
+	WebhookURL string    `json:"webhookURL,omitempty" bson:"webhook_url,omitempty"`
+	UpdatedAt  time.Time `json:"updatedAt" bson:"updated_at"`
+}
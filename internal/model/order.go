@@ -3,6 +3,7 @@ package model
 import (
 	"time"
 
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/money"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -15,12 +16,30 @@ type Order struct {
 	SellerName    string             `json:"sellerName" bson:"sellerName"`
 	BuyerID       primitive.ObjectID `json:"buyerID" bson:"buyerID"`
 	BuyerName     string             `json:"buyerName" bson:"buyerName"`
-	TotalPrice    float64            `json:"totalPrice" bson:"totalPrice"`
+	TotalPrice    money.Money        `json:"totalPrice" bson:"totalPrice"`
 	CreatedAt     time.Time          `json:"createdAt" bson:"createdAt"`
 	Payment       string             `json:"payment" bson:"payment"`
+	ChargeID      string             `json:"chargeID,omitempty" bson:"chargeID,omitempty"`
+	// Price before the coupon discount, TotalPrice is what the buyer paid
+	Subtotal   money.Money `json:"subtotal" bson:"subtotal,omitempty"`
+	Discount   money.Money `json:"discount" bson:"discount,omitempty"`
+	CouponCode string      `json:"couponCode,omitempty" bson:"couponCode,omitempty"`
+	// Platform coupons are paid for by the platform, not out of the seller payout
+	PlatformDiscount bool `json:"platformDiscount,omitempty" bson:"platformDiscount,omitempty"`
+	// How the revenue was split between seller and platform
+	Fees *OrderFees `json:"fees,omitempty" bson:"fees,omitempty"`
+	// Events staged with the last write, waiting for the outbox relay
+	Outbox []OutboxEvent `json:"-" bson:"outbox,omitempty"`
 }
 
+// OrderProduct is a line item. The product details are a snapshot taken when
+// the order is placed and are empty for cart items and older orders.
 type OrderProduct struct {
-	ProductID primitive.ObjectID `json:"productID" bson:"productID"`
-	Amount    int                `json:"amount" bson:"amount" binding:"required,gte=0"`
+	ProductID   primitive.ObjectID `json:"productID" bson:"productID"`
+	Amount      int                `json:"amount" bson:"amount" binding:"required,gte=0"`
+	ProductName string             `json:"productName,omitempty" bson:"productName,omitempty"`
+	Color       string             `json:"color,omitempty" bson:"color,omitempty"`
+	UnitPrice   money.Money        `json:"unitPrice" bson:"unitPrice,omitempty"`
+	Image       string             `json:"image,omitempty" bson:"image,omitempty"`
+	Subtotal    money.Money        `json:"subtotal" bson:"subtotal,omitempty"`
 }
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OutboxEvent is a domain event waiting to be published to subscribers. It is
+// staged on the document it is about, in the same write, and moved to the
+// outbox collection by the relay.
+type OutboxEvent struct {
+	EventID     primitive.ObjectID `json:"eventID" bson:"_id"`
+	Type        string             `json:"type" bson:"type"`
+	AggregateID primitive.ObjectID `json:"aggregateID" bson:"aggregate_id"`
+	Payload     bson.Raw           `json:"-" bson:"payload"`
+	OccurredAt  time.Time          `json:"occurredAt" bson:"occurred_at"`
+	Status      int16              `json:"status" bson:"status"`
+	// Attempts so far, counted when the relay claims the event
+	Attempts      int       `json:"attempts" bson:"attempts"`
+	NextAttemptAt time.Time `json:"nextAttemptAt" bson:"next_attempt_at"`
+	// Subscribers that already handled the event and are skipped on retries
+	Delivered   []string   `json:"delivered,omitempty" bson:"delivered,omitempty"`
+	LastError   string     `json:"lastError,omitempty" bson:"last_error,omitempty"`
+	PublishedAt *time.Time `json:"publishedAt,omitempty" bson:"published_at,omitempty"`
+}
@@ -3,13 +3,14 @@ package model
 import (
 	"time"
 
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/money"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type Product struct {
 	ProductID   primitive.ObjectID `json:"productID,omitempty" bson:"_id,omitempty"`
 	ProductName string             `json:"productName" bson:"productName" binding:"required"`
-	Price       float64            `json:"price,omitempty" bson:"price" binding:"required,gte=1"`
+	Price       money.Money        `json:"price" bson:"price"`
 	Description string             `json:"description,omitempty" bson:"description"`
 	Tag         []string           `json:"tag,omitempty" bson:"tag"`
 	Color       string             `json:"color,omitempty" bson:"color"`
@@ -17,4 +18,11 @@ type Product struct {
 	CreatedAt   time.Time          `json:"createdAt,omitempty" bson:"createdAt"`
 	Amount      int                `json:"amount" bson:"amount" binding:"required,gte=0"`
 	Image       string             `json:"image,omitempty" bson:"image"`
+	Images      *ImageVariants     `json:"images,omitempty" bson:"images,omitempty"`
+	Status      int                `json:"status" bson:"status"`
+	DeletedAt   *time.Time         `json:"deletedAt,omitempty" bson:"deletedAt,omitempty"`
+	// Status the product had before it was archived or deleted
+	ArchivedFrom *int `json:"-" bson:"archivedFrom,omitempty"`
+	// Scores given to the product in reviews of orders it was bought in
+	Rating *Rating `json:"rating,omitempty" bson:"rating,omitempty"`
 }
@@ -0,0 +1,10 @@
+package model
+
+// Rating is kept up to date as reviews are written, changed and deleted, so
+// a seller's score never needs to be worked out from every review
+type Rating struct {
+	Count int `json:"count" bson:"count"`
+	Sum   int `json:"sum" bson:"sum"`
+	// Number of reviews per score, keyed by the score
+	Histogram map[string]int `json:"histogram" bson:"histogram"`
+}
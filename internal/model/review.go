@@ -13,7 +13,35 @@ type Review struct {
 	SellerID   primitive.ObjectID `json:"sellerID" bson:"seller_id"`
 	SellerName string             `json:"sellerName" bson:"sellerName"`
 	Image      string             `json:"image,omitempty" bson:"image,omitempty"`
+	Images     *ImageVariants     `json:"images,omitempty" bson:"images,omitempty"`
 	Message    string             `json:"message" bson:"message" binding:"max=500"`
 	Score      int                `json:"score" bson:"score" binding:"gte=0,lte=10"`
 	Date       time.Time          `json:"date" bson:"date"`
+	// The completed order the review is for, empty on reviews written
+	// before reviews needed one
+	OrderID  primitive.ObjectID `json:"orderID,omitempty" bson:"order_id,omitempty"`
+	Products []ReviewProduct    `json:"products,omitempty" bson:"products,omitempty"`
+	// The seller's public answer, a review gets at most one
+	Reply *ReviewReply `json:"reply,omitempty" bson:"reply,omitempty"`
+	// Hidden and removed reviews are left out of listings and the rating
+	Status int `json:"status" bson:"status"`
+	// Events staged with the last write, waiting for the outbox relay
+	Outbox []OutboxEvent `json:"-" bson:"outbox,omitempty"`
+}
+
+type ReviewReply struct {
+	Message string    `json:"message" bson:"message"`
+	Date    time.Time `json:"date" bson:"date"`
+}
+
+// ReviewProduct is a product bought in the reviewed order
+type ReviewProduct struct {
+	ProductID   primitive.ObjectID `json:"productID" bson:"productID"`
+	ProductName string             `json:"productName,omitempty" bson:"productName,omitempty"`
+	Color       string             `json:"color,omitempty" bson:"color,omitempty"`
+	Image       string             `json:"image,omitempty" bson:"image,omitempty"`
+	Amount      int                `json:"amount" bson:"amount"`
+	// Set when the buyer reviewed the product itself
+	Score   *int   `json:"score,omitempty" bson:"score,omitempty"`
+	Comment string `json:"comment,omitempty" bson:"comment,omitempty"`
 }
@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ReviewReport is a buyer or a seller flagging a review as abusive
+type ReviewReport struct {
+	ReportID   primitive.ObjectID `json:"reportID,omitempty" bson:"_id"`
+	ReviewID   primitive.ObjectID `json:"reviewID" bson:"review_id"`
+	ReporterID primitive.ObjectID `json:"reporterID" bson:"reporter_id"`
+	Reason     string             `json:"reason" bson:"reason"`
+	Status     int                `json:"status" bson:"status"`
+	CreatedAt  time.Time          `json:"createdAt" bson:"createdAt"`
+	ResolvedAt *time.Time         `json:"resolvedAt,omitempty" bson:"resolvedAt,omitempty"`
+}
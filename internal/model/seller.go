@@ -1,6 +1,8 @@
 package model
 
 import (
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/geo"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/money"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -18,6 +20,13 @@ type Seller struct {
 	Zip         string             `json:"zip" bson:"zip"`
 	Score       float64            `json:"score" bson:"score,omitempty"`
 	Transaction []Transaction      `json:"transaction" bson:"transaction"`
-	Balance     float64            `json:"balance" bson:"balance"`
+	Balance     money.Money        `json:"balance" bson:"balance"`
 	ProfilePic  string             `json:"profilePic" bson:"profilePic"`
+	ProfilePics *ImageVariants     `json:"profilePics,omitempty" bson:"profilePics,omitempty"`
+	// Appointments the seller was reported missing from
+	NoShowStrikes int `json:"noShowStrikes" bson:"noShowStrikes,omitempty"`
+	// Where the seller usually meets, used for distance search and meet-up suggestions
+	Location *geo.Point `json:"location,omitempty" bson:"location,omitempty"`
+	// Review aggregates, Score is worked out from them
+	Rating *Rating `json:"rating,omitempty" bson:"rating,omitempty"`
 }
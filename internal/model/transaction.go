@@ -3,12 +3,13 @@ package model
 import (
 	"time"
 
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/money"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type Transaction struct {
 	Type    int16              `json:"type" bson:"type"`
-	Amount  float64            `json:"amount" bson:"amount" binding:"gte=0"`
+	Amount  money.Money        `json:"amount" bson:"amount"`
 	OrderID primitive.ObjectID `json:"orderID,omitempty" bson:"_id,omitempty"`
 	Payment string             `json:"payment" bson:"payment"`
 	Date    time.Time          `json:"data" bson:"date"`
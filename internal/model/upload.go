@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Upload tracks a stored object and the entities referencing it. Objects
+// without references are deleted by the orphan sweeper after a grace period.
+type Upload struct {
+	UploadID  primitive.ObjectID `json:"uploadID,omitempty" bson:"_id,omitempty"`
+	Key       string             `json:"key" bson:"key"`
+	URL       string             `json:"url" bson:"url"`
+	Refs      []UploadRef        `json:"refs" bson:"refs"`
+	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt time.Time          `json:"updatedAt" bson:"updatedAt"`
+	// Set while the sweeper deletes the object
+	SweepingAt *time.Time `json:"sweepingAt,omitempty" bson:"sweepingAt,omitempty"`
+}
+
+type UploadRef struct {
+	OwnerType string             `json:"ownerType" bson:"ownerType"`
+	OwnerID   primitive.ObjectID `json:"ownerID" bson:"ownerID"`
+}
@@ -0,0 +1,59 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/config"
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/notificationchannel"
+)
+
+// EmailNotifier sends notifications as plain text emails over SMTP
+type EmailNotifier struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+func NewEmailNotifier(cfg *config.NotificationConfig) Notifier {
+	var auth smtp.Auth
+	if cfg.SMTPUsername != "" {
+		auth = smtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPHost)
+	}
+	return EmailNotifier{
+		addr: net.JoinHostPort(cfg.SMTPHost, strconv.Itoa(cfg.SMTPPort)),
+		auth: auth,
+		from: cfg.EmailFrom,
+	}
+}
+
+func (n EmailNotifier) Channel() string {
+	return notificationchannel.EMAIL
+}
+
+func (n EmailNotifier) Send(ctx context.Context, to *dto.NotificationPreference, notification *dto.Notification) error {
+	if to.Email == "" {
+		return ErrNoRecipient
+	}
+	return smtp.SendMail(n.addr, n.auth, n.from, []string{to.Email}, emailMessage(n.from, to.Email, notification))
+}
+
+func emailMessage(from string, to string, notification *dto.Notification) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	// Titles are often Thai, which headers can only carry encoded
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", notification.Title))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(notification.Body, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
@@ -0,0 +1,73 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/config"
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/notificationchannel"
+)
+
+// LineNotifier pushes notifications through the LINE Messaging API to users
+// who added our LINE Official Account as a friend
+type LineNotifier struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func NewLineNotifier(cfg *config.NotificationConfig) Notifier {
+	return LineNotifier{
+		url:    strings.TrimSuffix(cfg.LineAPIURL, "/") + "/v2/bot/message/push",
+		token:  cfg.LineChannelAccessToken,
+		client: httpClient(cfg),
+	}
+}
+
+func (n LineNotifier) Channel() string {
+	return notificationchannel.LINE
+}
+
+type linePushMessage struct {
+	To       string        `json:"to"`
+	Messages []lineMessage `json:"messages"`
+}
+
+type lineMessage struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+func (n LineNotifier) Send(ctx context.Context, to *dto.NotificationPreference, notification *dto.Notification) error {
+	if to.LineUserID == "" {
+		return ErrNoRecipient
+	}
+	body, err := json.Marshal(linePushMessage{
+		To:       to.LineUserID,
+		Messages: []lineMessage{{Type: "text", Text: notification.Title + "\n" + notification.Body}},
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+n.token)
+
+	res, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("LINE Messaging API responded %s", res.Status)
+	}
+	return nil
+}
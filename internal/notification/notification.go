@@ -0,0 +1,67 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/config"
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/notificationchannel"
+	"github.com/Dongy-s-Advanture/back-end/internal/repository"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/converter"
+)
+
+// ErrNoRecipient is returned when the user hasn't given an address for the
+// channel, such as an email channel without an email
+var ErrNoRecipient = errors.New("no address to notify the user at")
+
+// Notifier delivers notifications to users over one channel
+type Notifier interface {
+	// Channel is one of notificationchannel
+	Channel() string
+	Send(ctx context.Context, to *dto.NotificationPreference, notification *dto.Notification) error
+}
+
+// New returns a notifier for every channel that can be used with cfg. Email
+// is only available once an SMTP server is configured, LINE once a Messaging
+// API channel is.
+func New(cfg *config.NotificationConfig, r repository.INotificationRepository) []Notifier {
+	notifiers := []Notifier{
+		NewInboxNotifier(r),
+		NewWebhookNotifier(cfg),
+	}
+	if cfg.SMTPHost != "" {
+		notifiers = append(notifiers, NewEmailNotifier(cfg))
+	}
+	if cfg.LineChannelAccessToken != "" {
+		notifiers = append(notifiers, NewLineNotifier(cfg))
+	}
+	return notifiers
+}
+
+// InboxNotifier stores notifications for the user to read in the app
+type InboxNotifier struct {
+	notificationRepository repository.INotificationRepository
+}
+
+func NewInboxNotifier(r repository.INotificationRepository) Notifier {
+	return InboxNotifier{notificationRepository: r}
+}
+
+func (n InboxNotifier) Channel() string {
+	return notificationchannel.INBOX
+}
+
+func (n InboxNotifier) Send(ctx context.Context, to *dto.NotificationPreference, notification *dto.Notification) error {
+	notificationModel, err := converter.NotificationDTOToModel(notification)
+	if err != nil {
+		return err
+	}
+	return n.notificationRepository.CreateNotification(notificationModel)
+}
+
+func httpClient(cfg *config.NotificationConfig) *http.Client {
+	return &http.Client{Timeout: time.Duration(cfg.TimeoutSeconds) * time.Second}
+}
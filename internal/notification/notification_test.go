@@ -0,0 +1,87 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/config"
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var testNotification = &dto.Notification{
+	NotificationID: primitive.NewObjectID(),
+	Type:           "order_status",
+	Title:          "คำสั่งซื้อใหม่",
+	Body:           "Somchai placed an order.",
+}
+
+func TestLineNotifier(t *testing.T) {
+	var auth, path string
+	var pushed linePushMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		path = r.URL.Path
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&pushed))
+	}))
+	defer server.Close()
+
+	notifier := NewLineNotifier(&config.NotificationConfig{LineChannelAccessToken: "channel-token", LineAPIURL: server.URL, TimeoutSeconds: 5})
+	err := notifier.Send(context.Background(), &dto.NotificationPreference{LineUserID: "U123"}, testNotification)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer channel-token", auth)
+	assert.Equal(t, "/v2/bot/message/push", path)
+	assert.Equal(t, linePushMessage{
+		To:       "U123",
+		Messages: []lineMessage{{Type: "text", Text: "คำสั่งซื้อใหม่\nSomchai placed an order."}},
+	}, pushed)
+
+	err = notifier.Send(context.Background(), &dto.NotificationPreference{}, testNotification)
+	assert.ErrorIs(t, err, ErrNoRecipient)
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var signature string
+	var received dto.Notification
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		signature = r.Header.Get(SignatureHeader)
+		assert.Equal(t, "sha256="+Sign([]byte("secret"), body), signature)
+		assert.NoError(t, json.Unmarshal(body, &received))
+	}))
+	defer server.Close()
+	to := &dto.NotificationPreference{WebhookURL: server.URL}
+
+	// The test server listens on loopback, which webhooks may not reach
+	notifier := NewWebhookNotifier(&config.NotificationConfig{WebhookSecret: "secret", TimeoutSeconds: 5})
+	err := notifier.Send(context.Background(), to, testNotification)
+	assert.ErrorIs(t, err, ErrPrivateAddress)
+
+	webhook := notifier.(WebhookNotifier)
+	webhook.client = server.Client()
+	require.NoError(t, webhook.Send(context.Background(), to, testNotification))
+	assert.NotEmpty(t, signature)
+	assert.Equal(t, testNotification.NotificationID, received.NotificationID)
+}
+
+func TestPublicOnly(t *testing.T) {
+	for _, address := range []string{"127.0.0.1:80", "10.0.0.5:443", "192.168.1.1:443", "169.254.169.254:80", "[::1]:443", "0.0.0.0:80"} {
+		assert.ErrorIs(t, publicOnly("tcp", address, nil), ErrPrivateAddress, address)
+	}
+	assert.NoError(t, publicOnly("tcp", "203.0.113.10:443", nil))
+}
+
+func TestEmailMessage(t *testing.T) {
+	message := string(emailMessage("shop@example.com", "buyer@example.com", testNotification))
+	assert.Contains(t, message, "To: buyer@example.com\r\n")
+	assert.Contains(t, message, "Subject: =?utf-8?q?")
+	assert.NotContains(t, message, "คำสั่งซื้อใหม่")
+	assert.True(t, strings.HasSuffix(message, "\r\n\r\nSomchai placed an order.\r\n"))
+}
@@ -0,0 +1,105 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/config"
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/notificationchannel"
+)
+
+// ErrPrivateAddress is returned for webhooks pointing into our own network
+var ErrPrivateAddress = errors.New("webhook address is not public")
+
+// SignatureHeader carries the hex HMAC-SHA256 of the body, keyed with the
+// webhook secret, so receivers can check a notification came from us
+const SignatureHeader = "X-Signature-256"
+
+// WebhookNotifier posts notifications as JSON to the user's webhook URL
+type WebhookNotifier struct {
+	client *http.Client
+	secret []byte
+}
+
+func NewWebhookNotifier(cfg *config.NotificationConfig) Notifier {
+	dialer := &net.Dialer{Timeout: 10 * time.Second, Control: publicOnly}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	client := httpClient(cfg)
+	client.Transport = transport
+	// A redirect could point anywhere, the URL itself was checked
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return WebhookNotifier{
+		client: client,
+		secret: []byte(cfg.WebhookSecret),
+	}
+}
+
+func (n WebhookNotifier) Channel() string {
+	return notificationchannel.WEBHOOK
+}
+
+func (n WebhookNotifier) Send(ctx context.Context, to *dto.NotificationPreference, notification *dto.Notification) error {
+	if to.WebhookURL == "" {
+		return ErrNoRecipient
+	}
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, to.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(n.secret) > 0 {
+		req.Header.Set(SignatureHeader, "sha256="+Sign(n.secret, body))
+	}
+
+	res, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded %s", res.Status)
+	}
+	return nil
+}
+
+// Sign is the hex HMAC-SHA256 of body keyed with secret
+func Sign(secret []byte, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// publicOnly refuses connections to loopback, private and link-local
+// addresses, so webhooks can't be used to reach internal services
+func publicOnly(network string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() {
+		return ErrPrivateAddress
+	}
+	return nil
+}
@@ -64,7 +64,7 @@ func (r AdvertisementRepository) GetWeightedRandomAdvertisements() ([]dto.Advert
 		var choices []weightedrand.Choice[model.Advertisement, uint]
 		for _, ad := range advertisementModels {
 			if !selectedAds[ad.AdvertisementID] { // Skip already selected ads
-				choices = append(choices, weightedrand.NewChoice(ad, uint(ad.Amount*100)))
+				choices = append(choices, weightedrand.NewChoice(ad, uint(ad.Amount.Amount)))
 			}
 		}
 
@@ -210,6 +210,7 @@ func (r AdvertisementRepository) UpdateAdvertisement(advertisementID primitive.O
 		"$set": bson.M{
 			"productID": updatedAdvertisement.ProductID,
 			"imageURL":  updatedAdvertisement.ImageURL,
+			"images":    updatedAdvertisement.Images,
 			"amount":    updatedAdvertisement.Amount,
 			"payment":   updatedAdvertisement.Payment,
 			"createdAt": time.Now(),
@@ -2,23 +2,37 @@ package repository
 
 import (
 	"context"
+	"log"
 	"time"
 
 	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/proposalstatus"
 	"github.com/Dongy-s-Advanture/back-end/internal/model"
 	"github.com/Dongy-s-Advanture/back-end/pkg/utils/converter"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type IAppointmentRepository interface {
 	GetAppointments() ([]dto.Appointment, error)
 	GetAppointmentByID(appointmentID primitive.ObjectID) (*dto.Appointment, error)
 	GetAppointmentByOrderID(orderID primitive.ObjectID) (*dto.Appointment, error)
+	GetAppointmentsByUserID(userID primitive.ObjectID) ([]dto.Appointment, error)
 	CreateAppointment(appointment *model.Appointment) (*dto.Appointment, error)
 	UpdateAppointmentDate(appointmentID primitive.ObjectID, updatedAppointment *model.Appointment) (*dto.Appointment, error)
 	UpdateAppointmentPlace(appointmentID primitive.ObjectID, updatedAppointment *model.Appointment) (*dto.Appointment, error)
+	GetBookedSlots(sellerID primitive.ObjectID, from time.Time, to time.Time) ([]dto.Appointment, error)
+	AddProposal(appointmentID primitive.ObjectID, proposal *model.AppointmentProposal) (*dto.Appointment, error)
+	CloseProposals(appointmentID primitive.ObjectID, kind int, proposedBy primitive.ObjectID, status int) error
+	SetProposalStatus(appointmentID primitive.ObjectID, proposalID primitive.ObjectID, from int, to int) (bool, error)
+	GetDueReminders(lead time.Duration, now time.Time) ([]dto.Appointment, error)
+	MarkReminderSent(appointmentID primitive.ObjectID, minutes int) (bool, error)
+	UnmarkReminderSent(appointmentID primitive.ObjectID, minutes int) error
+	ReportNoShow(appointmentID primitive.ObjectID, report *model.NoShowReport) (bool, error)
+	ClearNoShow(appointmentID primitive.ObjectID) error
+	CancelAppointment(orderID primitive.ObjectID) error
 }
 
 type AppointmentRepository struct {
@@ -26,8 +40,31 @@ type AppointmentRepository struct {
 }
 
 func NewAppointmentRepository(db *mongo.Database, collectionName string) IAppointmentRepository {
+	appointmentCollection := db.Collection(collectionName)
+
+	// A seller slot can only be booked once, appointments without a slot
+	// yet or cancelled ones, which no longer hold it, are left out of the
+	// index
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	_, err := appointmentCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "seller_id", Value: 1}, {Key: "slot_start", Value: 1}},
+		Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{
+			"slot_start": bson.M{"$type": "date"},
+		}),
+	})
+	if err != nil {
+		log.Printf("failed to create appointment indexes: %v", err)
+	}
+	_, err = appointmentCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "location", Value: "2dsphere"}},
+	})
+	if err != nil {
+		log.Printf("failed to create appointment indexes: %v", err)
+	}
+
 	return AppointmentRepository{
-		appointmentCollection: db.Collection(collectionName),
+		appointmentCollection: appointmentCollection,
 	}
 }
 
@@ -83,6 +120,35 @@ func (r AppointmentRepository) GetAppointmentByOrderID(orderID primitive.ObjectI
 	return converter.AppointmentModelToDTO(appointment)
 }
 
+// GetAppointmentsByUserID returns the appointments the user is the buyer or
+// the seller of, soonest first
+func (r AppointmentRepository) GetAppointmentsByUserID(userID primitive.ObjectID) ([]dto.Appointment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	filter := bson.M{"$or": bson.A{bson.M{"buyer_id": userID}, bson.M{"seller_id": userID}}}
+	opts := options.Find().SetSort(bson.D{{Key: "slot_start", Value: 1}, {Key: "date", Value: 1}})
+	cursor, err := r.appointmentCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	appointments := []dto.Appointment{}
+	for cursor.Next(ctx) {
+		var appointmentModel *model.Appointment
+		if err = cursor.Decode(&appointmentModel); err != nil {
+			return nil, err
+		}
+		appointmentDTO, err := converter.AppointmentModelToDTO(appointmentModel)
+		if err != nil {
+			return nil, err
+		}
+		appointments = append(appointments, *appointmentDTO)
+	}
+	return appointments, nil
+}
+
 func (r AppointmentRepository) CreateAppointment(appointment *model.Appointment) (*dto.Appointment, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
@@ -109,8 +175,10 @@ func (r AppointmentRepository) UpdateAppointmentDate(appointmentID primitive.Obj
 
 	update := bson.M{
 		"$set": bson.M{
-			"date":      updatedAppointment.Date,
-			"time_slot": updatedAppointment.TimeSlot,
+			"date":       updatedAppointment.Date,
+			"time_slot":  updatedAppointment.TimeSlot,
+			"slot_start": updatedAppointment.SlotStart,
+			"slot_end":   updatedAppointment.SlotEnd,
 		},
 	}
 
@@ -146,6 +214,12 @@ func (r AppointmentRepository) UpdateAppointmentPlace(appointmentID primitive.Ob
 			"zip":      updatedAppointment.Zip,
 		},
 	}
+	// A place without coordinates drops the old ones
+	if updatedAppointment.Location != nil {
+		update["$set"].(bson.M)["location"] = updatedAppointment.Location
+	} else {
+		update["$unset"] = bson.M{"location": ""}
+	}
 
 	filter := bson.M{"_id": appointmentID}
 	_, err := r.appointmentCollection.UpdateOne(ctx, filter, update)
@@ -161,3 +235,198 @@ func (r AppointmentRepository) UpdateAppointmentPlace(appointmentID primitive.Ob
 
 	return converter.AppointmentModelToDTO(newUpdatedAppointment)
 }
+
+// GetBookedSlots returns the seller's appointments with a slot starting
+// between from and to
+func (r AppointmentRepository) GetBookedSlots(sellerID primitive.ObjectID, from time.Time, to time.Time) ([]dto.Appointment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"seller_id":  sellerID,
+		"slot_start": bson.M{"$gte": from, "$lt": to},
+	}
+	cursor, err := r.appointmentCollection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	appointments := []dto.Appointment{}
+	for cursor.Next(ctx) {
+		var appointmentModel *model.Appointment
+		if err = cursor.Decode(&appointmentModel); err != nil {
+			return nil, err
+		}
+		appointmentDTO, err := converter.AppointmentModelToDTO(appointmentModel)
+		if err != nil {
+			return nil, err
+		}
+		appointments = append(appointments, *appointmentDTO)
+	}
+	return appointments, nil
+}
+
+func (r AppointmentRepository) AddProposal(appointmentID primitive.ObjectID, proposal *model.AppointmentProposal) (*dto.Appointment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	proposal.ProposalID = primitive.NewObjectID()
+	proposal.CreatedAt = time.Now()
+	update := bson.M{"$push": bson.M{"proposals": proposal}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var updatedAppointment *model.Appointment
+	err := r.appointmentCollection.FindOneAndUpdate(ctx, bson.M{"_id": appointmentID}, update, opts).Decode(&updatedAppointment)
+	if err != nil {
+		return nil, err
+	}
+	return converter.AppointmentModelToDTO(updatedAppointment)
+}
+
+// CloseProposals moves the pending proposals of a kind made by proposedBy
+// to status
+func (r AppointmentRepository) CloseProposals(appointmentID primitive.ObjectID, kind int, proposedBy primitive.ObjectID, status int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{
+		"proposals.$[p].status":       status,
+		"proposals.$[p].responded_at": time.Now(),
+	}}
+	opts := options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{
+		bson.M{"p.kind": kind, "p.proposed_by": proposedBy, "p.status": proposalstatus.PENDING},
+	}})
+	_, err := r.appointmentCollection.UpdateOne(ctx, bson.M{"_id": appointmentID}, update, opts)
+	return err
+}
+
+// SetProposalStatus moves the proposal to status to only if it is still in
+// status from, reporting whether it moved
+func (r AppointmentRepository) SetProposalStatus(appointmentID primitive.ObjectID, proposalID primitive.ObjectID, from int, to int) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"_id":       appointmentID,
+		"proposals": bson.M{"$elemMatch": bson.M{"proposal_id": proposalID, "status": from}},
+	}
+	update := bson.M{"$set": bson.M{
+		"proposals.$.status":       to,
+		"proposals.$.responded_at": time.Now(),
+	}}
+	if to == proposalstatus.PENDING {
+		update = bson.M{
+			"$set":   bson.M{"proposals.$.status": to},
+			"$unset": bson.M{"proposals.$.responded_at": ""},
+		}
+	}
+	result, err := r.appointmentCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// GetDueReminders returns the appointments starting within lead of now that
+// have not had the reminder for lead yet, leaving out the ones called off
+func (r AppointmentRepository) GetDueReminders(lead time.Duration, now time.Time) ([]dto.Appointment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"slot_start":   bson.M{"$gt": now, "$lte": now.Add(lead)},
+		"reminders":    bson.M{"$ne": int(lead.Minutes())},
+		"no_show":      bson.M{"$exists": false},
+		"cancellation": bson.M{"$exists": false},
+	}
+	cursor, err := r.appointmentCollection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	appointments := []dto.Appointment{}
+	for cursor.Next(ctx) {
+		var appointmentModel *model.Appointment
+		if err = cursor.Decode(&appointmentModel); err != nil {
+			return nil, err
+		}
+		appointmentDTO, err := converter.AppointmentModelToDTO(appointmentModel)
+		if err != nil {
+			return nil, err
+		}
+		appointments = append(appointments, *appointmentDTO)
+	}
+	return appointments, nil
+}
+
+// MarkReminderSent records the reminder with a lead of minutes, reporting
+// false if it was already recorded so it is only sent once
+func (r AppointmentRepository) MarkReminderSent(appointmentID primitive.ObjectID, minutes int) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": appointmentID, "reminders": bson.M{"$ne": minutes}}
+	update := bson.M{"$push": bson.M{"reminders": minutes}}
+	result, err := r.appointmentCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// UnmarkReminderSent takes back a reminder that couldn't be sent so it is
+// due again
+func (r AppointmentRepository) UnmarkReminderSent(appointmentID primitive.ObjectID, minutes int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	update := bson.M{"$pull": bson.M{"reminders": minutes}}
+	_, err := r.appointmentCollection.UpdateOne(ctx, bson.M{"_id": appointmentID}, update)
+	return err
+}
+
+// ReportNoShow stores the report unless the appointment already has one,
+// reporting whether it was stored
+func (r AppointmentRepository) ReportNoShow(appointmentID primitive.ObjectID, report *model.NoShowReport) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": appointmentID, "no_show": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"no_show": report}}
+	result, err := r.appointmentCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+func (r AppointmentRepository) ClearNoShow(appointmentID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	update := bson.M{"$unset": bson.M{"no_show": ""}}
+	_, err := r.appointmentCollection.UpdateOne(ctx, bson.M{"_id": appointmentID}, update)
+	return err
+}
+
+// CancelAppointment marks the appointment of the order as cancelled, an
+// appointment that already is keeps its first cancellation. The booked slot
+// is moved into the cancellation so it leaves the unique slot index.
+func (r AppointmentRepository) CancelAppointment(orderID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	filter := bson.M{"order_id": orderID, "cancellation": bson.M{"$exists": false}}
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{"cancellation": bson.M{
+			"cancelled_at": time.Now(),
+			"slot_start":   "$slot_start",
+			"slot_end":     "$slot_end",
+		}}}},
+		{{Key: "$unset", Value: bson.A{"slot_start", "slot_end"}}},
+	}
+	_, err := r.appointmentCollection.UpdateOne(ctx, filter, update)
+	return err
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/model"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/converter"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IAvailabilityRepository interface {
+	GetAvailability(sellerID primitive.ObjectID) (*dto.SellerAvailability, error)
+	SetAvailability(availability *model.SellerAvailability) (*dto.SellerAvailability, error)
+}
+
+type AvailabilityRepository struct {
+	availabilityCollection *mongo.Collection
+}
+
+func NewAvailabilityRepository(db *mongo.Database, collectionName string) IAvailabilityRepository {
+	return AvailabilityRepository{
+		availabilityCollection: db.Collection(collectionName),
+	}
+}
+
+func (r AvailabilityRepository) GetAvailability(sellerID primitive.ObjectID) (*dto.SellerAvailability, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	var availability *model.SellerAvailability
+	err := r.availabilityCollection.FindOne(ctx, bson.M{"_id": sellerID}).Decode(&availability)
+	if err != nil {
+		return nil, err
+	}
+	return converter.AvailabilityModelToDTO(availability)
+}
+
+func (r AvailabilityRepository) SetAvailability(availability *model.SellerAvailability) (*dto.SellerAvailability, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	availability.UpdatedAt = time.Now()
+	opts := options.FindOneAndReplace().SetUpsert(true).SetReturnDocument(options.After)
+
+	var updatedAvailability *model.SellerAvailability
+	err := r.availabilityCollection.FindOneAndReplace(ctx, bson.M{"_id": availability.SellerID}, availability, opts).Decode(&updatedAvailability)
+	if err != nil {
+		return nil, err
+	}
+	return converter.AvailabilityModelToDTO(updatedAvailability)
+}
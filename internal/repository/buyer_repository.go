@@ -11,6 +11,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type IBuyerRepository interface {
@@ -21,6 +22,10 @@ type IBuyerRepository interface {
 	UpdateBuyerData(buyerID primitive.ObjectID, updatedBuyer *model.Buyer) (*dto.Buyer, error)
 	UpdateProductInCart(buyerID primitive.ObjectID, product *model.OrderProduct) ([]dto.OrderProduct, error)
 	DeleteProductFromCart(buyerID, productID primitive.ObjectID) error
+	AddToWishlist(buyerID primitive.ObjectID, item *model.WishlistItem) ([]dto.WishlistItem, error)
+	RemoveFromWishlist(buyerID, productID primitive.ObjectID) error
+	GetBuyerIDsByWishlistProduct(productID primitive.ObjectID) ([]primitive.ObjectID, error)
+	AddNoShowStrike(buyerID primitive.ObjectID) error
 }
 
 type BuyerRepository struct {
@@ -156,6 +161,7 @@ func (r *BuyerRepository) UpdateProductInCart(buyerID primitive.ObjectID, produc
 	for i, p := range buyer.Cart {
 		if p.ProductID == product.ProductID {
 			buyer.Cart[i].Amount = product.Amount
+			buyer.Cart[i].UnitPrice = product.UnitPrice
 			found = true
 			break
 		}
@@ -205,3 +211,110 @@ func (r *BuyerRepository) DeleteProductFromCart(buyerID, productID primitive.Obj
 	return nil
 }
 
+
+// AddToWishlist adds the item, or updates the saved amount when the product
+// is already on the wishlist
+func (r *BuyerRepository) AddToWishlist(buyerID primitive.ObjectID, item *model.WishlistItem) ([]dto.WishlistItem, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var buyer struct {
+		Wishlist []model.WishlistItem `bson:"wishlist"`
+	}
+	err := r.buyerCollection.FindOne(ctx, bson.M{"_id": buyerID}).Decode(&buyer)
+	if err != nil {
+		return nil, err
+	}
+
+	found := false
+	for i, p := range buyer.Wishlist {
+		if p.ProductID == item.ProductID {
+			if item.Amount > 0 {
+				buyer.Wishlist[i].Amount = item.Amount
+			}
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		buyer.Wishlist = append(buyer.Wishlist, *item)
+	}
+
+	_, err = r.buyerCollection.UpdateOne(
+		ctx,
+		bson.M{"_id": buyerID},
+		bson.M{"$set": bson.M{"wishlist": buyer.Wishlist}},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var updatedWishlist []dto.WishlistItem
+	for _, p := range buyer.Wishlist {
+		updatedWishlist = append(updatedWishlist, dto.WishlistItem{
+			ProductID: p.ProductID,
+			Amount:    p.Amount,
+			AddedAt:   p.AddedAt,
+		})
+	}
+
+	return updatedWishlist, nil
+}
+
+func (r *BuyerRepository) RemoveFromWishlist(buyerID, productID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": buyerID}
+	update := bson.M{"$pull": bson.M{"wishlist": bson.M{"productID": productID}}}
+
+	result, err := r.buyerCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.ModifiedCount == 0 {
+		return fmt.Errorf("product not found in wishlist or already removed")
+	}
+
+	return nil
+}
+
+func (r *BuyerRepository) GetBuyerIDsByWishlistProduct(productID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
+	cursor, err := r.buyerCollection.Find(ctx, bson.M{"wishlist.productID": productID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var buyerIDs []primitive.ObjectID
+	for cursor.Next(ctx) {
+		var buyer struct {
+			BuyerID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.Decode(&buyer); err != nil {
+			return nil, err
+		}
+		buyerIDs = append(buyerIDs, buyer.BuyerID)
+	}
+	return buyerIDs, cursor.Err()
+}
+
+func (r *BuyerRepository) AddNoShowStrike(buyerID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	update := bson.M{"$inc": bson.M{"noShowStrikes": 1}}
+	result, err := r.buyerCollection.UpdateOne(ctx, bson.M{"_id": buyerID}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
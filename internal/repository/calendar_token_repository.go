@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ICalendarTokenRepository interface {
+	GetNonce(userID primitive.ObjectID) (string, error)
+	SetNonce(userID primitive.ObjectID, nonce string) error
+}
+
+type CalendarTokenRepository struct {
+	calendarTokenCollection *mongo.Collection
+}
+
+func NewCalendarTokenRepository(db *mongo.Database, collectionName string) ICalendarTokenRepository {
+	return CalendarTokenRepository{
+		calendarTokenCollection: db.Collection(collectionName),
+	}
+}
+
+// GetNonce returns the user's calendar token nonce, empty when the token was
+// never rotated
+func (r CalendarTokenRepository) GetNonce(userID primitive.ObjectID) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	var token model.CalendarToken
+	err := r.calendarTokenCollection.FindOne(ctx, bson.M{"_id": userID}).Decode(&token)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return token.Nonce, nil
+}
+
+func (r CalendarTokenRepository) SetNonce(userID primitive.ObjectID, nonce string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	token := model.CalendarToken{UserID: userID, Nonce: nonce, RotatedAt: time.Now()}
+	_, err := r.calendarTokenCollection.ReplaceOne(ctx, bson.M{"_id": userID}, token, options.Replace().SetUpsert(true))
+	return err
+}
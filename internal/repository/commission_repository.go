@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/model"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/converter"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ICommissionRepository interface {
+	GetRules() ([]dto.CommissionRule, error)
+	GetRulesForSeller(sellerID primitive.ObjectID) ([]dto.CommissionRule, error)
+	SetRule(rule *model.CommissionRule) (*dto.CommissionRule, error)
+	DeleteRule(ruleID primitive.ObjectID) error
+}
+
+type CommissionRepository struct {
+	ruleCollection *mongo.Collection
+}
+
+func NewCommissionRepository(db *mongo.Database, collectionName string) ICommissionRepository {
+	collection := db.Collection(collectionName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	// One rule per target, setting it again replaces the rate
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "scope", Value: 1}, {Key: "category", Value: 1}, {Key: "sellerID", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		log.Printf("failed to create commission rule indexes: %v", err)
+	}
+
+	return CommissionRepository{
+		ruleCollection: collection,
+	}
+}
+
+func (r CommissionRepository) GetRules() ([]dto.CommissionRule, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "scope", Value: 1}, {Key: "category", Value: 1}})
+	return r.findRules(ctx, bson.M{}, opts)
+}
+
+// GetRulesForSeller returns the global and category rules plus the seller's own rule
+func (r CommissionRepository) GetRulesForSeller(sellerID primitive.ObjectID) ([]dto.CommissionRule, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	filter := bson.M{"$or": bson.A{
+		bson.M{"sellerID": bson.M{"$exists": false}},
+		bson.M{"sellerID": sellerID},
+	}}
+	return r.findRules(ctx, filter, options.Find())
+}
+
+func (r CommissionRepository) SetRule(rule *model.CommissionRule) (*dto.CommissionRule, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	filter := bson.M{"scope": rule.Scope, "category": bson.M{"$exists": false}, "sellerID": bson.M{"$exists": false}}
+	if rule.Category != "" {
+		filter["category"] = rule.Category
+	}
+	if rule.SellerID != nil {
+		filter["sellerID"] = *rule.SellerID
+	}
+
+	rule.UpdatedAt = time.Now()
+	update := bson.M{"$set": bson.M{"rate": rule.Rate, "updatedAt": rule.UpdatedAt}}
+	if rule.Category != "" {
+		update["$setOnInsert"] = bson.M{"category": rule.Category}
+	}
+	if rule.SellerID != nil {
+		update["$setOnInsert"] = bson.M{"sellerID": *rule.SellerID}
+	}
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	var updatedRule *model.CommissionRule
+	err := r.ruleCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&updatedRule)
+	if err != nil {
+		return nil, err
+	}
+	return converter.CommissionRuleModelToDTO(updatedRule)
+}
+
+func (r CommissionRepository) DeleteRule(ruleID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	result, err := r.ruleCollection.DeleteOne(ctx, bson.M{"_id": ruleID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r CommissionRepository) findRules(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]dto.CommissionRule, error) {
+	cursor, err := r.ruleCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	rules := []dto.CommissionRule{}
+	for cursor.Next(ctx) {
+		var ruleModel *model.CommissionRule
+		if err = cursor.Decode(&ruleModel); err != nil {
+			return nil, err
+		}
+		ruleDTO, err := converter.CommissionRuleModelToDTO(ruleModel)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, *ruleDTO)
+	}
+	return rules, nil
+}
@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/model"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/converter"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IConversationRepository interface {
+	GetOrCreateConversation(conversation *model.Conversation) (*dto.Conversation, error)
+	GetConversationByID(conversationID primitive.ObjectID) (*dto.Conversation, error)
+	GetConversationsByUserID(userID primitive.ObjectID) ([]dto.Conversation, error)
+	CreateMessage(conversation *dto.Conversation, message *model.Message) (*dto.Message, error)
+	GetMessages(conversationID primitive.ObjectID, before primitive.ObjectID, limit int64) ([]dto.Message, error)
+	MarkRead(conversation *dto.Conversation, userID primitive.ObjectID) error
+	CountUnread(userID primitive.ObjectID) (int, error)
+}
+
+type ConversationRepository struct {
+	conversationCollection *mongo.Collection
+	messageCollection      *mongo.Collection
+}
+
+func NewConversationRepository(db *mongo.Database, conversationCollectionName string, messageCollectionName string) IConversationRepository {
+	conversationCollection := db.Collection(conversationCollectionName)
+	messageCollection := db.Collection(messageCollectionName)
+
+	// A buyer and a seller share one conversation per product or order
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	_, err := conversationCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "buyer_id", Value: 1}, {Key: "seller_id", Value: 1},
+				{Key: "product_id", Value: 1}, {Key: "order_id", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "buyer_id", Value: 1}, {Key: "updated_at", Value: -1}}},
+		{Keys: bson.D{{Key: "seller_id", Value: 1}, {Key: "updated_at", Value: -1}}},
+	})
+	if err != nil {
+		log.Printf("failed to create conversation indexes: %v", err)
+	}
+	_, err = messageCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "conversation_id", Value: 1}, {Key: "_id", Value: -1}},
+	})
+	if err != nil {
+		log.Printf("failed to create message indexes: %v", err)
+	}
+
+	return ConversationRepository{
+		conversationCollection: conversationCollection,
+		messageCollection:      messageCollection,
+	}
+}
+
+// GetOrCreateConversation returns the conversation between the buyer and the
+// seller about the product or order, starting it if there isn't one yet
+func (r ConversationRepository) GetOrCreateConversation(conversation *model.Conversation) (*dto.Conversation, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	// The upsert copies the buyer, seller and scope from the filter
+	filter := bson.M{
+		"buyer_id":   conversation.BuyerID,
+		"seller_id":  conversation.SellerID,
+		"product_id": scope(conversation.ProductID),
+		"order_id":   scope(conversation.OrderID),
+	}
+	now := time.Now()
+	insert := bson.M{
+		"_id":           primitive.NewObjectID(),
+		"buyer_unread":  0,
+		"seller_unread": 0,
+		"created_at":    now,
+		"updated_at":    now,
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var existing model.Conversation
+	err := r.conversationCollection.FindOneAndUpdate(ctx, filter, bson.M{"$setOnInsert": insert}, opts).Decode(&existing)
+	if mongo.IsDuplicateKeyError(err) {
+		// Started by a concurrent request
+		err = r.conversationCollection.FindOne(ctx, filter).Decode(&existing)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return converter.ConversationModelToDTO(&existing)
+}
+
+func (r ConversationRepository) GetConversationByID(conversationID primitive.ObjectID) (*dto.Conversation, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	var conversation model.Conversation
+	if err := r.conversationCollection.FindOne(ctx, bson.M{"_id": conversationID}).Decode(&conversation); err != nil {
+		return nil, err
+	}
+	return converter.ConversationModelToDTO(&conversation)
+}
+
+// GetConversationsByUserID returns the conversations of a buyer or a seller,
+// the most recently active first
+func (r ConversationRepository) GetConversationsByUserID(userID primitive.ObjectID) ([]dto.Conversation, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	filter := bson.M{"$or": bson.A{bson.M{"buyer_id": userID}, bson.M{"seller_id": userID}}}
+	opts := options.Find().SetSort(bson.D{{Key: "updated_at", Value: -1}})
+	cursor, err := r.conversationCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	conversations := []dto.Conversation{}
+	for cursor.Next(ctx) {
+		var conversation model.Conversation
+		if err := cursor.Decode(&conversation); err != nil {
+			return nil, err
+		}
+		conversationDTO, err := converter.ConversationModelToDTO(&conversation)
+		if err != nil {
+			return nil, err
+		}
+		conversations = append(conversations, *conversationDTO)
+	}
+	return conversations, cursor.Err()
+}
+
+// CreateMessage saves a message and counts it as unread for the party that
+// didn't send it
+func (r ConversationRepository) CreateMessage(conversation *dto.Conversation, message *model.Message) (*dto.Message, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	message.MessageID = primitive.NewObjectID()
+	message.ConversationID = conversation.ConversationID
+	message.CreatedAt = time.Now()
+	if _, err := r.messageCollection.InsertOne(ctx, message); err != nil {
+		return nil, err
+	}
+
+	unread := "seller_unread"
+	if message.SenderID == conversation.SellerID {
+		unread = "buyer_unread"
+	}
+	update := bson.M{
+		"$set": bson.M{"last_message": message, "updated_at": message.CreatedAt},
+		"$inc": bson.M{unread: 1},
+	}
+	if _, err := r.conversationCollection.UpdateByID(ctx, conversation.ConversationID, update); err != nil {
+		return nil, err
+	}
+	return converter.MessageModelToDTO(message)
+}
+
+// GetMessages returns up to limit messages of a conversation sent before the
+// given message, newest first. A zero before starts from the latest message.
+func (r ConversationRepository) GetMessages(conversationID primitive.ObjectID, before primitive.ObjectID, limit int64) ([]dto.Message, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	filter := bson.M{"conversation_id": conversationID}
+	if !before.IsZero() {
+		filter["_id"] = bson.M{"$lt": before}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}}).SetLimit(limit)
+	cursor, err := r.messageCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	messages := []dto.Message{}
+	for cursor.Next(ctx) {
+		var message model.Message
+		if err := cursor.Decode(&message); err != nil {
+			return nil, err
+		}
+		messageDTO, err := converter.MessageModelToDTO(&message)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, *messageDTO)
+	}
+	return messages, cursor.Err()
+}
+
+// MarkRead clears the unread count of the user's side of the conversation
+func (r ConversationRepository) MarkRead(conversation *dto.Conversation, userID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	unread := "buyer_unread"
+	if userID == conversation.SellerID {
+		unread = "seller_unread"
+	}
+	_, err := r.conversationCollection.UpdateByID(ctx, conversation.ConversationID, bson.M{"$set": bson.M{unread: 0}})
+	return err
+}
+
+// CountUnread adds up the unread messages across the user's conversations
+func (r ConversationRepository) CountUnread(userID primitive.ObjectID) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"$or": bson.A{bson.M{"buyer_id": userID}, bson.M{"seller_id": userID}}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": nil,
+			"unread": bson.M{"$sum": bson.M{"$cond": bson.A{
+				bson.M{"$eq": bson.A{"$buyer_id", userID}}, "$buyer_unread", "$seller_unread",
+			}}},
+		}}},
+	}
+	cursor, err := r.conversationCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var result []struct {
+		Unread int `bson:"unread"`
+	}
+	if err := cursor.All(ctx, &result); err != nil {
+		return 0, err
+	}
+	if len(result) == 0 {
+		return 0, nil
+	}
+	return result[0].Unread, nil
+}
+
+// scope matches a conversation's product or order, or its absence when zero
+func scope(id primitive.ObjectID) interface{} {
+	if id.IsZero() {
+		return bson.M{"$exists": false}
+	}
+	return id
+}
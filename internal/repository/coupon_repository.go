@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/model"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/converter"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ICouponRepository interface {
+	CreateCoupon(coupon *model.Coupon) (*dto.Coupon, error)
+	GetCouponByID(couponID primitive.ObjectID) (*dto.Coupon, error)
+	GetCouponByCode(code string) (*dto.Coupon, error)
+	GetCouponsBySellerID(sellerID primitive.ObjectID) ([]dto.Coupon, error)
+	DeactivateCoupon(couponID primitive.ObjectID) error
+	CountRedemptions(couponID primitive.ObjectID, buyerID primitive.ObjectID) (int64, error)
+	ReserveUsage(couponID primitive.ObjectID) (bool, error)
+	ReleaseUsage(couponID primitive.ObjectID) error
+	CreateRedemption(redemption *model.CouponRedemption, perBuyerLimit int) (primitive.ObjectID, bool, error)
+	DeleteRedemption(redemptionID primitive.ObjectID) error
+}
+
+type CouponRepository struct {
+	couponCollection     *mongo.Collection
+	redemptionCollection *mongo.Collection
+}
+
+func NewCouponRepository(db *mongo.Database, couponCollectionName string, redemptionCollectionName string) ICouponRepository {
+	couponCollection := db.Collection(couponCollectionName)
+	redemptionCollection := db.Collection(redemptionCollectionName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	_, err := couponCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "code", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "sellerID", Value: 1}}},
+	})
+	if err != nil {
+		log.Printf("failed to create coupon indexes: %v", err)
+	}
+	_, err = redemptionCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "couponID", Value: 1}, {Key: "buyerID", Value: 1}}},
+		// A buyer's uses of a limited coupon are numbered, so two
+		// concurrent checkouts can't both take the last one
+		{
+			Keys: bson.D{{Key: "couponID", Value: 1}, {Key: "buyerID", Value: 1}, {Key: "seq", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"seq": bson.M{"$exists": true}}),
+		},
+	})
+	if err != nil {
+		log.Printf("failed to create coupon redemption indexes: %v", err)
+	}
+
+	return CouponRepository{
+		couponCollection:     couponCollection,
+		redemptionCollection: redemptionCollection,
+	}
+}
+
+func (r CouponRepository) CreateCoupon(coupon *model.Coupon) (*dto.Coupon, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	coupon.CouponID = primitive.NewObjectID()
+	coupon.CreatedAt = time.Now()
+	result, err := r.couponCollection.InsertOne(ctx, coupon)
+	if err != nil {
+		return nil, err
+	}
+
+	var newCoupon *model.Coupon
+	err = r.couponCollection.FindOne(ctx, bson.M{"_id": result.InsertedID}).Decode(&newCoupon)
+	if err != nil {
+		return nil, err
+	}
+	return converter.CouponModelToDTO(newCoupon)
+}
+
+func (r CouponRepository) GetCouponByID(couponID primitive.ObjectID) (*dto.Coupon, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	var coupon *model.Coupon
+	err := r.couponCollection.FindOne(ctx, bson.M{"_id": couponID}).Decode(&coupon)
+	if err != nil {
+		return nil, err
+	}
+	return converter.CouponModelToDTO(coupon)
+}
+
+func (r CouponRepository) GetCouponByCode(code string) (*dto.Coupon, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	var coupon *model.Coupon
+	err := r.couponCollection.FindOne(ctx, bson.M{"code": code}).Decode(&coupon)
+	if err != nil {
+		return nil, err
+	}
+	return converter.CouponModelToDTO(coupon)
+}
+
+func (r CouponRepository) GetCouponsBySellerID(sellerID primitive.ObjectID) ([]dto.Coupon, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.couponCollection.Find(ctx, bson.M{"sellerID": sellerID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	couponList := []dto.Coupon{}
+	for cursor.Next(ctx) {
+		var couponModel *model.Coupon
+		if err = cursor.Decode(&couponModel); err != nil {
+			return nil, err
+		}
+		couponDTO, err := converter.CouponModelToDTO(couponModel)
+		if err != nil {
+			return nil, err
+		}
+		couponList = append(couponList, *couponDTO)
+	}
+	return couponList, nil
+}
+
+func (r CouponRepository) DeactivateCoupon(couponID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	result, err := r.couponCollection.UpdateOne(ctx, bson.M{"_id": couponID}, bson.M{"$set": bson.M{"active": false}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r CouponRepository) CountRedemptions(couponID primitive.ObjectID, buyerID primitive.ObjectID) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	return r.redemptionCollection.CountDocuments(ctx, bson.M{"couponID": couponID, "buyerID": buyerID})
+}
+
+// ReserveUsage takes one use of the coupon. It reports false once the total
+// usage limit has been reached, the check and increment are a single update so
+// concurrent checkouts can't overshoot the limit.
+func (r CouponRepository) ReserveUsage(couponID primitive.ObjectID) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"_id": couponID,
+		"$or": bson.A{
+			bson.M{"usageLimit": 0},
+			bson.M{"$expr": bson.M{"$lt": bson.A{"$usedCount", "$usageLimit"}}},
+		},
+	}
+	result, err := r.couponCollection.UpdateOne(ctx, filter, bson.M{"$inc": bson.M{"usedCount": 1}})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+func (r CouponRepository) ReleaseUsage(couponID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": couponID, "usedCount": bson.M{"$gt": 0}}
+	_, err := r.couponCollection.UpdateOne(ctx, filter, bson.M{"$inc": bson.M{"usedCount": -1}})
+	return err
+}
+
+// CreateRedemption records the buyer's use of the coupon. With a per buyer
+// limit it takes the first free use number up to the limit and reports false
+// when the buyer has used them all.
+func (r CouponRepository) CreateRedemption(redemption *model.CouponRedemption, perBuyerLimit int) (primitive.ObjectID, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	redemption.RedemptionID = primitive.NewObjectID()
+	redemption.CreatedAt = time.Now()
+	if perBuyerLimit <= 0 {
+		if _, err := r.redemptionCollection.InsertOne(ctx, redemption); err != nil {
+			return primitive.NilObjectID, false, err
+		}
+		return redemption.RedemptionID, true, nil
+	}
+
+	for seq := 1; seq <= perBuyerLimit; seq++ {
+		redemption.Seq = seq
+		_, err := r.redemptionCollection.InsertOne(ctx, redemption)
+		if mongo.IsDuplicateKeyError(err) {
+			continue
+		}
+		if err != nil {
+			return primitive.NilObjectID, false, err
+		}
+		return redemption.RedemptionID, true, nil
+	}
+	return primitive.NilObjectID, false, nil
+}
+
+func (r CouponRepository) DeleteRedemption(redemptionID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	_, err := r.redemptionCollection.DeleteOne(ctx, bson.M{"_id": redemptionID})
+	return err
+}
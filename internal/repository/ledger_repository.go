@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/model"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/converter"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ILedgerRepository interface {
+	CreateEntries(entries []model.LedgerEntry) error
+	GetEntries(from time.Time, to time.Time) ([]dto.LedgerEntry, error)
+}
+
+type LedgerRepository struct {
+	ledgerCollection *mongo.Collection
+}
+
+func NewLedgerRepository(db *mongo.Database, collectionName string) ILedgerRepository {
+	collection := db.Collection(collectionName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "createdAt", Value: 1}}},
+		{Keys: bson.D{{Key: "orderID", Value: 1}}},
+		// An order is booked and reversed once per entry type however often
+		// its event is delivered. Entries from before reversals were flagged
+		// aren't covered.
+		{
+			Keys: bson.D{{Key: "orderID", Value: 1}, {Key: "type", Value: 1}, {Key: "reversal", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"reversal": bson.M{"$exists": true}}),
+		},
+	})
+	if err != nil {
+		log.Printf("failed to create ledger indexes: %v", err)
+	}
+
+	return LedgerRepository{
+		ledgerCollection: collection,
+	}
+}
+
+// CreateEntries books the entries, skipping those already booked for their
+// order
+func (r LedgerRepository) CreateEntries(entries []model.LedgerEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	documents := make([]interface{}, 0, len(entries))
+	now := time.Now()
+	for _, entry := range entries {
+		entry.CreatedAt = now
+		documents = append(documents, entry)
+	}
+	_, err := r.ledgerCollection.InsertMany(ctx, documents, options.InsertMany().SetOrdered(false))
+	if err != nil && !onlyDuplicateKeys(err) {
+		return err
+	}
+	return nil
+}
+
+func (r LedgerRepository) GetEntries(from time.Time, to time.Time) ([]dto.LedgerEntry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	filter := bson.M{"createdAt": bson.M{"$gte": from, "$lt": to}}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	cursor, err := r.ledgerCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	entries := []dto.LedgerEntry{}
+	for cursor.Next(ctx) {
+		var entryModel *model.LedgerEntry
+		if err = cursor.Decode(&entryModel); err != nil {
+			return nil, err
+		}
+		entryDTO, err := converter.LedgerEntryModelToDTO(entryModel)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *entryDTO)
+	}
+	return entries, nil
+}
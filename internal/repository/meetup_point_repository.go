@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/model"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/converter"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/geo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IMeetupPointRepository interface {
+	CreateMeetupPoint(point *model.MeetupPoint) (*dto.MeetupPoint, error)
+	DeleteMeetupPoint(meetupPointID primitive.ObjectID) error
+	GetMeetupPointsNear(near geo.Point, radius float64, limit int64) ([]dto.MeetupPoint, error)
+}
+
+type MeetupPointRepository struct {
+	meetupPointCollection *mongo.Collection
+}
+
+func NewMeetupPointRepository(db *mongo.Database, collectionName string) IMeetupPointRepository {
+	meetupPointCollection := db.Collection(collectionName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	_, err := meetupPointCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "location", Value: "2dsphere"}},
+	})
+	if err != nil {
+		log.Printf("failed to create meetup point indexes: %v", err)
+	}
+
+	return MeetupPointRepository{
+		meetupPointCollection: meetupPointCollection,
+	}
+}
+
+func (r MeetupPointRepository) CreateMeetupPoint(point *model.MeetupPoint) (*dto.MeetupPoint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	point.MeetupPointID = primitive.NewObjectID()
+	point.CreatedAt = time.Now()
+	if _, err := r.meetupPointCollection.InsertOne(ctx, point); err != nil {
+		return nil, err
+	}
+
+	var newPoint *model.MeetupPoint
+	err := r.meetupPointCollection.FindOne(ctx, bson.M{"_id": point.MeetupPointID}).Decode(&newPoint)
+	if err != nil {
+		return nil, err
+	}
+	return converter.MeetupPointModelToDTO(newPoint)
+}
+
+func (r MeetupPointRepository) DeleteMeetupPoint(meetupPointID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	result, err := r.meetupPointCollection.DeleteOne(ctx, bson.M{"_id": meetupPointID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// GetMeetupPointsNear returns up to limit meet-up points within radius meters
+// of near, closest first
+func (r MeetupPointRepository) GetMeetupPointsNear(near geo.Point, radius float64, limit int64) ([]dto.MeetupPoint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	filter := bson.M{"location": bson.M{"$nearSphere": bson.M{
+		"$geometry":    near,
+		"$maxDistance": radius,
+	}}}
+	cursor, err := r.meetupPointCollection.Find(ctx, filter, options.Find().SetLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	points := []dto.MeetupPoint{}
+	for cursor.Next(ctx) {
+		var pointModel *model.MeetupPoint
+		if err = cursor.Decode(&pointModel); err != nil {
+			return nil, err
+		}
+		pointDTO, err := converter.MeetupPointModelToDTO(pointModel)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, *pointDTO)
+	}
+	return points, nil
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/model"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/converter"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type INotificationRepository interface {
+	CreateNotification(notification *model.Notification) error
+	GetNotificationsByUserID(userID primitive.ObjectID, unreadOnly bool, limit int64) ([]dto.Notification, error)
+	MarkRead(userID primitive.ObjectID, notificationID primitive.ObjectID) error
+	MarkAllRead(userID primitive.ObjectID) error
+	GetPreference(userID primitive.ObjectID) (*dto.NotificationPreference, error)
+	SavePreference(preference *model.NotificationPreference) (*dto.NotificationPreference, error)
+}
+
+type NotificationRepository struct {
+	notificationCollection *mongo.Collection
+	preferenceCollection   *mongo.Collection
+}
+
+func NewNotificationRepository(db *mongo.Database, notificationCollectionName string, preferenceCollectionName string) INotificationRepository {
+	notificationCollection := db.Collection(notificationCollectionName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	_, err := notificationCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		log.Printf("failed to create notification indexes: %v", err)
+	}
+
+	return NotificationRepository{
+		notificationCollection: notificationCollection,
+		preferenceCollection:   db.Collection(preferenceCollectionName),
+	}
+}
+
+// CreateNotification adds the notification to the user's inbox. The
+// notification keeps its ID so a retried delivery is only stored once.
+func (r NotificationRepository) CreateNotification(notification *model.Notification) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	if notification.NotificationID.IsZero() {
+		notification.NotificationID = primitive.NewObjectID()
+	}
+	if notification.CreatedAt.IsZero() {
+		notification.CreatedAt = time.Now()
+	}
+	_, err := r.notificationCollection.InsertOne(ctx, notification)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return err
+}
+
+// GetNotificationsByUserID returns the user's latest notifications, newest first
+func (r NotificationRepository) GetNotificationsByUserID(userID primitive.ObjectID, unreadOnly bool, limit int64) ([]dto.Notification, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	filter := bson.M{"user_id": userID}
+	if unreadOnly {
+		filter["read_at"] = bson.M{"$exists": false}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(limit)
+	cursor, err := r.notificationCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	notifications := []dto.Notification{}
+	for cursor.Next(ctx) {
+		var notification model.Notification
+		if err := cursor.Decode(&notification); err != nil {
+			return nil, err
+		}
+		notificationDTO, err := converter.NotificationModelToDTO(&notification)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, *notificationDTO)
+	}
+	return notifications, cursor.Err()
+}
+
+// MarkRead marks one of the user's notifications read, it returns
+// mongo.ErrNoDocuments when the user has no such notification
+func (r NotificationRepository) MarkRead(userID primitive.ObjectID, notificationID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": notificationID, "user_id": userID}
+	// Notifications read before keep the time they were first read
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"read_at": bson.M{"$ifNull": bson.A{"$read_at", "$$NOW"}},
+	}}}}
+	res, err := r.notificationCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r NotificationRepository) MarkAllRead(userID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	filter := bson.M{"user_id": userID, "read_at": bson.M{"$exists": false}}
+	_, err := r.notificationCollection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"read_at": time.Now()}})
+	return err
+}
+
+// GetPreference returns mongo.ErrNoDocuments for users who never set their
+// preferences
+func (r NotificationRepository) GetPreference(userID primitive.ObjectID) (*dto.NotificationPreference, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	var preference model.NotificationPreference
+	if err := r.preferenceCollection.FindOne(ctx, bson.M{"_id": userID}).Decode(&preference); err != nil {
+		return nil, err
+	}
+	return converter.NotificationPreferenceModelToDTO(&preference)
+}
+
+func (r NotificationRepository) SavePreference(preference *model.NotificationPreference) (*dto.NotificationPreference, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	preference.UpdatedAt = time.Now()
+	opts := options.Replace().SetUpsert(true)
+	if _, err := r.preferenceCollection.ReplaceOne(ctx, bson.M{"_id": preference.UserID}, preference, opts); err != nil {
+		return nil, err
+	}
+	return converter.NotificationPreferenceModelToDTO(preference)
+}
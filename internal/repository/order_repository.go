@@ -7,9 +7,11 @@ import (
 	"time"
 
 	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/eventtype"
 	"github.com/Dongy-s-Advanture/back-end/internal/enum/userrole"
 	"github.com/Dongy-s-Advanture/back-end/internal/model"
 	"github.com/Dongy-s-Advanture/back-end/pkg/utils/converter"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/money"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -17,10 +19,13 @@ import (
 
 type IOrderRepository interface {
 	CreateOrder(order *model.Order) (*dto.Order, error)
+	GetOrderByID(orderID primitive.ObjectID) (*dto.Order, error)
 	GetOrdersByUserID(userID primitive.ObjectID, userType userrole.UserType) ([]dto.Order, error)
 	DeleteOrderByOrderID(orderID primitive.ObjectID) error
 	UpdateOrder(orderID primitive.ObjectID, updatedOrder *model.Order) (*dto.Order, error)
 	UpdateOrderStatus(orderID primitive.ObjectID, orderStatus int) (int, error)
+	AdvanceOrderStatus(orderID primitive.ObjectID, from int, to int) (bool, error)
+	RecordOrderRefund(orderID primitive.ObjectID, refund money.Money) error
 }
 
 type OrderRepository struct {
@@ -32,9 +37,35 @@ func NewOrderRepository(db *mongo.Database, collectionName string) IOrderReposit
 		orderCollection: db.Collection(collectionName),
 	}
 }
+
+// CreateOrder stores the order along with its OrderCreated event, and a
+// PaymentSucceeded event when it was paid by card
 func (r OrderRepository) CreateOrder(order *model.Order) (*dto.Order, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
+
+	created, err := newOutboxEvent(eventtype.ORDER_CREATED, order.OrderID, dto.OrderCreatedEvent{
+		OrderID:  order.OrderID,
+		BuyerID:  order.BuyerID,
+		SellerID: order.SellerID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	order.Outbox = append(order.Outbox, created)
+	if order.ChargeID != "" {
+		paid, err := newOutboxEvent(eventtype.PAYMENT_SUCCEEDED, order.OrderID, dto.PaymentSucceededEvent{
+			OrderID:  order.OrderID,
+			BuyerID:  order.BuyerID,
+			ChargeID: order.ChargeID,
+			Amount:   order.TotalPrice,
+		})
+		if err != nil {
+			return nil, err
+		}
+		order.Outbox = append(order.Outbox, paid)
+	}
+
 	result, err := r.orderCollection.InsertOne(ctx, order)
 	if err != nil {
 		return nil, err
@@ -47,6 +78,18 @@ func (r OrderRepository) CreateOrder(order *model.Order) (*dto.Order, error) {
 	return converter.OrderModelToDTO(newOrder)
 }
 
+func (r OrderRepository) GetOrderByID(orderID primitive.ObjectID) (*dto.Order, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	var order *model.Order
+	err := r.orderCollection.FindOne(ctx, bson.M{"_id": orderID}).Decode(&order)
+	if err != nil {
+		return nil, err
+	}
+	return converter.OrderModelToDTO(order)
+}
+
 func (r OrderRepository) GetOrdersByUserID(userID primitive.ObjectID, userType userrole.UserType) ([]dto.Order, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	var orders []dto.Order
@@ -126,10 +169,18 @@ func (r OrderRepository) UpdateOrderStatus(orderID primitive.ObjectID, orderStat
 
 	filter := bson.M{"_id": orderID}
 
+	changed, err := newOutboxEvent(eventtype.ORDER_STATUS_CHANGED, orderID, dto.OrderStatusChangedEvent{
+		OrderID: orderID,
+		To:      orderStatus,
+	})
+	if err != nil {
+		return 0, err
+	}
 	update := bson.M{
 		"$set": bson.M{
 			"status": orderStatus,
 		},
+		"$push": bson.M{"outbox": changed},
 	}
 
 	result := r.orderCollection.FindOneAndUpdate(ctx, filter, update)
@@ -142,3 +193,54 @@ func (r OrderRepository) UpdateOrderStatus(orderID primitive.ObjectID, orderStat
 
 	return orderStatus, nil
 }
+
+// AdvanceOrderStatus moves the order to status to only if it is still in
+// status from, reporting whether it moved. Like UpdateOrderStatus it stages
+// an OrderStatusChanged event with the change.
+func (r OrderRepository) AdvanceOrderStatus(orderID primitive.ObjectID, from int, to int) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	changed, err := newOutboxEvent(eventtype.ORDER_STATUS_CHANGED, orderID, dto.OrderStatusChangedEvent{
+		OrderID: orderID,
+		From:    &from,
+		To:      to,
+	})
+	if err != nil {
+		return false, err
+	}
+	filter := bson.M{"_id": orderID, "status": from}
+	update := bson.M{
+		"$set":  bson.M{"status": to},
+		"$push": bson.M{"outbox": changed},
+	}
+
+	result, err := r.orderCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, fmt.Errorf("failed to update order status: %w", err)
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// RecordOrderRefund stages an OrderRefunded event on the order, so taking the
+// refund back from the seller and the ledger is retried until it goes through
+func (r OrderRepository) RecordOrderRefund(orderID primitive.ObjectID, refund money.Money) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	refunded, err := newOutboxEvent(eventtype.ORDER_REFUNDED, orderID, dto.OrderRefundedEvent{
+		OrderID: orderID,
+		Refund:  refund,
+	})
+	if err != nil {
+		return err
+	}
+	result, err := r.orderCollection.UpdateOne(ctx, bson.M{"_id": orderID}, bson.M{"$push": bson.M{"outbox": refunded}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
@@ -0,0 +1,273 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/outboxstatus"
+	"github.com/Dongy-s-Advanture/back-end/internal/model"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/converter"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// publishedRetention is how long published events are kept for debugging
+const publishedRetention = 7 * 24 * time.Hour
+
+type IOutboxRepository interface {
+	CollectStaged(limit int64) (int, error)
+	ClaimNext(now time.Time, lease time.Duration) (*dto.OutboxEvent, error)
+	MarkDelivered(eventID primitive.ObjectID, subscriber string) error
+	MarkPublished(eventID primitive.ObjectID, at time.Time) error
+	ScheduleRetry(eventID primitive.ObjectID, next time.Time, lastError string) error
+	MarkDead(eventID primitive.ObjectID, lastError string) error
+	GetDeadEvents(limit int64) ([]dto.OutboxEvent, error)
+	Requeue(eventID primitive.ObjectID, now time.Time) (bool, error)
+}
+
+type OutboxRepository struct {
+	outboxCollection *mongo.Collection
+	// Collections whose documents stage events in their outbox field
+	sourceCollections []*mongo.Collection
+}
+
+func NewOutboxRepository(db *mongo.Database, collectionName string, sourceCollectionNames ...string) IOutboxRepository {
+	outboxCollection := db.Collection(collectionName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	_, err := outboxCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+		// Only published events have the field, so pending and dead
+		// events are never expired
+		{
+			Keys:    bson.D{{Key: "published_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(publishedRetention.Seconds())),
+		},
+	})
+	if err != nil {
+		log.Printf("failed to create outbox indexes: %v", err)
+	}
+
+	var sourceCollections []*mongo.Collection
+	for _, name := range sourceCollectionNames {
+		source := db.Collection(name)
+		_, err := source.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "outbox._id", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		})
+		if err != nil {
+			log.Printf("failed to create outbox index on %s: %v", name, err)
+		}
+		sourceCollections = append(sourceCollections, source)
+	}
+
+	return OutboxRepository{
+		outboxCollection:  outboxCollection,
+		sourceCollections: sourceCollections,
+	}
+}
+
+// newOutboxEvent builds an event to stage on the document it is about
+func newOutboxEvent(eventType string, aggregateID primitive.ObjectID, payload interface{}) (model.OutboxEvent, error) {
+	raw, err := bson.Marshal(payload)
+	if err != nil {
+		return model.OutboxEvent{}, err
+	}
+	now := time.Now()
+	return model.OutboxEvent{
+		EventID:       primitive.NewObjectID(),
+		Type:          eventType,
+		AggregateID:   aggregateID,
+		Payload:       raw,
+		OccurredAt:    now,
+		Status:        outboxstatus.PENDING,
+		NextAttemptAt: now,
+	}, nil
+}
+
+// CollectStaged moves events staged on up to limit documents of each source
+// collection into the outbox. Events keep their ID, so events left behind by
+// an interrupted run are only stored once.
+func (r OutboxRepository) CollectStaged(limit int64) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	collected := 0
+	for _, source := range r.sourceCollections {
+		cursor, err := source.Find(ctx,
+			bson.M{"outbox._id": bson.M{"$exists": true}},
+			options.Find().SetProjection(bson.M{"outbox": 1}).SetLimit(limit),
+		)
+		if err != nil {
+			return collected, err
+		}
+		var staged []struct {
+			ID     primitive.ObjectID  `bson:"_id"`
+			Outbox []model.OutboxEvent `bson:"outbox"`
+		}
+		if err := cursor.All(ctx, &staged); err != nil {
+			return collected, err
+		}
+
+		for _, doc := range staged {
+			events := make([]interface{}, 0, len(doc.Outbox))
+			eventIDs := make([]primitive.ObjectID, 0, len(doc.Outbox))
+			for _, event := range doc.Outbox {
+				events = append(events, event)
+				eventIDs = append(eventIDs, event.EventID)
+			}
+			if len(events) == 0 {
+				continue
+			}
+			_, err := r.outboxCollection.InsertMany(ctx, events, options.InsertMany().SetOrdered(false))
+			if err != nil && !onlyDuplicateKeys(err) {
+				return collected, err
+			}
+			// Pulled by ID so events staged in the meantime stay
+			_, err = source.UpdateByID(ctx, doc.ID, bson.M{
+				"$pull": bson.M{"outbox": bson.M{"_id": bson.M{"$in": eventIDs}}},
+			})
+			if err != nil {
+				return collected, err
+			}
+			collected += len(eventIDs)
+		}
+	}
+	return collected, nil
+}
+
+// onlyDuplicateKeys reports whether every write of a bulk insert failed
+// because the document was already there
+func onlyDuplicateKeys(err error) bool {
+	var bulkErr mongo.BulkWriteException
+	if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil || len(bulkErr.WriteErrors) == 0 {
+		return false
+	}
+	for _, writeErr := range bulkErr.WriteErrors {
+		if !mongo.IsDuplicateKeyError(writeErr) {
+			return false
+		}
+	}
+	return true
+}
+
+// ClaimNext takes the pending event that has been due the longest, counting
+// the attempt. The event is not due again for lease, so it is retried if the
+// relay dies before settling it.
+func (r OutboxRepository) ClaimNext(now time.Time, lease time.Duration) (*dto.OutboxEvent, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"status":          outboxstatus.PENDING,
+		"next_attempt_at": bson.M{"$lte": now},
+	}
+	update := bson.M{
+		"$set": bson.M{"next_attempt_at": now.Add(lease)},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var event model.OutboxEvent
+	if err := r.outboxCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&event); err != nil {
+		return nil, err
+	}
+	return converter.OutboxEventModelToDTO(&event)
+}
+
+// MarkDelivered records that subscriber handled the event
+func (r OutboxRepository) MarkDelivered(eventID primitive.ObjectID, subscriber string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	_, err := r.outboxCollection.UpdateByID(ctx, eventID, bson.M{
+		"$addToSet": bson.M{"delivered": subscriber},
+	})
+	return err
+}
+
+func (r OutboxRepository) MarkPublished(eventID primitive.ObjectID, at time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	_, err := r.outboxCollection.UpdateByID(ctx, eventID, bson.M{
+		"$set":   bson.M{"status": outboxstatus.PUBLISHED, "published_at": at},
+		"$unset": bson.M{"last_error": ""},
+	})
+	return err
+}
+
+func (r OutboxRepository) ScheduleRetry(eventID primitive.ObjectID, next time.Time, lastError string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	_, err := r.outboxCollection.UpdateByID(ctx, eventID, bson.M{
+		"$set": bson.M{"next_attempt_at": next, "last_error": lastError},
+	})
+	return err
+}
+
+// MarkDead parks the event in the dead letter until an admin requeues it
+func (r OutboxRepository) MarkDead(eventID primitive.ObjectID, lastError string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	_, err := r.outboxCollection.UpdateByID(ctx, eventID, bson.M{
+		"$set": bson.M{"status": outboxstatus.DEAD, "last_error": lastError},
+	})
+	return err
+}
+
+// GetDeadEvents returns the latest dead events, newest first
+func (r OutboxRepository) GetDeadEvents(limit int64) ([]dto.OutboxEvent, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "occurred_at", Value: -1}}).SetLimit(limit)
+	cursor, err := r.outboxCollection.Find(ctx, bson.M{"status": outboxstatus.DEAD}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var events []model.OutboxEvent
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+
+	res := make([]dto.OutboxEvent, 0, len(events))
+	for i := range events {
+		event, err := converter.OutboxEventModelToDTO(&events[i])
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *event)
+	}
+	return res, nil
+}
+
+// Requeue makes a dead event pending again with fresh attempts, reporting
+// whether there was such an event. Subscribers that already handled it are
+// still skipped.
+func (r OutboxRepository) Requeue(eventID primitive.ObjectID, now time.Time) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": eventID, "status": outboxstatus.DEAD}
+	update := bson.M{"$set": bson.M{
+		"status":          outboxstatus.PENDING,
+		"attempts":        0,
+		"next_attempt_at": now,
+	}}
+	result, err := r.outboxCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
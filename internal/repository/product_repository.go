@@ -2,9 +2,11 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/productstatus"
 	"github.com/Dongy-s-Advanture/back-end/internal/model"
 	"github.com/Dongy-s-Advanture/back-end/pkg/utils/converter"
 	"go.mongodb.org/mongo-driver/bson"
@@ -15,13 +17,22 @@ import (
 type IProductRepository interface {
 	GetProductByID(productID primitive.ObjectID) (*dto.Product, error)
 	GetProductsBySellerID(sellerID primitive.ObjectID) ([]dto.Product, error)
+	GetProductsBySellerIDs(sellerIDs []primitive.ObjectID) ([]dto.Product, error)
 	GetProducts() ([]dto.Product, error)
 	CreateProduct(product *model.Product) (*dto.Product, error)
 	UpdateProduct(productID primitive.ObjectID, updatedProduct *model.Product) (*dto.Product, error)
 	DeleteProduct(productID primitive.ObjectID) error
 	UpdateProductAmount(productID primitive.ObjectID, amount int) error
+	RestockProduct(productID primitive.ObjectID, amount int) error
+	GetSellerListings(sellerID primitive.ObjectID, status *int) ([]dto.Product, error)
+	UpdateProductStatus(productID primitive.ObjectID, status int) (*dto.Product, error)
+	ArchiveProduct(productID primitive.ObjectID) (*dto.Product, error)
+	RestoreProduct(productID primitive.ObjectID, status int) (*dto.Product, error)
+	UpdateRating(productID primitive.ObjectID, removed []int, added []int) error
 }
 
+var ErrOutOfStock = errors.New("not enough stock")
+
 type ProductRepository struct {
 	productCollection *mongo.Collection
 }
@@ -49,35 +60,44 @@ func (r ProductRepository) GetProductsBySellerID(sellerID primitive.ObjectID) ([
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
-	var productList []dto.Product
+	filter := listedFilter()
+	filter["sellerID"] = sellerID
+	return r.findProducts(ctx, filter)
+}
 
-	dataList, err := r.productCollection.Find(ctx, bson.M{"sellerID": sellerID})
-	if err != nil {
-		return nil, err
-	}
-	defer dataList.Close(ctx)
-	for dataList.Next(ctx) {
-		var productModel *model.Product
-		if err = dataList.Decode(&productModel); err != nil {
-			return nil, err
-		}
-		productDTO, productErr := converter.ProductModelToDTO(productModel)
-		if productErr != nil {
-			return nil, err
-		}
-		productList = append(productList, *productDTO)
-	}
+func (r ProductRepository) GetProductsBySellerIDs(sellerIDs []primitive.ObjectID) ([]dto.Product, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
 
-	return productList, nil
+	filter := listedFilter()
+	filter["sellerID"] = bson.M{"$in": sellerIDs}
+	return r.findProducts(ctx, filter)
+}
+
+// GetSellerListings returns all of a seller's products, including drafts and
+// deleted ones, optionally narrowed down to a single status
+func (r ProductRepository) GetSellerListings(sellerID primitive.ObjectID, status *int) ([]dto.Product, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	filter := bson.M{"sellerID": sellerID}
+	if status != nil {
+		filter["status"] = *status
+	}
+	return r.findProducts(ctx, filter)
 }
 
 func (r *ProductRepository) GetProducts() ([]dto.Product, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
+	return r.findProducts(ctx, listedFilter())
+}
+
+func (r ProductRepository) findProducts(ctx context.Context, filter bson.M) ([]dto.Product, error) {
 	var productList []dto.Product
 
-	dataList, err := r.productCollection.Find(ctx, bson.M{})
+	dataList, err := r.productCollection.Find(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -90,7 +110,7 @@ func (r *ProductRepository) GetProducts() ([]dto.Product, error) {
 		}
 		productDTO, productErr := converter.ProductModelToDTO(productModel)
 		if productErr != nil {
-			return nil, err
+			return nil, productErr
 		}
 		productList = append(productList, *productDTO)
 	}
@@ -130,6 +150,12 @@ func (r *ProductRepository) UpdateProduct(productID primitive.ObjectID, updatedP
 		return nil, err
 	}
 
+	// Status only changes through UpdateProductStatus and stock changes
+	delete(update, "status")
+	delete(update, "deletedAt")
+	delete(update, "archivedFrom")
+	// and the rating only as reviews are written
+	delete(update, "rating")
 	update = bson.M{"$set": update}
 
 	filter := bson.M{"_id": productID}
@@ -137,6 +163,9 @@ func (r *ProductRepository) UpdateProduct(productID primitive.ObjectID, updatedP
 	if err != nil {
 		return nil, err
 	}
+	if err := r.syncStockStatus(ctx, productID); err != nil {
+		return nil, err
+	}
 
 	var newUpdatedProduct *model.Product
 	err = r.productCollection.FindOne(ctx, filter).Decode(&newUpdatedProduct)
@@ -148,14 +177,88 @@ func (r *ProductRepository) UpdateProduct(productID primitive.ObjectID, updatedP
 	return converter.ProductModelToDTO(newUpdatedProduct)
 }
 
+// DeleteProduct archives the product and marks it deleted. The document is
+// kept so orders referencing it still resolve.
 func (r *ProductRepository) DeleteProduct(productID primitive.ObjectID) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
-	_, err := r.productCollection.DeleteOne(ctx, bson.M{"_id": productID})
-	return err
+	update := archiveUpdate(bson.M{"deletedAt": time.Now()})
+	result, err := r.productCollection.UpdateOne(ctx, bson.M{"_id": productID}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *ProductRepository) UpdateProductStatus(productID primitive.ObjectID, status int) (*dto.Product, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": productID}
+	update := bson.M{"$set": bson.M{"status": status}}
+	if _, err := r.productCollection.UpdateOne(ctx, filter, update); err != nil {
+		return nil, err
+	}
+
+	var product *model.Product
+	if err := r.productCollection.FindOne(ctx, filter).Decode(&product); err != nil {
+		return nil, err
+	}
+	return converter.ProductModelToDTO(product)
+}
+
+func (r *ProductRepository) ArchiveProduct(productID primitive.ObjectID) (*dto.Product, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": productID}
+	if _, err := r.productCollection.UpdateOne(ctx, filter, archiveUpdate(bson.M{})); err != nil {
+		return nil, err
+	}
+
+	var product *model.Product
+	if err := r.productCollection.FindOne(ctx, filter).Decode(&product); err != nil {
+		return nil, err
+	}
+	return converter.ProductModelToDTO(product)
+}
+
+// archiveUpdate archives the product along with set, remembering the status
+// it had so a restored draft stays a draft
+func archiveUpdate(set bson.M) mongo.Pipeline {
+	set["archivedFrom"] = bson.M{"$cond": bson.A{
+		bson.M{"$eq": bson.A{"$status", productstatus.ARCHIVED}}, "$archivedFrom", "$status",
+	}}
+	set["status"] = productstatus.ARCHIVED
+	return mongo.Pipeline{{{Key: "$set", Value: set}}}
+}
+
+func (r *ProductRepository) RestoreProduct(productID primitive.ObjectID, status int) (*dto.Product, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": productID}
+	update := bson.M{
+		"$set":   bson.M{"status": status},
+		"$unset": bson.M{"deletedAt": "", "archivedFrom": ""},
+	}
+	if _, err := r.productCollection.UpdateOne(ctx, filter, update); err != nil {
+		return nil, err
+	}
+
+	var product *model.Product
+	if err := r.productCollection.FindOne(ctx, filter).Decode(&product); err != nil {
+		return nil, err
+	}
+	return converter.ProductModelToDTO(product)
 }
 
+// UpdateProductAmount takes amount out of the product's stock. It fails with
+// ErrOutOfStock rather than let concurrent orders take more than is left.
 func (r *ProductRepository) UpdateProductAmount(productID primitive.ObjectID, amount int) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
@@ -166,11 +269,77 @@ func (r *ProductRepository) UpdateProductAmount(productID primitive.ObjectID, am
 		},
 	}
 
-	filter := bson.M{"_id": productID}
-	_, err := r.productCollection.UpdateOne(ctx, filter, update)
+	filter := bson.M{"_id": productID, "amount": bson.M{"$gte": amount}}
+	result, err := r.productCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrOutOfStock
+	}
+
+	return r.syncStockStatus(ctx, productID)
+}
+
+// RestockProduct puts amount back into the product's stock
+func (r *ProductRepository) RestockProduct(productID primitive.ObjectID, amount int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	update := bson.M{"$inc": bson.M{"amount": amount}}
+	result, err := r.productCollection.UpdateOne(ctx, bson.M{"_id": productID}, update)
 	if err != nil {
 		return err
 	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return r.syncStockStatus(ctx, productID)
+}
+
+// UpdateRating takes the scores of removed product reviews out of the
+// product's rating and adds those of added ones
+func (r *ProductRepository) UpdateRating(productID primitive.ObjectID, removed []int, added []int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
 
+	update := bson.M{"$inc": ratingIncrement(removed, added)}
+	result, err := r.productCollection.UpdateOne(ctx, bson.M{"_id": productID}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
 	return nil
 }
+
+// syncStockStatus flips an active product to sold out when it runs out of
+// stock and back once it is restocked. Drafts and archived products are left alone.
+func (r *ProductRepository) syncStockStatus(ctx context.Context, productID primitive.ObjectID) error {
+	_, err := r.productCollection.UpdateOne(ctx, bson.M{
+		"_id":    productID,
+		"status": bson.M{"$in": bson.A{productstatus.ACTIVE, nil}},
+		"amount": bson.M{"$lte": 0},
+	}, bson.M{"$set": bson.M{"status": productstatus.SOLDOUT}})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.productCollection.UpdateOne(ctx, bson.M{
+		"_id":    productID,
+		"status": productstatus.SOLDOUT,
+		"amount": bson.M{"$gt": 0},
+	}, bson.M{"$set": bson.M{"status": productstatus.ACTIVE}})
+	return err
+}
+
+// listedFilter matches products buyers may see. Products stored before
+// statuses existed have no status field and count as active.
+func listedFilter() bson.M {
+	return bson.M{
+		"status":    bson.M{"$in": bson.A{productstatus.ACTIVE, nil}},
+		"deletedAt": bson.M{"$exists": false},
+	}
+}
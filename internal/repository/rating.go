@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ratingIncrement is the $inc that takes the scores of removed reviews out
+// of a rating stored at "rating" and adds those of added ones
+func ratingIncrement(removed []int, added []int) bson.M {
+	sum := 0
+	histogram := map[int]int{}
+	for _, score := range added {
+		sum += score
+		histogram[score]++
+	}
+	for _, score := range removed {
+		sum -= score
+		histogram[score]--
+	}
+	inc := bson.M{
+		"rating.count": len(added) - len(removed),
+		"rating.sum":   sum,
+	}
+	for score, n := range histogram {
+		if n != 0 {
+			inc[fmt.Sprintf("rating.histogram.%d", score)] = n
+		}
+	}
+	return inc
+}
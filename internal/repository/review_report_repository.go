@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/reportstatus"
+	"github.com/Dongy-s-Advanture/back-end/internal/model"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/converter"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IReviewReportRepository interface {
+	CreateReport(report *model.ReviewReport) (*dto.ReviewReport, error)
+	GetPendingReports() ([]dto.ReviewReport, error)
+	ResolveReports(reviewID primitive.ObjectID, status int) error
+}
+
+type ReviewReportRepository struct {
+	reportCollection *mongo.Collection
+}
+
+func NewReviewReportRepository(db *mongo.Database, collectionName string) IReviewReportRepository {
+	reportCollection := db.Collection(collectionName)
+
+	// Each user reports a review once
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	_, err := reportCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "review_id", Value: 1}, {Key: "reporter_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "createdAt", Value: 1}}},
+	})
+	if err != nil {
+		log.Printf("failed to create review report indexes: %v", err)
+	}
+
+	return ReviewReportRepository{
+		reportCollection: reportCollection,
+	}
+}
+
+func (r ReviewReportRepository) CreateReport(report *model.ReviewReport) (*dto.ReviewReport, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	report.ReportID = primitive.NewObjectID()
+	report.Status = reportstatus.PENDING
+	report.CreatedAt = time.Now()
+	if _, err := r.reportCollection.InsertOne(ctx, report); err != nil {
+		return nil, err
+	}
+	return converter.ReviewReportModelToDTO(report)
+}
+
+// GetPendingReports returns the reports waiting on a decision, oldest first
+func (r ReviewReportRepository) GetPendingReports() ([]dto.ReviewReport, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	cursor, err := r.reportCollection.Find(ctx, bson.M{"status": reportstatus.PENDING}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	reports := []dto.ReviewReport{}
+	for cursor.Next(ctx) {
+		var report model.ReviewReport
+		if err := cursor.Decode(&report); err != nil {
+			return nil, err
+		}
+		reportDTO, err := converter.ReviewReportModelToDTO(&report)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, *reportDTO)
+	}
+	return reports, cursor.Err()
+}
+
+// ResolveReports closes the pending reports about a review with status
+func (r ReviewReportRepository) ResolveReports(reviewID primitive.ObjectID, status int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	filter := bson.M{"review_id": reviewID, "status": reportstatus.PENDING}
+	update := bson.M{"$set": bson.M{"status": status, "resolvedAt": time.Now()}}
+	_, err := r.reportCollection.UpdateMany(ctx, filter, update)
+	return err
+}
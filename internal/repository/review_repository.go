@@ -2,14 +2,18 @@ package repository
 
 import (
 	"context"
+	"log"
 	"time"
 
 	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/eventtype"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/reviewstatus"
 	"github.com/Dongy-s-Advanture/back-end/internal/model"
 	"github.com/Dongy-s-Advanture/back-end/pkg/utils/converter"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type IReviewRepository interface {
@@ -20,17 +24,43 @@ type IReviewRepository interface {
 	CreateReview(review *model.Review) (*dto.Review, error)
 	UpdateReview(reviewID primitive.ObjectID, updatedReview *model.Review) (*dto.Review, error)
 	DeleteReview(reviewID primitive.ObjectID) error
+	SetReviewReply(reviewID primitive.ObjectID, reply *model.ReviewReply) (bool, error)
+	SetReviewStatus(reviewID primitive.ObjectID, from int, to int) (bool, error)
+	GetReviewsByProductID(productID primitive.ObjectID, sortBy string) ([]dto.ProductReview, error)
 }
 
+// Orders of product reviews
+const (
+	ReviewSortNewest  = "newest"
+	ReviewSortHighest = "highest"
+	ReviewSortLowest  = "lowest"
+)
+
 type ReviewRepository struct {
 	reviewCollection *mongo.Collection
-	sellerRepo       ISellerRepository
 }
 
-func NewReviewRepository(db *mongo.Database, reviewcollectionName string, sellerRepo ISellerRepository) IReviewRepository {
+func NewReviewRepository(db *mongo.Database, reviewcollectionName string) IReviewRepository {
+	reviewCollection := db.Collection(reviewcollectionName)
+
+	// One review per order. Reviews written before they needed an order
+	// have none and aren't indexed.
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	_, err := reviewCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "order_id", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"order_id": bson.M{"$exists": true}}),
+		},
+		{Keys: bson.D{{Key: "products.productID", Value: 1}}},
+	})
+	if err != nil {
+		log.Printf("failed to create review indexes: %v", err)
+	}
+
 	return ReviewRepository{
-		reviewCollection: db.Collection(reviewcollectionName),
-		sellerRepo:       sellerRepo,
+		reviewCollection: reviewCollection,
 	}
 }
 
@@ -40,7 +70,7 @@ func (r ReviewRepository) GetReviews() ([]dto.Review, error) {
 
 	var reviewList []dto.Review
 
-	dataList, err := r.reviewCollection.Find(ctx, bson.M{})
+	dataList, err := r.reviewCollection.Find(ctx, visibleReviews(bson.M{}))
 	if err != nil {
 		return nil, err
 	}
@@ -79,7 +109,7 @@ func (r ReviewRepository) GetReviewsBySellerID(sellerID primitive.ObjectID) ([]d
 
 	var reviewList []dto.Review
 
-	dataList, err := r.reviewCollection.Find(ctx, bson.M{"seller_id": sellerID})
+	dataList, err := r.reviewCollection.Find(ctx, visibleReviews(bson.M{"seller_id": sellerID}))
 	if err != nil {
 		return nil, err
 	}
@@ -105,7 +135,7 @@ func (r ReviewRepository) GetReviewsByBuyerID(buyerID primitive.ObjectID) ([]dto
 
 	var reviewList []dto.Review
 
-	dataList, err := r.reviewCollection.Find(ctx, bson.M{"buyer_id": buyerID})
+	dataList, err := r.reviewCollection.Find(ctx, visibleReviews(bson.M{"buyer_id": buyerID}))
 	if err != nil {
 		return nil, err
 	}
@@ -125,23 +155,36 @@ func (r ReviewRepository) GetReviewsByBuyerID(buyerID primitive.ObjectID) ([]dto
 	return reviewList, nil
 }
 
+// CreateReview stores the review along with its ReviewCreated event
 func (r ReviewRepository) CreateReview(review *model.Review) (*dto.Review, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 	review.ReviewID = primitive.NewObjectID()
 	review.Date = time.Now()
-	result, err := r.reviewCollection.InsertOne(ctx, review)
+
+	created := dto.ReviewCreatedEvent{
+		ReviewID:  review.ReviewID,
+		SellerID:  review.SellerID,
+		BuyerName: review.BuyerName,
+		Score:     review.Score,
+	}
+	for _, product := range review.Products {
+		if product.Score != nil {
+			created.Products = append(created.Products, dto.ProductScore{ProductID: product.ProductID, Score: *product.Score})
+		}
+	}
+	event, err := newOutboxEvent(eventtype.REVIEW_CREATED, review.ReviewID, created)
 	if err != nil {
 		return nil, err
 	}
-	var newReview *model.Review
-	err = r.reviewCollection.FindOne(ctx, bson.M{"_id": result.InsertedID}).Decode(&newReview)
+	review.Outbox = append(review.Outbox, event)
+
+	result, err := r.reviewCollection.InsertOne(ctx, review)
 	if err != nil {
 		return nil, err
 	}
-
-	//update seller's score
-	err = r.sellerRepo.UpdateSellerScore(newReview.SellerID)
+	var newReview *model.Review
+	err = r.reviewCollection.FindOne(ctx, bson.M{"_id": result.InsertedID}).Decode(&newReview)
 	if err != nil {
 		return nil, err
 	}
@@ -158,6 +201,7 @@ func (r ReviewRepository) UpdateReview(reviewID primitive.ObjectID, updatedRevie
 			"message": updatedReview.Message,
 			"score":   updatedReview.Score,
 			"image":   updatedReview.Image,
+			"images":  updatedReview.Images,
 			"date":    time.Now(),
 		},
 	}
@@ -174,19 +218,113 @@ func (r ReviewRepository) UpdateReview(reviewID primitive.ObjectID, updatedRevie
 		return nil, err
 	}
 
-	//update seller's score
-	err = r.sellerRepo.UpdateSellerScore(newUpdatedReview.SellerID)
+	return converter.ReviewModelToDTO(newUpdatedReview)
+}
+
+func (r ReviewRepository) DeleteReview(reviewID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	result, err := r.reviewCollection.DeleteOne(ctx, bson.M{"_id": reviewID})
 	if err != nil {
-		return nil, err
+		return err
+	}
+	// Someone else deleted it first and has taken it out of the rating
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
 	}
+	return nil
+}
 
-	return converter.ReviewModelToDTO(newUpdatedReview)
+// SetReviewReply adds the seller's reply, unless the review already has one
+func (r ReviewRepository) SetReviewReply(reviewID primitive.ObjectID, reply *model.ReviewReply) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": reviewID, "reply": bson.M{"$exists": false}}
+	result, err := r.reviewCollection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"reply": reply}})
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
 }
 
-func (r ReviewRepository) DeleteReview(reviewID primitive.ObjectID) error {
+// SetReviewStatus moves the review from one status to another, unless it
+// was moved by someone else first
+func (r ReviewRepository) SetReviewStatus(reviewID primitive.ObjectID, from int, to int) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": reviewID, "status": from}
+	if from == reviewstatus.VISIBLE {
+		filter = visibleReviews(bson.M{"_id": reviewID})
+	}
+	result, err := r.reviewCollection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"status": to}})
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// GetReviewsByProductID returns the product reviews of visible reviews
+// that scored the product
+func (r ReviewRepository) GetReviewsByProductID(productID primitive.ObjectID, sortBy string) ([]dto.ProductReview, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
-	_, err := r.reviewCollection.DeleteOne(ctx, bson.M{"_id": reviewID})
-	return err
+	sort := bson.D{{Key: "date", Value: -1}}
+	switch sortBy {
+	case ReviewSortHighest:
+		sort = append(bson.D{{Key: "products.score", Value: -1}}, sort...)
+	case ReviewSortLowest:
+		sort = append(bson.D{{Key: "products.score", Value: 1}}, sort...)
+	}
+	reviewed := bson.M{"productID": productID, "score": bson.M{"$type": "number"}}
+	pipeline := []bson.M{
+		{"$match": visibleReviews(bson.M{"products": bson.M{"$elemMatch": reviewed}})},
+		{"$unwind": "$products"},
+		{"$match": bson.M{"products.productID": productID, "products.score": bson.M{"$type": "number"}}},
+		{"$sort": sort},
+	}
+	cursor, err := r.reviewCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	reviews := []dto.ProductReview{}
+	for cursor.Next(ctx) {
+		// The products of each review are unwound into one per document
+		var review struct {
+			ReviewID  primitive.ObjectID  `bson:"_id"`
+			BuyerName string              `bson:"buyerName"`
+			Product   model.ReviewProduct `bson:"products"`
+			Reply     *model.ReviewReply  `bson:"reply"`
+			Date      time.Time           `bson:"date"`
+		}
+		if err := cursor.Decode(&review); err != nil {
+			return nil, err
+		}
+		productReview := dto.ProductReview{
+			ReviewID:  review.ReviewID,
+			ProductID: review.Product.ProductID,
+			BuyerName: review.BuyerName,
+			Color:     review.Product.Color,
+			Score:     *review.Product.Score,
+			Comment:   review.Product.Comment,
+			Date:      review.Date,
+		}
+		if review.Reply != nil {
+			productReview.Reply = &dto.ReviewReply{Message: review.Reply.Message, Date: review.Reply.Date}
+		}
+		reviews = append(reviews, productReview)
+	}
+	return reviews, cursor.Err()
+}
+
+// visibleReviews narrows filter down to reviews that aren't hidden or
+// removed, including those written before reviews had a status
+func visibleReviews(filter bson.M) bson.M {
+	filter["status"] = bson.M{"$nin": bson.A{reviewstatus.HIDDEN, reviewstatus.REMOVED}}
+	return filter
 }
@@ -3,15 +3,20 @@ package repository
 import (
 	"context"
 	"errors"
+	"log"
+	"strconv"
 	"time"
 
 	"github.com/Dongy-s-Advanture/back-end/internal/dto"
 	"github.com/Dongy-s-Advanture/back-end/internal/enum/paymenttype"
 	"github.com/Dongy-s-Advanture/back-end/internal/model"
 	"github.com/Dongy-s-Advanture/back-end/pkg/utils/converter"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/geo"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/money"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type ISellerRepository interface {
@@ -20,10 +25,15 @@ type ISellerRepository interface {
 	CreateSellerData(seller *model.Seller) (*dto.Seller, error)
 	GetSellerByUsername(req *dto.LoginRequest) (*model.Seller, error)
 	UpdateSeller(sellerID primitive.ObjectID, updatedSeller *model.Seller) (*dto.Seller, error)
-	UpdateSellerScore(sellerID primitive.ObjectID) error
-	GetSellerBalanceByID(sellerID primitive.ObjectID) (float64, error)
-	DepositSellerBalance(sellerID primitive.ObjectID, orderID primitive.ObjectID, payment string, amount float64) error
-	WithdrawSellerBalance(sellerID primitive.ObjectID, payment string, amount float64) error
+	UpdateRating(sellerID primitive.ObjectID, removed []int, added []int) (*dto.Rating, error)
+	RecalculateRating(sellerID primitive.ObjectID) (*dto.Rating, error)
+	SetSellerScore(sellerID primitive.ObjectID, rating *dto.Rating, score float64) (bool, error)
+	GetSellerBalanceByID(sellerID primitive.ObjectID) (money.Money, error)
+	DepositSellerBalance(sellerID primitive.ObjectID, orderID primitive.ObjectID, payment string, amount money.Money) error
+	WithdrawSellerBalance(sellerID primitive.ObjectID, payment string, amount money.Money) error
+	ChargebackSellerBalance(sellerID primitive.ObjectID, orderID primitive.ObjectID, payment string, amount money.Money) error
+	AddNoShowStrike(sellerID primitive.ObjectID) error
+	GetSellerIDsNear(near geo.Point, radius float64) ([]primitive.ObjectID, error)
 }
 
 type SellerRepository struct {
@@ -32,8 +42,20 @@ type SellerRepository struct {
 }
 
 func NewSellerRepository(db *mongo.Database, sellercollectionName string, reviewcollectionName string) ISellerRepository {
+	sellerCollection := db.Collection(sellercollectionName)
+
+	// Sellers without a location are left out of the index
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	_, err := sellerCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "location", Value: "2dsphere"}},
+	})
+	if err != nil {
+		log.Printf("failed to create seller indexes: %v", err)
+	}
+
 	return SellerRepository{
-		sellerCollection: db.Collection(sellercollectionName),
+		sellerCollection: sellerCollection,
 		reviewCollection: db.Collection(reviewcollectionName),
 	}
 }
@@ -150,50 +172,84 @@ func (r SellerRepository) UpdateSeller(sellerID primitive.ObjectID, updatedSelle
 	return converter.SellerModelToDTO(newUpdatedSeller)
 }
 
-func (r SellerRepository) UpdateSellerScore(sellerID primitive.ObjectID) error {
+// UpdateRating takes the scores of removed reviews out of the seller's
+// rating and adds those of added ones, in one atomic update
+func (r SellerRepository) UpdateRating(sellerID primitive.ObjectID, removed []int, added []int) (*dto.Rating, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	var seller model.Seller
+	update := bson.M{"$inc": ratingIncrement(removed, added)}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After).SetProjection(bson.M{"rating": 1})
+	err := r.sellerCollection.FindOneAndUpdate(ctx, bson.M{"_id": sellerID}, update, opts).Decode(&seller)
+	if err != nil {
+		return nil, err
+	}
+	return converter.RatingModelToDTO(seller.Rating)
+}
+
+// RecalculateRating works the seller's rating out from all their reviews,
+// for sellers whose reviews were written before ratings were kept
+func (r SellerRepository) RecalculateRating(sellerID primitive.ObjectID) (*dto.Rating, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
 	pipeline := []bson.M{
-		{"$match": bson.M{"seller_id": sellerID}},
+		{"$match": visibleReviews(bson.M{"seller_id": sellerID})},
 		{"$group": bson.M{
-			"_id":      "$seller_id",
-			"avgScore": bson.M{"$avg": "$score"},
+			"_id":   "$score",
+			"count": bson.M{"$sum": 1},
 		}},
 	}
-
-	var result struct {
-		AvgScore float64 `bson:"avgScore"`
-	}
-
 	cursor, err := r.reviewCollection.Aggregate(ctx, pipeline)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer cursor.Close(ctx)
 
-	if cursor.Next(ctx) {
-		if err := cursor.Decode(&result); err != nil {
-			return err
+	rating := &model.Rating{Histogram: map[string]int{}}
+	for cursor.Next(ctx) {
+		var bucket struct {
+			Score int `bson:"_id"`
+			Count int `bson:"count"`
 		}
-	} else {
-		result.AvgScore = 0
+		if err := cursor.Decode(&bucket); err != nil {
+			return nil, err
+		}
+		rating.Count += bucket.Count
+		rating.Sum += bucket.Score * bucket.Count
+		rating.Histogram[strconv.Itoa(bucket.Score)] = bucket.Count
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
 	}
 
-	_, err = r.sellerCollection.UpdateOne(
-		ctx,
-		bson.M{"_id": sellerID},
-		bson.M{"$set": bson.M{"score": result.AvgScore}},
-	)
-
+	_, err = r.sellerCollection.UpdateOne(ctx, bson.M{"_id": sellerID}, bson.M{"$set": bson.M{"rating": rating}})
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return converter.RatingModelToDTO(rating)
+}
 
-	return nil
+// SetSellerScore stores the score worked out from rating, unless the rating
+// has changed since, in which case whoever changed it sets the score
+func (r SellerRepository) SetSellerScore(sellerID primitive.ObjectID, rating *dto.Rating, score float64) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"_id":          sellerID,
+		"rating.count": rating.Count,
+		"rating.sum":   rating.Sum,
+	}
+	result, err := r.sellerCollection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"score": score}})
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
 }
 
-func (r SellerRepository) GetSellerBalanceByID(sellerID primitive.ObjectID) (float64, error) {
+func (r SellerRepository) GetSellerBalanceByID(sellerID primitive.ObjectID) (money.Money, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
@@ -201,7 +257,7 @@ func (r SellerRepository) GetSellerBalanceByID(sellerID primitive.ObjectID) (flo
 
 	err := r.sellerCollection.FindOne(ctx, bson.M{"_id": sellerID}).Decode(&seller)
 	if err != nil {
-		return 0, err
+		return money.Money{}, err
 	}
 
 	totalBalance := seller.Balance
@@ -209,7 +265,7 @@ func (r SellerRepository) GetSellerBalanceByID(sellerID primitive.ObjectID) (flo
 	return totalBalance, nil
 }
 
-func (r SellerRepository) DepositSellerBalance(sellerID primitive.ObjectID, orderID primitive.ObjectID, payment string, amount float64) error {
+func (r SellerRepository) DepositSellerBalance(sellerID primitive.ObjectID, orderID primitive.ObjectID, payment string, amount money.Money) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
@@ -223,15 +279,25 @@ func (r SellerRepository) DepositSellerBalance(sellerID primitive.ObjectID, orde
 
 	// Update Seller Balance & Add Transaction
 	update := bson.M{
-		"$inc":  bson.M{"balance": amount},          // Increase balance
+		"$inc":  bson.M{"balance.amount": amount.Amount}, // Increase balance
+		"$set":  bson.M{"balance.currency": amount.Currency},
 		"$push": bson.M{"transaction": transaction}, // Add transaction record
 	}
 
-	_, err := r.sellerCollection.UpdateOne(ctx, bson.M{"_id": sellerID}, update)
+	// Skipped when the order was already paid out, so a retried deposit
+	// doesn't pay the seller twice
+	filter := bson.M{
+		"_id": sellerID,
+		"transaction": bson.M{"$not": bson.M{"$elemMatch": bson.M{
+			"_id":  orderID,
+			"type": paymenttype.CREDIT,
+		}}},
+	}
+	_, err := r.sellerCollection.UpdateOne(ctx, filter, update)
 	return err
 }
 
-func (r SellerRepository) WithdrawSellerBalance(sellerID primitive.ObjectID, payment string, amount float64) error {
+func (r SellerRepository) WithdrawSellerBalance(sellerID primitive.ObjectID, payment string, amount money.Money) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
@@ -241,23 +307,108 @@ func (r SellerRepository) WithdrawSellerBalance(sellerID primitive.ObjectID, pay
 		return err
 	}
 
-	if seller.Balance < amount {
+	if seller.Balance.LessThan(amount) {
 		return errors.New("insufficient balance")
 	}
 
 	transaction := model.Transaction{
 		Type:    paymenttype.DEBIT,
-		Amount:  -amount,
+		Amount:  amount.Neg(),
 		Payment: payment,
 		Date:    time.Now(),
 	}
 
 	// Update balance & add transaction
 	update := bson.M{
-		"$inc":  bson.M{"balance": -amount},         // Decrease balance
-		"$push": bson.M{"transaction": transaction}, // Log transaction
+		"$inc":  bson.M{"balance.amount": -amount.Amount}, // Decrease balance
+		"$push": bson.M{"transaction": transaction},       // Log transaction
 	}
 
-	_, err = r.sellerCollection.UpdateOne(ctx, bson.M{"_id": sellerID}, update)
+	// The balance is checked again in the filter so concurrent withdrawals
+	// can't take it below zero
+	filter := bson.M{"_id": sellerID, "balance.amount": bson.M{"$gte": amount.Amount}}
+	result, err := r.sellerCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("insufficient balance")
+	}
+	return nil
+}
+
+// ChargebackSellerBalance takes back what the seller earned on a refunded
+// order. Unlike a withdrawal the balance may go below zero, in which case it
+// is settled from the seller's next sales. An order is only charged back
+// once, so a retried chargeback is safe.
+func (r SellerRepository) ChargebackSellerBalance(sellerID primitive.ObjectID, orderID primitive.ObjectID, payment string, amount money.Money) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	transaction := model.Transaction{
+		Type:    paymenttype.DEBIT,
+		Amount:  amount.Neg(),
+		OrderID: orderID,
+		Payment: payment,
+		Date:    time.Now(),
+	}
+
+	update := bson.M{
+		"$inc":  bson.M{"balance.amount": -amount.Amount},
+		"$push": bson.M{"transaction": transaction},
+	}
+	filter := bson.M{
+		"_id": sellerID,
+		"transaction": bson.M{"$not": bson.M{"$elemMatch": bson.M{
+			"_id":  orderID,
+			"type": paymenttype.DEBIT,
+		}}},
+	}
+	_, err := r.sellerCollection.UpdateOne(ctx, filter, update)
 	return err
 }
+
+func (r SellerRepository) AddNoShowStrike(sellerID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	update := bson.M{"$inc": bson.M{"noShowStrikes": 1}}
+	result, err := r.sellerCollection.UpdateOne(ctx, bson.M{"_id": sellerID}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// GetSellerIDsNear returns the sellers located within radius meters of near,
+// closest first
+func (r SellerRepository) GetSellerIDsNear(near geo.Point, radius float64) ([]primitive.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	filter := bson.M{"location": bson.M{"$nearSphere": bson.M{
+		"$geometry":    near,
+		"$maxDistance": radius,
+	}}}
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
+	cursor, err := r.sellerCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var sellerIDs []primitive.ObjectID
+	for cursor.Next(ctx) {
+		var seller struct {
+			SellerID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.Decode(&seller); err != nil {
+			return nil, err
+		}
+		sellerIDs = append(sellerIDs, seller.SellerID)
+	}
+	return sellerIDs, cursor.Err()
+}
@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IUploadRepository interface {
+	RecordUpload(key string, fileURL string) error
+	AddReference(key string, fileURL string, ref model.UploadRef) error
+	RemoveReference(key string, fileURL string, ref model.UploadRef) error
+	GetOrphans(before time.Time, limit int64) ([]model.Upload, error)
+	MarkSwept(uploadID primitive.ObjectID, before time.Time, now time.Time) (bool, error)
+	DeleteSwept(uploadID primitive.ObjectID, sweepingAt time.Time) error
+}
+
+var ErrUploadSwept = errors.New("file is being deleted, upload it again")
+
+// How long a sweeper has to delete an object it marked
+const uploadSweepLease = 10 * time.Minute
+
+type UploadRepository struct {
+	uploadCollection *mongo.Collection
+}
+
+func NewUploadRepository(db *mongo.Database, collectionName string) IUploadRepository {
+	collection := db.Collection(collectionName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "key", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "updatedAt", Value: 1}}},
+	})
+	if err != nil {
+		log.Printf("failed to create upload indexes: %v", err)
+	}
+
+	return &UploadRepository{
+		uploadCollection: collection,
+	}
+}
+
+// notSwept matches records the sweeper isn't deleting. A mark older than
+// uploadSweepLease was left by a sweeper that died and is ignored.
+func notSwept(now time.Time) bson.M {
+	return bson.M{"$or": bson.A{
+		bson.M{"sweepingAt": bson.M{"$exists": false}},
+		bson.M{"sweepingAt": bson.M{"$lt": now.Add(-uploadSweepLease)}},
+	}}
+}
+
+// RecordUpload registers an object about to be stored. Re-uploading an
+// existing key restarts its grace period so it isn't swept before the caller
+// can claim it. It fails with ErrUploadSwept while the sweeper deletes the
+// object, so the caller doesn't store it only to have it deleted.
+func (r *UploadRepository) RecordUpload(key string, fileURL string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	filter := notSwept(now)
+	filter["key"] = key
+	update := bson.M{
+		"$set":   bson.M{"url": fileURL, "updatedAt": now},
+		"$unset": bson.M{"sweepingAt": ""},
+		"$setOnInsert": bson.M{
+			"key":       key,
+			"refs":      []model.UploadRef{},
+			"createdAt": now,
+		},
+	}
+	_, err := r.uploadCollection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	// The upsert collides with the record being swept
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrUploadSwept
+	}
+	return err
+}
+
+// AddReference fails with ErrUploadSwept while the sweeper deletes the object
+func (r *UploadRepository) AddReference(key string, fileURL string, ref model.UploadRef) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	filter := notSwept(now)
+	filter["key"] = key
+	update := bson.M{
+		"$addToSet": bson.M{"refs": ref},
+		"$set":      bson.M{"updatedAt": now},
+		"$unset":    bson.M{"sweepingAt": ""},
+		"$setOnInsert": bson.M{
+			"key":       key,
+			"url":       fileURL,
+			"createdAt": now,
+		},
+	}
+	_, err := r.uploadCollection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrUploadSwept
+	}
+	return err
+}
+
+// RemoveReference drops ref from the object. Objects uploaded before uploads
+// were tracked are inserted without references so the sweeper can collect them.
+func (r *UploadRepository) RemoveReference(key string, fileURL string, ref model.UploadRef) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	update := bson.M{
+		"$pull": bson.M{"refs": ref},
+		"$set":  bson.M{"updatedAt": now},
+		"$setOnInsert": bson.M{
+			"key":       key,
+			"url":       fileURL,
+			"createdAt": now,
+		},
+	}
+	_, err := r.uploadCollection.UpdateOne(ctx, bson.M{"key": key}, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// GetOrphans returns objects nobody references that haven't been touched
+// since before and that no other sweeper is deleting
+func (r *UploadRepository) GetOrphans(before time.Time, limit int64) ([]model.Upload, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	filter := notSwept(time.Now())
+	filter["refs"] = bson.M{"$size": 0}
+	filter["updatedAt"] = bson.M{"$lt": before}
+	cursor, err := r.uploadCollection.Find(ctx, filter, options.Find().SetLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var uploads []model.Upload
+	if err = cursor.All(ctx, &uploads); err != nil {
+		return nil, err
+	}
+	return uploads, nil
+}
+
+// MarkSwept marks the record as being swept if it is still unreferenced,
+// reporting whether it did. The object must only be deleted when this
+// returns true, and the record dropped with DeleteSwept afterwards.
+func (r *UploadRepository) MarkSwept(uploadID primitive.ObjectID, before time.Time, now time.Time) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	filter := notSwept(now)
+	filter["_id"] = uploadID
+	filter["refs"] = bson.M{"$size": 0}
+	filter["updatedAt"] = bson.M{"$lt": before}
+	result, err := r.uploadCollection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"sweepingAt": now}})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// DeleteSwept drops the record marked at sweepingAt once its object is gone
+func (r *UploadRepository) DeleteSwept(uploadID primitive.ObjectID, sweepingAt time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	_, err := r.uploadCollection.DeleteOne(ctx, bson.M{"_id": uploadID, "sweepingAt": sweepingAt})
+	return err
+}
@@ -0,0 +1,17 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func (r Router) AddAddressRouter(rg *gin.RouterGroup) {
+
+	addressCont := r.deps.AddressController
+	addressRouter := rg.Group("address")
+
+	addressRouter.GET("/province", addressCont.GetProvinces)
+	addressRouter.GET("/province/:province/district", addressCont.GetDistricts)
+	addressRouter.GET("/province/:province/district/:district/subdistrict", addressCont.GetSubdistricts)
+	addressRouter.GET("/zip/:zip", addressCont.LookupZip)
+
+}
@@ -9,13 +9,22 @@ import (
 func (r Router) AddAppointmentRouter(rg *gin.RouterGroup) {
 
 	appointmentCont := r.deps.AppointmentController
+	calendarCont := r.deps.CalendarController
+	meetupCont := r.deps.MeetupController
 	appointmentRouter := rg.Group("appointment")
 
 	appointmentRouter.POST("/", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), appointmentCont.CreateAppointment)
 	appointmentRouter.GET("/", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), appointmentCont.GetAppointments)
+	appointmentRouter.GET("/calendar.ics", calendarCont.GetCalendarFeed)
+	appointmentRouter.GET("/calendar/token", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), calendarCont.GetCalendarSubscription)
+	appointmentRouter.POST("/calendar/token", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), calendarCont.RotateCalendarSubscription)
 	appointmentRouter.GET("/:appointment_id", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), appointmentCont.GetAppointmentByID)
 	appointmentRouter.GET("/order/:order_id", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), appointmentCont.GetAppointmentByOrderID)
-	appointmentRouter.PUT("/:appointment_id/date", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), appointmentCont.UpdateAppointmentDate)
-	appointmentRouter.PUT("/:appointment_id/place", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), appointmentCont.UpdateAppointmentPlace)
+	appointmentRouter.POST("/:appointment_id/proposal", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), appointmentCont.Propose)
+	appointmentRouter.POST("/:appointment_id/proposal/:proposal_id/accept", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), appointmentCont.AcceptProposal)
+	appointmentRouter.POST("/:appointment_id/proposal/:proposal_id/reject", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), appointmentCont.RejectProposal)
+	appointmentRouter.POST("/:appointment_id/no-show", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), appointmentCont.ReportNoShow)
+	appointmentRouter.GET("/:appointment_id/meetup-points", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), meetupCont.SuggestMeetupPoints)
+	appointmentRouter.GET("/:appointment_id/calendar.ics", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), calendarCont.GetAppointmentCalendar)
 
 }
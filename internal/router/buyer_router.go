@@ -16,7 +16,14 @@ func (r Router) AddBuyerRouter(rg *gin.RouterGroup) {
 	buyerRouter.GET("/", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), buyerCont.GetBuyers)
 	buyerRouter.GET("/:buyer_id", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), buyerCont.GetBuyerByID)
 	buyerRouter.PUT("/:buyer_id", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), buyerCont.UpdateBuyer)
+	buyerRouter.GET("/:buyer_id/cart", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), buyerCont.GetCart)
 	buyerRouter.POST("/:buyer_id/cart", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), buyerCont.UpdateProductInCart)
 	buyerRouter.DELETE("/:buyer_id/cart/:product_id", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), buyerCont.DeleteProductFromCart)
+	buyerRouter.POST("/:buyer_id/cart/:product_id/save-for-later", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), buyerCont.SaveForLater)
+	buyerRouter.GET("/:buyer_id/wishlist", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), buyerCont.GetWishlist)
+	buyerRouter.POST("/:buyer_id/wishlist", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), buyerCont.AddToWishlist)
+	buyerRouter.DELETE("/:buyer_id/wishlist/:product_id", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), buyerCont.RemoveFromWishlist)
+	buyerRouter.POST("/:buyer_id/wishlist/:product_id/move-to-cart", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), buyerCont.MoveToCart)
+	buyerRouter.POST("/:buyer_id/checkout", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), r.deps.OrderController.Checkout)
 
 }
@@ -0,0 +1,23 @@
+package router
+
+import (
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/tokenmode"
+	"github.com/Dongy-s-Advanture/back-end/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func (r Router) AddCommissionRouter(rg *gin.RouterGroup) {
+
+	commissionCont := r.deps.CommissionController
+	commissionRouter := rg.Group("commission")
+	commissionRouter.Use(
+		middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf),
+		middleware.AdminOnly(&r.deps.conf.Admin),
+	)
+
+	commissionRouter.GET("/rules", commissionCont.GetRules)
+	commissionRouter.PUT("/rules", commissionCont.SetRule)
+	commissionRouter.DELETE("/rules/:rule_id", commissionCont.DeleteRule)
+	commissionRouter.GET("/ledger", commissionCont.GetLedger)
+
+}
@@ -0,0 +1,25 @@
+package router
+
+import (
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/tokenmode"
+	"github.com/Dongy-s-Advanture/back-end/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func (r Router) AddConversationRouter(rg *gin.RouterGroup) {
+
+	messageCont := r.deps.MessageController
+	conversationRouter := rg.Group("conversation")
+	conversationRouter.Use(middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf))
+
+	conversationRouter.POST("/", messageCont.StartConversation)
+	conversationRouter.GET("/", messageCont.GetConversations)
+	conversationRouter.GET("/unread", messageCont.GetUnreadCount)
+	conversationRouter.GET("/stream", messageCont.StreamMessages)
+	conversationRouter.POST("/image", messageCont.UploadMessageImage)
+	conversationRouter.GET("/:conversation_id", messageCont.GetConversation)
+	conversationRouter.GET("/:conversation_id/message", messageCont.GetMessages)
+	conversationRouter.POST("/:conversation_id/message", messageCont.SendMessage)
+	conversationRouter.POST("/:conversation_id/read", messageCont.MarkRead)
+
+}
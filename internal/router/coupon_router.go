@@ -0,0 +1,18 @@
+package router
+
+import (
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/tokenmode"
+	"github.com/Dongy-s-Advanture/back-end/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func (r Router) AddCouponRouter(rg *gin.RouterGroup) {
+
+	couponCont := r.deps.CouponController
+	couponRouter := rg.Group("coupon")
+
+	couponRouter.POST("/", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), couponCont.CreateCoupon)
+	couponRouter.GET("/seller/:seller_id", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), couponCont.GetCouponsBySellerID)
+	couponRouter.DELETE("/:coupon_id", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), couponCont.DeactivateCoupon)
+
+}
@@ -5,11 +5,12 @@ import (
 
 	"github.com/Dongy-s-Advanture/back-end/internal/config"
 	"github.com/Dongy-s-Advanture/back-end/internal/controller"
+	"github.com/Dongy-s-Advanture/back-end/internal/notification"
 	"github.com/Dongy-s-Advanture/back-end/internal/repository"
 	"github.com/Dongy-s-Advanture/back-end/internal/service"
 	"github.com/Dongy-s-Advanture/back-end/internal/service/auth"
+	"github.com/Dongy-s-Advanture/back-end/internal/storage"
 	"github.com/Dongy-s-Advanture/back-end/pkg/redis"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/omise/omise-go"
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -31,17 +32,51 @@ type Dependencies struct {
 	ProductController controller.IProductController
 
 	ReviewRepo       repository.IReviewRepository
+	ReviewReportRepo repository.IReviewReportRepository
 	ReviewService    service.IReviewService
 	ReviewController controller.IReviewController
 
 	AppointmentRepo       repository.IAppointmentRepository
+	AvailabilityRepo      repository.IAvailabilityRepository
 	AppointmentService    service.IAppointmentService
 	AppointmentController controller.IAppointmentController
 
+	CalendarTokenRepo  repository.ICalendarTokenRepository
+	CalendarService    service.ICalendarService
+	CalendarController controller.ICalendarController
+
+	MeetupPointRepo  repository.IMeetupPointRepository
+	MeetupService    service.IMeetupService
+	MeetupController controller.IMeetupController
+
+	ConversationRepo  repository.IConversationRepository
+	MessageService    service.IMessageService
+	MessageController controller.IMessageController
+
+	NotificationRepo       repository.INotificationRepository
+	NotificationService    service.INotificationService
+	NotificationController controller.INotificationController
+
+	OutboxRepo       repository.IOutboxRepository
+	EventRelay       service.IEventRelay
+	OutboxController controller.IOutboxController
+
+	AddressService    service.IAddressService
+	AddressController controller.IAddressController
+
 	OrderRepo       repository.IOrderRepository
 	OrderService    service.IOrderService
 	OrderController controller.IOrderController
 
+	CouponRepo       repository.ICouponRepository
+	CouponService    service.ICouponService
+	CouponController controller.ICouponController
+
+	CommissionRepo       repository.ICommissionRepository
+	LedgerRepo           repository.ILedgerRepository
+	CommissionService    service.ICommissionService
+	CommissionController controller.ICommissionController
+
 	PaymentService    service.IPaymentService
 	PaymentController controller.IPaymentController
 
@@ -49,16 +84,19 @@ type Dependencies struct {
 	AdvertisementService    service.IAdvertisementService
 	AdvertisementController controller.IAdvertisementController
 
-	S3Service service.IS3Service
+	UploadRepo        repository.IUploadRepository
+	UploadService     service.IUploadService
+	S3Service         service.IS3Service
+	StorageController controller.IStorageController
 
-	redis    redis.IRedisClient
-	mongo    *mongo.Database
-	s3Client *s3.Client
+	redis   redis.IRedisClient
+	mongo   *mongo.Database
+	storage storage.Storage
 
 	conf *config.Config
 }
 
-func NewDependencies(mongoDB *mongo.Database, redisDB redis.IRedisClient, s3Client *s3.Client, conf *config.Config) *Dependencies {
+func NewDependencies(mongoDB *mongo.Database, redisDB redis.IRedisClient, store storage.Storage, conf *config.Config) *Dependencies {
 
 	// Initialize third party
 	omiseClient, e := omise.NewClient(conf.Payment.Public, conf.Payment.Private)
@@ -70,33 +108,63 @@ func NewDependencies(mongoDB *mongo.Database, redisDB redis.IRedisClient, s3Clie
 	buyerRepo := repository.NewBuyerRepository(mongoDB, "buyers")
 	sellerRepo := repository.NewSellerRepository(mongoDB, "sellers", "reviews")
 	productRepo := repository.NewProductRepository(mongoDB, "products")
-	reviewRepo := repository.NewReviewRepository(mongoDB, "reviews", sellerRepo)
+	reviewRepo := repository.NewReviewRepository(mongoDB, "reviews")
+	reviewReportRepo := repository.NewReviewReportRepository(mongoDB, "review_reports")
 	appointmentRepo := repository.NewAppointmentRepository(mongoDB, "appointments")
+	availabilityRepo := repository.NewAvailabilityRepository(mongoDB, "seller_availability")
+	calendarTokenRepo := repository.NewCalendarTokenRepository(mongoDB, "calendar_tokens")
 	orderRepo := repository.NewOrderRepository(mongoDB, "orders")
 	advertisementRepo := repository.NewAdvertisementRepository(mongoDB, "advertisements")
+	uploadRepo := repository.NewUploadRepository(mongoDB, "uploads")
+	couponRepo := repository.NewCouponRepository(mongoDB, "coupons", "coupon_redemptions")
+	commissionRepo := repository.NewCommissionRepository(mongoDB, "commission_rules")
+	ledgerRepo := repository.NewLedgerRepository(mongoDB, "platform_ledger")
+	meetupPointRepo := repository.NewMeetupPointRepository(mongoDB, "meetup_points")
+	conversationRepo := repository.NewConversationRepository(mongoDB, "conversations", "messages")
+	notificationRepo := repository.NewNotificationRepository(mongoDB, "notifications", "notification_preferences")
+	outboxRepo := repository.NewOutboxRepository(mongoDB, "outbox", "orders", "reviews")
 
 	// Initialize services
-	buyerService := service.NewBuyerService(buyerRepo)
-	sellerService := service.NewSellerService(sellerRepo)
+	uploadService := service.NewUploadService(uploadRepo, store)
+	notificationService := service.NewNotificationService(notificationRepo, notification.New(&conf.Notification, notificationRepo), &conf.Notification)
+	eventRelay := service.NewEventRelay(outboxRepo, &conf.Outbox)
+	buyerService := service.NewBuyerService(buyerRepo, productRepo, uploadService)
+	sellerService := service.NewSellerService(sellerRepo, uploadService)
 	authService := auth.NewAuthService(conf, redisDB, sellerRepo, buyerRepo)
-	productService := service.NewProductService(productRepo)
-	reviewService := service.NewReviewService(reviewRepo)
-	appointmentService := service.NewAppointmentService(appointmentRepo)
-	orderService := service.NewOrderService(orderRepo, appointmentRepo, sellerRepo, productRepo)
+	productService := service.NewProductService(productRepo, sellerRepo, uploadService, service.NewWishlistWatcher(buyerRepo, notificationService))
+	reviewService := service.NewReviewService(reviewRepo, orderRepo, sellerRepo, productRepo, reviewReportRepo, uploadService, notificationService, eventRelay, &conf.Rating)
+	calendarService := service.NewCalendarService(appointmentRepo, calendarTokenRepo, &conf.Calendar)
+	meetupService := service.NewMeetupService(meetupPointRepo, appointmentRepo, buyerRepo, sellerRepo)
+	addressService := service.NewAddressService()
+	messageService := service.NewMessageService(conversationRepo, productRepo, orderRepo, buyerRepo, uploadService, service.NewMessageHub())
 	paymentService := service.NewPaymentService(omiseClient)
-	advertisementService := service.NewAdvertisementService(advertisementRepo)
-	s3Service := service.NewS3Service(s3Client, &conf.AWS)
+	couponService := service.NewCouponService(couponRepo, sellerRepo)
+	commissionService := service.NewCommissionService(commissionRepo, ledgerRepo, &conf.Commission, &conf.Payment)
+	orderService := service.NewOrderService(orderRepo, appointmentRepo, sellerRepo, productRepo, buyerRepo, paymentService, couponService, commissionService, notificationService, eventRelay, &conf.Appointment)
+	appointmentService := service.NewAppointmentService(appointmentRepo, availabilityRepo, orderRepo, orderService, service.NewAppointmentNotifier(notificationService), &conf.Appointment)
+	advertisementService := service.NewAdvertisementService(advertisementRepo, uploadService)
+	s3Service := service.NewS3Service(store, uploadService, &conf.Storage, &conf.Image)
 
 	// Initialize controllers
 	buyerController := controller.NewBuyerController(buyerService, s3Service)
 	sellerController := controller.NewSellerController(sellerService, s3Service)
 	authController := controller.NewAuthController(conf, authService)
 	productController := controller.NewProductController(productService, s3Service)
-	reviewController := controller.NewReviewController(reviewService)
+	reviewController := controller.NewReviewController(reviewService, s3Service, &conf.Admin)
 	appointmentController := controller.NewAppointmentController(appointmentService)
+	calendarController := controller.NewCalendarController(calendarService)
+	meetupController := controller.NewMeetupController(meetupService)
+	addressController := controller.NewAddressController(addressService)
+	notificationController := controller.NewNotificationController(notificationService)
+	outboxController := controller.NewOutboxController(eventRelay)
+	messageController := controller.NewMessageController(messageService, s3Service)
 	orderController := controller.NewOrderController(orderService, paymentService)
 	paymentController := controller.NewPaymentController(paymentService)
+	couponController := controller.NewCouponController(couponService, &conf.Admin)
+	commissionController := controller.NewCommissionController(commissionService)
 	advertisementController := controller.NewAdvertisementController(advertisementService, s3Service)
+	localStorage, _ := store.(*storage.LocalStorage)
+	storageController := controller.NewStorageController(s3Service, localStorage)
 
 	return &Dependencies{
 		BuyerRepo:       buyerRepo,
@@ -115,17 +183,51 @@ func NewDependencies(mongoDB *mongo.Database, redisDB redis.IRedisClient, s3Clie
 		ProductController: productController,
 
 		ReviewRepo:       reviewRepo,
+		ReviewReportRepo: reviewReportRepo,
 		ReviewService:    reviewService,
 		ReviewController: reviewController,
 
 		AppointmentRepo:       appointmentRepo,
+		AvailabilityRepo:      availabilityRepo,
 		AppointmentService:    appointmentService,
 		AppointmentController: appointmentController,
 
+		CalendarTokenRepo:  calendarTokenRepo,
+		CalendarService:    calendarService,
+		CalendarController: calendarController,
+
+		MeetupPointRepo:  meetupPointRepo,
+		MeetupService:    meetupService,
+		MeetupController: meetupController,
+
+		ConversationRepo:  conversationRepo,
+		MessageService:    messageService,
+		MessageController: messageController,
+
+		NotificationRepo:       notificationRepo,
+		NotificationService:    notificationService,
+		NotificationController: notificationController,
+
+		OutboxRepo:       outboxRepo,
+		EventRelay:       eventRelay,
+		OutboxController: outboxController,
+
+		AddressService:    addressService,
+		AddressController: addressController,
+
 		OrderRepo:       orderRepo,
 		OrderService:    orderService,
 		OrderController: orderController,
 
+		CouponRepo:       couponRepo,
+		CouponService:    couponService,
+		CouponController: couponController,
+
+		CommissionRepo:       commissionRepo,
+		LedgerRepo:           ledgerRepo,
+		CommissionService:    commissionService,
+		CommissionController: commissionController,
+
 		PaymentService:    paymentService,
 		PaymentController: paymentController,
 
@@ -133,10 +235,14 @@ func NewDependencies(mongoDB *mongo.Database, redisDB redis.IRedisClient, s3Clie
 		AdvertisementService:    advertisementService,
 		AdvertisementController: advertisementController,
 
-		S3Service: s3Service,
-		redis:     redisDB,
-		s3Client:  s3Client,
-		mongo:     mongoDB,
-		conf:      conf,
+		UploadRepo:        uploadRepo,
+		UploadService:     uploadService,
+		S3Service:         s3Service,
+		StorageController: storageController,
+
+		redis:   redisDB,
+		storage: store,
+		mongo:   mongoDB,
+		conf:    conf,
 	}
 }
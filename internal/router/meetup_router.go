@@ -0,0 +1,18 @@
+package router
+
+import (
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/tokenmode"
+	"github.com/Dongy-s-Advanture/back-end/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func (r Router) AddMeetupRouter(rg *gin.RouterGroup) {
+
+	meetupCont := r.deps.MeetupController
+	meetupRouter := rg.Group("meetup-point")
+
+	meetupRouter.GET("/", meetupCont.GetMeetupPoints)
+	meetupRouter.POST("/", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), middleware.AdminOnly(&r.deps.conf.Admin), meetupCont.CreateMeetupPoint)
+	meetupRouter.DELETE("/:meetup_point_id", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), middleware.AdminOnly(&r.deps.conf.Admin), meetupCont.DeleteMeetupPoint)
+
+}
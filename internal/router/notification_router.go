@@ -0,0 +1,21 @@
+package router
+
+import (
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/tokenmode"
+	"github.com/Dongy-s-Advanture/back-end/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func (r Router) AddNotificationRouter(rg *gin.RouterGroup) {
+
+	notificationCont := r.deps.NotificationController
+	notificationRouter := rg.Group("notifications")
+	notificationRouter.Use(middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf))
+
+	notificationRouter.GET("", notificationCont.GetNotifications)
+	notificationRouter.POST("/read", notificationCont.MarkAllRead)
+	notificationRouter.POST("/:notification_id/read", notificationCont.MarkRead)
+	notificationRouter.GET("/preferences", notificationCont.GetPreferences)
+	notificationRouter.PUT("/preferences", notificationCont.UpdatePreferences)
+
+}
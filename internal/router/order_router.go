@@ -12,6 +12,7 @@ func (r Router) AddOrderRouter(rg *gin.RouterGroup) {
 	orderRouter := rg.Group("order")
 
 	orderRouter.POST("/", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), orderCont.CreateOrder)
+	orderRouter.POST("/quote", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), orderCont.GetQuote)
 	orderRouter.GET("/:user_id/:user_type", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), orderCont.GetOrdersByUserID)
 	orderRouter.DELETE("/:order_id", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), orderCont.DeleteOrderByOrderID)
 	orderRouter.PUT("/:order_id", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), orderCont.UpdateOrderByOrderID)
@@ -0,0 +1,21 @@
+package router
+
+import (
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/tokenmode"
+	"github.com/Dongy-s-Advanture/back-end/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func (r Router) AddOutboxRouter(rg *gin.RouterGroup) {
+
+	outboxCont := r.deps.OutboxController
+	outboxRouter := rg.Group("outbox")
+	outboxRouter.Use(
+		middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf),
+		middleware.AdminOnly(&r.deps.conf.Admin),
+	)
+
+	outboxRouter.GET("/dead", outboxCont.GetDeadEvents)
+	outboxRouter.POST("/:event_id/retry", outboxCont.RetryDeadEvent)
+
+}
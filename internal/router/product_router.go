@@ -9,14 +9,20 @@ import (
 func (r Router) AddProductRouter(rg *gin.RouterGroup) {
 
 	productCont := r.deps.ProductController
+	reviewCont := r.deps.ReviewController
 	productRouter := rg.Group("product")
 
 	productRouter.POST("/", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), productCont.CreateProduct)
 	productRouter.GET("/", productCont.GetProducts)
 	productRouter.GET("/:product_id", productCont.GetProductByID)
+	productRouter.GET("/:product_id/reviews", reviewCont.GetReviewsByProductID)
 	productRouter.GET("/seller/:seller_id", productCont.GetProductsBySellerID)
 	productRouter.PUT("/:product_id", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), productCont.UpdateProduct)
-	productRouter.DELETE("/:product_id", productCont.DeleteProduct)
+	productRouter.DELETE("/:product_id", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), productCont.DeleteProduct)
+	productRouter.GET("/seller/:seller_id/listings", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), productCont.GetSellerListings)
+	productRouter.POST("/:product_id/publish", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), productCont.PublishProduct)
+	productRouter.POST("/:product_id/archive", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), productCont.ArchiveProduct)
+	productRouter.POST("/:product_id/restore", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), productCont.RestoreProduct)
 
 	//test
 
@@ -12,11 +12,24 @@ func (r Router) AddReviewRouter(rg *gin.RouterGroup) {
 	reviewRouter := rg.Group("review")
 
 	reviewRouter.POST("/", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), reviewCont.CreateReview)
+	reviewRouter.POST("/image", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), reviewCont.UploadReviewImage)
 	reviewRouter.GET("/", reviewCont.GetReviews)
 	reviewRouter.GET("/:review_id", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), reviewCont.GetReviewByID)
 	reviewRouter.GET("/seller/:seller_id", reviewCont.GetReviewsBySellerID)
 	reviewRouter.GET("/buyer/:buyer_id", reviewCont.GetReviewsByBuyerID)
 	reviewRouter.PUT("/:review_id", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), reviewCont.UpdateReview)
-	reviewRouter.DELETE("/:review_id", reviewCont.DeleteReview)
+	reviewRouter.DELETE("/:review_id", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), reviewCont.DeleteReview)
+	reviewRouter.POST("/:review_id/reply", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), reviewCont.ReplyToReview)
+	reviewRouter.POST("/:review_id/report", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), reviewCont.ReportReview)
+
+	moderationRouter := reviewRouter.Group("")
+	moderationRouter.Use(
+		middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf),
+		middleware.AdminOnly(&r.deps.conf.Admin),
+	)
+	moderationRouter.GET("/moderation", reviewCont.GetModerationQueue)
+	moderationRouter.POST("/:review_id/hide", reviewCont.HideReview)
+	moderationRouter.POST("/:review_id/remove", reviewCont.RemoveReview)
+	moderationRouter.POST("/:review_id/restore", reviewCont.RestoreReview)
 
 }
@@ -6,8 +6,8 @@ import (
 	docs "github.com/Dongy-s-Advanture/back-end/docs"
 	"github.com/Dongy-s-Advanture/back-end/internal/config"
 	"github.com/Dongy-s-Advanture/back-end/internal/middleware"
+	"github.com/Dongy-s-Advanture/back-end/internal/storage"
 	"github.com/Dongy-s-Advanture/back-end/pkg/redis"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/gin-gonic/gin"
 	rd "github.com/redis/go-redis/v9"
 	swaggerfiles "github.com/swaggo/files"
@@ -25,7 +25,7 @@ func NewRouter(g *gin.Engine, conf *config.Config) *Router {
 	return &Router{g, conf, nil}
 }
 
-func (r *Router) Run(mongoDB *mongo.Database, redisDB *rd.Client, s3Client *s3.Client) {
+func (r *Router) Run(mongoDB *mongo.Database, redisDB *rd.Client, store storage.Storage) {
 
 	r.g.Use(middleware.CORS())
 	if r.conf.App.Env == "production" {
@@ -48,7 +48,7 @@ func (r *Router) Run(mongoDB *mongo.Database, redisDB *rd.Client, s3Client *s3.C
 	redisAdapter := redis.NewGoRedisAdapter(redisDB)
 
 	// setup
-	r.deps = NewDependencies(mongoDB, redisAdapter, s3Client, r.conf)
+	r.deps = NewDependencies(mongoDB, redisAdapter, store, r.conf)
 
 	// Add related path
 	r.AddSellerRouter(v1)
@@ -56,10 +56,18 @@ func (r *Router) Run(mongoDB *mongo.Database, redisDB *rd.Client, s3Client *s3.C
 	r.AddAuthRouter(v1)
 	r.AddProductRouter(v1)
 	r.AddOrderRouter(v1)
+	r.AddCouponRouter(v1)
+	r.AddCommissionRouter(v1)
 	r.AddReviewRouter(v1)
 	r.AddAppointmentRouter(v1)
+	r.AddMeetupRouter(v1)
+	r.AddAddressRouter(v1)
+	r.AddConversationRouter(v1)
+	r.AddNotificationRouter(v1)
+	r.AddOutboxRouter(v1)
 	r.AddPaymentRouter(v1)
 	r.AddAdvertisementRouter(v1)
+	r.AddStorageRouter(v1)
 
 	err := r.g.Run(":" + r.conf.App.Port)
 	if err != nil {
@@ -9,6 +9,8 @@ import (
 func (r Router) AddSellerRouter(rg *gin.RouterGroup) {
 
 	sellerCont := r.deps.SellerController
+	appointmentCont := r.deps.AppointmentController
+	reviewCont := r.deps.ReviewController
 	sellerRouter := rg.Group("seller")
 
 	sellerRouter.POST("/", sellerCont.CreateSeller)
@@ -17,4 +19,7 @@ func (r Router) AddSellerRouter(rg *gin.RouterGroup) {
 	sellerRouter.PUT("/:seller_id", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), sellerCont.UpdateSeller)
 	sellerRouter.POST("/:seller_id/withdraw", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), sellerCont.WithdrawSellerBalance)
 	sellerRouter.GET("/:seller_id/balance", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), sellerCont.GetSellerBalanceByID)
+	sellerRouter.GET("/:seller_id/rating", reviewCont.GetSellerRating)
+	sellerRouter.GET("/:seller_id/availability", appointmentCont.GetOpenSlots)
+	sellerRouter.PUT("/:seller_id/availability", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), appointmentCont.SetAvailability)
 }
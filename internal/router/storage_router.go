@@ -0,0 +1,23 @@
+package router
+
+import (
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/tokenmode"
+	"github.com/Dongy-s-Advanture/back-end/internal/middleware"
+	"github.com/Dongy-s-Advanture/back-end/internal/storage"
+	"github.com/gin-gonic/gin"
+)
+
+func (r Router) AddStorageRouter(rg *gin.RouterGroup) {
+
+	storageCont := r.deps.StorageController
+	storageRouter := rg.Group("storage")
+
+	storageRouter.POST("/presign", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), storageCont.PresignUpload)
+	storageRouter.POST("/complete", middleware.JWTAuthMiddleWare(tokenmode.ACCESS_TOKEN, r.deps.redis, r.deps.conf), storageCont.CompleteUpload)
+
+	// Files of the local driver are served by the API itself
+	if local, ok := r.deps.storage.(*storage.LocalStorage); ok {
+		r.g.Static("/uploads", local.Dir())
+		r.g.PUT("/uploads/*filepath", storageCont.LocalUpload)
+	}
+}
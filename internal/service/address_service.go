@@ -0,0 +1,119 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/thaiaddress"
+)
+
+var ErrInvalidAddress = errors.New("invalid address")
+
+type IAddressService interface {
+	GetProvinces() []dto.AddressArea
+	GetDistricts(province string) ([]dto.AddressArea, error)
+	GetSubdistricts(province string, district string) ([]dto.AddressArea, error)
+	LookupZip(zip string) ([]dto.AddressMatch, error)
+}
+
+type AddressService struct {
+	dataset *thaiaddress.Dataset
+}
+
+func NewAddressService() IAddressService {
+	return AddressService{
+		dataset: thaiaddress.Default(),
+	}
+}
+
+func (s AddressService) GetProvinces() []dto.AddressArea {
+	provinces := make([]dto.AddressArea, 0, len(s.dataset.Provinces))
+	for _, p := range s.dataset.Provinces {
+		provinces = append(provinces, dto.AddressArea{Code: p.Code, NameTH: p.NameTH, NameEN: p.NameEN})
+	}
+	return provinces
+}
+
+func (s AddressService) GetDistricts(province string) ([]dto.AddressArea, error) {
+	p, ok := s.dataset.Province(province)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", thaiaddress.ErrUnknownProvince, province)
+	}
+	districts := make([]dto.AddressArea, 0, len(p.Districts))
+	for _, d := range p.Districts {
+		districts = append(districts, dto.AddressArea{NameTH: d.NameTH, NameEN: d.NameEN})
+	}
+	return districts, nil
+}
+
+func (s AddressService) GetSubdistricts(province string, district string) ([]dto.AddressArea, error) {
+	p, ok := s.dataset.Province(province)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", thaiaddress.ErrUnknownProvince, province)
+	}
+	d, ok := p.District(district)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q in %s", thaiaddress.ErrUnknownDistrict, district, p.NameEN)
+	}
+	subdistricts := make([]dto.AddressArea, 0, len(d.Subdistricts))
+	for _, sd := range d.Subdistricts {
+		subdistricts = append(subdistricts, dto.AddressArea{NameTH: sd.NameTH, NameEN: sd.NameEN, Zip: sd.Zip})
+	}
+	return subdistricts, nil
+}
+
+func (s AddressService) LookupZip(zip string) ([]dto.AddressMatch, error) {
+	matches, err := s.dataset.LookupZip(zip)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAddress, err)
+	}
+	res := make([]dto.AddressMatch, 0, len(matches))
+	for _, m := range matches {
+		match := dto.AddressMatch{Province: m.Province.NameTH, Zip: zip}
+		if m.District != nil {
+			match.City = m.District.NameTH
+		}
+		if m.Subdistrict != nil {
+			match.Subdistrict = m.Subdistrict.NameTH
+		}
+		res = append(res, match)
+	}
+	return res, nil
+}
+
+// normalizeAddress checks that province, city and zip agree with each other
+// and rewrites them as spelled in the address dataset. In an update the
+// fields left empty are taken from current, the stored address, for the
+// check but stay empty so they aren't written. Nothing is checked when all
+// three are empty, so stored addresses that predate the check still load.
+func normalizeAddress(province, city, zip *string, current thaiaddress.Address) error {
+	if *province == "" && *city == "" && *zip == "" {
+		return nil
+	}
+	addr := current
+	if *province != "" {
+		addr.Province = *province
+	}
+	if *city != "" {
+		addr.District = *city
+	}
+	if *zip != "" {
+		addr.Zip = *zip
+	}
+
+	normalized, err := thaiaddress.Default().Normalize(addr)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAddress, err)
+	}
+	if *province != "" {
+		*province = normalized.Province
+	}
+	if *city != "" {
+		*city = normalized.District
+	}
+	if *zip != "" {
+		*zip = normalized.Zip
+	}
+	return nil
+}
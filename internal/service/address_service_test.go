@@ -0,0 +1,45 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/thaiaddress"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeAddress(t *testing.T) {
+	province, city, zip := "chiang mai", "", "50200"
+	assert.NoError(t, normalizeAddress(&province, &city, &zip, thaiaddress.Address{}))
+	assert.Equal(t, "เชียงใหม่", province)
+	assert.Equal(t, "", city)
+
+	province, city, zip = "Chiang Mai", "", "10330"
+	assert.ErrorIs(t, normalizeAddress(&province, &city, &zip, thaiaddress.Address{}), ErrInvalidAddress)
+
+	province, city, zip = "Atlantis", "", ""
+	assert.ErrorIs(t, normalizeAddress(&province, &city, &zip, thaiaddress.Address{}), ErrInvalidAddress)
+
+	// Nothing to check, even when the stored address is wrong
+	province, city, zip = "", "", ""
+	assert.NoError(t, normalizeAddress(&province, &city, &zip, thaiaddress.Address{Province: "Atlantis"}))
+}
+
+func TestNormalizeAddressUpdate(t *testing.T) {
+	current := thaiaddress.Address{Province: "กรุงเทพมหานคร", Zip: "10330"}
+
+	// A new zip is checked against the stored province
+	province, city, zip := "", "", "50200"
+	assert.ErrorIs(t, normalizeAddress(&province, &city, &zip, current), ErrInvalidAddress)
+
+	province, city, zip = "", "", "10110"
+	assert.NoError(t, normalizeAddress(&province, &city, &zip, current))
+	assert.Equal(t, "", province)
+
+	// Moving province needs a zip in the new one
+	province, city, zip = "Chiang Mai", "", ""
+	assert.ErrorIs(t, normalizeAddress(&province, &city, &zip, current), ErrInvalidAddress)
+
+	province, city, zip = "Chiang Mai", "", "50200"
+	assert.NoError(t, normalizeAddress(&province, &city, &zip, current))
+	assert.Equal(t, "เชียงใหม่", province)
+}
@@ -1,7 +1,11 @@
 package service
 
 import (
+	"errors"
+	"log"
+
 	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/uploadowner"
 	"github.com/Dongy-s-Advanture/back-end/internal/model"
 	"github.com/Dongy-s-Advanture/back-end/internal/repository"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -20,11 +24,13 @@ type IAdvertisementService interface {
 
 type AdvertisementService struct {
 	advertisementRepository repository.IAdvertisementRepository
+	uploadService           IUploadService
 }
 
-func NewAdvertisementService(r repository.IAdvertisementRepository) IAdvertisementService {
+func NewAdvertisementService(r repository.IAdvertisementRepository, uploadService IUploadService) IAdvertisementService {
 	return AdvertisementService{
 		advertisementRepository: r,
+		uploadService:           uploadService,
 	}
 }
 
@@ -69,34 +75,61 @@ func (s AdvertisementService) GetAdvertisementsByProductID(productID primitive.O
 }
 
 func (s AdvertisementService) CreateAdvertisement(advertisement *model.Advertisement) (*dto.Advertisement, error) {
-
+	if advertisement.Amount.IsNegative() {
+		return nil, errors.New("amount must not be negative")
+	}
 	newAdvertisement, err := s.advertisementRepository.CreateAdvertisement(advertisement)
 
 	if err != nil {
 		return nil, err
 	}
 
+	if err := s.uploadService.Claim(uploadowner.ADVERTISEMENT, newAdvertisement.AdvertisementID, imageURLs(newAdvertisement.ImageURL, newAdvertisement.Images)...); err != nil {
+		log.Printf("failed to claim uploads for advertisement %s: %v", newAdvertisement.AdvertisementID.Hex(), err)
+	}
+
 	return newAdvertisement, nil
 }
 
 
 func (s AdvertisementService) UpdateAdvertisement(advertisementID primitive.ObjectID, updatedAdvertisement *model.Advertisement) (*dto.Advertisement, error) {
+	if updatedAdvertisement.Amount.IsNegative() {
+		return nil, errors.New("amount must not be negative")
+	}
+	oldAdvertisement, err := s.advertisementRepository.GetAdvertisementByID(advertisementID)
+	if err != nil {
+		return nil, err
+	}
 
 	updatedAdvertisementDTO, err := s.advertisementRepository.UpdateAdvertisement(advertisementID, updatedAdvertisement)
 	if err != nil {
 		return nil, err
 	}
 
+	oldURLs := imageURLs(oldAdvertisement.ImageURL, oldAdvertisement.Images)
+	newURLs := imageURLs(updatedAdvertisementDTO.ImageURL, updatedAdvertisementDTO.Images)
+	if err := s.uploadService.Replace(uploadowner.ADVERTISEMENT, advertisementID, oldURLs, newURLs); err != nil {
+		log.Printf("failed to update uploads for advertisement %s: %v", advertisementID.Hex(), err)
+	}
+
 	return updatedAdvertisementDTO, nil
 }
 
 func (s AdvertisementService) DeleteAdvertisement(advertisementID primitive.ObjectID) error {
+	advertisement, err := s.advertisementRepository.GetAdvertisementByID(advertisementID)
+	if err != nil {
+		return err
+	}
 
-	err := s.advertisementRepository.DeleteAdvertisement(advertisementID)
+	err = s.advertisementRepository.DeleteAdvertisement(advertisementID)
 	if err != nil {
 		return err 
 	}
 
+	if err := s.uploadService.Release(uploadowner.ADVERTISEMENT, advertisementID, imageURLs(advertisement.ImageURL, advertisement.Images)...); err != nil {
+		log.Printf("failed to release uploads for advertisement %s: %v", advertisementID.Hex(), err)
+	}
+
 	return nil 
 }
 
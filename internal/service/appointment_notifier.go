@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/notificationtype"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/proposalkind"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/proposalstatus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IAppointmentNotifier tells the buyer and the seller about their
+// appointment. It is called after the change has been saved. Remind is
+// delivered before it returns so a failed reminder can be sent again.
+type IAppointmentNotifier interface {
+	Remind(appointment *dto.Appointment, lead time.Duration) error
+	NoShowReported(appointment *dto.Appointment)
+	Proposed(appointment *dto.Appointment, proposal *dto.AppointmentProposal)
+	ProposalAnswered(appointment *dto.Appointment, proposal *dto.AppointmentProposal)
+}
+
+// LogNotifier writes appointment notifications to the log
+type LogNotifier struct{}
+
+func NewLogNotifier() IAppointmentNotifier {
+	return LogNotifier{}
+}
+
+func (n LogNotifier) Remind(appointment *dto.Appointment, lead time.Duration) error {
+	for _, userID := range []string{appointment.BuyerID.Hex(), appointment.SellerID.Hex()} {
+		log.Printf("appointment reminder for %s: appointment %s starts in %s at %s", userID, appointment.AppointmentID.Hex(), lead, appointment.SlotStart.In(AppointmentZone).Format("2006-01-02 15:04"))
+	}
+	return nil
+}
+
+func (n LogNotifier) NoShowReported(appointment *dto.Appointment) {
+	if appointment.NoShow == nil {
+		return
+	}
+	log.Printf("no-show alert for %s: reported missing from appointment %s by %s", appointment.NoShow.AbsentID.Hex(), appointment.AppointmentID.Hex(), appointment.NoShow.ReportedBy.Hex())
+}
+
+func (n LogNotifier) Proposed(appointment *dto.Appointment, proposal *dto.AppointmentProposal) {
+	log.Printf("proposal %s on appointment %s by %s: %s", proposal.ProposalID.Hex(), appointment.AppointmentID.Hex(), proposal.ProposedBy.Hex(), proposalSummary(proposal))
+}
+
+func (n LogNotifier) ProposalAnswered(appointment *dto.Appointment, proposal *dto.AppointmentProposal) {
+	log.Printf("proposal %s on appointment %s answered: %d", proposal.ProposalID.Hex(), appointment.AppointmentID.Hex(), proposal.Status)
+}
+
+// AppointmentNotifier sends appointment notifications through the
+// notification center
+type AppointmentNotifier struct {
+	dispatcher INotificationDispatcher
+}
+
+func NewAppointmentNotifier(d INotificationDispatcher) IAppointmentNotifier {
+	return AppointmentNotifier{dispatcher: d}
+}
+
+func (n AppointmentNotifier) Remind(appointment *dto.Appointment, lead time.Duration) error {
+	event := appointmentNotification(appointment, notificationtype.APPOINTMENT_REMINDER,
+		"Appointment reminder",
+		fmt.Sprintf("Your appointment starts in %s, at %s.", lead, appointment.SlotStart.In(AppointmentZone).Format("2006-01-02 15:04")),
+	)
+	event.Key = fmt.Sprintf("%s/reminder/%d", appointment.AppointmentID.Hex(), int(lead.Minutes()))
+	return errors.Join(
+		n.dispatcher.NotifyNow(context.Background(), appointment.BuyerID, event),
+		n.dispatcher.NotifyNow(context.Background(), appointment.SellerID, event),
+	)
+}
+
+func (n AppointmentNotifier) NoShowReported(appointment *dto.Appointment) {
+	if appointment.NoShow == nil {
+		return
+	}
+	n.dispatcher.Notify(appointment.NoShow.AbsentID, appointmentNotification(appointment, notificationtype.NO_SHOW,
+		"Missed appointment",
+		"You were reported as not showing up to your appointment.",
+	))
+}
+
+func (n AppointmentNotifier) Proposed(appointment *dto.Appointment, proposal *dto.AppointmentProposal) {
+	otherID, ok := otherParty(appointment, proposal.ProposedBy)
+	if !ok {
+		return
+	}
+	event := appointmentNotification(appointment, notificationtype.APPOINTMENT_PROPOSAL,
+		"New appointment proposal",
+		fmt.Sprintf("You were asked to %s.", proposalSummary(proposal)),
+	)
+	event.Data["proposalID"] = proposal.ProposalID.Hex()
+	n.dispatcher.Notify(otherID, event)
+}
+
+func (n AppointmentNotifier) ProposalAnswered(appointment *dto.Appointment, proposal *dto.AppointmentProposal) {
+	answer := "rejected"
+	if proposal.Status == proposalstatus.ACCEPTED {
+		answer = "accepted"
+	}
+	event := appointmentNotification(appointment, notificationtype.APPOINTMENT_PROPOSAL,
+		"Appointment proposal "+answer,
+		fmt.Sprintf("Your proposal to %s was %s.", proposalSummary(proposal), answer),
+	)
+	event.Data["proposalID"] = proposal.ProposalID.Hex()
+	n.dispatcher.Notify(proposal.ProposedBy, event)
+}
+
+func appointmentNotification(appointment *dto.Appointment, eventType string, title string, body string) dto.NotificationEvent {
+	return dto.NotificationEvent{
+		Type:  eventType,
+		Title: title,
+		Body:  body,
+		Data: map[string]string{
+			"appointmentID": appointment.AppointmentID.Hex(),
+			"orderID":       appointment.OrderID.Hex(),
+		},
+	}
+}
+
+// proposalSummary describes what a proposal asks for, e.g. "meet on
+// 2025-01-31 at 10:00-11:00"
+func proposalSummary(proposal *dto.AppointmentProposal) string {
+	if proposal.Kind == proposalkind.TIME {
+		return fmt.Sprintf("meet on %s at %s", proposal.Date.Format(time.DateOnly), proposal.TimeSlot)
+	}
+	return fmt.Sprintf("meet at %s, %s", proposal.Address, proposal.Province)
+}
+
+// findProposal finds a proposal of the appointment by its ID
+func findProposal(appointment *dto.Appointment, proposalID primitive.ObjectID) *dto.AppointmentProposal {
+	for i := range appointment.Proposals {
+		if appointment.Proposals[i].ProposalID == proposalID {
+			return &appointment.Proposals[i]
+		}
+	}
+	return nil
+}
@@ -1,28 +1,80 @@
 package service
 
 import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/config"
 	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/orderstatus"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/proposalkind"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/proposalstatus"
 	"github.com/Dongy-s-Advanture/back-end/internal/model"
 	"github.com/Dongy-s-Advanture/back-end/internal/repository"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/thaiaddress"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrInvalidAvailability = errors.New("invalid availability")
+	ErrInvalidTimeSlot     = errors.New("invalid time slot")
+	ErrSlotUnavailable     = errors.New("the seller is not available at this time")
+	ErrSlotTaken           = errors.New("this time slot is already booked")
+	ErrInvalidProposal     = errors.New("invalid proposal")
+	ErrProposalNotFound    = errors.New("proposal not found")
+	ErrProposalNotPending  = errors.New("proposal is no longer pending")
+	ErrProposalForbidden   = errors.New("only the other party of the appointment can answer this proposal")
+	ErrNoShowForbidden     = errors.New("only the buyer and the seller can report a no-show")
+	ErrNoShowTooEarly      = errors.New("a no-show can't be reported yet")
+	ErrNoShowReported      = errors.New("a no-show was already reported for this appointment")
+	ErrAppointmentClosed   = errors.New("the order of this appointment was cancelled")
 )
 
+// AppointmentZone is the time zone of sellers' opening hours
+var AppointmentZone = time.FixedZone("ICT", 7*60*60)
+
+// maxSlotRange is the longest range of open slots returned at once
+const maxSlotRange = 62 * 24 * time.Hour
+
 type IAppointmentService interface {
 	GetAppointments() ([]dto.Appointment, error)
 	GetAppointmentByID(appointmentID primitive.ObjectID) (*dto.Appointment, error)
 	GetAppointmentByOrderID(orderID primitive.ObjectID) (*dto.Appointment, error)
 	CreateAppointment(appointment *model.Appointment) (*dto.Appointment, error)
-	UpdateAppointmentDate(appointmentID primitive.ObjectID, updatedAppointment *model.Appointment) (*dto.Appointment, error)
-	UpdateAppointmentPlace(appointmentID primitive.ObjectID, updatedAppointment *model.Appointment) (*dto.Appointment, error)
+	SetAvailability(sellerID primitive.ObjectID, req *dto.AvailabilityRequest) (*dto.SellerAvailability, error)
+	GetOpenSlots(sellerID primitive.ObjectID, from time.Time, to time.Time) (*dto.OpenSlots, error)
+	Propose(appointmentID primitive.ObjectID, callerID primitive.ObjectID, req *dto.AppointmentProposalRequest) (*dto.Appointment, error)
+	AcceptProposal(appointmentID primitive.ObjectID, proposalID primitive.ObjectID, callerID primitive.ObjectID) (*dto.Appointment, error)
+	RejectProposal(appointmentID primitive.ObjectID, proposalID primitive.ObjectID, callerID primitive.ObjectID) (*dto.Appointment, error)
+	ReportNoShow(appointmentID primitive.ObjectID, callerID primitive.ObjectID) (*dto.Appointment, error)
+	SendDueReminders(now time.Time) (int, error)
 }
 
 type AppointmentService struct {
-	appointmentRepository repository.IAppointmentRepository
+	appointmentRepository  repository.IAppointmentRepository
+	availabilityRepository repository.IAvailabilityRepository
+	orderRepository        repository.IOrderRepository
+	orderService           IOrderService
+	notifier               IAppointmentNotifier
+	reminderLeads          []time.Duration
+	noShowGrace            time.Duration
 }
 
-func NewAppointmentService(r repository.IAppointmentRepository) IAppointmentService {
+func NewAppointmentService(r repository.IAppointmentRepository, ar repository.IAvailabilityRepository, or repository.IOrderRepository, orderService IOrderService, notifier IAppointmentNotifier, appointmentCfg *config.AppointmentConfig) IAppointmentService {
 	return AppointmentService{
-		appointmentRepository: r,
+		appointmentRepository:  r,
+		availabilityRepository: ar,
+		orderRepository:        or,
+		orderService:           orderService,
+		notifier:               notifier,
+		reminderLeads:          appointmentCfg.ReminderLeads,
+		noShowGrace:            time.Duration(appointmentCfg.NoShowGraceMinutes) * time.Minute,
 	}
 }
 
@@ -61,25 +113,493 @@ func (s AppointmentService) CreateAppointment(appointment *model.Appointment) (*
 	return newAppointment, nil
 }
 
+// resolveSlot finds the seller's open slot starting at timeSlot on the
+// date. The slot currently held by the appointment counts as open.
+func (s AppointmentService) resolveSlot(appointment *dto.Appointment, date time.Time, timeSlot string) (*dto.TimeSlot, error) {
+	// "10:00-10:30" is accepted as well as the start alone
+	start, _, _ := strings.Cut(timeSlot, "-")
+	clock, err := parseClock(strings.TrimSpace(start))
+	if err != nil {
+		return nil, ErrInvalidTimeSlot
+	}
+	year, month, day := date.Date()
+	slotStart := time.Date(year, month, day, 0, 0, 0, 0, AppointmentZone).Add(clock)
 
-func (s AppointmentService) UpdateAppointmentDate(appointmentID primitive.ObjectID, updatedAppointment *model.Appointment) (*dto.Appointment, error) {
+	open, err := s.openSlots(appointment.SellerID, slotStart, slotStart.Add(time.Minute), &appointment.AppointmentID)
+	if err != nil {
+		return nil, err
+	}
+	if len(open.Slots) == 0 {
+		return nil, ErrSlotUnavailable
+	}
+	return &open.Slots[0], nil
+}
 
-	updatedAppointmentDTO, err := s.appointmentRepository.UpdateAppointmentDate(appointmentID, updatedAppointment)
+func (s AppointmentService) bookSlot(appointment *dto.Appointment, slot *dto.TimeSlot) (*dto.Appointment, error) {
+	year, month, day := slot.Start.In(AppointmentZone).Date()
+	updatedAppointmentDTO, err := s.appointmentRepository.UpdateAppointmentDate(appointment.AppointmentID, &model.Appointment{
+		Date:      time.Date(year, month, day, 0, 0, 0, 0, time.UTC),
+		TimeSlot:  slotLabel(slot),
+		SlotStart: slot.Start,
+		SlotEnd:   slot.End,
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, ErrSlotTaken
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	s.advanceOrder(updatedAppointmentDTO)
 	return updatedAppointmentDTO, nil
 }
 
-func (s AppointmentService) UpdateAppointmentPlace(appointmentID primitive.ObjectID, updatedAppointment *model.Appointment) (*dto.Appointment, error) {
+// advanceOrder moves the order along once the appointment has a place and
+// then a time
+func (s AppointmentService) advanceOrder(appointment *dto.Appointment) {
+	if appointment.Address != "" {
+		if _, err := s.orderRepository.AdvanceOrderStatus(appointment.OrderID, orderstatus.WAITFORLOCATION, orderstatus.WAITFORTIME); err != nil {
+			log.Printf("failed to mark order %s as waiting for a time: %v", appointment.OrderID.Hex(), err)
+		}
+	}
+	if !appointment.SlotStart.IsZero() {
+		if _, err := s.orderRepository.AdvanceOrderStatus(appointment.OrderID, orderstatus.WAITFORTIME, orderstatus.APPOINTED); err != nil {
+			log.Printf("failed to mark order %s as appointed: %v", appointment.OrderID.Hex(), err)
+		}
+	}
+}
+
+func (s AppointmentService) SetAvailability(sellerID primitive.ObjectID, req *dto.AvailabilityRequest) (*dto.SellerAvailability, error) {
+	if req.SlotMinutes <= 0 {
+		return nil, fmt.Errorf("%w: slot length must be positive", ErrInvalidAvailability)
+	}
+	availability := &model.SellerAvailability{
+		SellerID:    sellerID,
+		SlotMinutes: req.SlotMinutes,
+		Weekly:      []model.WeeklyWindow{},
+		Blackouts:   []time.Time{},
+	}
+	for _, window := range req.Weekly {
+		start, startErr := parseClock(window.Start)
+		end, endErr := parseClock(window.End)
+		if startErr != nil || endErr != nil || window.Weekday < time.Sunday || window.Weekday > time.Saturday {
+			return nil, fmt.Errorf("%w: bad window %v %s-%s", ErrInvalidAvailability, window.Weekday, window.Start, window.End)
+		}
+		if end-start < time.Duration(req.SlotMinutes)*time.Minute {
+			return nil, fmt.Errorf("%w: window %s-%s is shorter than a slot", ErrInvalidAvailability, window.Start, window.End)
+		}
+		availability.Weekly = append(availability.Weekly, model.WeeklyWindow{
+			Weekday: window.Weekday,
+			Start:   window.Start,
+			End:     window.End,
+		})
+	}
+	for _, date := range req.Blackouts {
+		day, err := time.ParseInLocation(time.DateOnly, date, AppointmentZone)
+		if err != nil {
+			return nil, fmt.Errorf("%w: bad blackout date %q", ErrInvalidAvailability, date)
+		}
+		availability.Blackouts = append(availability.Blackouts, day)
+	}
+
+	return s.availabilityRepository.SetAvailability(availability)
+}
+
+// GetOpenSlots lists the seller's free slots starting between from and to
+func (s AppointmentService) GetOpenSlots(sellerID primitive.ObjectID, from time.Time, to time.Time) (*dto.OpenSlots, error) {
+	if !to.After(from) || to.Sub(from) > maxSlotRange {
+		return nil, ErrInvalidTimeSlot
+	}
+	return s.openSlots(sellerID, from, to, nil)
+}
 
-	updatedAppointmentDTO, err := s.appointmentRepository.UpdateAppointmentPlace(appointmentID, updatedAppointment)
+// openSlots lists free slots, treating the slot held by the appointment
+// being rescheduled, if any, as free
+func (s AppointmentService) openSlots(sellerID primitive.ObjectID, from time.Time, to time.Time, rescheduling *primitive.ObjectID) (*dto.OpenSlots, error) {
+	availability, err := s.availabilityRepository.GetAvailability(sellerID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return &dto.OpenSlots{Slots: []dto.TimeSlot{}}, nil
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	return updatedAppointmentDTO, nil
+	booked, err := s.appointmentRepository.GetBookedSlots(sellerID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	taken := make(map[int64]bool)
+	for _, appointment := range booked {
+		if rescheduling == nil || appointment.AppointmentID != *rescheduling {
+			taken[appointment.SlotStart.Unix()] = true
+		}
+	}
+
+	return &dto.OpenSlots{
+		Availability: availability,
+		Slots:        availableSlots(availability, from, to, taken, time.Now()),
+	}, nil
 }
 
+// availableSlots expands the weekly windows into slots starting between
+// from and to, skipping blackout days, past slots and taken slot starts
+func availableSlots(availability *dto.SellerAvailability, from time.Time, to time.Time, taken map[int64]bool, now time.Time) []dto.TimeSlot {
+	slots := []dto.TimeSlot{}
+	length := time.Duration(availability.SlotMinutes) * time.Minute
+	if length <= 0 {
+		return slots
+	}
+
+	blackout := make(map[string]bool)
+	for _, day := range availability.Blackouts {
+		blackout[day.In(AppointmentZone).Format(time.DateOnly)] = true
+	}
+
+	seen := make(map[int64]bool)
+	year, month, day := from.In(AppointmentZone).Date()
+	for date := time.Date(year, month, day, 0, 0, 0, 0, AppointmentZone); date.Before(to); date = date.AddDate(0, 0, 1) {
+		if blackout[date.Format(time.DateOnly)] {
+			continue
+		}
+		for _, window := range availability.Weekly {
+			if window.Weekday != date.Weekday() {
+				continue
+			}
+			start, startErr := parseClock(window.Start)
+			end, endErr := parseClock(window.End)
+			if startErr != nil || endErr != nil {
+				continue
+			}
+			for offset := start; offset+length <= end; offset += length {
+				slotStart := date.Add(offset)
+				key := slotStart.Unix()
+				if slotStart.Before(from) || !slotStart.Before(to) || !slotStart.After(now) || taken[key] || seen[key] {
+					continue
+				}
+				seen[key] = true
+				slots = append(slots, dto.TimeSlot{Start: slotStart, End: slotStart.Add(length)})
+			}
+		}
+	}
+
+	sort.Slice(slots, func(i, j int) bool { return slots[i].Start.Before(slots[j].Start) })
+	return slots
+}
+
+// parseClock reads "15:04" as the time since midnight
+func parseClock(clock string) (time.Duration, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Propose adds a place or time proposal from the buyer or the seller. Any
+// pending proposal of the same kind is closed, as countered when it came
+// from the other party.
+func (s AppointmentService) Propose(appointmentID primitive.ObjectID, callerID primitive.ObjectID, req *dto.AppointmentProposalRequest) (*dto.Appointment, error) {
+	appointment, err := s.appointmentRepository.GetAppointmentByID(appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	otherID, ok := otherParty(appointment, callerID)
+	if !ok {
+		return nil, ErrProposalForbidden
+	}
+	if appointment.Cancellation != nil {
+		return nil, ErrAppointmentClosed
+	}
+
+	proposal := &model.AppointmentProposal{
+		Kind:       req.Kind,
+		Status:     proposalstatus.PENDING,
+		ProposedBy: callerID,
+	}
+	switch req.Kind {
+	case proposalkind.PLACE:
+		if strings.TrimSpace(req.Address) == "" || strings.TrimSpace(req.Province) == "" {
+			return nil, fmt.Errorf("%w: address and province are required", ErrInvalidProposal)
+		}
+		if err := validateLocation(req.Location); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidProposal, err)
+		}
+		if err := normalizeAddress(&req.Province, &req.City, &req.Zip, thaiaddress.Address{}); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidProposal, err)
+		}
+		proposal.Address = req.Address
+		proposal.City = req.City
+		proposal.Province = req.Province
+		proposal.Zip = req.Zip
+		proposal.Location = req.Location
+	case proposalkind.TIME:
+		date, err := time.Parse(time.DateOnly, req.Date)
+		if err != nil {
+			return nil, fmt.Errorf("%w: date must be YYYY-MM-DD", ErrInvalidProposal)
+		}
+		slot, err := s.resolveSlot(appointment, date, req.TimeSlot)
+		if err != nil {
+			return nil, err
+		}
+		proposal.Date = date
+		proposal.TimeSlot = slotLabel(slot)
+		proposal.SlotStart = slot.Start
+		proposal.SlotEnd = slot.End
+	default:
+		return nil, fmt.Errorf("%w: unknown kind %d", ErrInvalidProposal, req.Kind)
+	}
+
+	if err := s.appointmentRepository.CloseProposals(appointmentID, req.Kind, otherID, proposalstatus.COUNTERED); err != nil {
+		return nil, err
+	}
+	if err := s.appointmentRepository.CloseProposals(appointmentID, req.Kind, callerID, proposalstatus.WITHDRAWN); err != nil {
+		return nil, err
+	}
+	updatedAppointment, err := s.appointmentRepository.AddProposal(appointmentID, proposal)
+	if err != nil {
+		return nil, err
+	}
+	if added := findProposal(updatedAppointment, proposal.ProposalID); added != nil {
+		s.notifier.Proposed(updatedAppointment, added)
+	}
+	return updatedAppointment, nil
+}
+
+// AcceptProposal applies the other party's pending proposal to the
+// appointment and moves the order along
+func (s AppointmentService) AcceptProposal(appointmentID primitive.ObjectID, proposalID primitive.ObjectID, callerID primitive.ObjectID) (*dto.Appointment, error) {
+	appointment, proposal, err := s.pendingProposal(appointmentID, proposalID, callerID)
+	if err != nil {
+		return nil, err
+	}
 
+	// Claim the proposal first so it is applied once, and hand it back if
+	// applying it fails
+	moved, err := s.appointmentRepository.SetProposalStatus(appointmentID, proposalID, proposalstatus.PENDING, proposalstatus.ACCEPTED)
+	if err != nil {
+		return nil, err
+	}
+	if !moved {
+		return nil, ErrProposalNotPending
+	}
+
+	var updatedAppointment *dto.Appointment
+	switch proposal.Kind {
+	case proposalkind.PLACE:
+		updatedAppointment, err = s.appointmentRepository.UpdateAppointmentPlace(appointmentID, &model.Appointment{
+			Address:  proposal.Address,
+			City:     proposal.City,
+			Province: proposal.Province,
+			Zip:      proposal.Zip,
+			Location: proposal.Location,
+		})
+		if err == nil {
+			s.advanceOrder(updatedAppointment)
+		}
+	case proposalkind.TIME:
+		var slot *dto.TimeSlot
+		slot, err = s.resolveSlot(appointment, proposal.Date, proposal.TimeSlot)
+		if err == nil {
+			updatedAppointment, err = s.bookSlot(appointment, slot)
+		}
+	}
+	if err != nil {
+		if _, revertErr := s.appointmentRepository.SetProposalStatus(appointmentID, proposalID, proposalstatus.ACCEPTED, proposalstatus.PENDING); revertErr != nil {
+			log.Printf("failed to reopen proposal %s: %v", proposalID.Hex(), revertErr)
+		}
+		return nil, err
+	}
+
+	return s.answered(appointmentID, proposalID)
+}
+
+func (s AppointmentService) RejectProposal(appointmentID primitive.ObjectID, proposalID primitive.ObjectID, callerID primitive.ObjectID) (*dto.Appointment, error) {
+	if _, _, err := s.pendingProposal(appointmentID, proposalID, callerID); err != nil {
+		return nil, err
+	}
+	moved, err := s.appointmentRepository.SetProposalStatus(appointmentID, proposalID, proposalstatus.PENDING, proposalstatus.REJECTED)
+	if err != nil {
+		return nil, err
+	}
+	if !moved {
+		return nil, ErrProposalNotPending
+	}
+	return s.answered(appointmentID, proposalID)
+}
+
+// answered reloads the appointment once a proposal has been answered and
+// lets the proposer know
+func (s AppointmentService) answered(appointmentID primitive.ObjectID, proposalID primitive.ObjectID) (*dto.Appointment, error) {
+	appointment, err := s.appointmentRepository.GetAppointmentByID(appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	if proposal := findProposal(appointment, proposalID); proposal != nil {
+		s.notifier.ProposalAnswered(appointment, proposal)
+	}
+	return appointment, nil
+}
+
+// pendingProposal finds a pending proposal the caller may answer, one made
+// by the other party of the appointment
+func (s AppointmentService) pendingProposal(appointmentID primitive.ObjectID, proposalID primitive.ObjectID, callerID primitive.ObjectID) (*dto.Appointment, *dto.AppointmentProposal, error) {
+	appointment, err := s.appointmentRepository.GetAppointmentByID(appointmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	otherID, ok := otherParty(appointment, callerID)
+	if !ok {
+		return nil, nil, ErrProposalForbidden
+	}
+	// Its slot was given up, so it can't be booked again
+	if appointment.Cancellation != nil {
+		return nil, nil, ErrAppointmentClosed
+	}
+	for i := range appointment.Proposals {
+		proposal := &appointment.Proposals[i]
+		if proposal.ProposalID != proposalID {
+			continue
+		}
+		if proposal.ProposedBy != otherID {
+			return nil, nil, ErrProposalForbidden
+		}
+		if proposal.Status != proposalstatus.PENDING {
+			return nil, nil, ErrProposalNotPending
+		}
+		return appointment, proposal, nil
+	}
+	return nil, nil, ErrProposalNotFound
+}
+
+// otherParty is the buyer when the caller is the seller and the other way
+// round, ok is false when the caller is neither
+func otherParty(appointment *dto.Appointment, callerID primitive.ObjectID) (primitive.ObjectID, bool) {
+	switch callerID {
+	case appointment.BuyerID:
+		return appointment.SellerID, true
+	case appointment.SellerID:
+		return appointment.BuyerID, true
+	}
+	return primitive.NilObjectID, false
+}
+
+func slotLabel(slot *dto.TimeSlot) string {
+	return slot.Start.In(AppointmentZone).Format("15:04") + "-" + slot.End.In(AppointmentZone).Format("15:04")
+}
+
+// ReportNoShow records that the other party of the appointment didn't show
+// up and has the order service apply the consequences. It can be reported
+// once, after the grace period from the start of the booked slot.
+func (s AppointmentService) ReportNoShow(appointmentID primitive.ObjectID, callerID primitive.ObjectID) (*dto.Appointment, error) {
+	appointment, err := s.appointmentRepository.GetAppointmentByID(appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	absentID, ok := otherParty(appointment, callerID)
+	if !ok {
+		return nil, ErrNoShowForbidden
+	}
+	if appointment.SlotStart.IsZero() {
+		return nil, ErrAppointmentUnscheduled
+	}
+	now := time.Now()
+	if now.Before(appointment.SlotStart.Add(s.noShowGrace)) {
+		return nil, ErrNoShowTooEarly
+	}
+
+	// Stored first so only one report is acted on, and taken back if the
+	// order can't be handled
+	stored, err := s.appointmentRepository.ReportNoShow(appointmentID, &model.NoShowReport{
+		ReportedBy: callerID,
+		AbsentID:   absentID,
+		ReportedAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !stored {
+		return nil, ErrNoShowReported
+	}
+	if err := s.orderService.HandleNoShow(appointment.OrderID, absentID); err != nil {
+		if clearErr := s.appointmentRepository.ClearNoShow(appointmentID); clearErr != nil {
+			log.Printf("failed to clear no-show of appointment %s: %v", appointmentID.Hex(), clearErr)
+		}
+		return nil, err
+	}
+
+	updatedAppointment, err := s.appointmentRepository.GetAppointmentByID(appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	s.notifier.NoShowReported(updatedAppointment)
+	return updatedAppointment, nil
+}
+
+// SendDueReminders sends the reminders that are due at now and returns how
+// many were sent. An appointment booked at short notice only gets the
+// closest reminder, the earlier ones are marked as sent without sending.
+func (s AppointmentService) SendDueReminders(now time.Time) (int, error) {
+	due := make(map[primitive.ObjectID]dto.Appointment)
+	for _, lead := range s.reminderLeads {
+		appointments, err := s.appointmentRepository.GetDueReminders(lead, now)
+		if err != nil {
+			return 0, err
+		}
+		for _, appointment := range appointments {
+			due[appointment.AppointmentID] = appointment
+		}
+	}
+
+	sent := 0
+	for _, appointment := range due {
+		lead, skipped, ok := dueReminder(s.reminderLeads, appointment.Reminders, appointment.SlotStart.Sub(now))
+		if !ok {
+			continue
+		}
+		for _, skip := range skipped {
+			if _, err := s.appointmentRepository.MarkReminderSent(appointment.AppointmentID, int(skip.Minutes())); err != nil {
+				log.Printf("failed to skip reminder of appointment %s: %v", appointment.AppointmentID.Hex(), err)
+			}
+		}
+		// Marked before sending so another instance can't send it too
+		marked, err := s.appointmentRepository.MarkReminderSent(appointment.AppointmentID, int(lead.Minutes()))
+		if err != nil {
+			log.Printf("failed to mark reminder of appointment %s: %v", appointment.AppointmentID.Hex(), err)
+			continue
+		}
+		if !marked {
+			continue
+		}
+		// Unmarked when it fails so the next sweep sends it again
+		if err := s.notifier.Remind(&appointment, lead); err != nil {
+			log.Printf("failed to send reminder of appointment %s: %v", appointment.AppointmentID.Hex(), err)
+			if err := s.appointmentRepository.UnmarkReminderSent(appointment.AppointmentID, int(lead.Minutes())); err != nil {
+				log.Printf("failed to unmark reminder of appointment %s: %v", appointment.AppointmentID.Hex(), err)
+			}
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// dueReminder picks the reminder to send for an appointment starting in
+// untilStart: the shortest lead not sent yet that has been reached. The
+// longer leads still unsent are returned as skipped.
+func dueReminder(leads []time.Duration, sent []int, untilStart time.Duration) (time.Duration, []time.Duration, bool) {
+	if untilStart <= 0 {
+		return 0, nil, false
+	}
+	var due []time.Duration
+	for _, lead := range leads {
+		if lead < untilStart || slices.Contains(sent, int(lead.Minutes())) {
+			continue
+		}
+		due = append(due, lead)
+	}
+	if len(due) == 0 {
+		return 0, nil, false
+	}
+	slices.Sort(due)
+	return due[0], due[1:], true
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/config"
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/orderstatus"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/proposalkind"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/proposalstatus"
+	"github.com/Dongy-s-Advanture/back-end/internal/model"
+	repomock "github.com/Dongy-s-Advanture/back-end/pkg/mock/repository"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAvailableSlots(t *testing.T) {
+	monday := time.Date(2025, 3, 3, 0, 0, 0, 0, AppointmentZone)
+	availability := &dto.SellerAvailability{
+		SlotMinutes: 30,
+		Weekly: []dto.WeeklyWindow{
+			{Weekday: time.Monday, Start: "10:00", End: "11:15"},
+			{Weekday: time.Tuesday, Start: "09:00", End: "10:00"},
+			{Weekday: time.Wednesday, Start: "09:00", End: "10:00"},
+		},
+		Blackouts: []time.Time{monday.AddDate(0, 0, 1)},
+	}
+	at := func(days int, clock time.Duration) dto.TimeSlot {
+		start := monday.AddDate(0, 0, days).Add(clock)
+		return dto.TimeSlot{Start: start, End: start.Add(30 * time.Minute)}
+	}
+	taken := map[int64]bool{at(0, 10*time.Hour+30*time.Minute).Start.Unix(): true}
+
+	// 11:00 would run past the window, 10:30 is taken and Tuesday is a blackout
+	slots := availableSlots(availability, monday, monday.AddDate(0, 0, 3), taken, monday)
+	assert.Equal(t, []dto.TimeSlot{at(0, 10*time.Hour), at(2, 9*time.Hour), at(2, 9*time.Hour+30*time.Minute)}, slots)
+
+	// Slots that already started are gone
+	slots = availableSlots(availability, monday, monday.AddDate(0, 0, 3), nil, at(2, 9*time.Hour).Start)
+	assert.Equal(t, []dto.TimeSlot{at(2, 9*time.Hour+30*time.Minute)}, slots)
+}
+
+func TestParseClock(t *testing.T) {
+	clock, err := parseClock("09:45")
+	assert.NoError(t, err)
+	assert.Equal(t, 9*time.Hour+45*time.Minute, clock)
+
+	_, err = parseClock("9.45")
+	assert.Error(t, err)
+}
+
+func TestDueReminder(t *testing.T) {
+	leads := []time.Duration{24 * time.Hour, time.Hour}
+
+	// A day out only the 24 hour reminder is due
+	lead, skipped, ok := dueReminder(leads, nil, 20*time.Hour)
+	assert.True(t, ok)
+	assert.Equal(t, 24*time.Hour, lead)
+	assert.Empty(t, skipped)
+
+	// Booked at short notice, the day-before reminder is skipped
+	lead, skipped, ok = dueReminder(leads, nil, 30*time.Minute)
+	assert.True(t, ok)
+	assert.Equal(t, time.Hour, lead)
+	assert.Equal(t, []time.Duration{24 * time.Hour}, skipped)
+
+	// Nothing left to send
+	_, _, ok = dueReminder(leads, []int{1440}, 2*time.Hour)
+	assert.False(t, ok)
+	_, _, ok = dueReminder(leads, nil, -time.Minute)
+	assert.False(t, ok)
+}
+
+func TestRebookSlotAfterCancel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	appointmentRepo := repomock.NewMockIAppointmentRepository(ctrl)
+	availabilityRepo := repomock.NewMockIAvailabilityRepository(ctrl)
+	orderRepo := repomock.NewMockIOrderRepository(ctrl)
+
+	orders := NewOrderService(orderRepo, appointmentRepo, nil, nil, nil, nil, nil, nil, nil, noopBus{}, &config.AppointmentConfig{})
+	s := NewAppointmentService(appointmentRepo, availabilityRepo, orderRepo, orders, NewLogNotifier(), &config.AppointmentConfig{})
+
+	sellerID := primitive.NewObjectID()
+	tomorrow := time.Now().In(AppointmentZone).AddDate(0, 0, 1)
+	slotStart := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 10, 0, 0, 0, AppointmentZone)
+	slotEnd := slotStart.Add(30 * time.Minute)
+	availabilityRepo.EXPECT().GetAvailability(sellerID).Return(&dto.SellerAvailability{
+		SellerID:    sellerID,
+		SlotMinutes: 30,
+		Weekly:      []dto.WeeklyWindow{{Weekday: slotStart.Weekday(), Start: "10:00", End: "11:00"}},
+	}, nil).AnyTimes()
+
+	// Cancelling the order moves the slot out of the booked appointment
+	cancelled := &dto.Appointment{
+		AppointmentID: primitive.NewObjectID(),
+		OrderID:       primitive.NewObjectID(),
+		BuyerID:       primitive.NewObjectID(),
+		SellerID:      sellerID,
+		Cancellation:  &dto.AppointmentCancellation{CancelledAt: time.Now(), SlotStart: slotStart, SlotEnd: slotEnd},
+	}
+	orderRepo.EXPECT().UpdateOrderStatus(cancelled.OrderID, orderstatus.CANCELLED).Return(orderstatus.CANCELLED, nil)
+	appointmentRepo.EXPECT().CancelAppointment(cancelled.OrderID).Return(nil)
+	_, err := orders.UpdateOrderStatus(cancelled.OrderID, orderstatus.CANCELLED)
+	assert.NoError(t, err)
+
+	appointmentRepo.EXPECT().GetBookedSlots(sellerID, gomock.Any(), gomock.Any()).DoAndReturn(func(sellerID primitive.ObjectID, from time.Time, to time.Time) ([]dto.Appointment, error) {
+		booked := []dto.Appointment{}
+		if !cancelled.SlotStart.IsZero() && !cancelled.SlotStart.Before(from) && cancelled.SlotStart.Before(to) {
+			booked = append(booked, *cancelled)
+		}
+		return booked, nil
+	}).AnyTimes()
+
+	// The cancelled appointment can't take its slot back
+	appointmentRepo.EXPECT().GetAppointmentByID(cancelled.AppointmentID).Return(cancelled, nil)
+	_, err = s.Propose(cancelled.AppointmentID, cancelled.BuyerID, &dto.AppointmentProposalRequest{
+		Kind:     proposalkind.TIME,
+		Date:     slotStart.Format(time.DateOnly),
+		TimeSlot: "10:00",
+	})
+	assert.ErrorIs(t, err, ErrAppointmentClosed)
+
+	// Another buyer books it instead
+	proposal := dto.AppointmentProposal{
+		ProposalID: primitive.NewObjectID(),
+		Kind:       proposalkind.TIME,
+		Status:     proposalstatus.PENDING,
+		Date:       time.Date(slotStart.Year(), slotStart.Month(), slotStart.Day(), 0, 0, 0, 0, time.UTC),
+		TimeSlot:   "10:00-10:30",
+		SlotStart:  slotStart,
+		SlotEnd:    slotEnd,
+	}
+	rebooking := &dto.Appointment{
+		AppointmentID: primitive.NewObjectID(),
+		OrderID:       primitive.NewObjectID(),
+		BuyerID:       primitive.NewObjectID(),
+		SellerID:      sellerID,
+	}
+	proposal.ProposedBy = rebooking.BuyerID
+	rebooking.Proposals = []dto.AppointmentProposal{proposal}
+	booked := *rebooking
+	booked.SlotStart, booked.SlotEnd = slotStart, slotEnd
+
+	appointmentRepo.EXPECT().GetAppointmentByID(rebooking.AppointmentID).Return(rebooking, nil)
+	appointmentRepo.EXPECT().SetProposalStatus(rebooking.AppointmentID, proposal.ProposalID, proposalstatus.PENDING, proposalstatus.ACCEPTED).Return(true, nil)
+	appointmentRepo.EXPECT().UpdateAppointmentDate(rebooking.AppointmentID, gomock.Any()).DoAndReturn(func(appointmentID primitive.ObjectID, updated *model.Appointment) (*dto.Appointment, error) {
+		assert.True(t, slotStart.Equal(updated.SlotStart))
+		return &booked, nil
+	})
+	orderRepo.EXPECT().AdvanceOrderStatus(rebooking.OrderID, orderstatus.WAITFORTIME, orderstatus.APPOINTED).Return(true, nil)
+	appointmentRepo.EXPECT().GetAppointmentByID(rebooking.AppointmentID).Return(&booked, nil)
+
+	res, err := s.AcceptProposal(rebooking.AppointmentID, proposal.ProposalID, sellerID)
+	assert.NoError(t, err)
+	assert.True(t, slotStart.Equal(res.SlotStart))
+}
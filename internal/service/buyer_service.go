@@ -1,11 +1,20 @@
 package service
 
 import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
 	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/productstatus"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/uploadowner"
 	"github.com/Dongy-s-Advanture/back-end/internal/model"
 	"github.com/Dongy-s-Advanture/back-end/pkg/utils/converter"
 	"github.com/Dongy-s-Advanture/back-end/internal/repository"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/thaiaddress"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -16,19 +25,38 @@ type IBuyerService interface {
 	UpdateBuyerData(buyerID primitive.ObjectID, updatedBuyer *model.Buyer) (*dto.Buyer, error)
 	UpdateProductInCart(buyerID primitive.ObjectID, product dto.OrderProduct) ([]dto.OrderProduct, error)
 	DeleteProductFromCart(buyerID, productID primitive.ObjectID) error
+	GetCart(buyerID primitive.ObjectID) (*dto.Cart, error)
+	GetWishlist(buyerID primitive.ObjectID) ([]dto.WishlistProduct, error)
+	AddToWishlist(buyerID, productID primitive.ObjectID) ([]dto.WishlistItem, error)
+	RemoveFromWishlist(buyerID, productID primitive.ObjectID) error
+	MoveToCart(buyerID, productID primitive.ObjectID) ([]dto.OrderProduct, error)
+	SaveForLater(buyerID, productID primitive.ObjectID) ([]dto.WishlistItem, error)
 }
 
+var (
+	ErrInvalidCartAmount  = errors.New("amount must be at least 1")
+	ErrProductUnavailable = errors.New("product is not available")
+	ErrNotEnoughStock     = errors.New("not enough stock")
+)
+
 type BuyerService struct {
-	buyerRepository repository.IBuyerRepository
+	buyerRepository   repository.IBuyerRepository
+	productRepository repository.IProductRepository
+	uploadService     IUploadService
 }
 
-func NewBuyerService(r repository.IBuyerRepository) IBuyerService {
+func NewBuyerService(r repository.IBuyerRepository, p repository.IProductRepository, uploadService IUploadService) IBuyerService {
 	return BuyerService{
-		buyerRepository: r,
+		buyerRepository:   r,
+		productRepository: p,
+		uploadService:     uploadService,
 	}
 }
 
 func (s BuyerService) CreateBuyerData(buyer *model.Buyer) (*dto.Buyer, error) {
+	if err := normalizeAddress(&buyer.Province, &buyer.City, &buyer.Zip, thaiaddress.Address{}); err != nil {
+		return nil, err
+	}
 
 	passwordBytes := []byte(buyer.Password)
 	hashPasswordBytes, err := bcrypt.GenerateFromPassword(passwordBytes, bcrypt.DefaultCost)
@@ -46,6 +74,10 @@ func (s BuyerService) CreateBuyerData(buyer *model.Buyer) (*dto.Buyer, error) {
 		return nil, err
 	}
 
+	if err := s.uploadService.Claim(uploadowner.BUYER, newBuyer.BuyerID, imageURLs(newBuyer.ProfilePic, newBuyer.ProfilePics)...); err != nil {
+		log.Printf("failed to claim uploads for buyer %s: %v", newBuyer.BuyerID.Hex(), err)
+	}
+
 	return newBuyer, nil
 }
 
@@ -66,6 +98,19 @@ func (s BuyerService) GetBuyer() ([]dto.Buyer, error) {
 }
 
 func (s BuyerService) UpdateBuyerData(buyerID primitive.ObjectID, updatedBuyer *model.Buyer) (*dto.Buyer, error) {
+	if err := validateLocation(updatedBuyer.Location); err != nil {
+		return nil, err
+	}
+
+	oldBuyer, err := s.buyerRepository.GetBuyerByID(buyerID)
+	if err != nil {
+		return nil, err
+	}
+
+	current := thaiaddress.Address{Province: oldBuyer.Province, District: oldBuyer.City, Zip: oldBuyer.Zip}
+	if err := normalizeAddress(&updatedBuyer.Province, &updatedBuyer.City, &updatedBuyer.Zip, current); err != nil {
+		return nil, err
+	}
 
 	if updatedBuyer.Password != "" {
 		passwordBytes := []byte(updatedBuyer.Password)
@@ -83,12 +128,38 @@ func (s BuyerService) UpdateBuyerData(buyerID primitive.ObjectID, updatedBuyer *
 		return nil, err
 	}
 
+	oldURLs := imageURLs(oldBuyer.ProfilePic, oldBuyer.ProfilePics)
+	newURLs := imageURLs(updatedBuyerDTO.ProfilePic, updatedBuyerDTO.ProfilePics)
+	if err := s.uploadService.Replace(uploadowner.BUYER, buyerID, oldURLs, newURLs); err != nil {
+		log.Printf("failed to update uploads for buyer %s: %v", buyerID.Hex(), err)
+	}
+
 	return updatedBuyerDTO, nil
 }
 
 func (s BuyerService) UpdateProductInCart(buyerID primitive.ObjectID, product dto.OrderProduct) ([]dto.OrderProduct, error) {
 
-	productModel, err := converter.OrderProductDTOToModel(&product)
+	if product.Amount < 1 {
+		return nil, ErrInvalidCartAmount
+	}
+	stockProduct, err := s.productRepository.GetProductByID(product.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if stockProduct.Status != productstatus.ACTIVE || stockProduct.DeletedAt != nil {
+		return nil, fmt.Errorf("%w: %s", ErrProductUnavailable, stockProduct.ProductName)
+	}
+	if product.Amount > stockProduct.Amount {
+		return nil, fmt.Errorf("%w: only %d of %s left", ErrNotEnoughStock, stockProduct.Amount, stockProduct.ProductName)
+	}
+
+	// The price is kept so GetCart can flag items repriced since they were
+	// added, the rest of the line is snapshotted at checkout
+	productModel, err := converter.OrderProductDTOToModel(&dto.OrderProduct{
+		ProductID: product.ProductID,
+		Amount:    product.Amount,
+		UnitPrice: stockProduct.Price,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -108,3 +179,184 @@ func (s BuyerService) DeleteProductFromCart(buyerID, productID primitive.ObjectI
 
 	return nil 
 }
+
+// GetCart returns the cart joined with current product data. Lines whose
+// product is gone, short on stock or repriced are flagged rather than dropped
+// so the buyer can see what changed.
+func (s BuyerService) GetCart(buyerID primitive.ObjectID) (*dto.Cart, error) {
+	buyer, err := s.buyerRepository.GetBuyerByID(buyerID)
+	if err != nil {
+		return nil, err
+	}
+
+	cart := &dto.Cart{Items: []dto.CartItem{}}
+	for _, line := range buyer.Cart {
+		item := dto.CartItem{
+			ProductID:  line.ProductID,
+			Amount:     line.Amount,
+			AddedPrice: line.UnitPrice,
+		}
+
+		product, err := s.productRepository.GetProductByID(line.ProductID)
+		if err != nil {
+			if !errors.Is(err, mongo.ErrNoDocuments) {
+				return nil, err
+			}
+			item.Deleted = true
+			cart.Items = append(cart.Items, item)
+			continue
+		}
+
+		item.ProductName = product.ProductName
+		item.Image = product.Image
+		if product.Images != nil && product.Images.Thumbnail != "" {
+			item.Image = product.Images.Thumbnail
+		}
+		item.SellerID = product.SellerID
+		item.UnitPrice = product.Price
+		item.AvailableAmount = product.Amount
+		item.Subtotal = product.Price.Mul(line.Amount)
+
+		item.Deleted = product.DeletedAt != nil || (product.Status != productstatus.ACTIVE && product.Status != productstatus.SOLDOUT)
+		item.OutOfStock = product.Status == productstatus.SOLDOUT || product.Amount < line.Amount
+		// Items added before prices were recorded have nothing to compare to
+		item.Repriced = !line.UnitPrice.IsZero() && line.UnitPrice.Cmp(product.Price) != 0
+
+		if !item.Deleted && !item.OutOfStock {
+			cart.TotalPrice = cart.TotalPrice.Add(item.Subtotal)
+		}
+		cart.Items = append(cart.Items, item)
+	}
+
+	return cart, nil
+}
+
+func (s BuyerService) GetWishlist(buyerID primitive.ObjectID) ([]dto.WishlistProduct, error) {
+	buyer, err := s.buyerRepository.GetBuyerByID(buyerID)
+	if err != nil {
+		return nil, err
+	}
+
+	wishlist := []dto.WishlistProduct{}
+	for _, item := range buyer.Wishlist {
+		entry := dto.WishlistProduct{
+			ProductID: item.ProductID,
+			Amount:    item.Amount,
+			AddedAt:   item.AddedAt,
+		}
+
+		product, err := s.productRepository.GetProductByID(item.ProductID)
+		if err != nil {
+			if !errors.Is(err, mongo.ErrNoDocuments) {
+				return nil, err
+			}
+			entry.Deleted = true
+			wishlist = append(wishlist, entry)
+			continue
+		}
+
+		entry.ProductName = product.ProductName
+		entry.Image = product.Image
+		if product.Images != nil && product.Images.Thumbnail != "" {
+			entry.Image = product.Images.Thumbnail
+		}
+		entry.SellerID = product.SellerID
+		entry.Price = product.Price
+		entry.Deleted = product.DeletedAt != nil || (product.Status != productstatus.ACTIVE && product.Status != productstatus.SOLDOUT)
+		entry.InStock = !entry.Deleted && product.Status == productstatus.ACTIVE && product.Amount > 0
+		wishlist = append(wishlist, entry)
+	}
+
+	return wishlist, nil
+}
+
+// AddToWishlist accepts sold out products too, that's what back in stock
+// alerts are for
+func (s BuyerService) AddToWishlist(buyerID, productID primitive.ObjectID) ([]dto.WishlistItem, error) {
+	product, err := s.productRepository.GetProductByID(productID)
+	if err != nil {
+		return nil, err
+	}
+	if product.DeletedAt != nil || (product.Status != productstatus.ACTIVE && product.Status != productstatus.SOLDOUT) {
+		return nil, fmt.Errorf("%w: %s", ErrProductUnavailable, product.ProductName)
+	}
+
+	return s.buyerRepository.AddToWishlist(buyerID, &model.WishlistItem{
+		ProductID: productID,
+		AddedAt:   time.Now(),
+	})
+}
+
+func (s BuyerService) RemoveFromWishlist(buyerID, productID primitive.ObjectID) error {
+	return s.buyerRepository.RemoveFromWishlist(buyerID, productID)
+}
+
+// MoveToCart puts a wishlist item in the cart, with its saved amount if it
+// was saved for later, and takes it off the wishlist
+func (s BuyerService) MoveToCart(buyerID, productID primitive.ObjectID) ([]dto.OrderProduct, error) {
+	buyer, err := s.buyerRepository.GetBuyerByID(buyerID)
+	if err != nil {
+		return nil, err
+	}
+
+	var item *dto.WishlistItem
+	for i := range buyer.Wishlist {
+		if buyer.Wishlist[i].ProductID == productID {
+			item = &buyer.Wishlist[i]
+			break
+		}
+	}
+	if item == nil {
+		return nil, errors.New("product not found in wishlist")
+	}
+
+	amount := item.Amount
+	if amount < 1 {
+		amount = 1
+	}
+	updatedCart, err := s.UpdateProductInCart(buyerID, dto.OrderProduct{
+		ProductID: productID,
+		Amount:    amount,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.buyerRepository.RemoveFromWishlist(buyerID, productID); err != nil {
+		return nil, err
+	}
+	return updatedCart, nil
+}
+
+// SaveForLater moves a cart line to the wishlist, keeping its amount
+func (s BuyerService) SaveForLater(buyerID, productID primitive.ObjectID) ([]dto.WishlistItem, error) {
+	buyer, err := s.buyerRepository.GetBuyerByID(buyerID)
+	if err != nil {
+		return nil, err
+	}
+
+	var line *dto.OrderProduct
+	for i := range buyer.Cart {
+		if buyer.Cart[i].ProductID == productID {
+			line = &buyer.Cart[i]
+			break
+		}
+	}
+	if line == nil {
+		return nil, errors.New("product not found in cart")
+	}
+
+	wishlist, err := s.buyerRepository.AddToWishlist(buyerID, &model.WishlistItem{
+		ProductID: productID,
+		Amount:    line.Amount,
+		AddedAt:   time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.buyerRepository.DeleteProductFromCart(buyerID, productID); err != nil {
+		return nil, err
+	}
+	return wishlist, nil
+}
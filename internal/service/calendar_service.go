@@ -0,0 +1,187 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/config"
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/proposalstatus"
+	"github.com/Dongy-s-Advanture/back-end/internal/repository"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/ical"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidCalendarToken   = errors.New("invalid calendar token")
+	ErrCalendarNotConfigured  = errors.New("calendar feeds are not configured")
+	ErrAppointmentForbidden   = errors.New("only the buyer and the seller can see this appointment")
+	ErrAppointmentUnscheduled = errors.New("the appointment has no date yet")
+)
+
+type ICalendarService interface {
+	CalendarToken(userID primitive.ObjectID) (string, error)
+	RotateCalendarToken(userID primitive.ObjectID) (string, error)
+	VerifyCalendarToken(token string) (primitive.ObjectID, error)
+	GetCalendar(userID primitive.ObjectID) (*ical.Calendar, error)
+	GetAppointmentCalendar(appointmentID primitive.ObjectID, callerID primitive.ObjectID) (*ical.Calendar, error)
+}
+
+type CalendarService struct {
+	appointmentRepository   repository.IAppointmentRepository
+	calendarTokenRepository repository.ICalendarTokenRepository
+	conf                    *config.CalendarConfig
+}
+
+func NewCalendarService(r repository.IAppointmentRepository, tr repository.ICalendarTokenRepository, conf *config.CalendarConfig) ICalendarService {
+	return CalendarService{
+		appointmentRepository:   r,
+		calendarTokenRepository: tr,
+		conf:                    conf,
+	}
+}
+
+// CalendarToken is the secret part of the user's calendar feed URL, the
+// user ID signed with the calendar secret and the user's current nonce
+func (s CalendarService) CalendarToken(userID primitive.ObjectID) (string, error) {
+	if s.conf.Secret == "" {
+		return "", ErrCalendarNotConfigured
+	}
+	nonce, err := s.calendarTokenRepository.GetNonce(userID)
+	if err != nil {
+		return "", err
+	}
+	return userID.Hex() + "." + s.sign(userID, nonce), nil
+}
+
+// RotateCalendarToken gives the user a new calendar token, the feed URLs
+// with the old one stop working
+func (s CalendarService) RotateCalendarToken(userID primitive.ObjectID) (string, error) {
+	if s.conf.Secret == "" {
+		return "", ErrCalendarNotConfigured
+	}
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	nonce := hex.EncodeToString(b)
+	if err := s.calendarTokenRepository.SetNonce(userID, nonce); err != nil {
+		return "", err
+	}
+	return userID.Hex() + "." + s.sign(userID, nonce), nil
+}
+
+func (s CalendarService) VerifyCalendarToken(token string) (primitive.ObjectID, error) {
+	if s.conf.Secret == "" {
+		return primitive.NilObjectID, ErrCalendarNotConfigured
+	}
+	id, signature, _ := strings.Cut(token, ".")
+	userID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return primitive.NilObjectID, ErrInvalidCalendarToken
+	}
+	nonce, err := s.calendarTokenRepository.GetNonce(userID)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.sign(userID, nonce))) {
+		return primitive.NilObjectID, ErrInvalidCalendarToken
+	}
+	return userID, nil
+}
+
+// GetCalendar is every appointment of the user with a date, cancelled ones
+// included so calendar apps take them off
+func (s CalendarService) GetCalendar(userID primitive.ObjectID) (*ical.Calendar, error) {
+	appointments, err := s.appointmentRepository.GetAppointmentsByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := &ical.Calendar{Name: "Marketplace meet-ups", Events: []ical.Event{}}
+	for i := range appointments {
+		if event, ok := appointmentEvent(&appointments[i]); ok {
+			calendar.Events = append(calendar.Events, event)
+		}
+	}
+	return calendar, nil
+}
+
+func (s CalendarService) GetAppointmentCalendar(appointmentID primitive.ObjectID, callerID primitive.ObjectID) (*ical.Calendar, error) {
+	appointment, err := s.appointmentRepository.GetAppointmentByID(appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	if callerID != appointment.BuyerID && callerID != appointment.SellerID {
+		return nil, ErrAppointmentForbidden
+	}
+	event, ok := appointmentEvent(appointment)
+	if !ok {
+		return nil, ErrAppointmentUnscheduled
+	}
+	return &ical.Calendar{Events: []ical.Event{event}}, nil
+}
+
+// sign signs the user ID with the nonce, a user who never rotated their
+// token has none so the tokens handed out before nonces keep working
+func (s CalendarService) sign(userID primitive.ObjectID, nonce string) string {
+	message := "calendar\n" + userID.Hex()
+	if nonce != "" {
+		message += "\n" + nonce
+	}
+	mac := hmac.New(sha256.New, []byte(s.conf.Secret))
+	mac.Write([]byte(message))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// appointmentEvent is the appointment's booked slot, or an all-day event
+// when it only has a date. A cancelled appointment keeps the slot it had so
+// the event can be marked cancelled.
+func appointmentEvent(appointment *dto.Appointment) (ical.Event, bool) {
+	start, end := appointment.SlotStart, appointment.SlotEnd
+	if appointment.Cancellation != nil && start.IsZero() {
+		start, end = appointment.Cancellation.SlotStart, appointment.Cancellation.SlotEnd
+	}
+	if start.IsZero() && appointment.Date.IsZero() {
+		return ical.Event{}, false
+	}
+
+	var location []string
+	for _, part := range []string{appointment.Address, appointment.City, appointment.Province, appointment.Zip} {
+		if part = strings.TrimSpace(part); part != "" {
+			location = append(location, part)
+		}
+	}
+	description := "Order " + appointment.OrderID.Hex()
+	if start.IsZero() && appointment.TimeSlot != "" {
+		description += "\nTime: " + appointment.TimeSlot
+	}
+
+	event := ical.Event{
+		UID:         appointment.AppointmentID.Hex() + "@dongy-adventure",
+		Summary:     "Marketplace meet-up",
+		Description: description,
+		Location:    strings.Join(location, ", "),
+		Date:        appointment.Date,
+		Start:       start,
+		End:         end,
+		Created:     appointment.CreatedAt,
+	}
+	// Every accepted proposal moved the meet-up, and cancelling it is one
+	// more change on top
+	for _, proposal := range appointment.Proposals {
+		if proposal.Status == proposalstatus.ACCEPTED {
+			event.Sequence++
+		}
+	}
+	if appointment.Cancellation != nil {
+		event.Sequence++
+		event.Cancelled = true
+		event.Modified = appointment.Cancellation.CancelledAt
+	}
+	return event, true
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/config"
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/proposalstatus"
+	repomock "github.com/Dongy-s-Advanture/back-end/pkg/mock/repository"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCalendarToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tokenRepo := repomock.NewMockICalendarTokenRepository(ctrl)
+	tokenRepo.EXPECT().GetNonce(gomock.Any()).Return("", nil).AnyTimes()
+	s := NewCalendarService(nil, tokenRepo, &config.CalendarConfig{Secret: "secret"})
+	userID := primitive.NewObjectID()
+
+	token, err := s.CalendarToken(userID)
+	assert.NoError(t, err)
+	verified, err := s.VerifyCalendarToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, userID, verified)
+
+	// Someone else's ID with this signature
+	_, err = s.VerifyCalendarToken(primitive.NewObjectID().Hex() + token[24:])
+	assert.ErrorIs(t, err, ErrInvalidCalendarToken)
+
+	other := NewCalendarService(nil, tokenRepo, &config.CalendarConfig{Secret: "other"})
+	_, err = other.VerifyCalendarToken(token)
+	assert.ErrorIs(t, err, ErrInvalidCalendarToken)
+}
+
+func TestRotateCalendarToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tokenRepo := repomock.NewMockICalendarTokenRepository(ctrl)
+	s := NewCalendarService(nil, tokenRepo, &config.CalendarConfig{Secret: "secret"})
+	userID := primitive.NewObjectID()
+
+	var nonce string
+	tokenRepo.EXPECT().GetNonce(userID).DoAndReturn(func(primitive.ObjectID) (string, error) { return nonce, nil }).AnyTimes()
+	tokenRepo.EXPECT().SetNonce(userID, gomock.Any()).DoAndReturn(func(_ primitive.ObjectID, n string) error {
+		nonce = n
+		return nil
+	})
+
+	old, err := s.CalendarToken(userID)
+	assert.NoError(t, err)
+	rotated, err := s.RotateCalendarToken(userID)
+	assert.NoError(t, err)
+	assert.NotEqual(t, old, rotated)
+
+	// Only the new token opens the feed
+	_, err = s.VerifyCalendarToken(old)
+	assert.ErrorIs(t, err, ErrInvalidCalendarToken)
+	verified, err := s.VerifyCalendarToken(rotated)
+	assert.NoError(t, err)
+	assert.Equal(t, userID, verified)
+	current, err := s.CalendarToken(userID)
+	assert.NoError(t, err)
+	assert.Equal(t, rotated, current)
+}
+
+func TestAppointmentEvent(t *testing.T) {
+	_, ok := appointmentEvent(&dto.Appointment{})
+	assert.False(t, ok)
+
+	start := time.Date(2025, 3, 3, 10, 0, 0, 0, AppointmentZone)
+	event, ok := appointmentEvent(&dto.Appointment{
+		Address:   "Siam Paragon",
+		Province:  "Bangkok",
+		Zip:       "10330",
+		SlotStart: start,
+		SlotEnd:   start.Add(30 * time.Minute),
+	})
+	assert.True(t, ok)
+	assert.Equal(t, "Siam Paragon, Bangkok, 10330", event.Location)
+	assert.Equal(t, start, event.Start)
+}
+
+func TestCancelledAppointmentEvent(t *testing.T) {
+	start := time.Date(2025, 3, 3, 10, 0, 0, 0, AppointmentZone)
+	cancelledAt := start.Add(-time.Hour)
+	event, ok := appointmentEvent(&dto.Appointment{
+		Proposals: []dto.AppointmentProposal{{Status: proposalstatus.ACCEPTED}, {Status: proposalstatus.REJECTED}},
+		Cancellation: &dto.AppointmentCancellation{
+			CancelledAt: cancelledAt,
+			SlotStart:   start,
+			SlotEnd:     start.Add(30 * time.Minute),
+		},
+	})
+	assert.True(t, ok)
+	assert.True(t, event.Cancelled)
+	// One accepted change and the cancellation
+	assert.Equal(t, 2, event.Sequence)
+	assert.Equal(t, start, event.Start)
+	assert.Equal(t, cancelledAt, event.Modified)
+}
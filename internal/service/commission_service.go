@@ -0,0 +1,219 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/config"
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/commissionscope"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/ledgertype"
+	"github.com/Dongy-s-Advanture/back-end/internal/model"
+	"github.com/Dongy-s-Advanture/back-end/internal/repository"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/money"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type ICommissionService interface {
+	GetRules() ([]dto.CommissionRule, error)
+	SetRule(req *dto.CommissionRuleRequest) (*dto.CommissionRule, error)
+	DeleteRule(ruleID primitive.ObjectID) error
+	CalculateFees(sellerID primitive.ObjectID, lines []dto.CommissionLine, sellerGross money.Money, charged money.Money, couponSubsidy money.Money) (*dto.OrderFees, error)
+	RecordOrderFees(orderID primitive.ObjectID, sellerID primitive.ObjectID, fees *dto.OrderFees) error
+	ReverseOrderFees(orderID primitive.ObjectID, sellerID primitive.ObjectID, fees *dto.OrderFees) error
+	GetLedger(from time.Time, to time.Time) (*dto.LedgerSummary, error)
+}
+
+var (
+	ErrInvalidCommissionRule  = errors.New("invalid commission rule")
+	ErrCommissionRuleNotFound = errors.New("commission rule not found")
+)
+
+type CommissionService struct {
+	commissionRepository repository.ICommissionRepository
+	ledgerRepository     repository.ILedgerRepository
+	defaultPercent       float64
+	feePercent           float64
+	feeVATPercent        float64
+}
+
+func NewCommissionService(r repository.ICommissionRepository, l repository.ILedgerRepository, commissionCfg *config.CommissionConfig, paymentCfg *config.PaymentConfig) ICommissionService {
+	return CommissionService{
+		commissionRepository: r,
+		ledgerRepository:     l,
+		defaultPercent:       commissionCfg.DefaultPercent,
+		feePercent:           paymentCfg.FeePercent,
+		feeVATPercent:        paymentCfg.FeeVATPercent,
+	}
+}
+
+func (s CommissionService) GetRules() ([]dto.CommissionRule, error) {
+	return s.commissionRepository.GetRules()
+}
+
+// SetRule creates the rule for the scope's target or replaces its rate
+func (s CommissionService) SetRule(req *dto.CommissionRuleRequest) (*dto.CommissionRule, error) {
+	rule := &model.CommissionRule{Scope: req.Scope, Rate: req.Rate}
+	switch req.Scope {
+	case commissionscope.CATEGORY:
+		rule.Category = strings.ToLower(strings.TrimSpace(req.Category))
+		if rule.Category == "" {
+			return nil, fmt.Errorf("%w: category is required", ErrInvalidCommissionRule)
+		}
+	case commissionscope.SELLER:
+		sellerID, err := primitive.ObjectIDFromHex(req.SellerID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid sellerID", ErrInvalidCommissionRule)
+		}
+		rule.SellerID = &sellerID
+	}
+	return s.commissionRepository.SetRule(rule)
+}
+
+func (s CommissionService) DeleteRule(ruleID primitive.ObjectID) error {
+	err := s.commissionRepository.DeleteRule(ruleID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return ErrCommissionRuleNotFound
+	}
+	return err
+}
+
+// CalculateFees splits what a seller earned on an order. Commission is taken
+// per line at the rate of the most specific matching rule, the processing fee
+// is Omise's cut of the amount charged by card and is passed on to the seller.
+func (s CommissionService) CalculateFees(sellerID primitive.ObjectID, lines []dto.CommissionLine, sellerGross money.Money, charged money.Money, couponSubsidy money.Money) (*dto.OrderFees, error) {
+	rules, err := s.commissionRepository.GetRulesForSeller(sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	var subtotal money.Money
+	for _, line := range lines {
+		subtotal = subtotal.Add(line.Subtotal)
+	}
+
+	var commission money.Money
+	for _, line := range lines {
+		// A seller coupon lowers every line's revenue by the same share
+		revenue := sellerGross.Share(line.Subtotal, subtotal)
+		rate := commissionRate(rules, sellerID, line.Tags, s.defaultPercent)
+		commission = commission.Add(revenue.Percent(rate))
+	}
+
+	fees := &dto.OrderFees{
+		SellerGross:   sellerGross,
+		Commission:    commission,
+		ProcessingFee: processingFee(charged, s.feePercent, s.feeVATPercent),
+		CouponSubsidy: couponSubsidy,
+	}
+	fees.SellerNet = money.Max(money.New(0), sellerGross.Sub(commission).Sub(fees.ProcessingFee))
+	return fees, nil
+}
+
+// RecordOrderFees books the platform's side of an order in the ledger
+func (s CommissionService) RecordOrderFees(orderID primitive.ObjectID, sellerID primitive.ObjectID, fees *dto.OrderFees) error {
+	amounts := []ledgerAmount{
+		{ledgertype.COMMISSION, fees.Commission},
+		{ledgertype.PROCESSING_FEE, fees.ProcessingFee.Neg()},
+		{ledgertype.FEE_RECOVERY, fees.ProcessingFee},
+		{ledgertype.COUPON_SUBSIDY, fees.CouponSubsidy.Neg()},
+	}
+	return s.bookEntries(orderID, sellerID, amounts, false)
+}
+
+// ReverseOrderFees books a refunded order whose seller payout was taken back.
+// Commission, fee recovery and coupon subsidy are undone while the card fee
+// stays a cost as Omise keeps it.
+func (s CommissionService) ReverseOrderFees(orderID primitive.ObjectID, sellerID primitive.ObjectID, fees *dto.OrderFees) error {
+	amounts := []ledgerAmount{
+		{ledgertype.COMMISSION, fees.Commission.Neg()},
+		{ledgertype.FEE_RECOVERY, fees.ProcessingFee.Neg()},
+		{ledgertype.COUPON_SUBSIDY, fees.CouponSubsidy},
+	}
+	return s.bookEntries(orderID, sellerID, amounts, true)
+}
+
+type ledgerAmount struct {
+	entryType int
+	amount    money.Money
+}
+
+// bookEntries adds a ledger entry for every amount that isn't zero. Booking
+// the same order again is a no-op, so retried events don't count twice.
+func (s CommissionService) bookEntries(orderID primitive.ObjectID, sellerID primitive.ObjectID, amounts []ledgerAmount, reversal bool) error {
+	var entries []model.LedgerEntry
+	for _, a := range amounts {
+		if a.amount.IsZero() {
+			continue
+		}
+		entries = append(entries, model.LedgerEntry{
+			Type:     a.entryType,
+			Amount:   a.amount,
+			OrderID:  orderID,
+			SellerID: sellerID,
+			Reversal: reversal,
+		})
+	}
+	return s.ledgerRepository.CreateEntries(entries)
+}
+
+func (s CommissionService) GetLedger(from time.Time, to time.Time) (*dto.LedgerSummary, error) {
+	entries, err := s.ledgerRepository.GetEntries(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &dto.LedgerSummary{From: from, To: to, Entries: entries}
+	for _, entry := range entries {
+		switch entry.Type {
+		case ledgertype.COMMISSION:
+			summary.Commission = summary.Commission.Add(entry.Amount)
+		case ledgertype.PROCESSING_FEE:
+			summary.ProcessingFee = summary.ProcessingFee.Add(entry.Amount)
+		case ledgertype.FEE_RECOVERY:
+			summary.FeeRecovery = summary.FeeRecovery.Add(entry.Amount)
+		case ledgertype.COUPON_SUBSIDY:
+			summary.CouponSubsidy = summary.CouponSubsidy.Add(entry.Amount)
+		}
+		summary.Net = summary.Net.Add(entry.Amount)
+	}
+	return summary, nil
+}
+
+// commissionRate picks the seller's own rule, then the lowest matching
+// category rule, then the global rule and finally the configured default
+func commissionRate(rules []dto.CommissionRule, sellerID primitive.ObjectID, tags []string, defaultPercent float64) float64 {
+	global, category := -1.0, -1.0
+	for _, rule := range rules {
+		switch rule.Scope {
+		case commissionscope.SELLER:
+			if rule.SellerID != nil && *rule.SellerID == sellerID {
+				return rule.Rate
+			}
+		case commissionscope.CATEGORY:
+			for _, tag := range tags {
+				if strings.EqualFold(tag, rule.Category) && (category < 0 || rule.Rate < category) {
+					category = rule.Rate
+				}
+			}
+		case commissionscope.GLOBAL:
+			global = rule.Rate
+		}
+	}
+	if category >= 0 {
+		return category
+	}
+	if global >= 0 {
+		return global
+	}
+	return defaultPercent
+}
+
+// processingFee is the card fee on amount including the VAT charged on it
+func processingFee(amount money.Money, feePercent float64, vatPercent float64) money.Money {
+	fee := amount.Percent(feePercent)
+	return fee.Add(fee.Percent(vatPercent))
+}
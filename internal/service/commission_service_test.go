@@ -0,0 +1,34 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/commissionscope"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/money"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCommissionRate(t *testing.T) {
+	sellerID, otherSellerID := primitive.NewObjectID(), primitive.NewObjectID()
+	rules := []dto.CommissionRule{
+		{Scope: commissionscope.GLOBAL, Rate: 10},
+		{Scope: commissionscope.CATEGORY, Category: "books", Rate: 5},
+		{Scope: commissionscope.CATEGORY, Category: "electronics", Rate: 8},
+		{Scope: commissionscope.SELLER, SellerID: &otherSellerID, Rate: 2},
+	}
+
+	assert.Equal(t, 10.0, commissionRate(rules, sellerID, nil, 3))
+	assert.Equal(t, 8.0, commissionRate(rules, sellerID, []string{"Electronics"}, 3))
+	// The lowest matching category wins
+	assert.Equal(t, 5.0, commissionRate(rules, sellerID, []string{"electronics", "books"}, 3))
+	assert.Equal(t, 2.0, commissionRate(rules, otherSellerID, []string{"books"}, 3))
+	assert.Equal(t, 3.0, commissionRate(nil, sellerID, []string{"books"}, 3))
+}
+
+func TestProcessingFee(t *testing.T) {
+	// 3.65% of 1000 is 36.50, plus 7% VAT
+	assert.Equal(t, money.New(3906), processingFee(money.New(100000), 3.65, 7))
+	assert.Equal(t, money.New(0), processingFee(money.New(0), 3.65, 7))
+}
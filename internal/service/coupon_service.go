@@ -0,0 +1,245 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/coupontype"
+	"github.com/Dongy-s-Advanture/back-end/internal/model"
+	"github.com/Dongy-s-Advanture/back-end/internal/repository"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/money"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type ICouponService interface {
+	CreateCoupon(callerID primitive.ObjectID, isAdmin bool, req *dto.CouponCreateRequest) (*dto.Coupon, error)
+	GetCouponsBySellerID(sellerID primitive.ObjectID) ([]dto.Coupon, error)
+	DeactivateCoupon(callerID primitive.ObjectID, isAdmin bool, couponID primitive.ObjectID) error
+	ApplyCoupon(code string, buyerID primitive.ObjectID, lines []dto.CouponLine) (*dto.AppliedCoupon, error)
+	RedeemCoupon(applied *dto.AppliedCoupon, buyerID primitive.ObjectID) (primitive.ObjectID, error)
+	ReleaseCoupon(applied *dto.AppliedCoupon, redemptionID primitive.ObjectID)
+}
+
+var (
+	ErrCouponNotFound      = errors.New("coupon not found")
+	ErrCouponNotApplicable = errors.New("coupon cannot be applied")
+	ErrCouponCodeTaken     = errors.New("coupon code is already in use")
+	ErrCouponForbidden     = errors.New("not allowed to manage this coupon")
+	ErrInvalidCoupon       = errors.New("invalid coupon")
+)
+
+type CouponService struct {
+	couponRepository repository.ICouponRepository
+	sellerRepository repository.ISellerRepository
+}
+
+func NewCouponService(r repository.ICouponRepository, sr repository.ISellerRepository) ICouponService {
+	return CouponService{
+		couponRepository: r,
+		sellerRepository: sr,
+	}
+}
+
+// CreateCoupon creates a coupon for the calling seller, or a platform-wide
+// coupon when requested by an admin
+func (s CouponService) CreateCoupon(callerID primitive.ObjectID, isAdmin bool, req *dto.CouponCreateRequest) (*dto.Coupon, error) {
+	switch req.Type {
+	case coupontype.PERCENTAGE:
+		if req.Percent <= 0 {
+			return nil, fmt.Errorf("%w: percent is required", ErrInvalidCoupon)
+		}
+	case coupontype.FIXED:
+		if !req.Amount.IsPositive() {
+			return nil, fmt.Errorf("%w: amount is required", ErrInvalidCoupon)
+		}
+	}
+	if req.MinSpend.IsNegative() || req.MaxDiscount.IsNegative() {
+		return nil, fmt.Errorf("%w: amounts must not be negative", ErrInvalidCoupon)
+	}
+	startsAt := req.StartsAt
+	if startsAt.IsZero() {
+		startsAt = time.Now()
+	}
+	if !req.ExpiresAt.After(startsAt) {
+		return nil, fmt.Errorf("%w: expiresAt must be after startsAt", ErrInvalidCoupon)
+	}
+
+	coupon := &model.Coupon{
+		Code:          normalizeCouponCode(req.Code),
+		Type:          req.Type,
+		Percent:       req.Percent,
+		Amount:        req.Amount,
+		MinSpend:      req.MinSpend,
+		MaxDiscount:   req.MaxDiscount,
+		UsageLimit:    req.UsageLimit,
+		PerBuyerLimit: req.PerBuyerLimit,
+		StartsAt:      startsAt,
+		ExpiresAt:     req.ExpiresAt,
+		Active:        true,
+	}
+	if req.Platform {
+		if !isAdmin {
+			return nil, ErrCouponForbidden
+		}
+	} else {
+		if _, err := s.sellerRepository.GetSellerByID(callerID); err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return nil, ErrCouponForbidden
+			}
+			return nil, err
+		}
+		coupon.SellerID = &callerID
+	}
+
+	newCoupon, err := s.couponRepository.CreateCoupon(coupon)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrCouponCodeTaken
+		}
+		return nil, err
+	}
+	return newCoupon, nil
+}
+
+func (s CouponService) GetCouponsBySellerID(sellerID primitive.ObjectID) ([]dto.Coupon, error) {
+	return s.couponRepository.GetCouponsBySellerID(sellerID)
+}
+
+// DeactivateCoupon stops a coupon from being applied. Past orders keep the
+// discount they were placed with.
+func (s CouponService) DeactivateCoupon(callerID primitive.ObjectID, isAdmin bool, couponID primitive.ObjectID) error {
+	coupon, err := s.couponRepository.GetCouponByID(couponID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrCouponNotFound
+		}
+		return err
+	}
+	isOwner := coupon.SellerID != nil && *coupon.SellerID == callerID
+	if !isOwner && !isAdmin {
+		return ErrCouponForbidden
+	}
+	return s.couponRepository.DeactivateCoupon(couponID)
+}
+
+// ApplyCoupon checks the coupon against the purchase and works out the
+// discount. A seller coupon only discounts that seller's lines and its minimum
+// spend is checked against them alone. Nothing is redeemed yet.
+func (s CouponService) ApplyCoupon(code string, buyerID primitive.ObjectID, lines []dto.CouponLine) (*dto.AppliedCoupon, error) {
+	coupon, err := s.couponRepository.GetCouponByCode(normalizeCouponCode(code))
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrCouponNotFound
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if !coupon.Active {
+		return nil, fmt.Errorf("%w: coupon is no longer active", ErrCouponNotApplicable)
+	}
+	if now.Before(coupon.StartsAt) {
+		return nil, fmt.Errorf("%w: coupon is not valid yet", ErrCouponNotApplicable)
+	}
+	if !now.Before(coupon.ExpiresAt) {
+		return nil, fmt.Errorf("%w: coupon has expired", ErrCouponNotApplicable)
+	}
+	if coupon.UsageLimit > 0 && coupon.UsedCount >= coupon.UsageLimit {
+		return nil, fmt.Errorf("%w: coupon has been fully redeemed", ErrCouponNotApplicable)
+	}
+
+	var eligible money.Money
+	for _, line := range lines {
+		if coupon.SellerID == nil || *coupon.SellerID == line.SellerID {
+			eligible = eligible.Add(line.Subtotal)
+		}
+	}
+	if eligible.IsZero() {
+		return nil, fmt.Errorf("%w: coupon does not apply to these products", ErrCouponNotApplicable)
+	}
+	if eligible.LessThan(coupon.MinSpend) {
+		return nil, fmt.Errorf("%w: minimum spend is %s", ErrCouponNotApplicable, coupon.MinSpend)
+	}
+
+	if coupon.PerBuyerLimit > 0 {
+		used, err := s.couponRepository.CountRedemptions(coupon.CouponID, buyerID)
+		if err != nil {
+			return nil, err
+		}
+		if used >= int64(coupon.PerBuyerLimit) {
+			return nil, fmt.Errorf("%w: coupon has already been used", ErrCouponNotApplicable)
+		}
+	}
+
+	return &dto.AppliedCoupon{
+		CouponID:      coupon.CouponID,
+		Code:          coupon.Code,
+		SellerID:      coupon.SellerID,
+		Discount:      couponDiscount(coupon, eligible),
+		PerBuyerLimit: coupon.PerBuyerLimit,
+	}, nil
+}
+
+// RedeemCoupon takes one use of an applied coupon for the buyer. Both the
+// total and the per buyer limit are enforced here, ApplyCoupon only checks
+// them ahead of time.
+func (s CouponService) RedeemCoupon(applied *dto.AppliedCoupon, buyerID primitive.ObjectID) (primitive.ObjectID, error) {
+	ok, err := s.couponRepository.ReserveUsage(applied.CouponID)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	if !ok {
+		return primitive.NilObjectID, fmt.Errorf("%w: coupon has been fully redeemed", ErrCouponNotApplicable)
+	}
+
+	redemptionID, ok, err := s.couponRepository.CreateRedemption(&model.CouponRedemption{
+		CouponID: applied.CouponID,
+		Code:     applied.Code,
+		BuyerID:  buyerID,
+		Discount: applied.Discount,
+	}, applied.PerBuyerLimit)
+	if err == nil && !ok {
+		err = fmt.Errorf("%w: coupon has already been used", ErrCouponNotApplicable)
+	}
+	if err != nil {
+		if releaseErr := s.couponRepository.ReleaseUsage(applied.CouponID); releaseErr != nil {
+			log.Printf("failed to release coupon %s: %v", applied.Code, releaseErr)
+		}
+		return primitive.NilObjectID, err
+	}
+	return redemptionID, nil
+}
+
+// ReleaseCoupon undoes RedeemCoupon when the purchase didn't go through
+func (s CouponService) ReleaseCoupon(applied *dto.AppliedCoupon, redemptionID primitive.ObjectID) {
+	if err := s.couponRepository.DeleteRedemption(redemptionID); err != nil {
+		log.Printf("failed to delete redemption of coupon %s: %v", applied.Code, err)
+	}
+	if err := s.couponRepository.ReleaseUsage(applied.CouponID); err != nil {
+		log.Printf("failed to release coupon %s: %v", applied.Code, err)
+	}
+}
+
+// couponDiscount is the discount on the eligible amount, it never exceeds it
+func couponDiscount(coupon *dto.Coupon, eligible money.Money) money.Money {
+	var discount money.Money
+	switch coupon.Type {
+	case coupontype.PERCENTAGE:
+		discount = eligible.Percent(coupon.Percent)
+		if coupon.MaxDiscount.IsPositive() {
+			discount = money.Min(discount, coupon.MaxDiscount)
+		}
+	case coupontype.FIXED:
+		discount = coupon.Amount
+	}
+	return money.Min(discount, eligible)
+}
+
+func normalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
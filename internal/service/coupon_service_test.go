@@ -0,0 +1,42 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/coupontype"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/money"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCouponDiscount(t *testing.T) {
+	percentage := &dto.Coupon{Type: coupontype.PERCENTAGE, Percent: 15}
+	assert.Equal(t, money.New(1500), couponDiscount(percentage, money.New(10000)))
+	assert.Equal(t, money.New(1485), couponDiscount(percentage, money.New(9900)))
+
+	percentage.MaxDiscount = money.New(1000)
+	assert.Equal(t, money.New(1000), couponDiscount(percentage, money.New(10000)))
+
+	fixed := &dto.Coupon{Type: coupontype.FIXED, Amount: money.New(5000)}
+	assert.Equal(t, money.New(5000), couponDiscount(fixed, money.New(12000)))
+	// Never more than what the coupon applies to
+	assert.Equal(t, money.New(3000), couponDiscount(fixed, money.New(3000)))
+}
+
+func TestAllocateDiscount(t *testing.T) {
+	a, b, c := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	lines := []dto.CouponLine{
+		{SellerID: a, Subtotal: money.New(10000)},
+		{SellerID: b, Subtotal: money.New(10000)},
+		{SellerID: c, Subtotal: money.New(10000)},
+	}
+
+	platform := allocateDiscount(&dto.AppliedCoupon{Discount: money.New(1000)}, lines)
+	assert.Equal(t, money.New(333), platform[a])
+	assert.Equal(t, money.New(333), platform[b])
+	assert.Equal(t, money.New(334), platform[c])
+
+	seller := allocateDiscount(&dto.AppliedCoupon{SellerID: &b, Discount: money.New(2000)}, lines)
+	assert.Equal(t, map[primitive.ObjectID]money.Money{b: money.New(2000)}, seller)
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/config"
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var ErrEventNotDead = errors.New("no dead event with this ID")
+
+const (
+	// How many documents per collection the relay collects staged events from
+	relayBatch = 100
+	// How long a claimed event is left to its subscribers before it is
+	// claimed again
+	relayLease = 5 * time.Minute
+	// The longest a failed event waits for its next attempt
+	maxRetryDelay = time.Hour
+	deadEventPage = 100
+)
+
+// EventHandler is a subscriber's side effect of a domain event. Events are
+// delivered at least once, returning an error has the event retried.
+type EventHandler func(event *dto.OutboxEvent) error
+
+// IEventBus is how domain services subscribe their side effects to the
+// events staged by the repositories
+type IEventBus interface {
+	// Subscribe has handler called with every event of eventType. The name
+	// records which subscribers already handled an event, so it must be
+	// unique per event type and stay the same between releases.
+	Subscribe(eventType string, name string, handler EventHandler)
+}
+
+type IEventRelay interface {
+	IEventBus
+	RelayEvents(now time.Time) (int, error)
+	GetDeadEvents() ([]dto.OutboxEvent, error)
+	RetryDeadEvent(eventID primitive.ObjectID) error
+}
+
+type eventSubscriber struct {
+	name    string
+	handler EventHandler
+}
+
+type EventRelay struct {
+	outboxRepository repository.IOutboxRepository
+	mu               sync.RWMutex
+	// Subscribers by event type
+	subscribers map[string][]eventSubscriber
+	maxAttempts int
+	retryBase   time.Duration
+}
+
+func NewEventRelay(r repository.IOutboxRepository, outboxCfg *config.OutboxConfig) IEventRelay {
+	return &EventRelay{
+		outboxRepository: r,
+		subscribers:      make(map[string][]eventSubscriber),
+		maxAttempts:      outboxCfg.MaxAttempts,
+		retryBase:        time.Duration(outboxCfg.RetryBaseSeconds) * time.Second,
+	}
+}
+
+func (r *EventRelay) Subscribe(eventType string, name string, handler EventHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subscribers[eventType] = append(r.subscribers[eventType], eventSubscriber{name: name, handler: handler})
+}
+
+// RelayEvents moves staged events into the outbox and publishes the ones
+// that are due, returning how many were fully handled
+func (r *EventRelay) RelayEvents(now time.Time) (int, error) {
+	if _, err := r.outboxRepository.CollectStaged(relayBatch); err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for {
+		// Claiming pushes the event past now, so every event is tried at
+		// most once per run
+		event, err := r.outboxRepository.ClaimNext(now, relayLease)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return published, nil
+		}
+		if err != nil {
+			return published, err
+		}
+		if r.publish(event, now) {
+			published++
+		}
+	}
+}
+
+// publish hands the event to the subscribers that haven't handled it yet and
+// settles it: published when they all succeeded, otherwise retried later or,
+// out of attempts, moved to the dead letter
+func (r *EventRelay) publish(event *dto.OutboxEvent, now time.Time) bool {
+	r.mu.RLock()
+	subscribers := r.subscribers[event.Type]
+	r.mu.RUnlock()
+
+	var failures []string
+	for _, subscriber := range subscribers {
+		if slices.Contains(event.Delivered, subscriber.name) {
+			continue
+		}
+		if err := subscriber.handle(event); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", subscriber.name, err))
+			continue
+		}
+		if err := r.outboxRepository.MarkDelivered(event.EventID, subscriber.name); err != nil {
+			log.Printf("failed to record delivery of event %s to %s: %v", event.EventID.Hex(), subscriber.name, err)
+		}
+	}
+
+	var err error
+	lastError := strings.Join(failures, "; ")
+	switch {
+	case len(failures) == 0:
+		err = r.outboxRepository.MarkPublished(event.EventID, now)
+	case event.Attempts >= r.maxAttempts:
+		log.Printf("event %s %s failed %d times, moved to the dead letter: %s", event.Type, event.EventID.Hex(), event.Attempts, lastError)
+		err = r.outboxRepository.MarkDead(event.EventID, lastError)
+	default:
+		err = r.outboxRepository.ScheduleRetry(event.EventID, now.Add(retryDelay(event.Attempts, r.retryBase)), lastError)
+	}
+	if err != nil {
+		log.Printf("failed to settle event %s: %v", event.EventID.Hex(), err)
+	}
+	return len(failures) == 0
+}
+
+// handle runs the handler, turning a panic into an error so one bad event
+// can't stop the relay
+func (s eventSubscriber) handle(event *dto.OutboxEvent) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return s.handler(event)
+}
+
+// retryDelay doubles base for every attempt after the first, up to
+// maxRetryDelay
+func retryDelay(attempts int, base time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= maxRetryDelay {
+			return maxRetryDelay
+		}
+	}
+	return delay
+}
+
+// decodeEvent reads the event's payload into v
+func decodeEvent(event *dto.OutboxEvent, v interface{}) error {
+	if err := bson.Unmarshal(event.Payload, v); err != nil {
+		return fmt.Errorf("invalid %s payload: %w", event.Type, err)
+	}
+	return nil
+}
+
+func (r *EventRelay) GetDeadEvents() ([]dto.OutboxEvent, error) {
+	return r.outboxRepository.GetDeadEvents(deadEventPage)
+}
+
+// RetryDeadEvent gives a dead event another round of attempts, for once
+// whatever made its subscriber fail is fixed
+func (r *EventRelay) RetryDeadEvent(eventID primitive.ObjectID) error {
+	ok, err := r.outboxRepository.Requeue(eventID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrEventNotDead
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/config"
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/eventtype"
+	"github.com/Dongy-s-Advanture/back-end/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// settledOutbox records how the relay settles an event
+type settledOutbox struct {
+	repository.IOutboxRepository
+	delivered []string
+	published bool
+	dead      bool
+	retryAt   time.Time
+	lastError string
+}
+
+func (r *settledOutbox) MarkDelivered(eventID primitive.ObjectID, subscriber string) error {
+	r.delivered = append(r.delivered, subscriber)
+	return nil
+}
+
+func (r *settledOutbox) MarkPublished(eventID primitive.ObjectID, at time.Time) error {
+	r.published = true
+	return nil
+}
+
+func (r *settledOutbox) ScheduleRetry(eventID primitive.ObjectID, next time.Time, lastError string) error {
+	r.retryAt, r.lastError = next, lastError
+	return nil
+}
+
+func (r *settledOutbox) MarkDead(eventID primitive.ObjectID, lastError string) error {
+	r.dead, r.lastError = true, lastError
+	return nil
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, retryDelay(1, 30*time.Second))
+	assert.Equal(t, 2*time.Minute, retryDelay(3, 30*time.Second))
+	assert.Equal(t, maxRetryDelay, retryDelay(20, 30*time.Second))
+}
+
+func TestEventRelayPublish(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	var handled []string
+	handler := func(name string, err error) EventHandler {
+		return func(event *dto.OutboxEvent) error {
+			handled = append(handled, name)
+			return err
+		}
+	}
+	newRelay := func(outbox *settledOutbox) *EventRelay {
+		relay := NewEventRelay(outbox, &config.OutboxConfig{MaxAttempts: 3, RetryBaseSeconds: 30}).(*EventRelay)
+		relay.Subscribe(eventtype.ORDER_CREATED, "seller-balance", handler("seller-balance", nil))
+		relay.Subscribe(eventtype.ORDER_CREATED, "platform-ledger", handler("platform-ledger", errors.New("ledger is down")))
+		relay.Subscribe(eventtype.ORDER_CREATED, "notification", func(event *dto.OutboxEvent) error {
+			handled = append(handled, "notification")
+			panic("no dispatcher")
+		})
+		relay.Subscribe(eventtype.REVIEW_CREATED, "seller-rating", handler("seller-rating", nil))
+		return relay
+	}
+
+	// Failures are retried later without stopping the other subscribers
+	outbox := &settledOutbox{}
+	event := &dto.OutboxEvent{EventID: primitive.NewObjectID(), Type: eventtype.ORDER_CREATED, Attempts: 2}
+	assert.False(t, newRelay(outbox).publish(event, now))
+	assert.Equal(t, []string{"seller-balance", "platform-ledger", "notification"}, handled)
+	assert.Equal(t, []string{"seller-balance"}, outbox.delivered)
+	assert.Equal(t, now.Add(time.Minute), outbox.retryAt)
+	assert.Equal(t, "platform-ledger: ledger is down; notification: panic: no dispatcher", outbox.lastError)
+	assert.False(t, outbox.published)
+
+	// Subscribers that handled the event are skipped, and the last attempt
+	// moves it to the dead letter
+	handled, outbox = nil, &settledOutbox{}
+	event.Attempts, event.Delivered = 3, []string{"seller-balance"}
+	assert.False(t, newRelay(outbox).publish(event, now))
+	assert.Equal(t, []string{"platform-ledger", "notification"}, handled)
+	assert.True(t, outbox.dead)
+
+	handled, outbox = nil, &settledOutbox{}
+	review := &dto.OutboxEvent{EventID: primitive.NewObjectID(), Type: eventtype.REVIEW_CREATED, Attempts: 1}
+	assert.True(t, newRelay(outbox).publish(review, now))
+	assert.Equal(t, []string{"seller-rating"}, handled)
+	assert.True(t, outbox.published)
+}
+
+func TestDecodeEvent(t *testing.T) {
+	from := 1
+	payload, err := bson.Marshal(dto.OrderStatusChangedEvent{OrderID: primitive.NewObjectID(), From: &from, To: 4})
+	assert.NoError(t, err)
+
+	var changed dto.OrderStatusChangedEvent
+	assert.NoError(t, decodeEvent(&dto.OutboxEvent{Type: eventtype.ORDER_STATUS_CHANGED, Payload: payload}, &changed))
+	assert.Equal(t, 1, *changed.From)
+	assert.Equal(t, 4, changed.To)
+
+	assert.Error(t, decodeEvent(&dto.OutboxEvent{Type: eventtype.ORDER_STATUS_CHANGED, Payload: []byte("nope")}, &changed))
+}
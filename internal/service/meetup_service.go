@@ -0,0 +1,136 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/model"
+	"github.com/Dongy-s-Advanture/back-end/internal/repository"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/geo"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/thaiaddress"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidLocation = errors.New("invalid location")
+	ErrLocationMissing = errors.New("the buyer and the seller both need a location")
+)
+
+const (
+	// DefaultSearchRadius and MaxSearchRadius bound distance searches, in meters
+	DefaultSearchRadius = 5000
+	MaxSearchRadius     = 50000
+	// minMeetupRadius is how far from the midpoint meet-up points are looked
+	// for when the buyer and the seller are close together
+	minMeetupRadius = 1000
+	maxSuggestions  = 5
+	maxMeetupPoints = 50
+)
+
+type IMeetupService interface {
+	CreateMeetupPoint(req *dto.MeetupPointRequest) (*dto.MeetupPoint, error)
+	DeleteMeetupPoint(meetupPointID primitive.ObjectID) error
+	GetMeetupPointsNear(near geo.Point, radius float64) ([]dto.MeetupPoint, error)
+	SuggestMeetupPoints(appointmentID primitive.ObjectID, callerID primitive.ObjectID) (*dto.MeetupSuggestions, error)
+}
+
+type MeetupService struct {
+	meetupPointRepository repository.IMeetupPointRepository
+	appointmentRepository repository.IAppointmentRepository
+	buyerRepository       repository.IBuyerRepository
+	sellerRepository      repository.ISellerRepository
+}
+
+func NewMeetupService(r repository.IMeetupPointRepository, ar repository.IAppointmentRepository, br repository.IBuyerRepository, sr repository.ISellerRepository) IMeetupService {
+	return MeetupService{
+		meetupPointRepository: r,
+		appointmentRepository: ar,
+		buyerRepository:       br,
+		sellerRepository:      sr,
+	}
+}
+
+func (s MeetupService) CreateMeetupPoint(req *dto.MeetupPointRequest) (*dto.MeetupPoint, error) {
+	if err := validateLocation(&req.Location); err != nil {
+		return nil, err
+	}
+	if err := normalizeAddress(&req.Province, &req.City, &req.Zip, thaiaddress.Address{}); err != nil {
+		return nil, err
+	}
+	return s.meetupPointRepository.CreateMeetupPoint(&model.MeetupPoint{
+		Name:     req.Name,
+		Address:  req.Address,
+		City:     req.City,
+		Province: req.Province,
+		Zip:      req.Zip,
+		Location: req.Location,
+	})
+}
+
+func (s MeetupService) DeleteMeetupPoint(meetupPointID primitive.ObjectID) error {
+	return s.meetupPointRepository.DeleteMeetupPoint(meetupPointID)
+}
+
+func (s MeetupService) GetMeetupPointsNear(near geo.Point, radius float64) ([]dto.MeetupPoint, error) {
+	return s.meetupPointRepository.GetMeetupPointsNear(near, radius, maxMeetupPoints)
+}
+
+// SuggestMeetupPoints finds public meet-up points around the middle of the
+// buyer's and the seller's locations. The midpoint is returned even when
+// there is no meet-up point near it.
+func (s MeetupService) SuggestMeetupPoints(appointmentID primitive.ObjectID, callerID primitive.ObjectID) (*dto.MeetupSuggestions, error) {
+	appointment, err := s.appointmentRepository.GetAppointmentByID(appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := otherParty(appointment, callerID); !ok {
+		return nil, ErrAppointmentForbidden
+	}
+	buyer, err := s.buyerRepository.GetBuyerByID(appointment.BuyerID)
+	if err != nil {
+		return nil, err
+	}
+	seller, err := s.sellerRepository.GetSellerByID(appointment.SellerID)
+	if err != nil {
+		return nil, err
+	}
+	if buyer.Location == nil || seller.Location == nil {
+		return nil, ErrLocationMissing
+	}
+
+	midpoint := geo.Midpoint(*buyer.Location, *seller.Location)
+	points, err := s.meetupPointRepository.GetMeetupPointsNear(midpoint, meetupRadius(*buyer.Location, *seller.Location), maxSuggestions)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := &dto.MeetupSuggestions{Midpoint: midpoint, Points: []dto.MeetupSuggestion{}}
+	for _, point := range points {
+		suggestions.Points = append(suggestions.Points, dto.MeetupSuggestion{
+			MeetupPoint:    point,
+			BuyerDistance:  math.Round(geo.Distance(*buyer.Location, point.Location)),
+			SellerDistance: math.Round(geo.Distance(*seller.Location, point.Location)),
+		})
+	}
+	return suggestions, nil
+}
+
+// meetupRadius keeps suggestions within half the distance between the buyer
+// and the seller of the midpoint, so neither has to travel much further than
+// to the middle
+func meetupRadius(buyer geo.Point, seller geo.Point) float64 {
+	return math.Max(minMeetupRadius, geo.Distance(buyer, seller)/2)
+}
+
+// validateLocation checks an optional location
+func validateLocation(location *geo.Point) error {
+	if location == nil {
+		return nil
+	}
+	if err := location.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidLocation, err)
+	}
+	return nil
+}
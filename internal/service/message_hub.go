@@ -0,0 +1,65 @@
+package service
+
+import (
+	"log"
+	"sync"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// messageStreamBuffer is how many events a stream holds before further
+// events for that stream are dropped
+const messageStreamBuffer = 16
+
+// MessageHub hands message events to the streams users have open. Streams
+// are held in memory, so a user connected to another instance only sees
+// the messages the next time they load the conversation.
+type MessageHub struct {
+	mu      sync.Mutex
+	streams map[primitive.ObjectID]map[chan dto.MessageEvent]struct{}
+}
+
+func NewMessageHub() *MessageHub {
+	return &MessageHub{streams: make(map[primitive.ObjectID]map[chan dto.MessageEvent]struct{})}
+}
+
+// Subscribe opens a stream of the user's events. The returned function
+// closes it and must be called once the client has gone.
+func (h *MessageHub) Subscribe(userID primitive.ObjectID) (<-chan dto.MessageEvent, func()) {
+	stream := make(chan dto.MessageEvent, messageStreamBuffer)
+
+	h.mu.Lock()
+	if h.streams[userID] == nil {
+		h.streams[userID] = make(map[chan dto.MessageEvent]struct{})
+	}
+	h.streams[userID][stream] = struct{}{}
+	h.mu.Unlock()
+
+	return stream, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if _, ok := h.streams[userID][stream]; !ok {
+			return
+		}
+		delete(h.streams[userID], stream)
+		if len(h.streams[userID]) == 0 {
+			delete(h.streams, userID)
+		}
+		close(stream)
+	}
+}
+
+// Publish sends the event to every stream the user has open without
+// waiting on slow clients
+func (h *MessageHub) Publish(userID primitive.ObjectID, event dto.MessageEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for stream := range h.streams[userID] {
+		select {
+		case stream <- event:
+		default:
+			log.Printf("message stream of %s is full, dropping %s event", userID.Hex(), event.Type)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMessageHub(t *testing.T) {
+	hub := NewMessageHub()
+	buyer, seller := primitive.NewObjectID(), primitive.NewObjectID()
+	event := dto.MessageEvent{Type: MessageEventMessage, ConversationID: primitive.NewObjectID()}
+
+	phone, closePhone := hub.Subscribe(buyer)
+	laptop, closeLaptop := hub.Subscribe(buyer)
+	sellerStream, closeSeller := hub.Subscribe(seller)
+	defer closeSeller()
+
+	hub.Publish(buyer, event)
+	assert.Equal(t, event, <-phone)
+	assert.Equal(t, event, <-laptop)
+	assert.Empty(t, sellerStream)
+
+	closePhone()
+	_, open := <-phone
+	assert.False(t, open)
+	// Closing twice is harmless
+	closePhone()
+
+	hub.Publish(buyer, event)
+	assert.Equal(t, event, <-laptop)
+
+	// A full stream drops events rather than blocking the sender
+	for i := 0; i < messageStreamBuffer+1; i++ {
+		hub.Publish(buyer, event)
+	}
+	assert.Len(t, laptop, messageStreamBuffer)
+
+	closeLaptop()
+	assert.NotContains(t, hub.streams, buyer)
+}
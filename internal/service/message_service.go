@@ -0,0 +1,220 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/uploadowner"
+	"github.com/Dongy-s-Advanture/back-end/internal/model"
+	"github.com/Dongy-s-Advanture/back-end/internal/repository"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/converter"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidConversation   = errors.New("a conversation is about either a product or an order")
+	ErrConversationForbidden = errors.New("only the buyer and the seller can take part in the conversation")
+	ErrEmptyMessage          = errors.New("a message needs text or an image")
+)
+
+// Types of dto.MessageEvent
+const (
+	MessageEventMessage = "message"
+	MessageEventRead    = "read"
+)
+
+const (
+	defaultMessagePage = 50
+	maxMessagePage     = 100
+)
+
+type IMessageService interface {
+	StartConversation(callerID primitive.ObjectID, req *dto.ConversationRequest) (*dto.Conversation, error)
+	GetConversations(callerID primitive.ObjectID) ([]dto.Conversation, error)
+	GetConversation(callerID primitive.ObjectID, conversationID primitive.ObjectID) (*dto.Conversation, error)
+	GetMessages(callerID primitive.ObjectID, conversationID primitive.ObjectID, before primitive.ObjectID, limit int) ([]dto.Message, error)
+	SendMessage(callerID primitive.ObjectID, conversationID primitive.ObjectID, req *dto.MessageRequest) (*dto.Message, error)
+	MarkRead(callerID primitive.ObjectID, conversationID primitive.ObjectID) error
+	GetUnreadCount(callerID primitive.ObjectID) (*dto.UnreadCount, error)
+	Subscribe(callerID primitive.ObjectID) (<-chan dto.MessageEvent, func())
+}
+
+type MessageService struct {
+	conversationRepository repository.IConversationRepository
+	productRepository      repository.IProductRepository
+	orderRepository        repository.IOrderRepository
+	buyerRepository        repository.IBuyerRepository
+	uploadService          IUploadService
+	hub                    *MessageHub
+}
+
+func NewMessageService(r repository.IConversationRepository, pr repository.IProductRepository, or repository.IOrderRepository, br repository.IBuyerRepository, uploadService IUploadService, hub *MessageHub) IMessageService {
+	return MessageService{
+		conversationRepository: r,
+		productRepository:      pr,
+		orderRepository:        or,
+		buyerRepository:        br,
+		uploadService:          uploadService,
+		hub:                    hub,
+	}
+}
+
+// StartConversation opens the caller's conversation about a product, with its
+// seller, or about an order, with the other party of the order. An existing
+// conversation is returned as it is.
+func (s MessageService) StartConversation(callerID primitive.ObjectID, req *dto.ConversationRequest) (*dto.Conversation, error) {
+	if req.ProductID.IsZero() == req.OrderID.IsZero() {
+		return nil, ErrInvalidConversation
+	}
+
+	conversation := &model.Conversation{}
+	if !req.OrderID.IsZero() {
+		order, err := s.orderRepository.GetOrderByID(req.OrderID)
+		if err != nil {
+			return nil, err
+		}
+		if callerID != order.BuyerID && callerID != order.SellerID {
+			return nil, ErrConversationForbidden
+		}
+		conversation.BuyerID = order.BuyerID
+		conversation.SellerID = order.SellerID
+		conversation.OrderID = order.OrderID
+	} else {
+		product, err := s.productRepository.GetProductByID(req.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		// Only buyers ask about a product, its seller has nobody to ask
+		if callerID == product.SellerID {
+			return nil, ErrConversationForbidden
+		}
+		if _, err := s.buyerRepository.GetBuyerByID(callerID); err != nil {
+			return nil, ErrConversationForbidden
+		}
+		conversation.BuyerID = callerID
+		conversation.SellerID = product.SellerID
+		conversation.ProductID = product.ProductID
+	}
+	return s.conversationRepository.GetOrCreateConversation(conversation)
+}
+
+func (s MessageService) GetConversations(callerID primitive.ObjectID) ([]dto.Conversation, error) {
+	return s.conversationRepository.GetConversationsByUserID(callerID)
+}
+
+func (s MessageService) GetConversation(callerID primitive.ObjectID, conversationID primitive.ObjectID) (*dto.Conversation, error) {
+	conversation, err := s.conversationRepository.GetConversationByID(conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if callerID != conversation.BuyerID && callerID != conversation.SellerID {
+		return nil, ErrConversationForbidden
+	}
+	return conversation, nil
+}
+
+// GetMessages pages through a conversation from the newest message back,
+// limit messages at a time
+func (s MessageService) GetMessages(callerID primitive.ObjectID, conversationID primitive.ObjectID, before primitive.ObjectID, limit int) ([]dto.Message, error) {
+	if _, err := s.GetConversation(callerID, conversationID); err != nil {
+		return nil, err
+	}
+	return s.conversationRepository.GetMessages(conversationID, before, int64(messagePage(limit)))
+}
+
+func (s MessageService) SendMessage(callerID primitive.ObjectID, conversationID primitive.ObjectID, req *dto.MessageRequest) (*dto.Message, error) {
+	conversation, err := s.GetConversation(callerID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	message, err := newMessage(callerID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	messageDTO, err := s.conversationRepository.CreateMessage(conversation, message)
+	if err != nil {
+		return nil, err
+	}
+	var urls []string
+	for i := range messageDTO.Images {
+		urls = append(urls, imageURLs("", &messageDTO.Images[i])...)
+	}
+	if err := s.uploadService.Claim(uploadowner.MESSAGE, messageDTO.MessageID, urls...); err != nil {
+		log.Printf("failed to claim images of message %s: %v", messageDTO.MessageID.Hex(), err)
+	}
+
+	s.publish(conversation, dto.MessageEvent{
+		Type:           MessageEventMessage,
+		ConversationID: conversation.ConversationID,
+		Message:        messageDTO,
+	})
+	return messageDTO, nil
+}
+
+// MarkRead marks the conversation read for the caller and lets the other
+// party know
+func (s MessageService) MarkRead(callerID primitive.ObjectID, conversationID primitive.ObjectID) error {
+	conversation, err := s.GetConversation(callerID, conversationID)
+	if err != nil {
+		return err
+	}
+	if err := s.conversationRepository.MarkRead(conversation, callerID); err != nil {
+		return err
+	}
+	s.publish(conversation, dto.MessageEvent{
+		Type:           MessageEventRead,
+		ConversationID: conversation.ConversationID,
+		ReaderID:       callerID,
+	})
+	return nil
+}
+
+func (s MessageService) GetUnreadCount(callerID primitive.ObjectID) (*dto.UnreadCount, error) {
+	unread, err := s.conversationRepository.CountUnread(callerID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.UnreadCount{Unread: unread}, nil
+}
+
+func (s MessageService) Subscribe(callerID primitive.ObjectID) (<-chan dto.MessageEvent, func()) {
+	return s.hub.Subscribe(callerID)
+}
+
+// publish sends the event to both parties, so the sender's other devices
+// stay in step too
+func (s MessageService) publish(conversation *dto.Conversation, event dto.MessageEvent) {
+	s.hub.Publish(conversation.BuyerID, event)
+	s.hub.Publish(conversation.SellerID, event)
+}
+
+// newMessage builds the message the caller sends from the request
+func newMessage(senderID primitive.ObjectID, req *dto.MessageRequest) (*model.Message, error) {
+	text := strings.TrimSpace(req.Text)
+	images := make([]model.ImageVariants, 0, len(req.Images))
+	for i := range req.Images {
+		if req.Images[i] == (dto.ImageVariants{}) {
+			continue
+		}
+		image, err := converter.ImageVariantsDTOToModel(&req.Images[i])
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, *image)
+	}
+	if text == "" && len(images) == 0 {
+		return nil, ErrEmptyMessage
+	}
+	return &model.Message{SenderID: senderID, Text: text, Images: images}, nil
+}
+
+// messagePage caps how many messages are loaded at once
+func messagePage(limit int) int {
+	if limit <= 0 {
+		return defaultMessagePage
+	}
+	return min(limit, maxMessagePage)
+}
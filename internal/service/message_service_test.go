@@ -0,0 +1,35 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/model"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestNewMessage(t *testing.T) {
+	senderID := primitive.NewObjectID()
+	image := dto.ImageVariants{Thumbnail: "t.webp", Medium: "m.webp", Large: "l.webp"}
+
+	message, err := newMessage(senderID, &dto.MessageRequest{Text: "  is it still available?\n"})
+	assert.NoError(t, err)
+	assert.Equal(t, senderID, message.SenderID)
+	assert.Equal(t, "is it still available?", message.Text)
+	assert.Empty(t, message.Images)
+
+	message, err = newMessage(senderID, &dto.MessageRequest{Images: []dto.ImageVariants{{}, image}})
+	assert.NoError(t, err)
+	assert.Equal(t, []model.ImageVariants{{Thumbnail: "t.webp", Medium: "m.webp", Large: "l.webp"}}, message.Images)
+
+	_, err = newMessage(senderID, &dto.MessageRequest{Text: " ", Images: []dto.ImageVariants{{}}})
+	assert.ErrorIs(t, err, ErrEmptyMessage)
+}
+
+func TestMessagePage(t *testing.T) {
+	assert.Equal(t, defaultMessagePage, messagePage(0))
+	assert.Equal(t, defaultMessagePage, messagePage(-1))
+	assert.Equal(t, 20, messagePage(20))
+	assert.Equal(t, maxMessagePage, messagePage(1000))
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/config"
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/notificationchannel"
+	"github.com/Dongy-s-Advanture/back-end/internal/model"
+	"github.com/Dongy-s-Advanture/back-end/internal/notification"
+	"github.com/Dongy-s-Advanture/back-end/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrChannelUnavailable = errors.New("notification channel is not available")
+	ErrInvalidPreference  = errors.New("invalid notification preference")
+)
+
+// notificationPage is how many of the latest notifications are listed
+const notificationPage = 50
+
+// INotificationDispatcher is how domain services tell users about changes.
+// Notify returns straight away and never fails the change itself. NotifyNow
+// delivers before returning, for event handlers that are retried when it
+// fails.
+type INotificationDispatcher interface {
+	Notify(userID primitive.ObjectID, event dto.NotificationEvent)
+	NotifyNow(ctx context.Context, userID primitive.ObjectID, event dto.NotificationEvent) error
+}
+
+type INotificationService interface {
+	INotificationDispatcher
+	GetNotifications(userID primitive.ObjectID, unreadOnly bool) ([]dto.Notification, error)
+	MarkRead(userID primitive.ObjectID, notificationID primitive.ObjectID) error
+	MarkAllRead(userID primitive.ObjectID) error
+	GetPreferences(userID primitive.ObjectID) (*dto.NotificationPreference, error)
+	UpdatePreferences(userID primitive.ObjectID, req *dto.NotificationPreferenceRequest) (*dto.NotificationPreference, error)
+}
+
+type NotificationService struct {
+	notificationRepository repository.INotificationRepository
+	// Notifiers by channel
+	notifiers map[string]notification.Notifier
+	timeout   time.Duration
+}
+
+func NewNotificationService(r repository.INotificationRepository, notifiers []notification.Notifier, notificationCfg *config.NotificationConfig) INotificationService {
+	byChannel := make(map[string]notification.Notifier, len(notifiers))
+	for _, notifier := range notifiers {
+		byChannel[notifier.Channel()] = notifier
+	}
+	return NotificationService{
+		notificationRepository: r,
+		notifiers:              byChannel,
+		timeout:                time.Duration(notificationCfg.TimeoutSeconds) * time.Second,
+	}
+}
+
+// Notify sends the event to the user over every channel they enabled, in
+// the background. Delivery failures are logged.
+func (s NotificationService) Notify(userID primitive.ObjectID, event dto.NotificationEvent) {
+	go func() {
+		if err := s.NotifyNow(context.Background(), userID, event); err != nil {
+			log.Printf("failed to notify %s: %v", userID.Hex(), err)
+		}
+	}()
+}
+
+// NotifyNow sends the event to the user over every channel they enabled and
+// returns what failed. An event with a key always gets the same inbox
+// notification for the user, so sending it again doesn't add another.
+func (s NotificationService) NotifyNow(ctx context.Context, userID primitive.ObjectID, event dto.NotificationEvent) error {
+	preference, err := s.GetPreferences(userID)
+	if err != nil {
+		return fmt.Errorf("failed to get notification preferences of %s: %w", userID.Hex(), err)
+	}
+	return s.send(ctx, preference, &dto.Notification{
+		NotificationID: notificationID(event.Key, userID),
+		UserID:         userID,
+		Type:           event.Type,
+		Title:          event.Title,
+		Body:           event.Body,
+		Data:           event.Data,
+		CreatedAt:      time.Now(),
+	})
+}
+
+// notificationID is a new ID, or for a keyed event one derived from the key
+// and the user
+func notificationID(key string, userID primitive.ObjectID) primitive.ObjectID {
+	if key == "" {
+		return primitive.NewObjectID()
+	}
+	sum := sha256.Sum256([]byte(key + "\n" + userID.Hex()))
+	var id primitive.ObjectID
+	copy(id[:], sum[:])
+	return id
+}
+
+// send delivers the notification over the user's channels one by one, so a
+// slow channel only delays the ones after it. Channels that can't be used,
+// which sending again won't fix, are only logged.
+func (s NotificationService) send(ctx context.Context, preference *dto.NotificationPreference, notificationDTO *dto.Notification) error {
+	if slices.Contains(preference.Muted, notificationDTO.Type) {
+		return nil
+	}
+	var errs []error
+	for _, channel := range preference.Channels {
+		notifier, ok := s.notifiers[channel]
+		if !ok {
+			log.Printf("no notifier for channel %s, skipping notification %s", channel, notificationDTO.NotificationID.Hex())
+			continue
+		}
+		sendCtx, cancel := context.WithTimeout(ctx, s.timeout)
+		err := notifier.Send(sendCtx, preference, notificationDTO)
+		cancel()
+		if errors.Is(err, notification.ErrNoRecipient) {
+			log.Printf("no address for channel %s, skipping notification %s", channel, notificationDTO.NotificationID.Hex())
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to send notification %s to %s over %s: %w", notificationDTO.NotificationID.Hex(), preference.UserID.Hex(), channel, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s NotificationService) GetNotifications(userID primitive.ObjectID, unreadOnly bool) ([]dto.Notification, error) {
+	return s.notificationRepository.GetNotificationsByUserID(userID, unreadOnly, notificationPage)
+}
+
+func (s NotificationService) MarkRead(userID primitive.ObjectID, notificationID primitive.ObjectID) error {
+	return s.notificationRepository.MarkRead(userID, notificationID)
+}
+
+func (s NotificationService) MarkAllRead(userID primitive.ObjectID) error {
+	return s.notificationRepository.MarkAllRead(userID)
+}
+
+// GetPreferences returns the user's preferences. Users who never set them
+// are notified in the app only.
+func (s NotificationService) GetPreferences(userID primitive.ObjectID) (*dto.NotificationPreference, error) {
+	preference, err := s.notificationRepository.GetPreference(userID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return &dto.NotificationPreference{
+			UserID:   userID,
+			Channels: []string{notificationchannel.INBOX},
+		}, nil
+	}
+	return preference, err
+}
+
+func (s NotificationService) UpdatePreferences(userID primitive.ObjectID, req *dto.NotificationPreferenceRequest) (*dto.NotificationPreference, error) {
+	preference := &model.NotificationPreference{
+		UserID:     userID,
+		Muted:      req.Muted,
+		Email:      req.Email,
+		LineUserID: req.LineUserID,
+		WebhookURL: req.WebhookURL,
+	}
+	for _, channel := range req.Channels {
+		if !slices.Contains(preference.Channels, channel) {
+			preference.Channels = append(preference.Channels, channel)
+		}
+	}
+	if err := s.validatePreference(preference); err != nil {
+		return nil, err
+	}
+	return s.notificationRepository.SavePreference(preference)
+}
+
+// validatePreference checks every enabled channel can be used and has an
+// address to notify the user at
+func (s NotificationService) validatePreference(preference *model.NotificationPreference) error {
+	if preference.WebhookURL != "" {
+		webhookURL, err := url.Parse(preference.WebhookURL)
+		if err != nil || webhookURL.Scheme != "https" || webhookURL.Host == "" {
+			return fmt.Errorf("%w: the webhook URL must be an https URL", ErrInvalidPreference)
+		}
+	}
+	for _, channel := range preference.Channels {
+		if _, ok := s.notifiers[channel]; !ok {
+			return fmt.Errorf("%w: %s", ErrChannelUnavailable, channel)
+		}
+		switch {
+		case channel == notificationchannel.EMAIL && preference.Email == "":
+			return fmt.Errorf("%w: an email is needed for email notifications", ErrInvalidPreference)
+		case channel == notificationchannel.LINE && preference.LineUserID == "":
+			return fmt.Errorf("%w: a LINE user ID is needed for LINE notifications", ErrInvalidPreference)
+		case channel == notificationchannel.WEBHOOK && preference.WebhookURL == "":
+			return fmt.Errorf("%w: a webhook URL is needed for webhook notifications", ErrInvalidPreference)
+		}
+	}
+	return nil
+}
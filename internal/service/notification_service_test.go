@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/notificationchannel"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/notificationtype"
+	"github.com/Dongy-s-Advanture/back-end/internal/model"
+	"github.com/Dongy-s-Advanture/back-end/internal/notification"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type recordingNotifier struct {
+	channel string
+	sent    *[]string
+	err     error
+}
+
+func (n recordingNotifier) Channel() string {
+	return n.channel
+}
+
+func (n recordingNotifier) Send(ctx context.Context, to *dto.NotificationPreference, notification *dto.Notification) error {
+	*n.sent = append(*n.sent, n.channel)
+	return n.err
+}
+
+func testNotificationService(sent *[]string) NotificationService {
+	return NotificationService{notifiers: map[string]notification.Notifier{
+		notificationchannel.INBOX: recordingNotifier{channel: notificationchannel.INBOX, sent: sent},
+		notificationchannel.LINE:  recordingNotifier{channel: notificationchannel.LINE, sent: sent, err: errors.New("LINE is down")},
+		notificationchannel.EMAIL: recordingNotifier{channel: notificationchannel.EMAIL, sent: sent},
+	}}
+}
+
+func TestNotificationSend(t *testing.T) {
+	var sent []string
+	s := testNotificationService(&sent)
+	preference := &dto.NotificationPreference{
+		Channels: []string{notificationchannel.LINE, notificationchannel.WEBHOOK, notificationchannel.INBOX},
+		Muted:    []string{notificationtype.WISHLIST},
+	}
+
+	// A failing or missing channel doesn't stop the others, only the
+	// failure is returned
+	err := s.send(context.Background(), preference, &dto.Notification{Type: notificationtype.ORDER_STATUS})
+	assert.Equal(t, []string{notificationchannel.LINE, notificationchannel.INBOX}, sent)
+	assert.ErrorContains(t, err, "LINE is down")
+
+	sent = nil
+	err = s.send(context.Background(), preference, &dto.Notification{Type: notificationtype.WISHLIST})
+	assert.NoError(t, err)
+	assert.Empty(t, sent)
+}
+
+func TestNotificationID(t *testing.T) {
+	buyerID, sellerID := primitive.NewObjectID(), primitive.NewObjectID()
+
+	// A redelivered event gets the same inbox notification
+	assert.Equal(t, notificationID("event", buyerID), notificationID("event", buyerID))
+	assert.NotEqual(t, notificationID("event", buyerID), notificationID("event", sellerID))
+	assert.NotEqual(t, notificationID("event", buyerID), notificationID("other", buyerID))
+	assert.NotEqual(t, notificationID("", buyerID), notificationID("", buyerID))
+}
+
+func TestValidatePreference(t *testing.T) {
+	s := testNotificationService(new([]string))
+
+	assert.NoError(t, s.validatePreference(&model.NotificationPreference{
+		Channels:   []string{notificationchannel.INBOX, notificationchannel.EMAIL, notificationchannel.LINE},
+		Email:      "buyer@example.com",
+		LineUserID: "U4af4980629a5d7b5e0b0d1f8f0c3b2a1",
+		WebhookURL: "https://example.com/hook",
+	}))
+	assert.NoError(t, s.validatePreference(&model.NotificationPreference{}))
+
+	assert.ErrorIs(t, s.validatePreference(&model.NotificationPreference{
+		Channels:   []string{notificationchannel.WEBHOOK},
+		WebhookURL: "https://example.com/hook",
+	}), ErrChannelUnavailable)
+	assert.ErrorIs(t, s.validatePreference(&model.NotificationPreference{
+		Channels: []string{notificationchannel.EMAIL},
+	}), ErrInvalidPreference)
+	assert.ErrorIs(t, s.validatePreference(&model.NotificationPreference{
+		Channels: []string{notificationchannel.LINE},
+	}), ErrInvalidPreference)
+	assert.ErrorIs(t, s.validatePreference(&model.NotificationPreference{
+		WebhookURL: "http://example.com/hook",
+	}), ErrInvalidPreference)
+}
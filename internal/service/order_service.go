@@ -1,62 +1,231 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"log"
+	"strings"
 	"time"
 
+	"github.com/Dongy-s-Advanture/back-end/internal/config"
 	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/eventtype"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/notificationtype"
 	"github.com/Dongy-s-Advanture/back-end/internal/enum/orderstatus"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/productstatus"
 	"github.com/Dongy-s-Advanture/back-end/internal/enum/userrole"
 	"github.com/Dongy-s-Advanture/back-end/internal/model"
 	"github.com/Dongy-s-Advanture/back-end/internal/repository"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/converter"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/money"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type IOrderService interface {
 	CreateOrder(orderCreateRequest *dto.OrderCreateRequest) (*dto.Order, error)
 	GetOrdersByUserID(userID primitive.ObjectID, userType userrole.UserType) ([]dto.Order, error)
-	GetTotalPrice(products []dto.OrderProduct) (float64, error)
+	GetTotalPrice(buyerID primitive.ObjectID, products []dto.OrderProduct, couponCode string) (*dto.PriceQuote, error)
 	DeleteOrderByOrderID(orderID primitive.ObjectID) error
 	UpdateOrder(orderID primitive.ObjectID, updatedOrder *model.Order) (*dto.Order, error)
 	UpdateOrderStatus(orderID primitive.ObjectID, orderStatus int) (int, error)
+	Checkout(buyerID primitive.ObjectID, req *dto.CheckoutRequest) (*dto.CheckoutResponse, error)
+	HandleNoShow(orderID primitive.ObjectID, absentID primitive.ObjectID) error
 }
 
+var (
+	ErrOrderClosed    = errors.New("order is already done or cancelled")
+	ErrMixedSellers   = errors.New("all products of an order must be from one seller")
+	ErrSellerMismatch = errors.New("products are not sold by this seller")
+)
+
 type OrderService struct {
 	orderRepository       repository.IOrderRepository
 	appointmentRepository repository.IAppointmentRepository
 	sellerRepository      repository.ISellerRepository
 	productRepository     repository.IProductRepository
+	buyerRepository       repository.IBuyerRepository
+	paymentService        IPaymentService
+	couponService         ICouponService
+	commissionService     ICommissionService
+	dispatcher            INotificationDispatcher
+	noShowCancel          bool
+	noShowStrike          bool
+	// Percent of a card payment refunded when the buyer didn't show up
+	noShowBuyerRefund float64
 }
 
-func NewOrderService(r repository.IOrderRepository, a repository.IAppointmentRepository, sr repository.ISellerRepository, p repository.IProductRepository) IOrderService {
-	return OrderService{orderRepository: r, appointmentRepository: a, sellerRepository: sr, productRepository: p}
+func NewOrderService(r repository.IOrderRepository, a repository.IAppointmentRepository, sr repository.ISellerRepository, p repository.IProductRepository, b repository.IBuyerRepository, ps IPaymentService, cs ICouponService, cms ICommissionService, d INotificationDispatcher, bus IEventBus, appointmentCfg *config.AppointmentConfig) IOrderService {
+	s := OrderService{orderRepository: r, appointmentRepository: a, sellerRepository: sr, productRepository: p, buyerRepository: b, paymentService: ps, couponService: cs, commissionService: cms, dispatcher: d, noShowCancel: appointmentCfg.NoShowCancel, noShowStrike: appointmentCfg.NoShowStrike, noShowBuyerRefund: appointmentCfg.NoShowBuyerRefundPercent}
+	bus.Subscribe(eventtype.ORDER_CREATED, "seller-balance", s.depositSellerNet)
+	bus.Subscribe(eventtype.ORDER_CREATED, "platform-ledger", s.recordOrderFees)
+	bus.Subscribe(eventtype.ORDER_CREATED, "notification", s.notifyOrderCreated)
+	bus.Subscribe(eventtype.ORDER_STATUS_CHANGED, "notification", s.notifyStatusChanged)
+	bus.Subscribe(eventtype.PAYMENT_SUCCEEDED, "notification", s.notifyPayment)
+	bus.Subscribe(eventtype.ORDER_REFUNDED, "seller-balance", s.chargebackSellerNet)
+	bus.Subscribe(eventtype.ORDER_REFUNDED, "platform-ledger", s.reverseOrderFees)
+	return s
 }
 
 func (s OrderService) CreateOrder(orderCreateRequest *dto.OrderCreateRequest) (*dto.Order, error) {
 	if len(orderCreateRequest.Products) == 0 {
 		return nil, errors.New("no product")
 	}
-	buyerID, sellerID, products := orderCreateRequest.BuyerID, orderCreateRequest.SellerID, orderCreateRequest.Products
-	var productsModel []model.OrderProduct
+	snapshot, err := s.snapshotProducts(orderCreateRequest.Products)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot.sellerID != orderCreateRequest.SellerID {
+		return nil, ErrSellerMismatch
+	}
+
+	var applied *dto.AppliedCoupon
+	var redemptionID primitive.ObjectID
+	var discount *orderDiscount
+	if orderCreateRequest.CouponCode != "" {
+		applied, err = s.couponService.ApplyCoupon(orderCreateRequest.CouponCode, orderCreateRequest.BuyerID, []dto.CouponLine{
+			{SellerID: snapshot.sellerID, Subtotal: snapshot.subtotal},
+		})
+		if err != nil {
+			return nil, err
+		}
+		discount = &orderDiscount{coupon: applied, amount: applied.Discount}
+	}
+
+	if err := s.reserveStock(snapshot.products); err != nil {
+		return nil, err
+	}
+	if applied != nil {
+		if redemptionID, err = s.couponService.RedeemCoupon(applied, orderCreateRequest.BuyerID); err != nil {
+			s.restock(snapshot.products)
+			return nil, err
+		}
+	}
+	order, err := s.placeOrder(orderCreateRequest, snapshot, discount)
+	if err != nil {
+		s.restock(snapshot.products)
+		if applied != nil {
+			s.couponService.ReleaseCoupon(applied, redemptionID)
+		}
+		return nil, err
+	}
+	return order, nil
+}
+
+// orderDiscount is the part of a coupon discount that goes to one order
+type orderDiscount struct {
+	coupon *dto.AppliedCoupon
+	amount money.Money
+}
+
+// orderSnapshot is the products of an order as they were when it was placed
+type orderSnapshot struct {
+	// Taken from the products so an order can't be booked to another seller
+	sellerID primitive.ObjectID
+	products []model.OrderProduct
+	// Commission is worked out per line from the product's tags
+	lines    []dto.CommissionLine
+	subtotal money.Money
+}
+
+// snapshotProducts checks the products can be ordered and copies their
+// details so the order still shows what was bought after the seller edits or
+// deletes them
+func (s OrderService) snapshotProducts(products []dto.OrderProduct) (*orderSnapshot, error) {
+	snapshot := &orderSnapshot{}
 	for _, product := range products {
 		stockProduct, err := s.productRepository.GetProductByID(product.ProductID)
 		if err != nil {
 			return nil, err
 		}
 
+		if len(snapshot.products) == 0 {
+			snapshot.sellerID = stockProduct.SellerID
+		} else if stockProduct.SellerID != snapshot.sellerID {
+			return nil, ErrMixedSellers
+		}
+
+		if stockProduct.Status != productstatus.ACTIVE || stockProduct.DeletedAt != nil {
+			return nil, fmt.Errorf("%w: %s", ErrProductUnavailable, stockProduct.ProductName)
+		}
+
 		if stockProduct.Amount < product.Amount {
-			return nil, fmt.Errorf("not enough stock for product %s", stockProduct.ProductName)
+			return nil, fmt.Errorf("%w for product %s", ErrNotEnoughStock, stockProduct.ProductName)
 		}
 
-		productsModel = append(productsModel, model.OrderProduct{
-			ProductID: product.ProductID,
-			Amount:    product.Amount,
+		image := stockProduct.Image
+		if stockProduct.Images != nil && stockProduct.Images.Thumbnail != "" {
+			image = stockProduct.Images.Thumbnail
+		}
+		lineTotal := stockProduct.Price.Mul(product.Amount)
+		snapshot.subtotal = snapshot.subtotal.Add(lineTotal)
+		snapshot.lines = append(snapshot.lines, dto.CommissionLine{Tags: stockProduct.Tag, Subtotal: lineTotal})
+
+		snapshot.products = append(snapshot.products, model.OrderProduct{
+			ProductID:   product.ProductID,
+			Amount:      product.Amount,
+			ProductName: stockProduct.ProductName,
+			Color:       stockProduct.Color,
+			UnitPrice:   stockProduct.Price,
+			Image:       image,
+			Subtotal:    lineTotal,
 		})
 	}
+	return snapshot, nil
+}
+
+// placeOrder creates the appointment and stores the order, the stock must
+// already be reserved with reserveStock. A seller coupon comes out of the
+// seller's payout while a platform coupon is covered by the platform, so the
+// seller earns the subtotal. Commission and the card fee are taken from what
+// the seller earns. The seller is paid and the fees booked by the subscribers
+// of OrderCreated.
+func (s OrderService) placeOrder(orderCreateRequest *dto.OrderCreateRequest, snapshot *orderSnapshot, discount *orderDiscount) (*dto.Order, error) {
+	buyerID, sellerID := orderCreateRequest.BuyerID, snapshot.sellerID
 	createdAt := time.Now()
 
+	order := &model.Order{
+		Status:     orderstatus.WAITFORLOCATION,
+		Products:   snapshot.products,
+		BuyerID:    buyerID,
+		BuyerName:  orderCreateRequest.BuyerName,
+		SellerID:   sellerID,
+		TotalPrice: snapshot.subtotal,
+		SellerName: orderCreateRequest.SellerName,
+		Payment:    orderCreateRequest.Payment,
+		CreatedAt:  orderCreateRequest.CreatedAt,
+		ChargeID:   orderCreateRequest.ChargeID,
+		Subtotal:   snapshot.subtotal,
+	}
+	sellerGross, couponSubsidy := snapshot.subtotal, money.Money{}
+	if discount != nil {
+		order.TotalPrice = snapshot.subtotal.Sub(discount.amount)
+		order.Discount = discount.amount
+		order.CouponCode = discount.coupon.Code
+		order.PlatformDiscount = discount.coupon.SellerID == nil
+		if order.PlatformDiscount {
+			couponSubsidy = discount.amount
+		} else {
+			sellerGross = order.TotalPrice
+		}
+	}
+
+	// Only card payments go through Omise and carry its fee
+	var charged money.Money
+	if order.ChargeID != "" {
+		charged = order.TotalPrice
+	}
+	fees, err := s.commissionService.CalculateFees(sellerID, snapshot.lines, sellerGross, charged, couponSubsidy)
+	if err != nil {
+		return nil, err
+	}
+	order.Fees, err = converter.OrderFeesDTOToModel(fees)
+	if err != nil {
+		return nil, err
+	}
+
 	orderID := primitive.NewObjectID()
+	order.OrderID = orderID
 	app, err := s.appointmentRepository.CreateAppointment(&model.Appointment{
 		OrderID:   orderID,
 		BuyerID:   buyerID,
@@ -66,52 +235,284 @@ func (s OrderService) CreateOrder(orderCreateRequest *dto.OrderCreateRequest) (*
 	if err != nil {
 		return nil, err
 	}
+	order.AppointmentID = app.AppointmentID
 
-	// Get total price
-	totalPrice, err := s.GetTotalPrice(products)
+	newOrder, err := s.orderRepository.CreateOrder(order)
 	if err != nil {
 		return nil, err
 	}
+	return newOrder, nil
+}
 
-	// Add transaction and update (+deposit) seller balance
-	err = s.sellerRepository.DepositSellerBalance(sellerID, orderID, orderCreateRequest.Payment, totalPrice)
-	// err = s.sellerRepository.DepositSellerBalance(sellerID, orderID, orderCreateRequest.PaymentRequest.PaymentMethod, totalPrice)
+// reserveStock takes the ordered amounts out of stock, all of them or, when
+// one has run out in the meantime, none
+func (s OrderService) reserveStock(products []model.OrderProduct) error {
+	for i, product := range products {
+		if err := s.productRepository.UpdateProductAmount(product.ProductID, product.Amount); err != nil {
+			s.restock(products[:i])
+			if errors.Is(err, repository.ErrOutOfStock) {
+				return fmt.Errorf("%w for product %s", ErrNotEnoughStock, product.ProductName)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// restock puts reserved amounts back. It runs while undoing something that
+// already failed, so its own failures are only logged.
+func (s OrderService) restock(products []model.OrderProduct) {
+	for _, product := range products {
+		if err := s.productRepository.RestockProduct(product.ProductID, product.Amount); err != nil {
+			log.Printf("failed to restock %d of product %s: %v", product.Amount, product.ProductID.Hex(), err)
+		}
+	}
+}
+
+// checkoutOrder is the part of a checkout that goes to one seller
+type checkoutOrder struct {
+	request  *dto.OrderCreateRequest
+	snapshot *orderSnapshot
+	discount *orderDiscount
+}
+
+// total is what the buyer pays for the order
+func (o checkoutOrder) total() money.Money {
+	if o.discount == nil {
+		return o.snapshot.subtotal
+	}
+	return o.snapshot.subtotal.Sub(o.discount.amount)
+}
+
+// Checkout places the buyer's whole cart. Items are grouped by seller into
+// one order and appointment each, paid for with a single charge, and removed
+// from the cart as their order is created. Everything is checked and the
+// stock and coupon reserved before the card is charged. Should an order
+// still fail to be placed, the orders not placed are refunded and their
+// stock put back.
+func (s OrderService) Checkout(buyerID primitive.ObjectID, req *dto.CheckoutRequest) (*dto.CheckoutResponse, error) {
+	buyer, err := s.buyerRepository.GetBuyerByID(buyerID)
 	if err != nil {
 		return nil, err
 	}
+	if len(buyer.Cart) == 0 {
+		return nil, errors.New("cart is empty")
+	}
 
-	// Deduct product amount
-	for _, product := range products {
-		err = s.productRepository.UpdateProductAmount(product.ProductID, product.Amount)
+	var sellerIDs []primitive.ObjectID
+	groups := make(map[primitive.ObjectID][]dto.OrderProduct)
+	for _, item := range buyer.Cart {
+		product, err := s.productRepository.GetProductByID(item.ProductID)
+		if err != nil {
+			return nil, fmt.Errorf("product %s in cart: %w", item.ProductID.Hex(), err)
+		}
+		if _, ok := groups[product.SellerID]; !ok {
+			sellerIDs = append(sellerIDs, product.SellerID)
+		}
+		groups[product.SellerID] = append(groups[product.SellerID], dto.OrderProduct{
+			ProductID: item.ProductID,
+			Amount:    item.Amount,
+		})
+	}
+
+	createdAt := time.Now()
+	buyerName := strings.TrimSpace(buyer.Name + " " + buyer.Surname)
+	orders := make([]checkoutOrder, 0, len(sellerIDs))
+	var subtotal money.Money
+	for _, sellerID := range sellerIDs {
+		seller, err := s.sellerRepository.GetSellerByID(sellerID)
+		if err != nil {
+			return nil, err
+		}
+		snapshot, err := s.snapshotProducts(groups[sellerID])
 		if err != nil {
 			return nil, err
 		}
+		subtotal = subtotal.Add(snapshot.subtotal)
+		orders = append(orders, checkoutOrder{
+			request: &dto.OrderCreateRequest{
+				Products:   groups[sellerID],
+				BuyerID:    buyerID,
+				SellerID:   sellerID,
+				BuyerName:  buyerName,
+				SellerName: strings.TrimSpace(seller.Name + " " + seller.Surname),
+				Payment:    req.Payment,
+				CreatedAt:  createdAt,
+			},
+			snapshot: snapshot,
+		})
 	}
-	return s.orderRepository.CreateOrder(&model.Order{
-		OrderID:       orderID,
-		Status:        orderstatus.WAITFORLOCATION,
-		Products:      productsModel,
-		AppointmentID: app.AppointmentID,
-		BuyerID:       buyerID,
-		BuyerName:     orderCreateRequest.BuyerName,
-		SellerID:      sellerID,
-		TotalPrice:    totalPrice,
-		SellerName:    orderCreateRequest.SellerName,
-		Payment: 	   orderCreateRequest.Payment,	
-		CreatedAt:     orderCreateRequest.CreatedAt,	
-	})
+
+	res := &dto.CheckoutResponse{Subtotal: subtotal, TotalPrice: subtotal}
+	var applied *dto.AppliedCoupon
+	if req.CouponCode != "" {
+		lines := make([]dto.CouponLine, 0, len(orders))
+		for _, order := range orders {
+			lines = append(lines, dto.CouponLine{SellerID: order.request.SellerID, Subtotal: order.snapshot.subtotal})
+		}
+		applied, err = s.couponService.ApplyCoupon(req.CouponCode, buyerID, lines)
+		if err != nil {
+			return nil, err
+		}
+		discounts := allocateDiscount(applied, lines)
+		for i := range orders {
+			if amount := discounts[orders[i].request.SellerID]; amount.IsPositive() {
+				orders[i].discount = &orderDiscount{coupon: applied, amount: amount}
+			}
+		}
+		res.Discount = applied.Discount
+		res.TotalPrice = subtotal.Sub(applied.Discount)
+	}
+
+	// Reserved before charging so the buyer is never charged for stock or a
+	// coupon that ran out in the meantime
+	for i, order := range orders {
+		if err := s.reserveStock(order.snapshot.products); err != nil {
+			s.restockOrders(orders[:i])
+			return nil, err
+		}
+	}
+	var redemptionID primitive.ObjectID
+	if applied != nil {
+		if redemptionID, err = s.couponService.RedeemCoupon(applied, buyerID); err != nil {
+			s.restockOrders(orders)
+			return nil, err
+		}
+	}
+
+	if req.Token != "" {
+		charge, err := s.paymentService.HandlePayment(&dto.PaymentRequest{
+			BuyerID:       buyerID.Hex(),
+			PaymentMethod: req.Payment,
+			Amount:        res.TotalPrice,
+			Token:         req.Token,
+			CreatedAt:     createdAt,
+		})
+		if err != nil {
+			s.restockOrders(orders)
+			if applied != nil {
+				s.couponService.ReleaseCoupon(applied, redemptionID)
+			}
+			return nil, fmt.Errorf("payment failed: %w", err)
+		}
+		res.ChargeID = charge.ID
+	}
+
+	couponUsed := false
+	for i, order := range orders {
+		order.request.ChargeID = res.ChargeID
+		placed, err := s.placeOrder(order.request, order.snapshot, order.discount)
+		if err != nil {
+			if applied != nil && !couponUsed {
+				s.couponService.ReleaseCoupon(applied, redemptionID)
+			}
+			if refundErr := s.abandonCheckout(res.ChargeID, orders[i:]); refundErr != nil {
+				return nil, fmt.Errorf("placed %d of %d orders and failed to refund the rest: %w", i, len(orders), errors.Join(err, refundErr))
+			}
+			return nil, fmt.Errorf("placed %d of %d orders, the rest were refunded: %w", i, len(orders), err)
+		}
+		res.Orders = append(res.Orders, *placed)
+		couponUsed = couponUsed || order.discount != nil
+
+		// The order is placed and paid for, so a stale cart is only logged
+		for _, product := range order.request.Products {
+			if err := s.buyerRepository.DeleteProductFromCart(buyerID, product.ProductID); err != nil {
+				log.Printf("failed to remove product %s from the cart of buyer %s: %v", product.ProductID.Hex(), buyerID.Hex(), err)
+			}
+		}
+	}
+	return res, nil
 }
 
-func (s OrderService) GetTotalPrice(products []dto.OrderProduct) (float64, error) {
-	var totalPrice float64
+func (s OrderService) restockOrders(orders []checkoutOrder) {
+	for _, order := range orders {
+		s.restock(order.snapshot.products)
+	}
+}
+
+// abandonCheckout puts back the stock of the orders that couldn't be placed
+// and refunds what the buyer paid for them
+func (s OrderService) abandonCheckout(chargeID string, unplaced []checkoutOrder) error {
+	s.restockOrders(unplaced)
+	if chargeID == "" {
+		return nil
+	}
+	var refund money.Money
+	for _, order := range unplaced {
+		refund = refund.Add(order.total())
+	}
+	if !refund.IsPositive() {
+		return nil
+	}
+	if _, err := s.paymentService.RefundCharge(chargeID, refund); err != nil {
+		log.Printf("failed to refund %s of charge %s for orders that weren't placed: %v", refund, chargeID, err)
+		return err
+	}
+	return nil
+}
+
+// allocateDiscount splits a coupon discount over the orders of a checkout. A
+// seller coupon goes to that seller's order, a platform coupon is spread in
+// proportion to each order's subtotal with the rounding left on the last one.
+func allocateDiscount(applied *dto.AppliedCoupon, lines []dto.CouponLine) map[primitive.ObjectID]money.Money {
+	discounts := make(map[primitive.ObjectID]money.Money)
+	if applied.SellerID != nil {
+		discounts[*applied.SellerID] = applied.Discount
+		return discounts
+	}
+
+	var total money.Money
+	for _, line := range lines {
+		total = total.Add(line.Subtotal)
+	}
+	remaining := applied.Discount
+	for i, line := range lines {
+		if i == len(lines)-1 {
+			discounts[line.SellerID] = remaining
+			break
+		}
+		share := applied.Discount.Share(line.Subtotal, total)
+		discounts[line.SellerID] = share
+		remaining = remaining.Sub(share)
+	}
+	return discounts
+}
+
+// GetTotalPrice prices the products for the buyer with the coupon, if any,
+// applied. The coupon is only checked, it isn't redeemed.
+func (s OrderService) GetTotalPrice(buyerID primitive.ObjectID, products []dto.OrderProduct, couponCode string) (*dto.PriceQuote, error) {
+	var lines []dto.CouponLine
+	lineIndex := make(map[primitive.ObjectID]int)
+	var subtotal money.Money
 	for _, product := range products {
 		prod, err := s.productRepository.GetProductByID(product.ProductID)
 		if err != nil {
-			return 0, err
+			return nil, err
+		}
+		lineTotal := prod.Price.Mul(product.Amount)
+		subtotal = subtotal.Add(lineTotal)
+
+		i, ok := lineIndex[prod.SellerID]
+		if !ok {
+			i = len(lines)
+			lineIndex[prod.SellerID] = i
+			lines = append(lines, dto.CouponLine{SellerID: prod.SellerID})
 		}
-		totalPrice += prod.Price * float64(product.Amount)
+		lines[i].Subtotal = lines[i].Subtotal.Add(lineTotal)
 	}
-	return totalPrice, nil
+
+	quote := &dto.PriceQuote{Subtotal: subtotal, TotalPrice: subtotal}
+	if couponCode == "" {
+		return quote, nil
+	}
+	applied, err := s.couponService.ApplyCoupon(couponCode, buyerID, lines)
+	if err != nil {
+		return nil, err
+	}
+	quote.Coupon = applied
+	quote.Discount = applied.Discount
+	quote.TotalPrice = subtotal.Sub(applied.Discount)
+	return quote, nil
 }
 
 func (s OrderService) GetOrdersByUserID(userID primitive.ObjectID, userType userrole.UserType) ([]dto.Order, error) {
@@ -144,6 +545,254 @@ func (s OrderService) UpdateOrderStatus(orderID primitive.ObjectID, orderStatus
 	if err != nil {
 		return 0, fmt.Errorf("failed to update order status: %w", err)
 	}
+	if updatedStatus == orderstatus.CANCELLED {
+		s.cancelAppointment(orderID)
+	}
 
 	return updatedStatus, nil
 }
+
+// HandleNoShow applies the consequences of absentID missing the order's
+// appointment. Depending on the config the order is cancelled and refunded,
+// only in part when the buyer was absent, and the absent party gets a strike
+// on their account.
+func (s OrderService) HandleNoShow(orderID primitive.ObjectID, absentID primitive.ObjectID) error {
+	order, err := s.orderRepository.GetOrderByID(orderID)
+	if err != nil {
+		return err
+	}
+	if order.Status == orderstatus.DONE || order.Status == orderstatus.CANCELLED {
+		return ErrOrderClosed
+	}
+	if s.noShowCancel {
+		refundPercent := 100.0
+		if absentID == order.BuyerID {
+			refundPercent = s.noShowBuyerRefund
+		}
+		if err := s.cancelOrder(order, refundPercent); err != nil {
+			return err
+		}
+	}
+	if s.noShowStrike {
+		s.addNoShowStrike(order, absentID)
+	}
+	return nil
+}
+
+// cancelOrder cancels the order, puts its products back in stock and
+// refunds refundPercent of a card payment. What the seller earned on the
+// refunded part is taken back by the OrderRefunded event.
+func (s OrderService) cancelOrder(order *dto.Order, refundPercent float64) error {
+	from := int(order.Status)
+	// Claimed first so a refund can only be issued once
+	ok, err := s.orderRepository.AdvanceOrderStatus(order.OrderID, from, orderstatus.CANCELLED)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrOrderClosed
+	}
+
+	// Nothing was paid online for a cash order, so all of it is undone
+	refund := order.TotalPrice
+	if order.ChargeID != "" {
+		refund = order.TotalPrice.Percent(refundPercent)
+		if refund.IsPositive() {
+			if _, err := s.paymentService.RefundCharge(order.ChargeID, refund); err != nil {
+				if _, revertErr := s.orderRepository.AdvanceOrderStatus(order.OrderID, orderstatus.CANCELLED, from); revertErr != nil {
+					log.Printf("failed to restore status of order %s: %v", order.OrderID.Hex(), revertErr)
+				}
+				return fmt.Errorf("refund failed: %w", err)
+			}
+		}
+	}
+
+	// The order is cancelled and the buyer paid back, so what's left is
+	// only logged
+	s.cancelAppointment(order.OrderID)
+	for _, product := range order.Products {
+		if err := s.productRepository.RestockProduct(product.ProductID, product.Amount); err != nil {
+			log.Printf("failed to restock %d of product %s: %v", product.Amount, product.ProductID.Hex(), err)
+		}
+	}
+	if refund.IsPositive() {
+		if err := s.orderRepository.RecordOrderRefund(order.OrderID, refund); err != nil {
+			log.Printf("order %s was refunded %s but taking it back from seller %s couldn't be queued: %v", order.OrderID.Hex(), refund, order.SellerID.Hex(), err)
+		}
+	}
+	return nil
+}
+
+// cancelAppointment calls off the order's appointment so no reminders go
+// out for it. The order is already cancelled, so a failure is only logged.
+func (s OrderService) cancelAppointment(orderID primitive.ObjectID) {
+	if err := s.appointmentRepository.CancelAppointment(orderID); err != nil {
+		log.Printf("failed to cancel appointment of order %s: %v", orderID.Hex(), err)
+	}
+}
+
+// chargebackSellerNet takes back the seller's share of a refund
+func (s OrderService) chargebackSellerNet(event *dto.OutboxEvent) error {
+	var refunded dto.OrderRefundedEvent
+	if err := decodeEvent(event, &refunded); err != nil {
+		return err
+	}
+	order, err := s.orderRepository.GetOrderByID(refunded.OrderID)
+	if err != nil {
+		return err
+	}
+	sellerNet := orderSellerNet(order).Share(refunded.Refund, order.TotalPrice)
+	if !sellerNet.IsPositive() {
+		return nil
+	}
+	return s.sellerRepository.ChargebackSellerBalance(order.SellerID, order.OrderID, order.Payment, sellerNet)
+}
+
+// reverseOrderFees undoes the platform's fees on the refunded part of an
+// order
+func (s OrderService) reverseOrderFees(event *dto.OutboxEvent) error {
+	var refunded dto.OrderRefundedEvent
+	if err := decodeEvent(event, &refunded); err != nil {
+		return err
+	}
+	order, err := s.orderRepository.GetOrderByID(refunded.OrderID)
+	if err != nil {
+		return err
+	}
+	if order.Fees == nil {
+		return nil
+	}
+	share := func(m money.Money) money.Money {
+		return m.Share(refunded.Refund, order.TotalPrice)
+	}
+	return s.commissionService.ReverseOrderFees(order.OrderID, order.SellerID, &dto.OrderFees{
+		SellerGross:   share(order.Fees.SellerGross),
+		Commission:    share(order.Fees.Commission),
+		ProcessingFee: share(order.Fees.ProcessingFee),
+		CouponSubsidy: share(order.Fees.CouponSubsidy),
+		SellerNet:     share(order.Fees.SellerNet),
+	})
+}
+
+// orderSellerNet is what the seller earns on the order. Orders placed before
+// fees were recorded paid the seller the total.
+func orderSellerNet(order *dto.Order) money.Money {
+	if order.Fees != nil {
+		return order.Fees.SellerNet
+	}
+	return order.TotalPrice
+}
+
+// depositSellerNet pays the seller for a new order. The deposit is skipped
+// if the order was already paid out, so a retried event is safe.
+func (s OrderService) depositSellerNet(event *dto.OutboxEvent) error {
+	order, err := s.orderRepository.GetOrderByID(event.AggregateID)
+	if err != nil {
+		return err
+	}
+	return s.sellerRepository.DepositSellerBalance(order.SellerID, order.OrderID, order.Payment, orderSellerNet(order))
+}
+
+// recordOrderFees books the platform's side of a new order in the ledger
+func (s OrderService) recordOrderFees(event *dto.OutboxEvent) error {
+	order, err := s.orderRepository.GetOrderByID(event.AggregateID)
+	if err != nil {
+		return err
+	}
+	if order.Fees == nil {
+		return nil
+	}
+	return s.commissionService.RecordOrderFees(order.OrderID, order.SellerID, order.Fees)
+}
+
+// notifyOrderCreated tells the seller about a new order
+func (s OrderService) notifyOrderCreated(event *dto.OutboxEvent) error {
+	order, err := s.orderRepository.GetOrderByID(event.AggregateID)
+	if err != nil {
+		return err
+	}
+	return s.dispatcher.NotifyNow(context.Background(), order.SellerID, orderNotification(event, order, notificationtype.ORDER_STATUS,
+		"New order",
+		fmt.Sprintf("%s placed an order of %s.", order.BuyerName, order.TotalPrice),
+	))
+}
+
+// notifyStatusChanged tells the buyer and the seller the order moved to a
+// new status
+func (s OrderService) notifyStatusChanged(event *dto.OutboxEvent) error {
+	var changed dto.OrderStatusChangedEvent
+	if err := decodeEvent(event, &changed); err != nil {
+		return err
+	}
+	order, err := s.orderRepository.GetOrderByID(changed.OrderID)
+	if err != nil {
+		return err
+	}
+	statusEvent := orderNotification(event, order, notificationtype.ORDER_STATUS,
+		"Order "+orderStatusName(changed.To),
+		fmt.Sprintf("Order %s between %s and %s is now %s.", order.OrderID.Hex(), order.BuyerName, order.SellerName, orderStatusName(changed.To)),
+	)
+	return errors.Join(
+		s.dispatcher.NotifyNow(context.Background(), order.BuyerID, statusEvent),
+		s.dispatcher.NotifyNow(context.Background(), order.SellerID, statusEvent),
+	)
+}
+
+// notifyPayment tells the buyer their card payment for an order went through
+func (s OrderService) notifyPayment(event *dto.OutboxEvent) error {
+	var paid dto.PaymentSucceededEvent
+	if err := decodeEvent(event, &paid); err != nil {
+		return err
+	}
+	return s.dispatcher.NotifyNow(context.Background(), paid.BuyerID, dto.NotificationEvent{
+		Type:  notificationtype.PAYMENT,
+		Title: "Payment received",
+		Body:  fmt.Sprintf("We received your payment of %s for order %s.", paid.Amount, paid.OrderID.Hex()),
+		Data:  map[string]string{"orderID": paid.OrderID.Hex(), "chargeID": paid.ChargeID},
+		Key:   event.EventID.Hex(),
+	})
+}
+
+// orderNotification is the notification raised by event about the order
+func orderNotification(event *dto.OutboxEvent, order *dto.Order, eventType string, title string, body string) dto.NotificationEvent {
+	return dto.NotificationEvent{
+		Type:  eventType,
+		Title: title,
+		Body:  body,
+		Data:  map[string]string{"orderID": order.OrderID.Hex()},
+		Key:   event.EventID.Hex(),
+	}
+}
+
+func orderStatusName(status int) string {
+	switch status {
+	case orderstatus.WAITFORLOCATION:
+		return "waiting for a meeting place"
+	case orderstatus.WAITFORTIME:
+		return "waiting for a meeting time"
+	case orderstatus.APPOINTED:
+		return "appointed"
+	case orderstatus.DONE:
+		return "done"
+	case orderstatus.CANCELLED:
+		return "cancelled"
+	}
+	return fmt.Sprintf("in status %d", status)
+}
+
+// addNoShowStrike counts the no-show against the absent party. The order is
+// already handled by now, so a failure is only logged.
+func (s OrderService) addNoShowStrike(order *dto.Order, absentID primitive.ObjectID) {
+	var err error
+	switch absentID {
+	case order.BuyerID:
+		err = s.buyerRepository.AddNoShowStrike(absentID)
+	case order.SellerID:
+		err = s.sellerRepository.AddNoShowStrike(absentID)
+	default:
+		err = errors.New("not a party to the order")
+	}
+	if err != nil {
+		log.Printf("failed to add no-show strike to %s on order %s: %v", absentID.Hex(), order.OrderID.Hex(), err)
+	}
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/config"
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/eventtype"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/orderstatus"
+	"github.com/Dongy-s-Advanture/back-end/internal/model"
+	repomock "github.com/Dongy-s-Advanture/back-end/pkg/mock/repository"
+	servicemock "github.com/Dongy-s-Advanture/back-end/pkg/mock/service"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/money"
+	servicegomock "github.com/golang/mock/gomock"
+	"github.com/omise/omise-go"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/mock/gomock"
+)
+
+// noopBus drops subscriptions, the test only places orders
+type noopBus struct{}
+
+func (noopBus) Subscribe(eventType string, name string, handler EventHandler) {}
+
+// failingDispatcher fails to deliver to one user and records the keys of
+// what it was asked to send
+type failingDispatcher struct {
+	failFor primitive.ObjectID
+	keys    *[]string
+}
+
+func (d failingDispatcher) Notify(userID primitive.ObjectID, event dto.NotificationEvent) {}
+
+func (d failingDispatcher) NotifyNow(ctx context.Context, userID primitive.ObjectID, event dto.NotificationEvent) error {
+	*d.keys = append(*d.keys, event.Key)
+	if userID == d.failFor {
+		return errors.New("LINE is down")
+	}
+	return nil
+}
+
+func TestCheckoutRefundsOrdersNotPlaced(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	serviceCtrl := servicegomock.NewController(t)
+
+	orderRepo := repomock.NewMockIOrderRepository(ctrl)
+	appointmentRepo := repomock.NewMockIAppointmentRepository(ctrl)
+	sellerRepo := repomock.NewMockISellerRepository(ctrl)
+	productRepo := repomock.NewMockIProductRepository(ctrl)
+	buyerRepo := repomock.NewMockIBuyerRepository(ctrl)
+	paymentService := servicemock.NewMockIPaymentService(serviceCtrl)
+	couponService := servicemock.NewMockICouponService(serviceCtrl)
+	commissionService := servicemock.NewMockICommissionService(serviceCtrl)
+
+	s := NewOrderService(orderRepo, appointmentRepo, sellerRepo, productRepo, buyerRepo, paymentService, couponService, commissionService, nil, noopBus{}, &config.AppointmentConfig{})
+
+	buyerID, sellerA, sellerB := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	mug := &dto.Product{ProductID: primitive.NewObjectID(), ProductName: "Mug", SellerID: sellerA, Price: money.New(10000), Amount: 5}
+	lamp := &dto.Product{ProductID: primitive.NewObjectID(), ProductName: "Lamp", SellerID: sellerB, Price: money.New(5000), Amount: 5}
+	coupon := &dto.AppliedCoupon{CouponID: primitive.NewObjectID(), Code: "LAMP10", SellerID: &sellerB, Discount: money.New(1000)}
+	redemptionID := primitive.NewObjectID()
+
+	buyerRepo.EXPECT().GetBuyerByID(buyerID).Return(&dto.Buyer{
+		BuyerID: buyerID,
+		Cart: []dto.OrderProduct{
+			{ProductID: mug.ProductID, Amount: 1},
+			{ProductID: lamp.ProductID, Amount: 2},
+		},
+	}, nil)
+	productRepo.EXPECT().GetProductByID(mug.ProductID).Return(mug, nil).AnyTimes()
+	productRepo.EXPECT().GetProductByID(lamp.ProductID).Return(lamp, nil).AnyTimes()
+	sellerRepo.EXPECT().GetSellerByID(sellerA).Return(&dto.Seller{SellerID: sellerA}, nil)
+	sellerRepo.EXPECT().GetSellerByID(sellerB).Return(&dto.Seller{SellerID: sellerB}, nil)
+	couponService.EXPECT().ApplyCoupon("LAMP10", buyerID, servicegomock.Any()).Return(coupon, nil)
+
+	// Stock and coupon are reserved before the card is charged
+	productRepo.EXPECT().UpdateProductAmount(mug.ProductID, 1).Return(nil)
+	productRepo.EXPECT().UpdateProductAmount(lamp.ProductID, 2).Return(nil)
+	redeem := couponService.EXPECT().RedeemCoupon(coupon, buyerID).Return(redemptionID, nil)
+	charge := paymentService.EXPECT().HandlePayment(servicegomock.Any()).DoAndReturn(func(req *dto.PaymentRequest) (*omise.Charge, error) {
+		assert.Equal(t, money.New(19000), req.Amount)
+		return &omise.Charge{Base: omise.Base{ID: "chrg_test"}}, nil
+	}).After(redeem)
+
+	// The mug's order is placed, the lamp's fails
+	commissionService.EXPECT().CalculateFees(servicegomock.Any(), servicegomock.Any(), servicegomock.Any(), servicegomock.Any(), servicegomock.Any()).Return(&dto.OrderFees{}, nil).Times(2).After(charge)
+	appointmentRepo.EXPECT().CreateAppointment(gomock.Any()).Return(&dto.Appointment{AppointmentID: primitive.NewObjectID()}, nil)
+	orderRepo.EXPECT().CreateOrder(gomock.Any()).DoAndReturn(func(order *model.Order) (*dto.Order, error) {
+		assert.Equal(t, sellerA, order.SellerID)
+		assert.Equal(t, "chrg_test", order.ChargeID)
+		return &dto.Order{OrderID: order.OrderID, SellerID: order.SellerID}, nil
+	})
+	buyerRepo.EXPECT().DeleteProductFromCart(buyerID, mug.ProductID).Return(nil)
+	appointmentRepo.EXPECT().CreateAppointment(gomock.Any()).Return(nil, errors.New("database is down"))
+
+	// Only what wasn't placed is put back and refunded, and the coupon went
+	// to the lamp's order so it is released
+	productRepo.EXPECT().RestockProduct(lamp.ProductID, 2).Return(nil)
+	paymentService.EXPECT().RefundCharge("chrg_test", money.New(9000)).Return(&omise.Refund{}, nil)
+	couponService.EXPECT().ReleaseCoupon(coupon, redemptionID)
+
+	res, err := s.Checkout(buyerID, &dto.CheckoutRequest{Payment: "card", Token: "tokn_test", CouponCode: "LAMP10"})
+	assert.Nil(t, res)
+	assert.ErrorContains(t, err, "placed 1 of 2 orders")
+}
+
+func TestCreateOrderTakesTheSellerFromItsProducts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	productRepo := repomock.NewMockIProductRepository(ctrl)
+	s := NewOrderService(nil, nil, nil, productRepo, nil, nil, nil, nil, nil, noopBus{}, &config.AppointmentConfig{})
+
+	sellerA, sellerB := primitive.NewObjectID(), primitive.NewObjectID()
+	mug := &dto.Product{ProductID: primitive.NewObjectID(), ProductName: "Mug", SellerID: sellerA, Price: money.New(10000), Amount: 5}
+	lamp := &dto.Product{ProductID: primitive.NewObjectID(), ProductName: "Lamp", SellerID: sellerB, Price: money.New(5000), Amount: 5}
+	productRepo.EXPECT().GetProductByID(mug.ProductID).Return(mug, nil).AnyTimes()
+	productRepo.EXPECT().GetProductByID(lamp.ProductID).Return(lamp, nil).AnyTimes()
+
+	// Nothing is reserved or charged when the seller doesn't check out
+	tests := map[string]struct {
+		sellerID primitive.ObjectID
+		products []dto.OrderProduct
+		err      error
+	}{
+		"another seller's product": {sellerB, []dto.OrderProduct{{ProductID: mug.ProductID, Amount: 1}}, ErrSellerMismatch},
+		"products of two sellers": {sellerA, []dto.OrderProduct{
+			{ProductID: mug.ProductID, Amount: 1},
+			{ProductID: lamp.ProductID, Amount: 1},
+		}, ErrMixedSellers},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			res, err := s.CreateOrder(&dto.OrderCreateRequest{
+				Products:   tt.products,
+				BuyerID:    primitive.NewObjectID(),
+				SellerID:   tt.sellerID,
+				CouponCode: "LAMP10",
+			})
+			assert.Nil(t, res)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestBuyerNoShowRefundsPartOfTheOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	serviceCtrl := servicegomock.NewController(t)
+
+	orderRepo := repomock.NewMockIOrderRepository(ctrl)
+	appointmentRepo := repomock.NewMockIAppointmentRepository(ctrl)
+	productRepo := repomock.NewMockIProductRepository(ctrl)
+	buyerRepo := repomock.NewMockIBuyerRepository(ctrl)
+	paymentService := servicemock.NewMockIPaymentService(serviceCtrl)
+
+	s := NewOrderService(orderRepo, appointmentRepo, nil, productRepo, buyerRepo, paymentService, nil, nil, nil, noopBus{}, &config.AppointmentConfig{
+		NoShowCancel:             true,
+		NoShowStrike:             true,
+		NoShowBuyerRefundPercent: 50,
+	})
+
+	order := &dto.Order{
+		OrderID:    primitive.NewObjectID(),
+		BuyerID:    primitive.NewObjectID(),
+		SellerID:   primitive.NewObjectID(),
+		Status:     orderstatus.APPOINTED,
+		Products:   []dto.OrderProduct{{ProductID: primitive.NewObjectID(), Amount: 2}},
+		TotalPrice: money.New(20000),
+		ChargeID:   "chrg_test",
+	}
+	orderRepo.EXPECT().GetOrderByID(order.OrderID).Return(order, nil)
+	orderRepo.EXPECT().AdvanceOrderStatus(order.OrderID, orderstatus.APPOINTED, orderstatus.CANCELLED).Return(true, nil)
+	paymentService.EXPECT().RefundCharge("chrg_test", money.New(10000)).Return(&omise.Refund{}, nil)
+	// No reminders go out for the called off appointment
+	appointmentRepo.EXPECT().CancelAppointment(order.OrderID).Return(nil)
+	productRepo.EXPECT().RestockProduct(order.Products[0].ProductID, 2).Return(nil)
+	// The seller's side is taken back by the event, even if it has to be
+	// retried
+	orderRepo.EXPECT().RecordOrderRefund(order.OrderID, money.New(10000)).Return(nil)
+	buyerRepo.EXPECT().AddNoShowStrike(order.BuyerID).Return(nil)
+
+	assert.NoError(t, s.HandleNoShow(order.OrderID, order.BuyerID))
+}
+
+func TestNotifyStatusChangedIsRetriedWhenDeliveryFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	orderRepo := repomock.NewMockIOrderRepository(ctrl)
+	var keys []string
+	order := &dto.Order{OrderID: primitive.NewObjectID(), BuyerID: primitive.NewObjectID(), SellerID: primitive.NewObjectID()}
+	s := NewOrderService(orderRepo, nil, nil, nil, nil, nil, nil, nil, failingDispatcher{failFor: order.SellerID, keys: &keys}, noopBus{}, &config.AppointmentConfig{}).(OrderService)
+
+	payload, err := bson.Marshal(dto.OrderStatusChangedEvent{OrderID: order.OrderID, To: orderstatus.APPOINTED})
+	assert.NoError(t, err)
+	event := &dto.OutboxEvent{EventID: primitive.NewObjectID(), Type: eventtype.ORDER_STATUS_CHANGED, AggregateID: order.OrderID, Payload: payload}
+	orderRepo.EXPECT().GetOrderByID(order.OrderID).Return(order, nil).Times(2)
+
+	// The failure goes back to the relay, and the retry sends the same
+	// notifications so the inbox keeps one of each
+	assert.ErrorContains(t, s.notifyStatusChanged(event), "LINE is down")
+	assert.Error(t, s.notifyStatusChanged(event))
+	assert.Equal(t, []string{event.EventID.Hex(), event.EventID.Hex(), event.EventID.Hex(), event.EventID.Hex()}, keys)
+}
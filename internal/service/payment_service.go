@@ -1,9 +1,12 @@
 package service
 
 import (
+	"errors"
 	"log"
+	"strings"
 
 	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/money"
 	"github.com/omise/omise-go"
 	"github.com/omise/omise-go/operations"
 )
@@ -14,6 +17,7 @@ type IPaymentService interface {
 	RemoveClient(chargeID string, client chan string)
 	BroadcastChargeStatus(chargeID, status string)
 	UpdatePaymentStatus(chargeID, status string) error
+	RefundCharge(chargeID string, amount money.Money) (*omise.Refund, error)
 }
 type PaymentService struct {
 	client     *omise.Client
@@ -25,11 +29,14 @@ func NewPaymentService(client *omise.Client) IPaymentService {
 }
 
 func (s PaymentService) HandlePayment(paymentRequest *dto.PaymentRequest) (*omise.Charge, error) {
+	if !paymentRequest.Amount.IsPositive() {
+		return nil, errors.New("amount must be positive")
+	}
 	token := paymentRequest.Token
 	charge := &omise.Charge{}
 	if err := s.client.Do(charge, &operations.CreateCharge{
-		Amount:   paymentRequest.Amount,
-		Currency: "thb",
+		Amount:   paymentRequest.Amount.Amount,
+		Currency: strings.ToLower(paymentRequest.Amount.CurrencyCode()),
 		Card:     token,
 	}); err != nil {
 		return nil, err
@@ -38,6 +45,22 @@ func (s PaymentService) HandlePayment(paymentRequest *dto.PaymentRequest) (*omis
 	return charge, nil
 }
 
+// RefundCharge gives amount of the charge back to the card. A checkout pays
+// for several orders with one charge, so an order is refunded in part.
+func (s PaymentService) RefundCharge(chargeID string, amount money.Money) (*omise.Refund, error) {
+	if !amount.IsPositive() {
+		return nil, errors.New("amount must be positive")
+	}
+	refund := &omise.Refund{}
+	if err := s.client.Do(refund, &operations.CreateRefund{
+		ChargeID: chargeID,
+		Amount:   amount.Amount,
+	}); err != nil {
+		return nil, err
+	}
+	return refund, nil
+}
+
 func (s PaymentService) BroadcastChargeStatus(chargeID, status string) {
 	if clients, ok := s.sseClients[chargeID]; ok {
 		for _, client := range clients {
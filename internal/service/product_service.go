@@ -1,9 +1,16 @@
 package service
 
 import (
+	"errors"
+	"log"
+	"sort"
+
 	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/productstatus"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/uploadowner"
 	"github.com/Dongy-s-Advanture/back-end/internal/model"
 	"github.com/Dongy-s-Advanture/back-end/internal/repository"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/geo"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -11,27 +18,47 @@ type IProductService interface {
 	GetProductByID(productID primitive.ObjectID) (*dto.Product, error)
 	GetProductsBySellerID(sellerID primitive.ObjectID) ([]dto.Product, error)
 	GetProducts() ([]dto.Product, error)
+	GetProductsNear(near geo.Point, radius float64) ([]dto.Product, error)
 	CreateProduct(product *model.Product) (*dto.Product, error)
 	UpdateProduct(productID primitive.ObjectID, updatedProduct *model.Product) (*dto.Product, error)
 	DeleteProduct(productID primitive.ObjectID) error
+	GetSellerListings(sellerID primitive.ObjectID, status *int) ([]dto.Product, error)
+	PublishProduct(productID primitive.ObjectID) (*dto.Product, error)
+	ArchiveProduct(productID primitive.ObjectID) (*dto.Product, error)
+	RestoreProduct(productID primitive.ObjectID) (*dto.Product, error)
 }
 
 type ProductService struct {
 	productRepository repository.IProductRepository
+	sellerRepository  repository.ISellerRepository
+	uploadService     IUploadService
+	watchers          []IProductWatcher
 }
 
-func NewProductService(r repository.IProductRepository) IProductService {
+func NewProductService(r repository.IProductRepository, sr repository.ISellerRepository, uploadService IUploadService, watchers ...IProductWatcher) IProductService {
 	return ProductService{
 		productRepository: r,
+		sellerRepository:  sr,
+		uploadService:     uploadService,
+		watchers:          watchers,
 	}
 }
 
 func (s ProductService) CreateProduct(product *model.Product) (*dto.Product, error) {
 	// You may not need to hash passwords for products, so you can remove that part.
+	if product.Price.IsNegative() {
+		return nil, errors.New("price must not be negative")
+	}
+	if product.Status != productstatus.DRAFT {
+		product.Status = stockStatus(product.Amount)
+	}
 	newProduct, err := s.productRepository.CreateProduct(product)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.uploadService.Claim(uploadowner.PRODUCT, newProduct.ProductID, imageURLs(newProduct.Image, newProduct.Images)...); err != nil {
+		log.Printf("failed to claim uploads for product %s: %v", newProduct.ProductID.Hex(), err)
+	}
 	return newProduct, nil
 }
 
@@ -59,14 +86,56 @@ func (s ProductService) GetProducts() ([]dto.Product, error) {
 	return products, nil
 }
 
+// GetProductsNear returns the products of sellers within radius meters of
+// near, from the closest seller to the furthest
+func (s ProductService) GetProductsNear(near geo.Point, radius float64) ([]dto.Product, error) {
+	sellerIDs, err := s.sellerRepository.GetSellerIDsNear(near, radius)
+	if err != nil {
+		return nil, err
+	}
+	if len(sellerIDs) == 0 {
+		return []dto.Product{}, nil
+	}
+	products, err := s.productRepository.GetProductsBySellerIDs(sellerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	rank := make(map[primitive.ObjectID]int, len(sellerIDs))
+	for i, sellerID := range sellerIDs {
+		rank[sellerID] = i
+	}
+	sort.SliceStable(products, func(i, j int) bool {
+		return rank[products[i].SellerID] < rank[products[j].SellerID]
+	})
+	return products, nil
+}
+
 func (s ProductService) UpdateProduct(productID primitive.ObjectID, updatedProduct *model.Product) (*dto.Product, error) {
+	if updatedProduct.Price.IsNegative() {
+		return nil, errors.New("price must not be negative")
+	}
+	oldProduct, err := s.productRepository.GetProductByID(productID)
+	if err != nil {
+		return nil, err
+	}
+
 	updatedProductDTO, err := s.productRepository.UpdateProduct(productID, updatedProduct)
 	if err != nil {
 		return nil, err
 	}
+
+	oldURLs := imageURLs(oldProduct.Image, oldProduct.Images)
+	newURLs := imageURLs(updatedProductDTO.Image, updatedProductDTO.Images)
+	if err := s.uploadService.Replace(uploadowner.PRODUCT, productID, oldURLs, newURLs); err != nil {
+		log.Printf("failed to update uploads for product %s: %v", productID.Hex(), err)
+	}
+	s.notifyWatchers(oldProduct, updatedProductDTO)
 	return updatedProductDTO, nil
 }
 
+// DeleteProduct soft deletes the product. Its images stay claimed because
+// past orders still show them.
 func (s ProductService) DeleteProduct(productID primitive.ObjectID) error {
 
 	err := s.productRepository.DeleteProduct(productID)
@@ -76,3 +145,78 @@ func (s ProductService) DeleteProduct(productID primitive.ObjectID) error {
 
 	return nil
 }
+
+func (s ProductService) GetSellerListings(sellerID primitive.ObjectID, status *int) ([]dto.Product, error) {
+	products, err := s.productRepository.GetSellerListings(sellerID, status)
+	if err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+func (s ProductService) PublishProduct(productID primitive.ObjectID) (*dto.Product, error) {
+	product, err := s.productRepository.GetProductByID(productID)
+	if err != nil {
+		return nil, err
+	}
+	if product.Status != productstatus.DRAFT {
+		return nil, errors.New("only draft products can be published")
+	}
+	return s.productRepository.UpdateProductStatus(productID, stockStatus(product.Amount))
+}
+
+func (s ProductService) ArchiveProduct(productID primitive.ObjectID) (*dto.Product, error) {
+	product, err := s.productRepository.GetProductByID(productID)
+	if err != nil {
+		return nil, err
+	}
+	if product.DeletedAt != nil {
+		return nil, errors.New("product is deleted")
+	}
+	return s.productRepository.ArchiveProduct(productID)
+}
+
+// RestoreProduct brings an archived or deleted product back on sale, or back
+// to the drafts if it was archived before it was published
+func (s ProductService) RestoreProduct(productID primitive.ObjectID) (*dto.Product, error) {
+	product, err := s.productRepository.GetProductByID(productID)
+	if err != nil {
+		return nil, err
+	}
+	if product.Status != productstatus.ARCHIVED {
+		return nil, errors.New("only archived products can be restored")
+	}
+	status := stockStatus(product.Amount)
+	if product.ArchivedFrom != nil && *product.ArchivedFrom == productstatus.DRAFT {
+		status = productstatus.DRAFT
+	}
+	restored, err := s.productRepository.RestoreProduct(productID, status)
+	if err != nil {
+		return nil, err
+	}
+	s.notifyWatchers(product, restored)
+	return restored, nil
+}
+
+// notifyWatchers compares a product before and after an update and tells
+// the watchers about price drops and products that can be bought again
+func (s ProductService) notifyWatchers(before *dto.Product, after *dto.Product) {
+	if after.Status != productstatus.ACTIVE || after.DeletedAt != nil {
+		return
+	}
+	wasBuyable := before.Status == productstatus.ACTIVE && before.DeletedAt == nil && before.Amount > 0
+	for _, watcher := range s.watchers {
+		if !wasBuyable {
+			watcher.BackInStock(after)
+		} else if after.Price.LessThan(before.Price) {
+			watcher.PriceDropped(after, before.Price)
+		}
+	}
+}
+
+func stockStatus(amount int) int {
+	if amount <= 0 {
+		return productstatus.SOLDOUT
+	}
+	return productstatus.ACTIVE
+}
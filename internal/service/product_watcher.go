@@ -0,0 +1,56 @@
+package service
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/notificationtype"
+	"github.com/Dongy-s-Advanture/back-end/internal/repository"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/money"
+)
+
+// IProductWatcher is notified by ProductService when a product becomes more
+// attractive to buyers. Watchers run after the update has been saved.
+type IProductWatcher interface {
+	PriceDropped(product *dto.Product, oldPrice money.Money)
+	BackInStock(product *dto.Product)
+}
+
+// WishlistWatcher alerts the buyers who have the product on their wishlist
+type WishlistWatcher struct {
+	buyerRepository repository.IBuyerRepository
+	dispatcher      INotificationDispatcher
+}
+
+func NewWishlistWatcher(r repository.IBuyerRepository, d INotificationDispatcher) IProductWatcher {
+	return WishlistWatcher{
+		buyerRepository: r,
+		dispatcher:      d,
+	}
+}
+
+func (w WishlistWatcher) PriceDropped(product *dto.Product, oldPrice money.Money) {
+	w.alert(product, "Price drop", "The price of %s dropped from %s to %s.", product.ProductName, oldPrice, product.Price)
+}
+
+func (w WishlistWatcher) BackInStock(product *dto.Product) {
+	w.alert(product, "Back in stock", "%s is back in stock.", product.ProductName)
+}
+
+func (w WishlistWatcher) alert(product *dto.Product, title string, format string, args ...interface{}) {
+	buyerIDs, err := w.buyerRepository.GetBuyerIDsByWishlistProduct(product.ProductID)
+	if err != nil {
+		log.Printf("failed to get buyers watching product %s: %v", product.ProductID.Hex(), err)
+		return
+	}
+	event := dto.NotificationEvent{
+		Type:  notificationtype.WISHLIST,
+		Title: title,
+		Body:  fmt.Sprintf(format, args...),
+		Data:  map[string]string{"productID": product.ProductID.Hex()},
+	}
+	for _, buyerID := range buyerIDs {
+		w.dispatcher.Notify(buyerID, event)
+	}
+}
@@ -1,30 +1,92 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/config"
 	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/eventtype"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/notificationtype"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/orderstatus"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/reportstatus"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/reviewstatus"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/uploadowner"
 	"github.com/Dongy-s-Advanture/back-end/internal/model"
 	"github.com/Dongy-s-Advanture/back-end/internal/repository"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrReviewForbidden   = errors.New("only the buyer of the order can review it")
+	ErrReviewNotOwned    = errors.New("only the buyer who wrote the review can change it")
+	ErrOrderNotCompleted = errors.New("only completed orders can be reviewed")
+	ErrOrderReviewed     = errors.New("the order has already been reviewed")
+	ErrReplyForbidden    = errors.New("only the reviewed seller can reply")
+	ErrReviewReplied     = errors.New("the review already has a reply")
+	ErrReportOwnReview   = errors.New("cannot report your own review")
+	ErrReviewReported    = errors.New("you have already reported this review")
+	ErrInvalidModeration = errors.New("the review cannot be moved to this status")
+	ErrReviewChanged     = errors.New("the review was moderated in the meantime")
+	// Product reviews must score a product bought in the order
+	ErrInvalidProductReview = errors.New("invalid product review")
+	ErrInvalidReviewSort    = errors.New("sort must be newest, highest or lowest")
 )
 
+// maxReviewScore is the highest score a review can give, reviews score from 0
+const maxReviewScore = 10
+
 type IReviewService interface {
 	GetReviews() ([]dto.Review, error)
 	GetReviewByID(reviewID primitive.ObjectID) (*dto.Review, error)
 	GetReviewsBySellerID(sellerID primitive.ObjectID) ([]dto.Review, error)
 	GetReviewsByBuyerID(buyerID primitive.ObjectID) ([]dto.Review, error)
-	CreateReview(review *model.Review) (*dto.Review, error)
-	UpdateReview(reviewID primitive.ObjectID, updatedReview *model.Review) (*dto.Review, error)
-	DeleteReview(reviewID primitive.ObjectID) error
+	CreateReview(buyerID primitive.ObjectID, review *model.Review) (*dto.Review, error)
+	UpdateReview(reviewID primitive.ObjectID, callerID primitive.ObjectID, isAdmin bool, updatedReview *model.Review) (*dto.Review, error)
+	DeleteReview(reviewID primitive.ObjectID, callerID primitive.ObjectID, isAdmin bool) error
+	GetSellerRating(sellerID primitive.ObjectID) (*dto.SellerRating, error)
+	ReplyToReview(reviewID primitive.ObjectID, sellerID primitive.ObjectID, message string) (*dto.Review, error)
+	ReportReview(reviewID primitive.ObjectID, reporterID primitive.ObjectID, reason string) (*dto.ReviewReport, error)
+	GetModerationQueue() ([]dto.ModerationItem, error)
+	HideReview(reviewID primitive.ObjectID) (*dto.Review, error)
+	RemoveReview(reviewID primitive.ObjectID) (*dto.Review, error)
+	RestoreReview(reviewID primitive.ObjectID) (*dto.Review, error)
+	GetReviewsByProductID(productID primitive.ObjectID, sortBy string) ([]dto.ProductReview, error)
 }
 
 type ReviewService struct {
-	reviewRepository repository.IReviewRepository
+	reviewRepository  repository.IReviewRepository
+	orderRepository   repository.IOrderRepository
+	sellerRepository  repository.ISellerRepository
+	productRepository repository.IProductRepository
+	reportRepository  repository.IReviewReportRepository
+	uploadService     IUploadService
+	dispatcher        INotificationDispatcher
+	ratingConfig      *config.RatingConfig
 }
 
-func NewReviewService(r repository.IReviewRepository) IReviewService {
-	return ReviewService{
-		reviewRepository: r,
+func NewReviewService(r repository.IReviewRepository, or repository.IOrderRepository, sr repository.ISellerRepository, pr repository.IProductRepository, rr repository.IReviewReportRepository, uploadService IUploadService, d INotificationDispatcher, bus IEventBus, ratingCfg *config.RatingConfig) IReviewService {
+	s := ReviewService{
+		reviewRepository:  r,
+		orderRepository:   or,
+		sellerRepository:  sr,
+		productRepository: pr,
+		reportRepository:  rr,
+		uploadService:     uploadService,
+		dispatcher:        d,
+		ratingConfig:      ratingCfg,
 	}
+	bus.Subscribe(eventtype.REVIEW_CREATED, "seller-rating", s.addSellerRating)
+	bus.Subscribe(eventtype.REVIEW_CREATED, "product-rating", s.addProductRatings)
+	bus.Subscribe(eventtype.REVIEW_CREATED, "notification", s.notifyReviewCreated)
+	return s
 }
 
 func (s ReviewService) GetReviews() ([]dto.Review, error) {
@@ -59,35 +121,421 @@ func (s ReviewService) GetReviewsByBuyerID(buyerID primitive.ObjectID) ([]dto.Re
 	return reviews, nil
 }
 
-func (s ReviewService) CreateReview(review *model.Review) (*dto.Review, error) {
+func (s ReviewService) CreateReview(buyerID primitive.ObjectID, review *model.Review) (*dto.Review, error) {
+	order, err := s.orderRepository.GetOrderByID(review.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.BuyerID != buyerID {
+		return nil, ErrReviewForbidden
+	}
+	if order.Status != orderstatus.DONE {
+		return nil, ErrOrderNotCompleted
+	}
+	if err := verifyReview(review, order); err != nil {
+		return nil, err
+	}
 
 	newReview, err := s.reviewRepository.CreateReview(review)
 
 	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrOrderReviewed
+		}
 		return nil, err
 	}
+	// The ratings are updated and the seller told by the subscribers of
+	// ReviewCreated
+
+	if err := s.uploadService.Claim(uploadowner.REVIEW, newReview.ReviewID, imageURLs(newReview.Image, newReview.Images)...); err != nil {
+		log.Printf("failed to claim uploads for review %s: %v", newReview.ReviewID.Hex(), err)
+	}
 
 	return newReview, nil
 }
 
 
-func (s ReviewService) UpdateReview(reviewID primitive.ObjectID, updatedReview *model.Review) (*dto.Review, error) {
+// UpdateReview changes a review. Only its buyer or an admin may.
+func (s ReviewService) UpdateReview(reviewID primitive.ObjectID, callerID primitive.ObjectID, isAdmin bool, updatedReview *model.Review) (*dto.Review, error) {
+	oldReview, err := s.reviewRepository.GetReviewByID(reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if oldReview.BuyerID != callerID && !isAdmin {
+		return nil, ErrReviewNotOwned
+	}
 
 	updatedReviewDTO, err := s.reviewRepository.UpdateReview(reviewID, updatedReview)
 	if err != nil {
 		return nil, err
 	}
+	if oldReview.Status == reviewstatus.VISIBLE && updatedReviewDTO.Score != oldReview.Score {
+		s.updateRating(updatedReviewDTO.SellerID, []int{oldReview.Score}, []int{updatedReviewDTO.Score})
+	}
+
+	oldURLs := imageURLs(oldReview.Image, oldReview.Images)
+	newURLs := imageURLs(updatedReviewDTO.Image, updatedReviewDTO.Images)
+	if err := s.uploadService.Replace(uploadowner.REVIEW, reviewID, oldURLs, newURLs); err != nil {
+		log.Printf("failed to update uploads for review %s: %v", reviewID.Hex(), err)
+	}
 
 	return updatedReviewDTO, nil
 }
 
-func (s ReviewService) DeleteReview(reviewID primitive.ObjectID) error {
+// DeleteReview deletes a review. Only its buyer or an admin may.
+func (s ReviewService) DeleteReview(reviewID primitive.ObjectID, callerID primitive.ObjectID, isAdmin bool) error {
+	review, err := s.reviewRepository.GetReviewByID(reviewID)
+	if err != nil {
+		return err
+	}
+	if review.BuyerID != callerID && !isAdmin {
+		return ErrReviewNotOwned
+	}
 
-	err := s.reviewRepository.DeleteReview(reviewID)
+	err = s.reviewRepository.DeleteReview(reviewID)
 	if err != nil {
 		return err 
 	}
+	if review.Status == reviewstatus.VISIBLE {
+		s.updateRating(review.SellerID, []int{review.Score}, nil)
+		s.updateProductRatings(review.Products, true)
+	}
+	if err := s.reportRepository.ResolveReports(reviewID, reportstatus.DISMISSED); err != nil {
+		log.Printf("failed to dismiss reports of review %s: %v", reviewID.Hex(), err)
+	}
+
+	if err := s.uploadService.Release(uploadowner.REVIEW, reviewID, imageURLs(review.Image, review.Images)...); err != nil {
+		log.Printf("failed to release uploads for review %s: %v", reviewID.Hex(), err)
+	}
 
 	return nil 
 }
 
+// verifyReview takes who the review is by and for, and what was bought,
+// from the order rather than from the request. Only the scores and comments
+// of the products are kept from the request.
+func verifyReview(review *model.Review, order *dto.Order) error {
+	productReviews := map[primitive.ObjectID]model.ReviewProduct{}
+	for _, p := range review.Products {
+		if p.Score == nil || *p.Score < 0 || *p.Score > maxReviewScore {
+			return fmt.Errorf("%w: product scores go from 0 to %d", ErrInvalidProductReview, maxReviewScore)
+		}
+		productReviews[p.ProductID] = p
+	}
+
+	review.Status = reviewstatus.VISIBLE
+	review.Reply = nil
+	review.BuyerID = order.BuyerID
+	review.BuyerName = order.BuyerName
+	review.SellerID = order.SellerID
+	review.SellerName = order.SellerName
+	review.Products = make([]model.ReviewProduct, 0, len(order.Products))
+	reviewed := map[primitive.ObjectID]bool{}
+	for _, p := range order.Products {
+		product := model.ReviewProduct{
+			ProductID:   p.ProductID,
+			ProductName: p.ProductName,
+			Color:       p.Color,
+			Image:       p.Image,
+			Amount:      p.Amount,
+		}
+		// A product bought in two colors is reviewed once
+		if r, ok := productReviews[p.ProductID]; ok && !reviewed[p.ProductID] {
+			product.Score = r.Score
+			product.Comment = r.Comment
+			reviewed[p.ProductID] = true
+		}
+		review.Products = append(review.Products, product)
+	}
+	for productID := range productReviews {
+		if !reviewed[productID] {
+			return fmt.Errorf("%w: product %s was not bought in the order", ErrInvalidProductReview, productID.Hex())
+		}
+	}
+	return nil
+}
+
+// GetReviewsByProductID lists what reviews said about a product, sorted by
+// sortBy, newest first by default
+func (s ReviewService) GetReviewsByProductID(productID primitive.ObjectID, sortBy string) ([]dto.ProductReview, error) {
+	if sortBy == "" {
+		sortBy = repository.ReviewSortNewest
+	}
+	if !slices.Contains([]string{repository.ReviewSortNewest, repository.ReviewSortHighest, repository.ReviewSortLowest}, sortBy) {
+		return nil, ErrInvalidReviewSort
+	}
+	return s.reviewRepository.GetReviewsByProductID(productID, sortBy)
+}
+
+func (s ReviewService) GetSellerRating(sellerID primitive.ObjectID) (*dto.SellerRating, error) {
+	seller, err := s.sellerRepository.GetSellerByID(sellerID)
+	if err != nil {
+		return nil, err
+	}
+	rating := seller.Rating
+	if rating == nil {
+		rating = &dto.Rating{}
+	}
+	return ratingBreakdown(sellerID, rating, SellerScore(rating, s.ratingConfig)), nil
+}
+
+func (s ReviewService) ReplyToReview(reviewID primitive.ObjectID, sellerID primitive.ObjectID, message string) (*dto.Review, error) {
+	review, err := s.reviewRepository.GetReviewByID(reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.SellerID != sellerID {
+		return nil, ErrReplyForbidden
+	}
+
+	ok, err := s.reviewRepository.SetReviewReply(reviewID, &model.ReviewReply{Message: message, Date: time.Now()})
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrReviewReplied
+	}
+	s.dispatcher.Notify(review.BuyerID, reviewNotification(review,
+		"Reply to your review",
+		fmt.Sprintf("The seller replied to your review: %s", message),
+	))
+	return s.reviewRepository.GetReviewByID(reviewID)
+}
+
+func reviewNotification(review *dto.Review, title string, body string) dto.NotificationEvent {
+	return dto.NotificationEvent{
+		Type:  notificationtype.REVIEW,
+		Title: title,
+		Body:  body,
+		Data:  map[string]string{"reviewID": review.ReviewID.Hex(), "orderID": review.OrderID.Hex()},
+	}
+}
+
+func (s ReviewService) ReportReview(reviewID primitive.ObjectID, reporterID primitive.ObjectID, reason string) (*dto.ReviewReport, error) {
+	review, err := s.reviewRepository.GetReviewByID(reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.BuyerID == reporterID {
+		return nil, ErrReportOwnReview
+	}
+
+	report, err := s.reportRepository.CreateReport(&model.ReviewReport{
+		ReviewID:   reviewID,
+		ReporterID: reporterID,
+		Reason:     reason,
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, ErrReviewReported
+	}
+	return report, err
+}
+
+// GetModerationQueue lists the reviews with reports waiting on a decision,
+// longest waiting first
+func (s ReviewService) GetModerationQueue() ([]dto.ModerationItem, error) {
+	reports, err := s.reportRepository.GetPendingReports()
+	if err != nil {
+		return nil, err
+	}
+
+	queue := []dto.ModerationItem{}
+	index := map[primitive.ObjectID]int{}
+	for _, report := range reports {
+		if i, ok := index[report.ReviewID]; ok {
+			queue[i].Reports = append(queue[i].Reports, report)
+			continue
+		}
+		review, err := s.reviewRepository.GetReviewByID(report.ReviewID)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		index[report.ReviewID] = len(queue)
+		queue = append(queue, dto.ModerationItem{Review: *review, Reports: []dto.ReviewReport{report}})
+	}
+	return queue, nil
+}
+
+// HideReview takes a review down until an admin removes or restores it
+func (s ReviewService) HideReview(reviewID primitive.ObjectID) (*dto.Review, error) {
+	return s.moveReview(reviewID, reviewstatus.HIDDEN, reviewstatus.VISIBLE)
+}
+
+// RemoveReview upholds the reports about a review
+func (s ReviewService) RemoveReview(reviewID primitive.ObjectID) (*dto.Review, error) {
+	review, err := s.moveReview(reviewID, reviewstatus.REMOVED, reviewstatus.VISIBLE, reviewstatus.HIDDEN)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.reportRepository.ResolveReports(reviewID, reportstatus.UPHELD); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// RestoreReview dismisses the reports about a review and shows it again
+func (s ReviewService) RestoreReview(reviewID primitive.ObjectID) (*dto.Review, error) {
+	review, err := s.moveReview(reviewID, reviewstatus.VISIBLE, reviewstatus.HIDDEN, reviewstatus.REMOVED)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.reportRepository.ResolveReports(reviewID, reportstatus.DISMISSED); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// moveReview sets the status of a review in one of the from statuses, and
+// takes it out of or puts it back into the seller's rating
+func (s ReviewService) moveReview(reviewID primitive.ObjectID, to int, from ...int) (*dto.Review, error) {
+	review, err := s.reviewRepository.GetReviewByID(reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.Status == to {
+		return review, nil
+	}
+	if !slices.Contains(from, review.Status) {
+		return nil, ErrInvalidModeration
+	}
+
+	ok, err := s.reviewRepository.SetReviewStatus(reviewID, review.Status, to)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrReviewChanged
+	}
+	switch {
+	case review.Status == reviewstatus.VISIBLE:
+		s.updateRating(review.SellerID, []int{review.Score}, nil)
+		s.updateProductRatings(review.Products, true)
+	case to == reviewstatus.VISIBLE:
+		s.updateRating(review.SellerID, nil, []int{review.Score})
+		s.updateProductRatings(review.Products, false)
+	}
+	review.Status = to
+	return review, nil
+}
+
+// updateRating moves the seller's rating and score along with a review
+// change. The review is saved by then, so failures are only logged.
+func (s ReviewService) updateRating(sellerID primitive.ObjectID, removed []int, added []int) {
+	if err := s.rateSeller(sellerID, removed, added); err != nil {
+		log.Printf("failed to update rating of seller %s: %v", sellerID.Hex(), err)
+	}
+}
+
+// rateSeller moves the seller's rating and then the score worked out from
+// it. Only a failed rating is returned, the score catches up on the next
+// review.
+func (s ReviewService) rateSeller(sellerID primitive.ObjectID, removed []int, added []int) error {
+	rating, err := s.sellerRepository.UpdateRating(sellerID, removed, added)
+	if err != nil {
+		return err
+	}
+	if _, err := s.sellerRepository.SetSellerScore(sellerID, rating, SellerScore(rating, s.ratingConfig)); err != nil {
+		log.Printf("failed to update score of seller %s: %v", sellerID.Hex(), err)
+	}
+	return nil
+}
+
+// addSellerRating adds a new review's score to the seller's rating
+func (s ReviewService) addSellerRating(event *dto.OutboxEvent) error {
+	var created dto.ReviewCreatedEvent
+	if err := decodeEvent(event, &created); err != nil {
+		return err
+	}
+	return s.rateSeller(created.SellerID, nil, []int{created.Score})
+}
+
+// addProductRatings adds the scores a new review gave products to their
+// ratings. Products are updated one at a time, so a retry after only some
+// of them were updated counts those again.
+func (s ReviewService) addProductRatings(event *dto.OutboxEvent) error {
+	var created dto.ReviewCreatedEvent
+	if err := decodeEvent(event, &created); err != nil {
+		return err
+	}
+	for _, product := range created.Products {
+		if err := s.productRepository.UpdateRating(product.ProductID, nil, []int{product.Score}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// notifyReviewCreated tells the seller about a new review, unless it was
+// deleted in the meantime
+func (s ReviewService) notifyReviewCreated(event *dto.OutboxEvent) error {
+	var created dto.ReviewCreatedEvent
+	if err := decodeEvent(event, &created); err != nil {
+		return err
+	}
+	review, err := s.reviewRepository.GetReviewByID(created.ReviewID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	notification := reviewNotification(review,
+		"New review",
+		fmt.Sprintf("%s scored your order %d out of %d.", created.BuyerName, created.Score, maxReviewScore),
+	)
+	notification.Key = event.EventID.Hex()
+	return s.dispatcher.NotifyNow(context.Background(), created.SellerID, notification)
+}
+
+// updateProductRatings adds the scores the review gave products to their
+// ratings, or takes them out when removed is set. Failures are only logged.
+func (s ReviewService) updateProductRatings(products []dto.ReviewProduct, removed bool) {
+	for _, p := range products {
+		if p.Score == nil {
+			continue
+		}
+		scores := []int{*p.Score}
+		var err error
+		if removed {
+			err = s.productRepository.UpdateRating(p.ProductID, scores, nil)
+		} else {
+			err = s.productRepository.UpdateRating(p.ProductID, nil, scores)
+		}
+		if err != nil {
+			log.Printf("failed to update rating of product %s: %v", p.ProductID.Hex(), err)
+		}
+	}
+}
+
+// SellerScore is the average review score weighted towards the configured
+// prior, or 0 for sellers without reviews
+func SellerScore(rating *dto.Rating, cfg *config.RatingConfig) float64 {
+	if rating.Count <= 0 {
+		return 0
+	}
+	score := (cfg.PriorMean*cfg.PriorWeight + float64(rating.Sum)) / (cfg.PriorWeight + float64(rating.Count))
+	return math.Round(score*100) / 100
+}
+
+// ratingBreakdown lists how many reviews gave each score, from 0 to 10
+func ratingBreakdown(sellerID primitive.ObjectID, rating *dto.Rating, score float64) *dto.SellerRating {
+	breakdown := &dto.SellerRating{
+		SellerID:  sellerID,
+		Count:     rating.Count,
+		Score:     score,
+		Histogram: make([]dto.RatingBucket, 0, maxReviewScore+1),
+	}
+	if rating.Count > 0 {
+		breakdown.Average = math.Round(float64(rating.Sum)/float64(rating.Count)*100) / 100
+	}
+	for i := 0; i <= maxReviewScore; i++ {
+		breakdown.Histogram = append(breakdown.Histogram, dto.RatingBucket{
+			Score: i,
+			Count: rating.Histogram[strconv.Itoa(i)],
+		})
+	}
+	return breakdown
+}
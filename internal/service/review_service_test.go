@@ -0,0 +1,134 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/config"
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/reviewstatus"
+	"github.com/Dongy-s-Advanture/back-end/internal/model"
+	repomock "github.com/Dongy-s-Advanture/back-end/pkg/mock/repository"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/converter"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/mock/gomock"
+)
+
+func TestVerifyReview(t *testing.T) {
+	order := &dto.Order{
+		OrderID:    primitive.NewObjectID(),
+		BuyerID:    primitive.NewObjectID(),
+		BuyerName:  "buyer",
+		SellerID:   primitive.NewObjectID(),
+		SellerName: "seller",
+		Products: []dto.OrderProduct{
+			{ProductID: primitive.NewObjectID(), ProductName: "Mug", Color: "red", Amount: 2},
+		},
+	}
+	// Whatever the request says about the buyer, the seller, the reply and
+	// the status is replaced
+	review := &model.Review{
+		OrderID:  order.OrderID,
+		BuyerID:  primitive.NewObjectID(),
+		SellerID: primitive.NewObjectID(),
+		Score:    8,
+		Reply:    &model.ReviewReply{Message: "thanks"},
+		Status:   reviewstatus.REMOVED,
+	}
+
+	assert.NoError(t, verifyReview(review, order))
+	assert.Equal(t, order.BuyerID, review.BuyerID)
+	assert.Equal(t, "buyer", review.BuyerName)
+	assert.Equal(t, order.SellerID, review.SellerID)
+	assert.Equal(t, "seller", review.SellerName)
+	assert.Nil(t, review.Reply)
+	assert.Equal(t, reviewstatus.VISIBLE, review.Status)
+	assert.Equal(t, []model.ReviewProduct{
+		{ProductID: order.Products[0].ProductID, ProductName: "Mug", Color: "red", Amount: 2},
+	}, review.Products)
+
+	reviewDTO, err := converter.ReviewModelToDTO(review)
+	assert.NoError(t, err)
+	assert.True(t, reviewDTO.Verified)
+	assert.Len(t, reviewDTO.Products, 1)
+
+	reviewDTO, err = converter.ReviewModelToDTO(&model.Review{Score: 5})
+	assert.NoError(t, err)
+	assert.False(t, reviewDTO.Verified)
+}
+
+func TestVerifyProductReviews(t *testing.T) {
+	mug, plate := primitive.NewObjectID(), primitive.NewObjectID()
+	order := &dto.Order{
+		Products: []dto.OrderProduct{
+			{ProductID: mug, Color: "red", Amount: 1},
+			{ProductID: mug, Color: "blue", Amount: 1},
+			{ProductID: plate, Amount: 4},
+		},
+	}
+	score := func(n int) *int { return &n }
+
+	review := &model.Review{Products: []model.ReviewProduct{
+		{ProductID: mug, Score: score(9), Comment: "sturdy"},
+	}}
+	assert.NoError(t, verifyReview(review, order))
+	assert.Len(t, review.Products, 3)
+	assert.Equal(t, score(9), review.Products[0].Score)
+	assert.Equal(t, "sturdy", review.Products[0].Comment)
+	// Counted once in the product's rating
+	assert.Nil(t, review.Products[1].Score)
+	assert.Nil(t, review.Products[2].Score)
+
+	review = &model.Review{Products: []model.ReviewProduct{{ProductID: primitive.NewObjectID(), Score: score(5)}}}
+	assert.ErrorIs(t, verifyReview(review, order), ErrInvalidProductReview)
+
+	review = &model.Review{Products: []model.ReviewProduct{{ProductID: plate, Score: score(11)}}}
+	assert.ErrorIs(t, verifyReview(review, order), ErrInvalidProductReview)
+
+	review = &model.Review{Products: []model.ReviewProduct{{ProductID: plate, Comment: "no score"}}}
+	assert.ErrorIs(t, verifyReview(review, order), ErrInvalidProductReview)
+}
+
+func TestSellerScore(t *testing.T) {
+	cfg := &config.RatingConfig{PriorMean: 7, PriorWeight: 5}
+
+	assert.Equal(t, 0.0, SellerScore(&dto.Rating{}, cfg))
+	// A single perfect review barely moves the score off the prior
+	assert.Equal(t, 7.5, SellerScore(&dto.Rating{Count: 1, Sum: 10}, cfg))
+	// Many reviews outweigh it
+	assert.Equal(t, 9.85, SellerScore(&dto.Rating{Count: 95, Sum: 950}, cfg))
+
+	assert.Equal(t, 4.0, SellerScore(&dto.Rating{Count: 2, Sum: 8}, &config.RatingConfig{}))
+}
+
+func TestRatingBreakdown(t *testing.T) {
+	sellerID := primitive.NewObjectID()
+	rating := &dto.Rating{Count: 3, Sum: 26, Histogram: map[string]int{"8": 1, "9": 2, "10": 0}}
+
+	breakdown := ratingBreakdown(sellerID, rating, 7.75)
+	assert.Equal(t, sellerID, breakdown.SellerID)
+	assert.Equal(t, 3, breakdown.Count)
+	assert.Equal(t, 8.67, breakdown.Average)
+	assert.Equal(t, 7.75, breakdown.Score)
+	assert.Len(t, breakdown.Histogram, 11)
+	assert.Equal(t, dto.RatingBucket{Score: 0, Count: 0}, breakdown.Histogram[0])
+	assert.Equal(t, dto.RatingBucket{Score: 8, Count: 1}, breakdown.Histogram[8])
+	assert.Equal(t, dto.RatingBucket{Score: 9, Count: 2}, breakdown.Histogram[9])
+
+	empty := ratingBreakdown(sellerID, &dto.Rating{}, 0)
+	assert.Equal(t, 0.0, empty.Average)
+	assert.Len(t, empty.Histogram, 11)
+}
+
+func TestChangeReviewOnlyByItsBuyer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	reviewRepo := repomock.NewMockIReviewRepository(ctrl)
+	s := NewReviewService(reviewRepo, nil, nil, nil, nil, nil, nil, noopBus{}, &config.RatingConfig{})
+
+	review := &dto.Review{ReviewID: primitive.NewObjectID(), BuyerID: primitive.NewObjectID()}
+	reviewRepo.EXPECT().GetReviewByID(review.ReviewID).Return(review, nil).Times(2)
+
+	_, err := s.UpdateReview(review.ReviewID, primitive.NewObjectID(), false, &model.Review{Score: 1})
+	assert.ErrorIs(t, err, ErrReviewNotOwned)
+	assert.ErrorIs(t, s.DeleteReview(review.ReviewID, primitive.NewObjectID(), false), ErrReviewNotOwned)
+}
@@ -3,17 +3,21 @@ package service
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"mime/multipart"
 	"net/http"
 	"path/filepath"
 	"strings"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
-
 	"github.com/Dongy-s-Advanture/back-end/internal/config"
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/storage"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/imageproc"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 var allowedExtensions = map[string]bool{
@@ -24,19 +28,46 @@ var allowedExtensions = map[string]bool{
 	".webp": true,
 }
 
+// Presigned uploads land under this prefix and are only published once
+// CompleteUpload has processed them like UploadImage does
+const incomingPrefix = "incoming/"
+
+// The largest presigned upload CompleteUpload will process
+const maxIncomingBytes = 20 << 20
+
+var allowedFolders = map[string]bool{
+	"products":       true,
+	"buyers":         true,
+	"sellers":        true,
+	"reviews":        true,
+	"advertisements": true,
+	"messages":       true,
+}
+
 type IS3Service interface {
 	UploadFile(file *multipart.FileHeader, folderName string) (string, error)
+	UploadImage(file *multipart.FileHeader, folderName string) (*dto.ImageVariants, error)
+	PresignUpload(req *dto.PresignUploadRequest) (*dto.PresignUploadResponse, error)
+	CompleteUpload(key string) (*dto.ImageVariants, error)
+	DeleteFile(fileURL string) error
 }
 
 type S3Service struct {
-	Client     *s3.Client
-	BucketName string
+	Storage       storage.Storage
+	UploadService IUploadService
+	ImageOptions  imageproc.Options
+	PresignExpiry time.Duration
 }
 
-func NewS3Service(s3Client *s3.Client, cfg *config.AWSConfig) IS3Service {
+func NewS3Service(store storage.Storage, uploadService IUploadService, storageCfg *config.StorageConfig, imageCfg *config.ImageConfig) IS3Service {
 	return &S3Service{
-		Client:     s3Client,
-		BucketName: cfg.BucketName,
+		Storage:       store,
+		UploadService: uploadService,
+		ImageOptions: imageproc.Options{
+			Format:      imageproc.Format(strings.ToLower(imageCfg.Format)),
+			JPEGQuality: imageCfg.JPEGQuality,
+		},
+		PresignExpiry: time.Duration(storageCfg.PresignExpiryMinutes) * time.Minute,
 	}
 }
 
@@ -63,18 +94,143 @@ func (s *S3Service) UploadFile(fileHeader *multipart.FileHeader, folderName stri
 		return "", fmt.Errorf("invalid file type: only image files are allowed")
 	}
 
-	uniqueFileName := fmt.Sprintf("%s/%d%s", folderName, time.Now().Unix(), ext)
+	return s.put(contentKey(folderName, buffer.Bytes(), ext), buffer.Bytes(), mimeType)
+}
+
+// UploadImage decodes the uploaded image, strips its metadata and stores a
+// thumbnail, medium and large rendition. The URLs of all renditions are returned.
+func (s *S3Service) UploadImage(fileHeader *multipart.FileHeader, folderName string) (*dto.ImageVariants, error) {
+	ext := strings.ToLower(filepath.Ext(fileHeader.Filename))
+	if !allowedExtensions[ext] {
+		return nil, fmt.Errorf("invalid file type: only images are allowed")
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %v", err)
+	}
+	defer file.Close()
+
+	buffer := bytes.NewBuffer(nil)
+	if _, err := buffer.ReadFrom(file); err != nil {
+		return nil, fmt.Errorf("failed to read file: %v", err)
+	}
+
+	return s.putImage(buffer.Bytes(), folderName)
+}
+
+// putImage strips and resizes the image and stores its renditions in folderName
+func (s *S3Service) putImage(data []byte, folderName string) (*dto.ImageVariants, error) {
+	variants, err := imageproc.Process(data, s.ImageOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	urls := make(map[string]string, len(variants))
+	for _, variant := range variants {
+		fileURL, err := s.put(contentKey(folderName, variant.Data, variant.Ext), variant.Data, variant.ContentType)
+		if err != nil {
+			return nil, err
+		}
+		urls[variant.Name] = fileURL
+	}
+
+	return &dto.ImageVariants{
+		Thumbnail: urls["thumbnail"],
+		Medium:    urls["medium"],
+		Large:     urls["large"],
+	}, nil
+}
 
-	_, err = s.Client.PutObject(context.TODO(), &s3.PutObjectInput{
-		Bucket: aws.String(s.BucketName),
-		Key:    aws.String(uniqueFileName),
-		Body:   bytes.NewReader(buffer.Bytes()),
-	})
+// PresignUpload lets clients upload a file straight to storage. The upload
+// isn't published as is, once the PUT has completed the returned Key is passed
+// to CompleteUpload to get the URLs of its renditions.
+func (s *S3Service) PresignUpload(req *dto.PresignUploadRequest) (*dto.PresignUploadResponse, error) {
+	if !allowedFolders[req.Folder] {
+		return nil, fmt.Errorf("invalid folder: %s", req.Folder)
+	}
+	ext := strings.ToLower(filepath.Ext(req.FileName))
+	if !allowedExtensions[ext] {
+		return nil, fmt.Errorf("invalid file type: only images are allowed")
+	}
+	if !strings.HasPrefix(req.ContentType, "image/") {
+		return nil, fmt.Errorf("invalid content type: only image files are allowed")
+	}
+
+	// The content isn't known yet, so presigned uploads get a random key
+	key := fmt.Sprintf("%s%s/%s%s", incomingPrefix, req.Folder, primitive.NewObjectID().Hex(), ext)
+	uploadURL, err := s.Storage.PresignPut(context.TODO(), key, req.ContentType, s.PresignExpiry)
 	if err != nil {
-		return "", fmt.Errorf("failed to upload file: %v", err)
+		return nil, err
+	}
+	// Recorded up front so the object is swept if the client never completes it
+	if err := s.UploadService.RecordUpload(s.Storage.URL(key)); err != nil {
+		return nil, err
 	}
 
-	fileURL := fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.BucketName, "ap-southeast-1", uniqueFileName)
+	return &dto.PresignUploadResponse{
+		UploadURL: uploadURL,
+		Method:    http.MethodPut,
+		Headers:   map[string]string{"Content-Type": req.ContentType},
+		Key:       key,
+		ExpiresAt: time.Now().Add(s.PresignExpiry),
+	}, nil
+}
+
+// CompleteUpload processes a presigned upload into stripped, resized
+// renditions stored in the folder it was presigned for, and deletes the
+// original so its metadata is never served. Its record is left for the
+// sweeper.
+func (s *S3Service) CompleteUpload(key string) (*dto.ImageVariants, error) {
+	folder, name, ok := strings.Cut(strings.TrimPrefix(key, incomingPrefix), "/")
+	if !strings.HasPrefix(key, incomingPrefix) || !ok || !allowedFolders[folder] || name == "" || strings.Contains(name, "/") {
+		return nil, fmt.Errorf("invalid upload key: %s", key)
+	}
+
+	body, err := s.Storage.Get(context.TODO(), key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(io.LimitReader(body, maxIncomingBytes+1))
+	body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %v", err)
+	}
+	if len(data) > maxIncomingBytes {
+		return nil, fmt.Errorf("file is larger than %d bytes", maxIncomingBytes)
+	}
+
+	variants, err := s.putImage(data, folder)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Storage.Delete(context.TODO(), key); err != nil {
+		return nil, err
+	}
+	return variants, nil
+}
+
+func (s *S3Service) DeleteFile(fileURL string) error {
+	key, ok := s.Storage.Key(fileURL)
+	if !ok {
+		return fmt.Errorf("file is not managed by this storage: %s", fileURL)
+	}
+	return s.Storage.Delete(context.TODO(), key)
+}
+
+// put records data as an unclaimed upload and stores it under key. Recording
+// first keeps the sweeper from deleting an object with the same content while
+// it is stored again.
+func (s *S3Service) put(key string, data []byte, contentType string) (string, error) {
+	if err := s.UploadService.RecordUpload(s.Storage.URL(key)); err != nil {
+		return "", err
+	}
+	return s.Storage.Put(context.TODO(), key, bytes.NewReader(data), contentType)
+}
 
-	return fileURL, nil
+// contentKey names objects after the SHA-256 of their content so concurrent
+// uploads never overwrite each other and identical files share one object.
+func contentKey(folderName string, data []byte, ext string) string {
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf("%s/%s%s", folderName, hex.EncodeToString(sum[:]), ext)
 }
@@ -1,9 +1,15 @@
 package service
 
 import (
+	"errors"
+	"log"
+
 	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/uploadowner"
 	"github.com/Dongy-s-Advanture/back-end/internal/model"
 	"github.com/Dongy-s-Advanture/back-end/internal/repository"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/money"
+	"github.com/Dongy-s-Advanture/back-end/pkg/utils/thaiaddress"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -13,21 +19,26 @@ type ISellerService interface {
 	GetSellerByID(sellerID primitive.ObjectID) (*dto.Seller, error)
 	GetSellers() ([]dto.Seller, error)
 	UpdateSeller(sellerID primitive.ObjectID, updatedSeller *model.Seller) (*dto.Seller, error)
-	GetSellerBalanceByID(sellerID primitive.ObjectID) (float64, error)
-	WithdrawSellerBalance(sellerID primitive.ObjectID, payment string, amount float64) error
+	GetSellerBalanceByID(sellerID primitive.ObjectID) (money.Money, error)
+	WithdrawSellerBalance(sellerID primitive.ObjectID, payment string, amount money.Money) error
 }
 
 type SellerService struct {
 	sellerRepository repository.ISellerRepository
+	uploadService    IUploadService
 }
 
-func NewSellerService(r repository.ISellerRepository) ISellerService {
+func NewSellerService(r repository.ISellerRepository, uploadService IUploadService) ISellerService {
 	return SellerService{
 		sellerRepository: r,
+		uploadService:    uploadService,
 	}
 }
 
 func (s SellerService) CreateSellerData(seller *model.Seller) (*dto.Seller, error) {
+	if err := normalizeAddress(&seller.Province, &seller.City, &seller.Zip, thaiaddress.Address{}); err != nil {
+		return nil, err
+	}
 
 	passwordBytes := []byte(seller.Password)
 	hashPasswordBytes, err := bcrypt.GenerateFromPassword(passwordBytes, bcrypt.DefaultCost)
@@ -46,6 +57,10 @@ func (s SellerService) CreateSellerData(seller *model.Seller) (*dto.Seller, erro
 		return nil, err
 	}
 
+	if err := s.uploadService.Claim(uploadowner.SELLER, newSeller.SellerID, imageURLs(newSeller.ProfilePic, newSeller.ProfilePics)...); err != nil {
+		log.Printf("failed to claim uploads for seller %s: %v", newSeller.SellerID.Hex(), err)
+	}
+
 	return newSeller, nil
 }
 
@@ -66,6 +81,19 @@ func (s SellerService) GetSellers() ([]dto.Seller, error) {
 }
 
 func (s SellerService) UpdateSeller(sellerID primitive.ObjectID, updatedSeller *model.Seller) (*dto.Seller, error) {
+	if err := validateLocation(updatedSeller.Location); err != nil {
+		return nil, err
+	}
+
+	oldSeller, err := s.sellerRepository.GetSellerByID(sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	current := thaiaddress.Address{Province: oldSeller.Province, District: oldSeller.City, Zip: oldSeller.Zip}
+	if err := normalizeAddress(&updatedSeller.Province, &updatedSeller.City, &updatedSeller.Zip, current); err != nil {
+		return nil, err
+	}
 
 	if updatedSeller.Password != "" {
 		passwordBytes := []byte(updatedSeller.Password)
@@ -83,18 +111,27 @@ func (s SellerService) UpdateSeller(sellerID primitive.ObjectID, updatedSeller *
 		return nil, err
 	}
 
+	oldURLs := imageURLs(oldSeller.ProfilePic, oldSeller.ProfilePics)
+	newURLs := imageURLs(updatedSellerDTO.ProfilePic, updatedSellerDTO.ProfilePics)
+	if err := s.uploadService.Replace(uploadowner.SELLER, sellerID, oldURLs, newURLs); err != nil {
+		log.Printf("failed to update uploads for seller %s: %v", sellerID.Hex(), err)
+	}
+
 	return updatedSellerDTO, nil
 }
 
-func (s SellerService) GetSellerBalanceByID(sellerID primitive.ObjectID) (float64, error) {
+func (s SellerService) GetSellerBalanceByID(sellerID primitive.ObjectID) (money.Money, error) {
 	totalBalance, err := s.sellerRepository.GetSellerBalanceByID(sellerID)
 	if err != nil {
-		return 0, err
+		return money.Money{}, err
 	}
 	return totalBalance, nil
 }
 
-func (s SellerService) WithdrawSellerBalance(sellerID primitive.ObjectID, payment string, amount float64) error {
+func (s SellerService) WithdrawSellerBalance(sellerID primitive.ObjectID, payment string, amount money.Money) error {
+	if !amount.IsPositive() {
+		return errors.New("amount must be positive")
+	}
 	err := s.sellerRepository.WithdrawSellerBalance(sellerID, payment, amount)
 	if err != nil {
 		return err
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/model"
+	"github.com/Dongy-s-Advanture/back-end/internal/repository"
+	"github.com/Dongy-s-Advanture/back-end/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const orphanSweepBatchSize = 100
+
+// IUploadService tracks which entities reference stored objects. Objects are
+// recorded when uploaded, claimed when an entity is saved with their URL and
+// released when it is replaced or deleted. Unreferenced objects are removed
+// from storage once the grace period has passed.
+type IUploadService interface {
+	RecordUpload(fileURL string) error
+	Claim(ownerType string, ownerID primitive.ObjectID, fileURLs ...string) error
+	Release(ownerType string, ownerID primitive.ObjectID, fileURLs ...string) error
+	Replace(ownerType string, ownerID primitive.ObjectID, oldURLs []string, newURLs []string) error
+	SweepOrphans(grace time.Duration) (int, error)
+}
+
+type UploadService struct {
+	uploadRepository repository.IUploadRepository
+	storage          storage.Storage
+}
+
+func NewUploadService(r repository.IUploadRepository, store storage.Storage) IUploadService {
+	return UploadService{
+		uploadRepository: r,
+		storage:          store,
+	}
+}
+
+func (s UploadService) RecordUpload(fileURL string) error {
+	key, ok := s.storage.Key(fileURL)
+	if !ok {
+		return nil
+	}
+	return s.uploadRepository.RecordUpload(key, fileURL)
+}
+
+// Claim marks the objects behind fileURLs as used by the owner. URLs that
+// aren't served by our storage (e.g. external images) are ignored, and so are
+// unprocessed presigned uploads, which are swept even when referenced.
+func (s UploadService) Claim(ownerType string, ownerID primitive.ObjectID, fileURLs ...string) error {
+	ref := model.UploadRef{OwnerType: ownerType, OwnerID: ownerID}
+	for _, fileURL := range fileURLs {
+		key, ok := s.storage.Key(fileURL)
+		if !ok || strings.HasPrefix(key, incomingPrefix) {
+			continue
+		}
+		if err := s.uploadRepository.AddReference(key, fileURL, ref); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s UploadService) Release(ownerType string, ownerID primitive.ObjectID, fileURLs ...string) error {
+	ref := model.UploadRef{OwnerType: ownerType, OwnerID: ownerID}
+	for _, fileURL := range fileURLs {
+		key, ok := s.storage.Key(fileURL)
+		if !ok {
+			continue
+		}
+		if err := s.uploadRepository.RemoveReference(key, fileURL, ref); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Replace claims newURLs and releases the oldURLs the owner no longer uses
+func (s UploadService) Replace(ownerType string, ownerID primitive.ObjectID, oldURLs []string, newURLs []string) error {
+	if err := s.Claim(ownerType, ownerID, newURLs...); err != nil {
+		return err
+	}
+
+	kept := make(map[string]bool, len(newURLs))
+	for _, fileURL := range newURLs {
+		kept[fileURL] = true
+	}
+	var released []string
+	for _, fileURL := range oldURLs {
+		if !kept[fileURL] {
+			released = append(released, fileURL)
+		}
+	}
+	return s.Release(ownerType, ownerID, released...)
+}
+
+// SweepOrphans deletes objects that have been unreferenced for longer than
+// grace and returns how many were removed.
+func (s UploadService) SweepOrphans(grace time.Duration) (int, error) {
+	cutoff := time.Now().Add(-grace)
+	deleted := 0
+
+	for {
+		orphans, err := s.uploadRepository.GetOrphans(cutoff, orphanSweepBatchSize)
+		if err != nil {
+			return deleted, err
+		}
+
+		for _, orphan := range orphans {
+			// Marked first so a concurrent upload or claim of the same key
+			// either wins or waits until both the object and its record are
+			// gone, never leaves a record pointing at a deleted object
+			sweepingAt := time.Now()
+			ok, err := s.uploadRepository.MarkSwept(orphan.UploadID, cutoff, sweepingAt)
+			if err != nil {
+				return deleted, err
+			}
+			if !ok {
+				continue
+			}
+			if err := s.storage.Delete(context.TODO(), orphan.Key); err != nil {
+				return deleted, err
+			}
+			if err := s.uploadRepository.DeleteSwept(orphan.UploadID, sweepingAt); err != nil {
+				return deleted, err
+			}
+			deleted++
+		}
+
+		if len(orphans) < orphanSweepBatchSize {
+			return deleted, nil
+		}
+	}
+}
+
+func imageURLs(image string, variants *dto.ImageVariants) []string {
+	var urls []string
+	if image != "" {
+		urls = append(urls, image)
+	}
+	if variants != nil {
+		for _, url := range []string{variants.Thumbnail, variants.Medium, variants.Large} {
+			if url != "" {
+				urls = append(urls, url)
+			}
+		}
+	}
+	return urls
+}
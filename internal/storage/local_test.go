@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStorage_PutAndDelete(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLocalStorage(dir, "http://localhost:3001/uploads/", "secret")
+	require.NoError(t, err)
+
+	fileURL, err := store.Put(context.Background(), "products/a.jpg", strings.NewReader("data"), "image/jpeg")
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:3001/uploads/products/a.jpg", fileURL)
+
+	content, err := os.ReadFile(filepath.Join(dir, "products", "a.jpg"))
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(content))
+
+	body, err := store.Get(context.Background(), "products/a.jpg")
+	require.NoError(t, err)
+	content, err = io.ReadAll(body)
+	body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(content))
+
+	key, ok := store.Key(fileURL)
+	require.True(t, ok)
+	assert.Equal(t, "products/a.jpg", key)
+
+	_, ok = store.Key("https://example.com/products/a.jpg")
+	assert.False(t, ok)
+
+	require.NoError(t, store.Delete(context.Background(), key))
+	_, err = os.Stat(filepath.Join(dir, "products", "a.jpg"))
+	assert.True(t, os.IsNotExist(err))
+	_, err = store.Get(context.Background(), key)
+	assert.Error(t, err)
+
+	// Deleting twice is not an error
+	assert.NoError(t, store.Delete(context.Background(), key))
+}
+
+func TestLocalStorage_RejectsKeysOutsideDir(t *testing.T) {
+	store, err := NewLocalStorage(t.TempDir(), "", "secret")
+	require.NoError(t, err)
+
+	for _, key := range []string{"", "../escape.jpg", "products/../../escape.jpg", "/abs.jpg"} {
+		_, err := store.Put(context.Background(), key, strings.NewReader("data"), "image/jpeg")
+		assert.Error(t, err, key)
+	}
+}
+
+func TestLocalStorage_PresignPut(t *testing.T) {
+	store, err := NewLocalStorage(t.TempDir(), "", "secret")
+	require.NoError(t, err)
+
+	uploadURL, err := store.PresignPut(context.Background(), "reviews/b.png", "image/png", time.Minute)
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(uploadURL)
+	require.NoError(t, err)
+	assert.Equal(t, "/uploads/reviews/b.png", parsed.Path)
+
+	query := parsed.Query()
+	assert.NoError(t, store.VerifyPresigned("reviews/b.png", "image/png", query.Get("expires"), query.Get("signature")))
+	assert.Error(t, store.VerifyPresigned("reviews/other.png", "image/png", query.Get("expires"), query.Get("signature")))
+	assert.Error(t, store.VerifyPresigned("reviews/b.png", "image/gif", query.Get("expires"), query.Get("signature")))
+
+	expired, err := store.PresignPut(context.Background(), "reviews/b.png", "image/png", -time.Minute)
+	require.NoError(t, err)
+	parsed, _ = url.Parse(expired)
+	query = parsed.Query()
+	assert.Error(t, store.VerifyPresigned("reviews/b.png", "image/png", query.Get("expires"), query.Get("signature")))
+}
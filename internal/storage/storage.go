@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/config"
+	"github.com/Dongy-s-Advanture/back-end/internal/database"
+)
+
+const (
+	DriverS3           = "s3"
+	DriverS3Compatible = "s3-compatible"
+	DriverLocal        = "local"
+)
+
+// Storage is an object store addressed by slash separated keys such as
+// "products/9f86d081884c7d65….jpg".
+type Storage interface {
+	Put(ctx context.Context, key string, body io.Reader, contentType string) (string, error)
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	// PresignPut returns a URL the client can PUT the file body to directly
+	PresignPut(ctx context.Context, key string, contentType string, expires time.Duration) (string, error)
+	URL(key string) string
+	// Key is the inverse of URL, it reports false for URLs this storage doesn't serve
+	Key(fileURL string) (string, bool)
+}
+
+func New(cfg *config.StorageConfig, awsCfg *config.AWSConfig) (Storage, error) {
+	switch cfg.Driver {
+	case DriverS3, DriverS3Compatible:
+		if cfg.Driver == DriverS3Compatible && cfg.Endpoint == "" {
+			return nil, fmt.Errorf("STORAGE_ENDPOINT is required for the %s driver", cfg.Driver)
+		}
+		client, err := database.InitS3Client(awsCfg, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return NewS3Storage(client, awsCfg.BucketName, publicURL(cfg, awsCfg)), nil
+	case DriverLocal:
+		local, err := NewLocalStorage(cfg.LocalDir, cfg.PublicURL, cfg.SigningSecret)
+		if err != nil {
+			return nil, err
+		}
+		return local, nil
+	default:
+		return nil, fmt.Errorf("unknown storage driver: %s", cfg.Driver)
+	}
+}
+
+func publicURL(cfg *config.StorageConfig, awsCfg *config.AWSConfig) string {
+	if cfg.PublicURL != "" {
+		return strings.TrimSuffix(cfg.PublicURL, "/")
+	}
+	if cfg.Endpoint != "" {
+		return fmt.Sprintf("%s/%s", strings.TrimSuffix(cfg.Endpoint, "/"), awsCfg.BucketName)
+	}
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com", awsCfg.BucketName, awsCfg.Region)
+}
+
+func keyFromURL(baseURL, fileURL string) (string, bool) {
+	prefix := baseURL + "/"
+	if !strings.HasPrefix(fileURL, prefix) {
+		return "", false
+	}
+	key := strings.TrimPrefix(fileURL, prefix)
+	return key, key != ""
+}
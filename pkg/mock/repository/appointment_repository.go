@@ -0,0 +1,295 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/appointment_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/appointment_repository.go -destination=pkg/mock/repository/appointment_repository.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+	time "time"
+
+	dto "github.com/Dongy-s-Advanture/back-end/internal/dto"
+	model "github.com/Dongy-s-Advanture/back-end/internal/model"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIAppointmentRepository is a mock of IAppointmentRepository interface.
+type MockIAppointmentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAppointmentRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockIAppointmentRepositoryMockRecorder is the mock recorder for MockIAppointmentRepository.
+type MockIAppointmentRepositoryMockRecorder struct {
+	mock *MockIAppointmentRepository
+}
+
+// NewMockIAppointmentRepository creates a new mock instance.
+func NewMockIAppointmentRepository(ctrl *gomock.Controller) *MockIAppointmentRepository {
+	mock := &MockIAppointmentRepository{ctrl: ctrl}
+	mock.recorder = &MockIAppointmentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAppointmentRepository) EXPECT() *MockIAppointmentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddProposal mocks base method.
+func (m *MockIAppointmentRepository) AddProposal(appointmentID primitive.ObjectID, proposal *model.AppointmentProposal) (*dto.Appointment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddProposal", appointmentID, proposal)
+	ret0, _ := ret[0].(*dto.Appointment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddProposal indicates an expected call of AddProposal.
+func (mr *MockIAppointmentRepositoryMockRecorder) AddProposal(appointmentID, proposal any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddProposal", reflect.TypeOf((*MockIAppointmentRepository)(nil).AddProposal), appointmentID, proposal)
+}
+
+// CancelAppointment mocks base method.
+func (m *MockIAppointmentRepository) CancelAppointment(orderID primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelAppointment", orderID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelAppointment indicates an expected call of CancelAppointment.
+func (mr *MockIAppointmentRepositoryMockRecorder) CancelAppointment(orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelAppointment", reflect.TypeOf((*MockIAppointmentRepository)(nil).CancelAppointment), orderID)
+}
+
+// ClearNoShow mocks base method.
+func (m *MockIAppointmentRepository) ClearNoShow(appointmentID primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearNoShow", appointmentID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearNoShow indicates an expected call of ClearNoShow.
+func (mr *MockIAppointmentRepositoryMockRecorder) ClearNoShow(appointmentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearNoShow", reflect.TypeOf((*MockIAppointmentRepository)(nil).ClearNoShow), appointmentID)
+}
+
+// CloseProposals mocks base method.
+func (m *MockIAppointmentRepository) CloseProposals(appointmentID primitive.ObjectID, kind int, proposedBy primitive.ObjectID, status int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseProposals", appointmentID, kind, proposedBy, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseProposals indicates an expected call of CloseProposals.
+func (mr *MockIAppointmentRepositoryMockRecorder) CloseProposals(appointmentID, kind, proposedBy, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseProposals", reflect.TypeOf((*MockIAppointmentRepository)(nil).CloseProposals), appointmentID, kind, proposedBy, status)
+}
+
+// CreateAppointment mocks base method.
+func (m *MockIAppointmentRepository) CreateAppointment(appointment *model.Appointment) (*dto.Appointment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAppointment", appointment)
+	ret0, _ := ret[0].(*dto.Appointment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAppointment indicates an expected call of CreateAppointment.
+func (mr *MockIAppointmentRepositoryMockRecorder) CreateAppointment(appointment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAppointment", reflect.TypeOf((*MockIAppointmentRepository)(nil).CreateAppointment), appointment)
+}
+
+// GetAppointmentByID mocks base method.
+func (m *MockIAppointmentRepository) GetAppointmentByID(appointmentID primitive.ObjectID) (*dto.Appointment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppointmentByID", appointmentID)
+	ret0, _ := ret[0].(*dto.Appointment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppointmentByID indicates an expected call of GetAppointmentByID.
+func (mr *MockIAppointmentRepositoryMockRecorder) GetAppointmentByID(appointmentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppointmentByID", reflect.TypeOf((*MockIAppointmentRepository)(nil).GetAppointmentByID), appointmentID)
+}
+
+// GetAppointmentByOrderID mocks base method.
+func (m *MockIAppointmentRepository) GetAppointmentByOrderID(orderID primitive.ObjectID) (*dto.Appointment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppointmentByOrderID", orderID)
+	ret0, _ := ret[0].(*dto.Appointment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppointmentByOrderID indicates an expected call of GetAppointmentByOrderID.
+func (mr *MockIAppointmentRepositoryMockRecorder) GetAppointmentByOrderID(orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppointmentByOrderID", reflect.TypeOf((*MockIAppointmentRepository)(nil).GetAppointmentByOrderID), orderID)
+}
+
+// GetAppointments mocks base method.
+func (m *MockIAppointmentRepository) GetAppointments() ([]dto.Appointment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppointments")
+	ret0, _ := ret[0].([]dto.Appointment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppointments indicates an expected call of GetAppointments.
+func (mr *MockIAppointmentRepositoryMockRecorder) GetAppointments() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppointments", reflect.TypeOf((*MockIAppointmentRepository)(nil).GetAppointments))
+}
+
+// GetAppointmentsByUserID mocks base method.
+func (m *MockIAppointmentRepository) GetAppointmentsByUserID(userID primitive.ObjectID) ([]dto.Appointment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppointmentsByUserID", userID)
+	ret0, _ := ret[0].([]dto.Appointment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppointmentsByUserID indicates an expected call of GetAppointmentsByUserID.
+func (mr *MockIAppointmentRepositoryMockRecorder) GetAppointmentsByUserID(userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppointmentsByUserID", reflect.TypeOf((*MockIAppointmentRepository)(nil).GetAppointmentsByUserID), userID)
+}
+
+// GetBookedSlots mocks base method.
+func (m *MockIAppointmentRepository) GetBookedSlots(sellerID primitive.ObjectID, from, to time.Time) ([]dto.Appointment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookedSlots", sellerID, from, to)
+	ret0, _ := ret[0].([]dto.Appointment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookedSlots indicates an expected call of GetBookedSlots.
+func (mr *MockIAppointmentRepositoryMockRecorder) GetBookedSlots(sellerID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookedSlots", reflect.TypeOf((*MockIAppointmentRepository)(nil).GetBookedSlots), sellerID, from, to)
+}
+
+// GetDueReminders mocks base method.
+func (m *MockIAppointmentRepository) GetDueReminders(lead time.Duration, now time.Time) ([]dto.Appointment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueReminders", lead, now)
+	ret0, _ := ret[0].([]dto.Appointment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueReminders indicates an expected call of GetDueReminders.
+func (mr *MockIAppointmentRepositoryMockRecorder) GetDueReminders(lead, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueReminders", reflect.TypeOf((*MockIAppointmentRepository)(nil).GetDueReminders), lead, now)
+}
+
+// MarkReminderSent mocks base method.
+func (m *MockIAppointmentRepository) MarkReminderSent(appointmentID primitive.ObjectID, minutes int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkReminderSent", appointmentID, minutes)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkReminderSent indicates an expected call of MarkReminderSent.
+func (mr *MockIAppointmentRepositoryMockRecorder) MarkReminderSent(appointmentID, minutes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkReminderSent", reflect.TypeOf((*MockIAppointmentRepository)(nil).MarkReminderSent), appointmentID, minutes)
+}
+
+// ReportNoShow mocks base method.
+func (m *MockIAppointmentRepository) ReportNoShow(appointmentID primitive.ObjectID, report *model.NoShowReport) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportNoShow", appointmentID, report)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReportNoShow indicates an expected call of ReportNoShow.
+func (mr *MockIAppointmentRepositoryMockRecorder) ReportNoShow(appointmentID, report any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportNoShow", reflect.TypeOf((*MockIAppointmentRepository)(nil).ReportNoShow), appointmentID, report)
+}
+
+// SetProposalStatus mocks base method.
+func (m *MockIAppointmentRepository) SetProposalStatus(appointmentID, proposalID primitive.ObjectID, from, to int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetProposalStatus", appointmentID, proposalID, from, to)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetProposalStatus indicates an expected call of SetProposalStatus.
+func (mr *MockIAppointmentRepositoryMockRecorder) SetProposalStatus(appointmentID, proposalID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetProposalStatus", reflect.TypeOf((*MockIAppointmentRepository)(nil).SetProposalStatus), appointmentID, proposalID, from, to)
+}
+
+// UnmarkReminderSent mocks base method.
+func (m *MockIAppointmentRepository) UnmarkReminderSent(appointmentID primitive.ObjectID, minutes int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnmarkReminderSent", appointmentID, minutes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnmarkReminderSent indicates an expected call of UnmarkReminderSent.
+func (mr *MockIAppointmentRepositoryMockRecorder) UnmarkReminderSent(appointmentID, minutes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnmarkReminderSent", reflect.TypeOf((*MockIAppointmentRepository)(nil).UnmarkReminderSent), appointmentID, minutes)
+}
+
+// UpdateAppointmentDate mocks base method.
+func (m *MockIAppointmentRepository) UpdateAppointmentDate(appointmentID primitive.ObjectID, updatedAppointment *model.Appointment) (*dto.Appointment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAppointmentDate", appointmentID, updatedAppointment)
+	ret0, _ := ret[0].(*dto.Appointment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAppointmentDate indicates an expected call of UpdateAppointmentDate.
+func (mr *MockIAppointmentRepositoryMockRecorder) UpdateAppointmentDate(appointmentID, updatedAppointment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAppointmentDate", reflect.TypeOf((*MockIAppointmentRepository)(nil).UpdateAppointmentDate), appointmentID, updatedAppointment)
+}
+
+// UpdateAppointmentPlace mocks base method.
+func (m *MockIAppointmentRepository) UpdateAppointmentPlace(appointmentID primitive.ObjectID, updatedAppointment *model.Appointment) (*dto.Appointment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAppointmentPlace", appointmentID, updatedAppointment)
+	ret0, _ := ret[0].(*dto.Appointment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAppointmentPlace indicates an expected call of UpdateAppointmentPlace.
+func (mr *MockIAppointmentRepositoryMockRecorder) UpdateAppointmentPlace(appointmentID, updatedAppointment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAppointmentPlace", reflect.TypeOf((*MockIAppointmentRepository)(nil).UpdateAppointmentPlace), appointmentID, updatedAppointment)
+}
@@ -0,0 +1,73 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/availability_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/availability_repository.go -destination=pkg/mock/repository/availability_repository.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+
+	dto "github.com/Dongy-s-Advanture/back-end/internal/dto"
+	model "github.com/Dongy-s-Advanture/back-end/internal/model"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIAvailabilityRepository is a mock of IAvailabilityRepository interface.
+type MockIAvailabilityRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAvailabilityRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockIAvailabilityRepositoryMockRecorder is the mock recorder for MockIAvailabilityRepository.
+type MockIAvailabilityRepositoryMockRecorder struct {
+	mock *MockIAvailabilityRepository
+}
+
+// NewMockIAvailabilityRepository creates a new mock instance.
+func NewMockIAvailabilityRepository(ctrl *gomock.Controller) *MockIAvailabilityRepository {
+	mock := &MockIAvailabilityRepository{ctrl: ctrl}
+	mock.recorder = &MockIAvailabilityRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAvailabilityRepository) EXPECT() *MockIAvailabilityRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetAvailability mocks base method.
+func (m *MockIAvailabilityRepository) GetAvailability(sellerID primitive.ObjectID) (*dto.SellerAvailability, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAvailability", sellerID)
+	ret0, _ := ret[0].(*dto.SellerAvailability)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAvailability indicates an expected call of GetAvailability.
+func (mr *MockIAvailabilityRepositoryMockRecorder) GetAvailability(sellerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAvailability", reflect.TypeOf((*MockIAvailabilityRepository)(nil).GetAvailability), sellerID)
+}
+
+// SetAvailability mocks base method.
+func (m *MockIAvailabilityRepository) SetAvailability(availability *model.SellerAvailability) (*dto.SellerAvailability, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAvailability", availability)
+	ret0, _ := ret[0].(*dto.SellerAvailability)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetAvailability indicates an expected call of SetAvailability.
+func (mr *MockIAvailabilityRepositoryMockRecorder) SetAvailability(availability any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAvailability", reflect.TypeOf((*MockIAvailabilityRepository)(nil).SetAvailability), availability)
+}
@@ -42,6 +42,35 @@ func (m *MockIBuyerRepository) EXPECT() *MockIBuyerRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AddNoShowStrike mocks base method.
+func (m *MockIBuyerRepository) AddNoShowStrike(buyerID primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddNoShowStrike", buyerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddNoShowStrike indicates an expected call of AddNoShowStrike.
+func (mr *MockIBuyerRepositoryMockRecorder) AddNoShowStrike(buyerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddNoShowStrike", reflect.TypeOf((*MockIBuyerRepository)(nil).AddNoShowStrike), buyerID)
+}
+
+// AddToWishlist mocks base method.
+func (m *MockIBuyerRepository) AddToWishlist(buyerID primitive.ObjectID, item *model.WishlistItem) ([]dto.WishlistItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddToWishlist", buyerID, item)
+	ret0, _ := ret[0].([]dto.WishlistItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddToWishlist indicates an expected call of AddToWishlist.
+func (mr *MockIBuyerRepositoryMockRecorder) AddToWishlist(buyerID, item any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToWishlist", reflect.TypeOf((*MockIBuyerRepository)(nil).AddToWishlist), buyerID, item)
+}
+
 // CreateBuyerData mocks base method.
 func (m *MockIBuyerRepository) CreateBuyerData(buyer *model.Buyer) (*dto.Buyer, error) {
 	m.ctrl.T.Helper()
@@ -116,6 +145,35 @@ func (mr *MockIBuyerRepositoryMockRecorder) GetBuyerByUsername(req any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBuyerByUsername", reflect.TypeOf((*MockIBuyerRepository)(nil).GetBuyerByUsername), req)
 }
 
+// GetBuyerIDsByWishlistProduct mocks base method.
+func (m *MockIBuyerRepository) GetBuyerIDsByWishlistProduct(productID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBuyerIDsByWishlistProduct", productID)
+	ret0, _ := ret[0].([]primitive.ObjectID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBuyerIDsByWishlistProduct indicates an expected call of GetBuyerIDsByWishlistProduct.
+func (mr *MockIBuyerRepositoryMockRecorder) GetBuyerIDsByWishlistProduct(productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBuyerIDsByWishlistProduct", reflect.TypeOf((*MockIBuyerRepository)(nil).GetBuyerIDsByWishlistProduct), productID)
+}
+
+// RemoveFromWishlist mocks base method.
+func (m *MockIBuyerRepository) RemoveFromWishlist(buyerID, productID primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveFromWishlist", buyerID, productID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveFromWishlist indicates an expected call of RemoveFromWishlist.
+func (mr *MockIBuyerRepositoryMockRecorder) RemoveFromWishlist(buyerID, productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFromWishlist", reflect.TypeOf((*MockIBuyerRepository)(nil).RemoveFromWishlist), buyerID, productID)
+}
+
 // UpdateBuyerData mocks base method.
 func (m *MockIBuyerRepository) UpdateBuyerData(buyerID primitive.ObjectID, updatedBuyer *model.Buyer) (*dto.Buyer, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,70 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/calendar_token_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/calendar_token_repository.go -destination=pkg/mock/repository/calendar_token_repository.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockICalendarTokenRepository is a mock of ICalendarTokenRepository interface.
+type MockICalendarTokenRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockICalendarTokenRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockICalendarTokenRepositoryMockRecorder is the mock recorder for MockICalendarTokenRepository.
+type MockICalendarTokenRepositoryMockRecorder struct {
+	mock *MockICalendarTokenRepository
+}
+
+// NewMockICalendarTokenRepository creates a new mock instance.
+func NewMockICalendarTokenRepository(ctrl *gomock.Controller) *MockICalendarTokenRepository {
+	mock := &MockICalendarTokenRepository{ctrl: ctrl}
+	mock.recorder = &MockICalendarTokenRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockICalendarTokenRepository) EXPECT() *MockICalendarTokenRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetNonce mocks base method.
+func (m *MockICalendarTokenRepository) GetNonce(userID primitive.ObjectID) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNonce", userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNonce indicates an expected call of GetNonce.
+func (mr *MockICalendarTokenRepositoryMockRecorder) GetNonce(userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNonce", reflect.TypeOf((*MockICalendarTokenRepository)(nil).GetNonce), userID)
+}
+
+// SetNonce mocks base method.
+func (m *MockICalendarTokenRepository) SetNonce(userID primitive.ObjectID, nonce string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNonce", userID, nonce)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetNonce indicates an expected call of SetNonce.
+func (mr *MockICalendarTokenRepositoryMockRecorder) SetNonce(userID, nonce any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNonce", reflect.TypeOf((*MockICalendarTokenRepository)(nil).SetNonce), userID, nonce)
+}
@@ -0,0 +1,163 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/order_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/order_repository.go -destination=pkg/mock/repository/order_repository.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+
+	dto "github.com/Dongy-s-Advanture/back-end/internal/dto"
+	userrole "github.com/Dongy-s-Advanture/back-end/internal/enum/userrole"
+	model "github.com/Dongy-s-Advanture/back-end/internal/model"
+	money "github.com/Dongy-s-Advanture/back-end/pkg/utils/money"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIOrderRepository is a mock of IOrderRepository interface.
+type MockIOrderRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIOrderRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockIOrderRepositoryMockRecorder is the mock recorder for MockIOrderRepository.
+type MockIOrderRepositoryMockRecorder struct {
+	mock *MockIOrderRepository
+}
+
+// NewMockIOrderRepository creates a new mock instance.
+func NewMockIOrderRepository(ctrl *gomock.Controller) *MockIOrderRepository {
+	mock := &MockIOrderRepository{ctrl: ctrl}
+	mock.recorder = &MockIOrderRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIOrderRepository) EXPECT() *MockIOrderRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AdvanceOrderStatus mocks base method.
+func (m *MockIOrderRepository) AdvanceOrderStatus(orderID primitive.ObjectID, from, to int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdvanceOrderStatus", orderID, from, to)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdvanceOrderStatus indicates an expected call of AdvanceOrderStatus.
+func (mr *MockIOrderRepositoryMockRecorder) AdvanceOrderStatus(orderID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdvanceOrderStatus", reflect.TypeOf((*MockIOrderRepository)(nil).AdvanceOrderStatus), orderID, from, to)
+}
+
+// CreateOrder mocks base method.
+func (m *MockIOrderRepository) CreateOrder(order *model.Order) (*dto.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrder", order)
+	ret0, _ := ret[0].(*dto.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrder indicates an expected call of CreateOrder.
+func (mr *MockIOrderRepositoryMockRecorder) CreateOrder(order any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrder", reflect.TypeOf((*MockIOrderRepository)(nil).CreateOrder), order)
+}
+
+// DeleteOrderByOrderID mocks base method.
+func (m *MockIOrderRepository) DeleteOrderByOrderID(orderID primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOrderByOrderID", orderID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOrderByOrderID indicates an expected call of DeleteOrderByOrderID.
+func (mr *MockIOrderRepositoryMockRecorder) DeleteOrderByOrderID(orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOrderByOrderID", reflect.TypeOf((*MockIOrderRepository)(nil).DeleteOrderByOrderID), orderID)
+}
+
+// GetOrderByID mocks base method.
+func (m *MockIOrderRepository) GetOrderByID(orderID primitive.ObjectID) (*dto.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderByID", orderID)
+	ret0, _ := ret[0].(*dto.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderByID indicates an expected call of GetOrderByID.
+func (mr *MockIOrderRepositoryMockRecorder) GetOrderByID(orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderByID", reflect.TypeOf((*MockIOrderRepository)(nil).GetOrderByID), orderID)
+}
+
+// GetOrdersByUserID mocks base method.
+func (m *MockIOrderRepository) GetOrdersByUserID(userID primitive.ObjectID, userType userrole.UserType) ([]dto.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrdersByUserID", userID, userType)
+	ret0, _ := ret[0].([]dto.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrdersByUserID indicates an expected call of GetOrdersByUserID.
+func (mr *MockIOrderRepositoryMockRecorder) GetOrdersByUserID(userID, userType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrdersByUserID", reflect.TypeOf((*MockIOrderRepository)(nil).GetOrdersByUserID), userID, userType)
+}
+
+// RecordOrderRefund mocks base method.
+func (m *MockIOrderRepository) RecordOrderRefund(orderID primitive.ObjectID, refund money.Money) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordOrderRefund", orderID, refund)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordOrderRefund indicates an expected call of RecordOrderRefund.
+func (mr *MockIOrderRepositoryMockRecorder) RecordOrderRefund(orderID, refund any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordOrderRefund", reflect.TypeOf((*MockIOrderRepository)(nil).RecordOrderRefund), orderID, refund)
+}
+
+// UpdateOrder mocks base method.
+func (m *MockIOrderRepository) UpdateOrder(orderID primitive.ObjectID, updatedOrder *model.Order) (*dto.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOrder", orderID, updatedOrder)
+	ret0, _ := ret[0].(*dto.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOrder indicates an expected call of UpdateOrder.
+func (mr *MockIOrderRepositoryMockRecorder) UpdateOrder(orderID, updatedOrder any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrder", reflect.TypeOf((*MockIOrderRepository)(nil).UpdateOrder), orderID, updatedOrder)
+}
+
+// UpdateOrderStatus mocks base method.
+func (m *MockIOrderRepository) UpdateOrderStatus(orderID primitive.ObjectID, orderStatus int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOrderStatus", orderID, orderStatus)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOrderStatus indicates an expected call of UpdateOrderStatus.
+func (mr *MockIOrderRepositoryMockRecorder) UpdateOrderStatus(orderID, orderStatus any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrderStatus", reflect.TypeOf((*MockIOrderRepository)(nil).UpdateOrderStatus), orderID, orderStatus)
+}
@@ -0,0 +1,249 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/product_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/product_repository.go -destination=pkg/mock/repository/product_repository.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+
+	dto "github.com/Dongy-s-Advanture/back-end/internal/dto"
+	model "github.com/Dongy-s-Advanture/back-end/internal/model"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIProductRepository is a mock of IProductRepository interface.
+type MockIProductRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIProductRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockIProductRepositoryMockRecorder is the mock recorder for MockIProductRepository.
+type MockIProductRepositoryMockRecorder struct {
+	mock *MockIProductRepository
+}
+
+// NewMockIProductRepository creates a new mock instance.
+func NewMockIProductRepository(ctrl *gomock.Controller) *MockIProductRepository {
+	mock := &MockIProductRepository{ctrl: ctrl}
+	mock.recorder = &MockIProductRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIProductRepository) EXPECT() *MockIProductRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ArchiveProduct mocks base method.
+func (m *MockIProductRepository) ArchiveProduct(productID primitive.ObjectID) (*dto.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchiveProduct", productID)
+	ret0, _ := ret[0].(*dto.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ArchiveProduct indicates an expected call of ArchiveProduct.
+func (mr *MockIProductRepositoryMockRecorder) ArchiveProduct(productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveProduct", reflect.TypeOf((*MockIProductRepository)(nil).ArchiveProduct), productID)
+}
+
+// CreateProduct mocks base method.
+func (m *MockIProductRepository) CreateProduct(product *model.Product) (*dto.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateProduct", product)
+	ret0, _ := ret[0].(*dto.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateProduct indicates an expected call of CreateProduct.
+func (mr *MockIProductRepositoryMockRecorder) CreateProduct(product any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProduct", reflect.TypeOf((*MockIProductRepository)(nil).CreateProduct), product)
+}
+
+// DeleteProduct mocks base method.
+func (m *MockIProductRepository) DeleteProduct(productID primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProduct", productID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteProduct indicates an expected call of DeleteProduct.
+func (mr *MockIProductRepositoryMockRecorder) DeleteProduct(productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProduct", reflect.TypeOf((*MockIProductRepository)(nil).DeleteProduct), productID)
+}
+
+// GetProductByID mocks base method.
+func (m *MockIProductRepository) GetProductByID(productID primitive.ObjectID) (*dto.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProductByID", productID)
+	ret0, _ := ret[0].(*dto.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProductByID indicates an expected call of GetProductByID.
+func (mr *MockIProductRepositoryMockRecorder) GetProductByID(productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductByID", reflect.TypeOf((*MockIProductRepository)(nil).GetProductByID), productID)
+}
+
+// GetProducts mocks base method.
+func (m *MockIProductRepository) GetProducts() ([]dto.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProducts")
+	ret0, _ := ret[0].([]dto.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProducts indicates an expected call of GetProducts.
+func (mr *MockIProductRepositoryMockRecorder) GetProducts() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProducts", reflect.TypeOf((*MockIProductRepository)(nil).GetProducts))
+}
+
+// GetProductsBySellerID mocks base method.
+func (m *MockIProductRepository) GetProductsBySellerID(sellerID primitive.ObjectID) ([]dto.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProductsBySellerID", sellerID)
+	ret0, _ := ret[0].([]dto.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProductsBySellerID indicates an expected call of GetProductsBySellerID.
+func (mr *MockIProductRepositoryMockRecorder) GetProductsBySellerID(sellerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductsBySellerID", reflect.TypeOf((*MockIProductRepository)(nil).GetProductsBySellerID), sellerID)
+}
+
+// GetProductsBySellerIDs mocks base method.
+func (m *MockIProductRepository) GetProductsBySellerIDs(sellerIDs []primitive.ObjectID) ([]dto.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProductsBySellerIDs", sellerIDs)
+	ret0, _ := ret[0].([]dto.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProductsBySellerIDs indicates an expected call of GetProductsBySellerIDs.
+func (mr *MockIProductRepositoryMockRecorder) GetProductsBySellerIDs(sellerIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductsBySellerIDs", reflect.TypeOf((*MockIProductRepository)(nil).GetProductsBySellerIDs), sellerIDs)
+}
+
+// GetSellerListings mocks base method.
+func (m *MockIProductRepository) GetSellerListings(sellerID primitive.ObjectID, status *int) ([]dto.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSellerListings", sellerID, status)
+	ret0, _ := ret[0].([]dto.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSellerListings indicates an expected call of GetSellerListings.
+func (mr *MockIProductRepositoryMockRecorder) GetSellerListings(sellerID, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSellerListings", reflect.TypeOf((*MockIProductRepository)(nil).GetSellerListings), sellerID, status)
+}
+
+// RestockProduct mocks base method.
+func (m *MockIProductRepository) RestockProduct(productID primitive.ObjectID, amount int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestockProduct", productID, amount)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestockProduct indicates an expected call of RestockProduct.
+func (mr *MockIProductRepositoryMockRecorder) RestockProduct(productID, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestockProduct", reflect.TypeOf((*MockIProductRepository)(nil).RestockProduct), productID, amount)
+}
+
+// RestoreProduct mocks base method.
+func (m *MockIProductRepository) RestoreProduct(productID primitive.ObjectID, status int) (*dto.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreProduct", productID, status)
+	ret0, _ := ret[0].(*dto.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreProduct indicates an expected call of RestoreProduct.
+func (mr *MockIProductRepositoryMockRecorder) RestoreProduct(productID, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreProduct", reflect.TypeOf((*MockIProductRepository)(nil).RestoreProduct), productID, status)
+}
+
+// UpdateProduct mocks base method.
+func (m *MockIProductRepository) UpdateProduct(productID primitive.ObjectID, updatedProduct *model.Product) (*dto.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProduct", productID, updatedProduct)
+	ret0, _ := ret[0].(*dto.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProduct indicates an expected call of UpdateProduct.
+func (mr *MockIProductRepositoryMockRecorder) UpdateProduct(productID, updatedProduct any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProduct", reflect.TypeOf((*MockIProductRepository)(nil).UpdateProduct), productID, updatedProduct)
+}
+
+// UpdateProductAmount mocks base method.
+func (m *MockIProductRepository) UpdateProductAmount(productID primitive.ObjectID, amount int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProductAmount", productID, amount)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProductAmount indicates an expected call of UpdateProductAmount.
+func (mr *MockIProductRepositoryMockRecorder) UpdateProductAmount(productID, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProductAmount", reflect.TypeOf((*MockIProductRepository)(nil).UpdateProductAmount), productID, amount)
+}
+
+// UpdateProductStatus mocks base method.
+func (m *MockIProductRepository) UpdateProductStatus(productID primitive.ObjectID, status int) (*dto.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProductStatus", productID, status)
+	ret0, _ := ret[0].(*dto.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProductStatus indicates an expected call of UpdateProductStatus.
+func (mr *MockIProductRepositoryMockRecorder) UpdateProductStatus(productID, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProductStatus", reflect.TypeOf((*MockIProductRepository)(nil).UpdateProductStatus), productID, status)
+}
+
+// UpdateRating mocks base method.
+func (m *MockIProductRepository) UpdateRating(productID primitive.ObjectID, removed, added []int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRating", productID, removed, added)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRating indicates an expected call of UpdateRating.
+func (mr *MockIProductRepositoryMockRecorder) UpdateRating(productID, removed, added any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRating", reflect.TypeOf((*MockIProductRepository)(nil).UpdateRating), productID, removed, added)
+}
@@ -0,0 +1,192 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/review_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/review_repository.go -destination=pkg/mock/repository/review_repository.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+
+	dto "github.com/Dongy-s-Advanture/back-end/internal/dto"
+	model "github.com/Dongy-s-Advanture/back-end/internal/model"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIReviewRepository is a mock of IReviewRepository interface.
+type MockIReviewRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIReviewRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockIReviewRepositoryMockRecorder is the mock recorder for MockIReviewRepository.
+type MockIReviewRepositoryMockRecorder struct {
+	mock *MockIReviewRepository
+}
+
+// NewMockIReviewRepository creates a new mock instance.
+func NewMockIReviewRepository(ctrl *gomock.Controller) *MockIReviewRepository {
+	mock := &MockIReviewRepository{ctrl: ctrl}
+	mock.recorder = &MockIReviewRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIReviewRepository) EXPECT() *MockIReviewRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateReview mocks base method.
+func (m *MockIReviewRepository) CreateReview(review *model.Review) (*dto.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReview", review)
+	ret0, _ := ret[0].(*dto.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReview indicates an expected call of CreateReview.
+func (mr *MockIReviewRepositoryMockRecorder) CreateReview(review any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReview", reflect.TypeOf((*MockIReviewRepository)(nil).CreateReview), review)
+}
+
+// DeleteReview mocks base method.
+func (m *MockIReviewRepository) DeleteReview(reviewID primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReview", reviewID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteReview indicates an expected call of DeleteReview.
+func (mr *MockIReviewRepositoryMockRecorder) DeleteReview(reviewID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReview", reflect.TypeOf((*MockIReviewRepository)(nil).DeleteReview), reviewID)
+}
+
+// GetReviewByID mocks base method.
+func (m *MockIReviewRepository) GetReviewByID(reviewID primitive.ObjectID) (*dto.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewByID", reviewID)
+	ret0, _ := ret[0].(*dto.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewByID indicates an expected call of GetReviewByID.
+func (mr *MockIReviewRepositoryMockRecorder) GetReviewByID(reviewID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewByID", reflect.TypeOf((*MockIReviewRepository)(nil).GetReviewByID), reviewID)
+}
+
+// GetReviews mocks base method.
+func (m *MockIReviewRepository) GetReviews() ([]dto.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviews")
+	ret0, _ := ret[0].([]dto.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviews indicates an expected call of GetReviews.
+func (mr *MockIReviewRepositoryMockRecorder) GetReviews() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviews", reflect.TypeOf((*MockIReviewRepository)(nil).GetReviews))
+}
+
+// GetReviewsByBuyerID mocks base method.
+func (m *MockIReviewRepository) GetReviewsByBuyerID(buyerID primitive.ObjectID) ([]dto.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewsByBuyerID", buyerID)
+	ret0, _ := ret[0].([]dto.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewsByBuyerID indicates an expected call of GetReviewsByBuyerID.
+func (mr *MockIReviewRepositoryMockRecorder) GetReviewsByBuyerID(buyerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewsByBuyerID", reflect.TypeOf((*MockIReviewRepository)(nil).GetReviewsByBuyerID), buyerID)
+}
+
+// GetReviewsByProductID mocks base method.
+func (m *MockIReviewRepository) GetReviewsByProductID(productID primitive.ObjectID, sortBy string) ([]dto.ProductReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewsByProductID", productID, sortBy)
+	ret0, _ := ret[0].([]dto.ProductReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewsByProductID indicates an expected call of GetReviewsByProductID.
+func (mr *MockIReviewRepositoryMockRecorder) GetReviewsByProductID(productID, sortBy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewsByProductID", reflect.TypeOf((*MockIReviewRepository)(nil).GetReviewsByProductID), productID, sortBy)
+}
+
+// GetReviewsBySellerID mocks base method.
+func (m *MockIReviewRepository) GetReviewsBySellerID(sellerID primitive.ObjectID) ([]dto.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewsBySellerID", sellerID)
+	ret0, _ := ret[0].([]dto.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewsBySellerID indicates an expected call of GetReviewsBySellerID.
+func (mr *MockIReviewRepositoryMockRecorder) GetReviewsBySellerID(sellerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewsBySellerID", reflect.TypeOf((*MockIReviewRepository)(nil).GetReviewsBySellerID), sellerID)
+}
+
+// SetReviewReply mocks base method.
+func (m *MockIReviewRepository) SetReviewReply(reviewID primitive.ObjectID, reply *model.ReviewReply) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetReviewReply", reviewID, reply)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetReviewReply indicates an expected call of SetReviewReply.
+func (mr *MockIReviewRepositoryMockRecorder) SetReviewReply(reviewID, reply any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReviewReply", reflect.TypeOf((*MockIReviewRepository)(nil).SetReviewReply), reviewID, reply)
+}
+
+// SetReviewStatus mocks base method.
+func (m *MockIReviewRepository) SetReviewStatus(reviewID primitive.ObjectID, from, to int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetReviewStatus", reviewID, from, to)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetReviewStatus indicates an expected call of SetReviewStatus.
+func (mr *MockIReviewRepositoryMockRecorder) SetReviewStatus(reviewID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReviewStatus", reflect.TypeOf((*MockIReviewRepository)(nil).SetReviewStatus), reviewID, from, to)
+}
+
+// UpdateReview mocks base method.
+func (m *MockIReviewRepository) UpdateReview(reviewID primitive.ObjectID, updatedReview *model.Review) (*dto.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateReview", reviewID, updatedReview)
+	ret0, _ := ret[0].(*dto.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateReview indicates an expected call of UpdateReview.
+func (mr *MockIReviewRepositoryMockRecorder) UpdateReview(reviewID, updatedReview any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReview", reflect.TypeOf((*MockIReviewRepository)(nil).UpdateReview), reviewID, updatedReview)
+}
@@ -14,6 +14,8 @@ import (
 
 	dto "github.com/Dongy-s-Advanture/back-end/internal/dto"
 	model "github.com/Dongy-s-Advanture/back-end/internal/model"
+	geo "github.com/Dongy-s-Advanture/back-end/pkg/utils/geo"
+	money "github.com/Dongy-s-Advanture/back-end/pkg/utils/money"
 	primitive "go.mongodb.org/mongo-driver/bson/primitive"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -42,6 +44,34 @@ func (m *MockISellerRepository) EXPECT() *MockISellerRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AddNoShowStrike mocks base method.
+func (m *MockISellerRepository) AddNoShowStrike(sellerID primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddNoShowStrike", sellerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddNoShowStrike indicates an expected call of AddNoShowStrike.
+func (mr *MockISellerRepositoryMockRecorder) AddNoShowStrike(sellerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddNoShowStrike", reflect.TypeOf((*MockISellerRepository)(nil).AddNoShowStrike), sellerID)
+}
+
+// ChargebackSellerBalance mocks base method.
+func (m *MockISellerRepository) ChargebackSellerBalance(sellerID, orderID primitive.ObjectID, payment string, amount money.Money) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChargebackSellerBalance", sellerID, orderID, payment, amount)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChargebackSellerBalance indicates an expected call of ChargebackSellerBalance.
+func (mr *MockISellerRepositoryMockRecorder) ChargebackSellerBalance(sellerID, orderID, payment, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChargebackSellerBalance", reflect.TypeOf((*MockISellerRepository)(nil).ChargebackSellerBalance), sellerID, orderID, payment, amount)
+}
+
 // CreateSellerData mocks base method.
 func (m *MockISellerRepository) CreateSellerData(seller *model.Seller) (*dto.Seller, error) {
 	m.ctrl.T.Helper()
@@ -58,7 +88,7 @@ func (mr *MockISellerRepositoryMockRecorder) CreateSellerData(seller any) *gomoc
 }
 
 // DepositSellerBalance mocks base method.
-func (m *MockISellerRepository) DepositSellerBalance(sellerID, orderID primitive.ObjectID, payment string, amount float64) error {
+func (m *MockISellerRepository) DepositSellerBalance(sellerID, orderID primitive.ObjectID, payment string, amount money.Money) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "DepositSellerBalance", sellerID, orderID, payment, amount)
 	ret0, _ := ret[0].(error)
@@ -72,10 +102,10 @@ func (mr *MockISellerRepositoryMockRecorder) DepositSellerBalance(sellerID, orde
 }
 
 // GetSellerBalanceByID mocks base method.
-func (m *MockISellerRepository) GetSellerBalanceByID(sellerID primitive.ObjectID) (float64, error) {
+func (m *MockISellerRepository) GetSellerBalanceByID(sellerID primitive.ObjectID) (money.Money, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetSellerBalanceByID", sellerID)
-	ret0, _ := ret[0].(float64)
+	ret0, _ := ret[0].(money.Money)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -116,6 +146,21 @@ func (mr *MockISellerRepositoryMockRecorder) GetSellerByUsername(req any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSellerByUsername", reflect.TypeOf((*MockISellerRepository)(nil).GetSellerByUsername), req)
 }
 
+// GetSellerIDsNear mocks base method.
+func (m *MockISellerRepository) GetSellerIDsNear(near geo.Point, radius float64) ([]primitive.ObjectID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSellerIDsNear", near, radius)
+	ret0, _ := ret[0].([]primitive.ObjectID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSellerIDsNear indicates an expected call of GetSellerIDsNear.
+func (mr *MockISellerRepositoryMockRecorder) GetSellerIDsNear(near, radius any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSellerIDsNear", reflect.TypeOf((*MockISellerRepository)(nil).GetSellerIDsNear), near, radius)
+}
+
 // GetSellers mocks base method.
 func (m *MockISellerRepository) GetSellers() ([]dto.Seller, error) {
 	m.ctrl.T.Helper()
@@ -131,6 +176,51 @@ func (mr *MockISellerRepositoryMockRecorder) GetSellers() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSellers", reflect.TypeOf((*MockISellerRepository)(nil).GetSellers))
 }
 
+// RecalculateRating mocks base method.
+func (m *MockISellerRepository) RecalculateRating(sellerID primitive.ObjectID) (*dto.Rating, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecalculateRating", sellerID)
+	ret0, _ := ret[0].(*dto.Rating)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecalculateRating indicates an expected call of RecalculateRating.
+func (mr *MockISellerRepositoryMockRecorder) RecalculateRating(sellerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecalculateRating", reflect.TypeOf((*MockISellerRepository)(nil).RecalculateRating), sellerID)
+}
+
+// SetSellerScore mocks base method.
+func (m *MockISellerRepository) SetSellerScore(sellerID primitive.ObjectID, rating *dto.Rating, score float64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSellerScore", sellerID, rating, score)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetSellerScore indicates an expected call of SetSellerScore.
+func (mr *MockISellerRepositoryMockRecorder) SetSellerScore(sellerID, rating, score any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSellerScore", reflect.TypeOf((*MockISellerRepository)(nil).SetSellerScore), sellerID, rating, score)
+}
+
+// UpdateRating mocks base method.
+func (m *MockISellerRepository) UpdateRating(sellerID primitive.ObjectID, removed, added []int) (*dto.Rating, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRating", sellerID, removed, added)
+	ret0, _ := ret[0].(*dto.Rating)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateRating indicates an expected call of UpdateRating.
+func (mr *MockISellerRepositoryMockRecorder) UpdateRating(sellerID, removed, added any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRating", reflect.TypeOf((*MockISellerRepository)(nil).UpdateRating), sellerID, removed, added)
+}
+
 // UpdateSeller mocks base method.
 func (m *MockISellerRepository) UpdateSeller(sellerID primitive.ObjectID, updatedSeller *model.Seller) (*dto.Seller, error) {
 	m.ctrl.T.Helper()
@@ -146,22 +236,8 @@ func (mr *MockISellerRepositoryMockRecorder) UpdateSeller(sellerID, updatedSelle
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSeller", reflect.TypeOf((*MockISellerRepository)(nil).UpdateSeller), sellerID, updatedSeller)
 }
 
-// UpdateSellerScore mocks base method.
-func (m *MockISellerRepository) UpdateSellerScore(sellerID primitive.ObjectID) error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateSellerScore", sellerID)
-	ret0, _ := ret[0].(error)
-	return ret0
-}
-
-// UpdateSellerScore indicates an expected call of UpdateSellerScore.
-func (mr *MockISellerRepositoryMockRecorder) UpdateSellerScore(sellerID any) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSellerScore", reflect.TypeOf((*MockISellerRepository)(nil).UpdateSellerScore), sellerID)
-}
-
 // WithdrawSellerBalance mocks base method.
-func (m *MockISellerRepository) WithdrawSellerBalance(sellerID primitive.ObjectID, payment string, amount float64) error {
+func (m *MockISellerRepository) WithdrawSellerBalance(sellerID primitive.ObjectID, payment string, amount money.Money) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "WithdrawSellerBalance", sellerID, payment, amount)
 	ret0, _ := ret[0].(error)
@@ -6,6 +6,7 @@ package mock
 
 import (
 	reflect "reflect"
+	time "time"
 
 	dto "github.com/Dongy-s-Advanture/back-end/internal/dto"
 	model "github.com/Dongy-s-Advanture/back-end/internal/model"
@@ -36,6 +37,21 @@ func (m *MockIAppointmentService) EXPECT() *MockIAppointmentServiceMockRecorder
 	return m.recorder
 }
 
+// AcceptProposal mocks base method.
+func (m *MockIAppointmentService) AcceptProposal(appointmentID, proposalID, callerID primitive.ObjectID) (*dto.Appointment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptProposal", appointmentID, proposalID, callerID)
+	ret0, _ := ret[0].(*dto.Appointment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptProposal indicates an expected call of AcceptProposal.
+func (mr *MockIAppointmentServiceMockRecorder) AcceptProposal(appointmentID, proposalID, callerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptProposal", reflect.TypeOf((*MockIAppointmentService)(nil).AcceptProposal), appointmentID, proposalID, callerID)
+}
+
 // CreateAppointment mocks base method.
 func (m *MockIAppointmentService) CreateAppointment(appointment *model.Appointment) (*dto.Appointment, error) {
 	m.ctrl.T.Helper()
@@ -96,32 +112,92 @@ func (mr *MockIAppointmentServiceMockRecorder) GetAppointments() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppointments", reflect.TypeOf((*MockIAppointmentService)(nil).GetAppointments))
 }
 
-// UpdateAppointmentDate mocks base method.
-func (m *MockIAppointmentService) UpdateAppointmentDate(appointmentID primitive.ObjectID, updatedAppointment *model.Appointment) (*dto.Appointment, error) {
+// GetOpenSlots mocks base method.
+func (m *MockIAppointmentService) GetOpenSlots(sellerID primitive.ObjectID, from, to time.Time) (*dto.OpenSlots, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOpenSlots", sellerID, from, to)
+	ret0, _ := ret[0].(*dto.OpenSlots)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOpenSlots indicates an expected call of GetOpenSlots.
+func (mr *MockIAppointmentServiceMockRecorder) GetOpenSlots(sellerID, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenSlots", reflect.TypeOf((*MockIAppointmentService)(nil).GetOpenSlots), sellerID, from, to)
+}
+
+// Propose mocks base method.
+func (m *MockIAppointmentService) Propose(appointmentID, callerID primitive.ObjectID, req *dto.AppointmentProposalRequest) (*dto.Appointment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Propose", appointmentID, callerID, req)
+	ret0, _ := ret[0].(*dto.Appointment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Propose indicates an expected call of Propose.
+func (mr *MockIAppointmentServiceMockRecorder) Propose(appointmentID, callerID, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Propose", reflect.TypeOf((*MockIAppointmentService)(nil).Propose), appointmentID, callerID, req)
+}
+
+// RejectProposal mocks base method.
+func (m *MockIAppointmentService) RejectProposal(appointmentID, proposalID, callerID primitive.ObjectID) (*dto.Appointment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateAppointmentDate", appointmentID, updatedAppointment)
+	ret := m.ctrl.Call(m, "RejectProposal", appointmentID, proposalID, callerID)
 	ret0, _ := ret[0].(*dto.Appointment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdateAppointmentDate indicates an expected call of UpdateAppointmentDate.
-func (mr *MockIAppointmentServiceMockRecorder) UpdateAppointmentDate(appointmentID, updatedAppointment interface{}) *gomock.Call {
+// RejectProposal indicates an expected call of RejectProposal.
+func (mr *MockIAppointmentServiceMockRecorder) RejectProposal(appointmentID, proposalID, callerID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAppointmentDate", reflect.TypeOf((*MockIAppointmentService)(nil).UpdateAppointmentDate), appointmentID, updatedAppointment)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectProposal", reflect.TypeOf((*MockIAppointmentService)(nil).RejectProposal), appointmentID, proposalID, callerID)
 }
 
-// UpdateAppointmentPlace mocks base method.
-func (m *MockIAppointmentService) UpdateAppointmentPlace(appointmentID primitive.ObjectID, updatedAppointment *model.Appointment) (*dto.Appointment, error) {
+// ReportNoShow mocks base method.
+func (m *MockIAppointmentService) ReportNoShow(appointmentID, callerID primitive.ObjectID) (*dto.Appointment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateAppointmentPlace", appointmentID, updatedAppointment)
+	ret := m.ctrl.Call(m, "ReportNoShow", appointmentID, callerID)
 	ret0, _ := ret[0].(*dto.Appointment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdateAppointmentPlace indicates an expected call of UpdateAppointmentPlace.
-func (mr *MockIAppointmentServiceMockRecorder) UpdateAppointmentPlace(appointmentID, updatedAppointment interface{}) *gomock.Call {
+// ReportNoShow indicates an expected call of ReportNoShow.
+func (mr *MockIAppointmentServiceMockRecorder) ReportNoShow(appointmentID, callerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportNoShow", reflect.TypeOf((*MockIAppointmentService)(nil).ReportNoShow), appointmentID, callerID)
+}
+
+// SendDueReminders mocks base method.
+func (m *MockIAppointmentService) SendDueReminders(now time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendDueReminders", now)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendDueReminders indicates an expected call of SendDueReminders.
+func (mr *MockIAppointmentServiceMockRecorder) SendDueReminders(now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendDueReminders", reflect.TypeOf((*MockIAppointmentService)(nil).SendDueReminders), now)
+}
+
+// SetAvailability mocks base method.
+func (m *MockIAppointmentService) SetAvailability(sellerID primitive.ObjectID, req *dto.AvailabilityRequest) (*dto.SellerAvailability, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAvailability", sellerID, req)
+	ret0, _ := ret[0].(*dto.SellerAvailability)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetAvailability indicates an expected call of SetAvailability.
+func (mr *MockIAppointmentServiceMockRecorder) SetAvailability(sellerID, req interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAppointmentPlace", reflect.TypeOf((*MockIAppointmentService)(nil).UpdateAppointmentPlace), appointmentID, updatedAppointment)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAvailability", reflect.TypeOf((*MockIAppointmentService)(nil).SetAvailability), sellerID, req)
 }
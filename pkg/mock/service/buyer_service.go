@@ -36,6 +36,21 @@ func (m *MockIBuyerService) EXPECT() *MockIBuyerServiceMockRecorder {
 	return m.recorder
 }
 
+// AddToWishlist mocks base method.
+func (m *MockIBuyerService) AddToWishlist(buyerID, productID primitive.ObjectID) ([]dto.WishlistItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddToWishlist", buyerID, productID)
+	ret0, _ := ret[0].([]dto.WishlistItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddToWishlist indicates an expected call of AddToWishlist.
+func (mr *MockIBuyerServiceMockRecorder) AddToWishlist(buyerID, productID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToWishlist", reflect.TypeOf((*MockIBuyerService)(nil).AddToWishlist), buyerID, productID)
+}
+
 // CreateBuyerData mocks base method.
 func (m *MockIBuyerService) CreateBuyerData(buyer *model.Buyer) (*dto.Buyer, error) {
 	m.ctrl.T.Helper()
@@ -51,6 +66,20 @@ func (mr *MockIBuyerServiceMockRecorder) CreateBuyerData(buyer interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBuyerData", reflect.TypeOf((*MockIBuyerService)(nil).CreateBuyerData), buyer)
 }
 
+// DeleteProductFromCart mocks base method.
+func (m *MockIBuyerService) DeleteProductFromCart(buyerID, productID primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProductFromCart", buyerID, productID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteProductFromCart indicates an expected call of DeleteProductFromCart.
+func (mr *MockIBuyerServiceMockRecorder) DeleteProductFromCart(buyerID, productID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProductFromCart", reflect.TypeOf((*MockIBuyerService)(nil).DeleteProductFromCart), buyerID, productID)
+}
+
 // GetBuyer mocks base method.
 func (m *MockIBuyerService) GetBuyer() ([]dto.Buyer, error) {
 	m.ctrl.T.Helper()
@@ -81,6 +110,80 @@ func (mr *MockIBuyerServiceMockRecorder) GetBuyerByID(buyerID interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBuyerByID", reflect.TypeOf((*MockIBuyerService)(nil).GetBuyerByID), buyerID)
 }
 
+// GetCart mocks base method.
+func (m *MockIBuyerService) GetCart(buyerID primitive.ObjectID) (*dto.Cart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCart", buyerID)
+	ret0, _ := ret[0].(*dto.Cart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCart indicates an expected call of GetCart.
+func (mr *MockIBuyerServiceMockRecorder) GetCart(buyerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCart", reflect.TypeOf((*MockIBuyerService)(nil).GetCart), buyerID)
+}
+
+// GetWishlist mocks base method.
+func (m *MockIBuyerService) GetWishlist(buyerID primitive.ObjectID) ([]dto.WishlistProduct, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWishlist", buyerID)
+	ret0, _ := ret[0].([]dto.WishlistProduct)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWishlist indicates an expected call of GetWishlist.
+func (mr *MockIBuyerServiceMockRecorder) GetWishlist(buyerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWishlist", reflect.TypeOf((*MockIBuyerService)(nil).GetWishlist), buyerID)
+}
+
+// MoveToCart mocks base method.
+func (m *MockIBuyerService) MoveToCart(buyerID, productID primitive.ObjectID) ([]dto.OrderProduct, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveToCart", buyerID, productID)
+	ret0, _ := ret[0].([]dto.OrderProduct)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveToCart indicates an expected call of MoveToCart.
+func (mr *MockIBuyerServiceMockRecorder) MoveToCart(buyerID, productID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveToCart", reflect.TypeOf((*MockIBuyerService)(nil).MoveToCart), buyerID, productID)
+}
+
+// RemoveFromWishlist mocks base method.
+func (m *MockIBuyerService) RemoveFromWishlist(buyerID, productID primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveFromWishlist", buyerID, productID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveFromWishlist indicates an expected call of RemoveFromWishlist.
+func (mr *MockIBuyerServiceMockRecorder) RemoveFromWishlist(buyerID, productID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFromWishlist", reflect.TypeOf((*MockIBuyerService)(nil).RemoveFromWishlist), buyerID, productID)
+}
+
+// SaveForLater mocks base method.
+func (m *MockIBuyerService) SaveForLater(buyerID, productID primitive.ObjectID) ([]dto.WishlistItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveForLater", buyerID, productID)
+	ret0, _ := ret[0].([]dto.WishlistItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveForLater indicates an expected call of SaveForLater.
+func (mr *MockIBuyerServiceMockRecorder) SaveForLater(buyerID, productID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveForLater", reflect.TypeOf((*MockIBuyerService)(nil).SaveForLater), buyerID, productID)
+}
+
 // UpdateBuyerData mocks base method.
 func (m *MockIBuyerService) UpdateBuyerData(buyerID primitive.ObjectID, updatedBuyer *model.Buyer) (*dto.Buyer, error) {
 	m.ctrl.T.Helper()
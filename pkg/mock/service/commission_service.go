@@ -0,0 +1,140 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/commission_service.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+	time "time"
+
+	dto "github.com/Dongy-s-Advanture/back-end/internal/dto"
+	money "github.com/Dongy-s-Advanture/back-end/pkg/utils/money"
+	gomock "github.com/golang/mock/gomock"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockICommissionService is a mock of ICommissionService interface.
+type MockICommissionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockICommissionServiceMockRecorder
+}
+
+// MockICommissionServiceMockRecorder is the mock recorder for MockICommissionService.
+type MockICommissionServiceMockRecorder struct {
+	mock *MockICommissionService
+}
+
+// NewMockICommissionService creates a new mock instance.
+func NewMockICommissionService(ctrl *gomock.Controller) *MockICommissionService {
+	mock := &MockICommissionService{ctrl: ctrl}
+	mock.recorder = &MockICommissionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockICommissionService) EXPECT() *MockICommissionServiceMockRecorder {
+	return m.recorder
+}
+
+// CalculateFees mocks base method.
+func (m *MockICommissionService) CalculateFees(sellerID primitive.ObjectID, lines []dto.CommissionLine, sellerGross, charged, couponSubsidy money.Money) (*dto.OrderFees, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CalculateFees", sellerID, lines, sellerGross, charged, couponSubsidy)
+	ret0, _ := ret[0].(*dto.OrderFees)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CalculateFees indicates an expected call of CalculateFees.
+func (mr *MockICommissionServiceMockRecorder) CalculateFees(sellerID, lines, sellerGross, charged, couponSubsidy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CalculateFees", reflect.TypeOf((*MockICommissionService)(nil).CalculateFees), sellerID, lines, sellerGross, charged, couponSubsidy)
+}
+
+// DeleteRule mocks base method.
+func (m *MockICommissionService) DeleteRule(ruleID primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRule", ruleID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRule indicates an expected call of DeleteRule.
+func (mr *MockICommissionServiceMockRecorder) DeleteRule(ruleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRule", reflect.TypeOf((*MockICommissionService)(nil).DeleteRule), ruleID)
+}
+
+// GetLedger mocks base method.
+func (m *MockICommissionService) GetLedger(from, to time.Time) (*dto.LedgerSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLedger", from, to)
+	ret0, _ := ret[0].(*dto.LedgerSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLedger indicates an expected call of GetLedger.
+func (mr *MockICommissionServiceMockRecorder) GetLedger(from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLedger", reflect.TypeOf((*MockICommissionService)(nil).GetLedger), from, to)
+}
+
+// GetRules mocks base method.
+func (m *MockICommissionService) GetRules() ([]dto.CommissionRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRules")
+	ret0, _ := ret[0].([]dto.CommissionRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRules indicates an expected call of GetRules.
+func (mr *MockICommissionServiceMockRecorder) GetRules() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRules", reflect.TypeOf((*MockICommissionService)(nil).GetRules))
+}
+
+// RecordOrderFees mocks base method.
+func (m *MockICommissionService) RecordOrderFees(orderID, sellerID primitive.ObjectID, fees *dto.OrderFees) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordOrderFees", orderID, sellerID, fees)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordOrderFees indicates an expected call of RecordOrderFees.
+func (mr *MockICommissionServiceMockRecorder) RecordOrderFees(orderID, sellerID, fees interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordOrderFees", reflect.TypeOf((*MockICommissionService)(nil).RecordOrderFees), orderID, sellerID, fees)
+}
+
+// ReverseOrderFees mocks base method.
+func (m *MockICommissionService) ReverseOrderFees(orderID, sellerID primitive.ObjectID, fees *dto.OrderFees) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReverseOrderFees", orderID, sellerID, fees)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReverseOrderFees indicates an expected call of ReverseOrderFees.
+func (mr *MockICommissionServiceMockRecorder) ReverseOrderFees(orderID, sellerID, fees interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReverseOrderFees", reflect.TypeOf((*MockICommissionService)(nil).ReverseOrderFees), orderID, sellerID, fees)
+}
+
+// SetRule mocks base method.
+func (m *MockICommissionService) SetRule(req *dto.CommissionRuleRequest) (*dto.CommissionRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRule", req)
+	ret0, _ := ret[0].(*dto.CommissionRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetRule indicates an expected call of SetRule.
+func (mr *MockICommissionServiceMockRecorder) SetRule(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRule", reflect.TypeOf((*MockICommissionService)(nil).SetRule), req)
+}
@@ -0,0 +1,122 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/coupon_service.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+
+	dto "github.com/Dongy-s-Advanture/back-end/internal/dto"
+	gomock "github.com/golang/mock/gomock"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockICouponService is a mock of ICouponService interface.
+type MockICouponService struct {
+	ctrl     *gomock.Controller
+	recorder *MockICouponServiceMockRecorder
+}
+
+// MockICouponServiceMockRecorder is the mock recorder for MockICouponService.
+type MockICouponServiceMockRecorder struct {
+	mock *MockICouponService
+}
+
+// NewMockICouponService creates a new mock instance.
+func NewMockICouponService(ctrl *gomock.Controller) *MockICouponService {
+	mock := &MockICouponService{ctrl: ctrl}
+	mock.recorder = &MockICouponServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockICouponService) EXPECT() *MockICouponServiceMockRecorder {
+	return m.recorder
+}
+
+// ApplyCoupon mocks base method.
+func (m *MockICouponService) ApplyCoupon(code string, buyerID primitive.ObjectID, lines []dto.CouponLine) (*dto.AppliedCoupon, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyCoupon", code, buyerID, lines)
+	ret0, _ := ret[0].(*dto.AppliedCoupon)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyCoupon indicates an expected call of ApplyCoupon.
+func (mr *MockICouponServiceMockRecorder) ApplyCoupon(code, buyerID, lines interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyCoupon", reflect.TypeOf((*MockICouponService)(nil).ApplyCoupon), code, buyerID, lines)
+}
+
+// CreateCoupon mocks base method.
+func (m *MockICouponService) CreateCoupon(callerID primitive.ObjectID, isAdmin bool, req *dto.CouponCreateRequest) (*dto.Coupon, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCoupon", callerID, isAdmin, req)
+	ret0, _ := ret[0].(*dto.Coupon)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCoupon indicates an expected call of CreateCoupon.
+func (mr *MockICouponServiceMockRecorder) CreateCoupon(callerID, isAdmin, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCoupon", reflect.TypeOf((*MockICouponService)(nil).CreateCoupon), callerID, isAdmin, req)
+}
+
+// DeactivateCoupon mocks base method.
+func (m *MockICouponService) DeactivateCoupon(callerID primitive.ObjectID, isAdmin bool, couponID primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeactivateCoupon", callerID, isAdmin, couponID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeactivateCoupon indicates an expected call of DeactivateCoupon.
+func (mr *MockICouponServiceMockRecorder) DeactivateCoupon(callerID, isAdmin, couponID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateCoupon", reflect.TypeOf((*MockICouponService)(nil).DeactivateCoupon), callerID, isAdmin, couponID)
+}
+
+// GetCouponsBySellerID mocks base method.
+func (m *MockICouponService) GetCouponsBySellerID(sellerID primitive.ObjectID) ([]dto.Coupon, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCouponsBySellerID", sellerID)
+	ret0, _ := ret[0].([]dto.Coupon)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCouponsBySellerID indicates an expected call of GetCouponsBySellerID.
+func (mr *MockICouponServiceMockRecorder) GetCouponsBySellerID(sellerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCouponsBySellerID", reflect.TypeOf((*MockICouponService)(nil).GetCouponsBySellerID), sellerID)
+}
+
+// RedeemCoupon mocks base method.
+func (m *MockICouponService) RedeemCoupon(applied *dto.AppliedCoupon, buyerID primitive.ObjectID) (primitive.ObjectID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedeemCoupon", applied, buyerID)
+	ret0, _ := ret[0].(primitive.ObjectID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RedeemCoupon indicates an expected call of RedeemCoupon.
+func (mr *MockICouponServiceMockRecorder) RedeemCoupon(applied, buyerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeemCoupon", reflect.TypeOf((*MockICouponService)(nil).RedeemCoupon), applied, buyerID)
+}
+
+// ReleaseCoupon mocks base method.
+func (m *MockICouponService) ReleaseCoupon(applied *dto.AppliedCoupon, redemptionID primitive.ObjectID) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ReleaseCoupon", applied, redemptionID)
+}
+
+// ReleaseCoupon indicates an expected call of ReleaseCoupon.
+func (mr *MockICouponServiceMockRecorder) ReleaseCoupon(applied, redemptionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseCoupon", reflect.TypeOf((*MockICouponService)(nil).ReleaseCoupon), applied, redemptionID)
+}
@@ -37,6 +37,21 @@ func (m *MockIOrderService) EXPECT() *MockIOrderServiceMockRecorder {
 	return m.recorder
 }
 
+// Checkout mocks base method.
+func (m *MockIOrderService) Checkout(buyerID primitive.ObjectID, req *dto.CheckoutRequest) (*dto.CheckoutResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Checkout", buyerID, req)
+	ret0, _ := ret[0].(*dto.CheckoutResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Checkout indicates an expected call of Checkout.
+func (mr *MockIOrderServiceMockRecorder) Checkout(buyerID, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Checkout", reflect.TypeOf((*MockIOrderService)(nil).Checkout), buyerID, req)
+}
+
 // CreateOrder mocks base method.
 func (m *MockIOrderService) CreateOrder(orderCreateRequest *dto.OrderCreateRequest) (*dto.Order, error) {
 	m.ctrl.T.Helper()
@@ -82,18 +97,32 @@ func (mr *MockIOrderServiceMockRecorder) GetOrdersByUserID(userID, userType inte
 }
 
 // GetTotalPrice mocks base method.
-func (m *MockIOrderService) GetTotalPrice(products []dto.OrderProduct) (float64, error) {
+func (m *MockIOrderService) GetTotalPrice(buyerID primitive.ObjectID, products []dto.OrderProduct, couponCode string) (*dto.PriceQuote, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTotalPrice", products)
-	ret0, _ := ret[0].(float64)
+	ret := m.ctrl.Call(m, "GetTotalPrice", buyerID, products, couponCode)
+	ret0, _ := ret[0].(*dto.PriceQuote)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTotalPrice indicates an expected call of GetTotalPrice.
-func (mr *MockIOrderServiceMockRecorder) GetTotalPrice(products interface{}) *gomock.Call {
+func (mr *MockIOrderServiceMockRecorder) GetTotalPrice(buyerID, products, couponCode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTotalPrice", reflect.TypeOf((*MockIOrderService)(nil).GetTotalPrice), buyerID, products, couponCode)
+}
+
+// HandleNoShow mocks base method.
+func (m *MockIOrderService) HandleNoShow(orderID, absentID primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleNoShow", orderID, absentID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleNoShow indicates an expected call of HandleNoShow.
+func (mr *MockIOrderServiceMockRecorder) HandleNoShow(orderID, absentID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTotalPrice", reflect.TypeOf((*MockIOrderService)(nil).GetTotalPrice), products)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleNoShow", reflect.TypeOf((*MockIOrderService)(nil).HandleNoShow), orderID, absentID)
 }
 
 // UpdateOrder mocks base method.
@@ -8,6 +8,7 @@ import (
 	reflect "reflect"
 
 	dto "github.com/Dongy-s-Advanture/back-end/internal/dto"
+	money "github.com/Dongy-s-Advanture/back-end/pkg/utils/money"
 	gomock "github.com/golang/mock/gomock"
 	omise "github.com/omise/omise-go"
 )
@@ -74,6 +75,21 @@ func (mr *MockIPaymentServiceMockRecorder) HandlePayment(paymentRequest interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandlePayment", reflect.TypeOf((*MockIPaymentService)(nil).HandlePayment), paymentRequest)
 }
 
+// RefundCharge mocks base method.
+func (m *MockIPaymentService) RefundCharge(chargeID string, amount money.Money) (*omise.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefundCharge", chargeID, amount)
+	ret0, _ := ret[0].(*omise.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefundCharge indicates an expected call of RefundCharge.
+func (mr *MockIPaymentServiceMockRecorder) RefundCharge(chargeID, amount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefundCharge", reflect.TypeOf((*MockIPaymentService)(nil).RefundCharge), chargeID, amount)
+}
+
 // RemoveClient mocks base method.
 func (m *MockIPaymentService) RemoveClient(chargeID string, client chan string) {
 	m.ctrl.T.Helper()
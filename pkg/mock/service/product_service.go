@@ -9,6 +9,7 @@ import (
 
 	dto "github.com/Dongy-s-Advanture/back-end/internal/dto"
 	model "github.com/Dongy-s-Advanture/back-end/internal/model"
+	geo "github.com/Dongy-s-Advanture/back-end/pkg/utils/geo"
 	gomock "github.com/golang/mock/gomock"
 	primitive "go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -36,6 +37,21 @@ func (m *MockIProductService) EXPECT() *MockIProductServiceMockRecorder {
 	return m.recorder
 }
 
+// ArchiveProduct mocks base method.
+func (m *MockIProductService) ArchiveProduct(productID primitive.ObjectID) (*dto.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchiveProduct", productID)
+	ret0, _ := ret[0].(*dto.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ArchiveProduct indicates an expected call of ArchiveProduct.
+func (mr *MockIProductServiceMockRecorder) ArchiveProduct(productID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveProduct", reflect.TypeOf((*MockIProductService)(nil).ArchiveProduct), productID)
+}
+
 // CreateProduct mocks base method.
 func (m *MockIProductService) CreateProduct(product *model.Product) (*dto.Product, error) {
 	m.ctrl.T.Helper()
@@ -110,6 +126,66 @@ func (mr *MockIProductServiceMockRecorder) GetProductsBySellerID(sellerID interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductsBySellerID", reflect.TypeOf((*MockIProductService)(nil).GetProductsBySellerID), sellerID)
 }
 
+// GetProductsNear mocks base method.
+func (m *MockIProductService) GetProductsNear(near geo.Point, radius float64) ([]dto.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProductsNear", near, radius)
+	ret0, _ := ret[0].([]dto.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProductsNear indicates an expected call of GetProductsNear.
+func (mr *MockIProductServiceMockRecorder) GetProductsNear(near, radius interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductsNear", reflect.TypeOf((*MockIProductService)(nil).GetProductsNear), near, radius)
+}
+
+// GetSellerListings mocks base method.
+func (m *MockIProductService) GetSellerListings(sellerID primitive.ObjectID, status *int) ([]dto.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSellerListings", sellerID, status)
+	ret0, _ := ret[0].([]dto.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSellerListings indicates an expected call of GetSellerListings.
+func (mr *MockIProductServiceMockRecorder) GetSellerListings(sellerID, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSellerListings", reflect.TypeOf((*MockIProductService)(nil).GetSellerListings), sellerID, status)
+}
+
+// PublishProduct mocks base method.
+func (m *MockIProductService) PublishProduct(productID primitive.ObjectID) (*dto.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishProduct", productID)
+	ret0, _ := ret[0].(*dto.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishProduct indicates an expected call of PublishProduct.
+func (mr *MockIProductServiceMockRecorder) PublishProduct(productID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishProduct", reflect.TypeOf((*MockIProductService)(nil).PublishProduct), productID)
+}
+
+// RestoreProduct mocks base method.
+func (m *MockIProductService) RestoreProduct(productID primitive.ObjectID) (*dto.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreProduct", productID)
+	ret0, _ := ret[0].(*dto.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreProduct indicates an expected call of RestoreProduct.
+func (mr *MockIProductServiceMockRecorder) RestoreProduct(productID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreProduct", reflect.TypeOf((*MockIProductService)(nil).RestoreProduct), productID)
+}
+
 // UpdateProduct mocks base method.
 func (m *MockIProductService) UpdateProduct(productID primitive.ObjectID, updatedProduct *model.Product) (*dto.Product, error) {
 	m.ctrl.T.Helper()
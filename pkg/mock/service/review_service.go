@@ -0,0 +1,261 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/review_service.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+
+	dto "github.com/Dongy-s-Advanture/back-end/internal/dto"
+	model "github.com/Dongy-s-Advanture/back-end/internal/model"
+	gomock "github.com/golang/mock/gomock"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockIReviewService is a mock of IReviewService interface.
+type MockIReviewService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIReviewServiceMockRecorder
+}
+
+// MockIReviewServiceMockRecorder is the mock recorder for MockIReviewService.
+type MockIReviewServiceMockRecorder struct {
+	mock *MockIReviewService
+}
+
+// NewMockIReviewService creates a new mock instance.
+func NewMockIReviewService(ctrl *gomock.Controller) *MockIReviewService {
+	mock := &MockIReviewService{ctrl: ctrl}
+	mock.recorder = &MockIReviewServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIReviewService) EXPECT() *MockIReviewServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateReview mocks base method.
+func (m *MockIReviewService) CreateReview(buyerID primitive.ObjectID, review *model.Review) (*dto.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReview", buyerID, review)
+	ret0, _ := ret[0].(*dto.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReview indicates an expected call of CreateReview.
+func (mr *MockIReviewServiceMockRecorder) CreateReview(buyerID, review interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReview", reflect.TypeOf((*MockIReviewService)(nil).CreateReview), buyerID, review)
+}
+
+// DeleteReview mocks base method.
+func (m *MockIReviewService) DeleteReview(reviewID, callerID primitive.ObjectID, isAdmin bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReview", reviewID, callerID, isAdmin)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteReview indicates an expected call of DeleteReview.
+func (mr *MockIReviewServiceMockRecorder) DeleteReview(reviewID, callerID, isAdmin interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReview", reflect.TypeOf((*MockIReviewService)(nil).DeleteReview), reviewID, callerID, isAdmin)
+}
+
+// GetModerationQueue mocks base method.
+func (m *MockIReviewService) GetModerationQueue() ([]dto.ModerationItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetModerationQueue")
+	ret0, _ := ret[0].([]dto.ModerationItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetModerationQueue indicates an expected call of GetModerationQueue.
+func (mr *MockIReviewServiceMockRecorder) GetModerationQueue() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModerationQueue", reflect.TypeOf((*MockIReviewService)(nil).GetModerationQueue))
+}
+
+// GetReviewByID mocks base method.
+func (m *MockIReviewService) GetReviewByID(reviewID primitive.ObjectID) (*dto.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewByID", reviewID)
+	ret0, _ := ret[0].(*dto.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewByID indicates an expected call of GetReviewByID.
+func (mr *MockIReviewServiceMockRecorder) GetReviewByID(reviewID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewByID", reflect.TypeOf((*MockIReviewService)(nil).GetReviewByID), reviewID)
+}
+
+// GetReviews mocks base method.
+func (m *MockIReviewService) GetReviews() ([]dto.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviews")
+	ret0, _ := ret[0].([]dto.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviews indicates an expected call of GetReviews.
+func (mr *MockIReviewServiceMockRecorder) GetReviews() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviews", reflect.TypeOf((*MockIReviewService)(nil).GetReviews))
+}
+
+// GetReviewsByBuyerID mocks base method.
+func (m *MockIReviewService) GetReviewsByBuyerID(buyerID primitive.ObjectID) ([]dto.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewsByBuyerID", buyerID)
+	ret0, _ := ret[0].([]dto.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewsByBuyerID indicates an expected call of GetReviewsByBuyerID.
+func (mr *MockIReviewServiceMockRecorder) GetReviewsByBuyerID(buyerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewsByBuyerID", reflect.TypeOf((*MockIReviewService)(nil).GetReviewsByBuyerID), buyerID)
+}
+
+// GetReviewsByProductID mocks base method.
+func (m *MockIReviewService) GetReviewsByProductID(productID primitive.ObjectID, sortBy string) ([]dto.ProductReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewsByProductID", productID, sortBy)
+	ret0, _ := ret[0].([]dto.ProductReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewsByProductID indicates an expected call of GetReviewsByProductID.
+func (mr *MockIReviewServiceMockRecorder) GetReviewsByProductID(productID, sortBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewsByProductID", reflect.TypeOf((*MockIReviewService)(nil).GetReviewsByProductID), productID, sortBy)
+}
+
+// GetReviewsBySellerID mocks base method.
+func (m *MockIReviewService) GetReviewsBySellerID(sellerID primitive.ObjectID) ([]dto.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewsBySellerID", sellerID)
+	ret0, _ := ret[0].([]dto.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewsBySellerID indicates an expected call of GetReviewsBySellerID.
+func (mr *MockIReviewServiceMockRecorder) GetReviewsBySellerID(sellerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewsBySellerID", reflect.TypeOf((*MockIReviewService)(nil).GetReviewsBySellerID), sellerID)
+}
+
+// GetSellerRating mocks base method.
+func (m *MockIReviewService) GetSellerRating(sellerID primitive.ObjectID) (*dto.SellerRating, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSellerRating", sellerID)
+	ret0, _ := ret[0].(*dto.SellerRating)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSellerRating indicates an expected call of GetSellerRating.
+func (mr *MockIReviewServiceMockRecorder) GetSellerRating(sellerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSellerRating", reflect.TypeOf((*MockIReviewService)(nil).GetSellerRating), sellerID)
+}
+
+// HideReview mocks base method.
+func (m *MockIReviewService) HideReview(reviewID primitive.ObjectID) (*dto.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HideReview", reviewID)
+	ret0, _ := ret[0].(*dto.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HideReview indicates an expected call of HideReview.
+func (mr *MockIReviewServiceMockRecorder) HideReview(reviewID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HideReview", reflect.TypeOf((*MockIReviewService)(nil).HideReview), reviewID)
+}
+
+// RemoveReview mocks base method.
+func (m *MockIReviewService) RemoveReview(reviewID primitive.ObjectID) (*dto.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveReview", reviewID)
+	ret0, _ := ret[0].(*dto.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveReview indicates an expected call of RemoveReview.
+func (mr *MockIReviewServiceMockRecorder) RemoveReview(reviewID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveReview", reflect.TypeOf((*MockIReviewService)(nil).RemoveReview), reviewID)
+}
+
+// ReplyToReview mocks base method.
+func (m *MockIReviewService) ReplyToReview(reviewID, sellerID primitive.ObjectID, message string) (*dto.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplyToReview", reviewID, sellerID, message)
+	ret0, _ := ret[0].(*dto.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplyToReview indicates an expected call of ReplyToReview.
+func (mr *MockIReviewServiceMockRecorder) ReplyToReview(reviewID, sellerID, message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplyToReview", reflect.TypeOf((*MockIReviewService)(nil).ReplyToReview), reviewID, sellerID, message)
+}
+
+// ReportReview mocks base method.
+func (m *MockIReviewService) ReportReview(reviewID, reporterID primitive.ObjectID, reason string) (*dto.ReviewReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportReview", reviewID, reporterID, reason)
+	ret0, _ := ret[0].(*dto.ReviewReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReportReview indicates an expected call of ReportReview.
+func (mr *MockIReviewServiceMockRecorder) ReportReview(reviewID, reporterID, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportReview", reflect.TypeOf((*MockIReviewService)(nil).ReportReview), reviewID, reporterID, reason)
+}
+
+// RestoreReview mocks base method.
+func (m *MockIReviewService) RestoreReview(reviewID primitive.ObjectID) (*dto.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreReview", reviewID)
+	ret0, _ := ret[0].(*dto.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreReview indicates an expected call of RestoreReview.
+func (mr *MockIReviewServiceMockRecorder) RestoreReview(reviewID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreReview", reflect.TypeOf((*MockIReviewService)(nil).RestoreReview), reviewID)
+}
+
+// UpdateReview mocks base method.
+func (m *MockIReviewService) UpdateReview(reviewID, callerID primitive.ObjectID, isAdmin bool, updatedReview *model.Review) (*dto.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateReview", reviewID, callerID, isAdmin, updatedReview)
+	ret0, _ := ret[0].(*dto.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateReview indicates an expected call of UpdateReview.
+func (mr *MockIReviewServiceMockRecorder) UpdateReview(reviewID, callerID, isAdmin, updatedReview interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReview", reflect.TypeOf((*MockIReviewService)(nil).UpdateReview), reviewID, callerID, isAdmin, updatedReview)
+}
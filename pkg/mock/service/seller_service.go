@@ -9,6 +9,7 @@ import (
 
 	dto "github.com/Dongy-s-Advanture/back-end/internal/dto"
 	model "github.com/Dongy-s-Advanture/back-end/internal/model"
+	money "github.com/Dongy-s-Advanture/back-end/pkg/utils/money"
 	gomock "github.com/golang/mock/gomock"
 	primitive "go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -36,21 +37,6 @@ func (m *MockISellerService) EXPECT() *MockISellerServiceMockRecorder {
 	return m.recorder
 }
 
-// // AddTransaction mocks base method.
-// func (m *MockISellerService) AddTransaction(sellerID primitive.ObjectID, transaction *dto.Transaction) (*dto.Transaction, error) {
-// 	m.ctrl.T.Helper()
-// 	ret := m.ctrl.Call(m, "AddTransaction", sellerID, transaction)
-// 	ret0, _ := ret[0].(*dto.Transaction)
-// 	ret1, _ := ret[1].(error)
-// 	return ret0, ret1
-// }
-
-// // AddTransaction indicates an expected call of AddTransaction.
-// func (mr *MockISellerServiceMockRecorder) AddTransaction(sellerID, transaction interface{}) *gomock.Call {
-// 	mr.mock.ctrl.T.Helper()
-// 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTransaction", reflect.TypeOf((*MockISellerService)(nil).AddTransaction), sellerID, transaction)
-// }
-
 // CreateSellerData mocks base method.
 func (m *MockISellerService) CreateSellerData(seller *model.Seller) (*dto.Seller, error) {
 	m.ctrl.T.Helper()
@@ -67,10 +53,10 @@ func (mr *MockISellerServiceMockRecorder) CreateSellerData(seller interface{}) *
 }
 
 // GetSellerBalanceByID mocks base method.
-func (m *MockISellerService) GetSellerBalanceByID(sellerID primitive.ObjectID) (float64, error) {
+func (m *MockISellerService) GetSellerBalanceByID(sellerID primitive.ObjectID) (money.Money, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetSellerBalanceByID", sellerID)
-	ret0, _ := ret[0].(float64)
+	ret0, _ := ret[0].(money.Money)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -125,3 +111,17 @@ func (mr *MockISellerServiceMockRecorder) UpdateSeller(sellerID, updatedSeller i
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSeller", reflect.TypeOf((*MockISellerService)(nil).UpdateSeller), sellerID, updatedSeller)
 }
+
+// WithdrawSellerBalance mocks base method.
+func (m *MockISellerService) WithdrawSellerBalance(sellerID primitive.ObjectID, payment string, amount money.Money) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithdrawSellerBalance", sellerID, payment, amount)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithdrawSellerBalance indicates an expected call of WithdrawSellerBalance.
+func (mr *MockISellerServiceMockRecorder) WithdrawSellerBalance(sellerID, payment, amount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithdrawSellerBalance", reflect.TypeOf((*MockISellerService)(nil).WithdrawSellerBalance), sellerID, payment, amount)
+}
@@ -0,0 +1,36 @@
+package converter
+
+import (
+	"errors"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/model"
+	"github.com/jinzhu/copier"
+)
+
+func CommissionRuleModelToDTO(dataModel *model.CommissionRule) (*dto.CommissionRule, error) {
+	dataDTO := &dto.CommissionRule{}
+	err := copier.Copy(&dataDTO, &dataModel)
+	if err != nil {
+		return nil, errors.New("error converting commission rule model to dto")
+	}
+	return dataDTO, nil
+}
+
+func LedgerEntryModelToDTO(dataModel *model.LedgerEntry) (*dto.LedgerEntry, error) {
+	dataDTO := &dto.LedgerEntry{}
+	err := copier.Copy(&dataDTO, &dataModel)
+	if err != nil {
+		return nil, errors.New("error converting ledger entry model to dto")
+	}
+	return dataDTO, nil
+}
+
+func OrderFeesDTOToModel(dataDTO *dto.OrderFees) (*model.OrderFees, error) {
+	dataModel := &model.OrderFees{}
+	err := copier.Copy(&dataModel, &dataDTO)
+	if err != nil {
+		return nil, errors.New("error converting order fees dto to model")
+	}
+	return dataModel, nil
+}
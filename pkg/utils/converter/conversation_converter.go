@@ -0,0 +1,27 @@
+package converter
+
+import (
+	"errors"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/model"
+	"github.com/jinzhu/copier"
+)
+
+func ConversationModelToDTO(dataModel *model.Conversation) (*dto.Conversation, error) {
+	dataDTO := &dto.Conversation{}
+	err := copier.CopyWithOption(&dataDTO, &dataModel, copier.Option{DeepCopy: true})
+	if err != nil {
+		return nil, errors.New("error converting conversation model to dto")
+	}
+	return dataDTO, nil
+}
+
+func MessageModelToDTO(dataModel *model.Message) (*dto.Message, error) {
+	dataDTO := &dto.Message{}
+	err := copier.CopyWithOption(&dataDTO, &dataModel, copier.Option{DeepCopy: true})
+	if err != nil {
+		return nil, errors.New("error converting message model to dto")
+	}
+	return dataDTO, nil
+}
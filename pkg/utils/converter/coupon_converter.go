@@ -0,0 +1,27 @@
+package converter
+
+import (
+	"errors"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/model"
+	"github.com/jinzhu/copier"
+)
+
+func CouponModelToDTO(dataModel *model.Coupon) (*dto.Coupon, error) {
+	dataDTO := &dto.Coupon{}
+	err := copier.Copy(&dataDTO, &dataModel)
+	if err != nil {
+		return nil, errors.New("error converting coupon model to dto")
+	}
+	return dataDTO, nil
+}
+
+func CouponDTOToModel(dataDTO *dto.Coupon) (*model.Coupon, error) {
+	dataModel := &model.Coupon{}
+	err := copier.CopyWithOption(&dataModel, &dataDTO, copier.Option{DeepCopy: true})
+	if err != nil {
+		return nil, errors.New("error converting coupon dto to model")
+	}
+	return dataModel, nil
+}
@@ -0,0 +1,27 @@
+package converter
+
+import (
+	"errors"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/model"
+	"github.com/jinzhu/copier"
+)
+
+func ImageVariantsModelToDTO(dataModel *model.ImageVariants) (*dto.ImageVariants, error) {
+	dataDTO := &dto.ImageVariants{}
+	err := copier.Copy(&dataDTO, &dataModel)
+	if err != nil {
+		return nil, errors.New("error converting image variants model to dto")
+	}
+	return dataDTO, nil
+}
+
+func ImageVariantsDTOToModel(dataDTO *dto.ImageVariants) (*model.ImageVariants, error) {
+	dataModel := &model.ImageVariants{}
+	err := copier.CopyWithOption(&dataModel, &dataDTO, copier.Option{DeepCopy: true})
+	if err != nil {
+		return nil, errors.New("error converting image variants dto to model")
+	}
+	return dataModel, nil
+}
@@ -0,0 +1,36 @@
+package converter
+
+import (
+	"errors"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/model"
+	"github.com/jinzhu/copier"
+)
+
+func NotificationModelToDTO(dataModel *model.Notification) (*dto.Notification, error) {
+	dataDTO := &dto.Notification{}
+	err := copier.CopyWithOption(&dataDTO, &dataModel, copier.Option{DeepCopy: true})
+	if err != nil {
+		return nil, errors.New("error converting notification model to dto")
+	}
+	return dataDTO, nil
+}
+
+func NotificationDTOToModel(dataDTO *dto.Notification) (*model.Notification, error) {
+	dataModel := &model.Notification{}
+	err := copier.CopyWithOption(&dataModel, &dataDTO, copier.Option{DeepCopy: true})
+	if err != nil {
+		return nil, errors.New("error converting notification dto to model")
+	}
+	return dataModel, nil
+}
+
+func NotificationPreferenceModelToDTO(dataModel *model.NotificationPreference) (*dto.NotificationPreference, error) {
+	dataDTO := &dto.NotificationPreference{}
+	err := copier.CopyWithOption(&dataDTO, &dataModel, copier.Option{DeepCopy: true})
+	if err != nil {
+		return nil, errors.New("error converting notification preference model to dto")
+	}
+	return dataDTO, nil
+}
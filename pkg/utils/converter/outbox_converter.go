@@ -0,0 +1,18 @@
+package converter
+
+import (
+	"errors"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/model"
+	"github.com/jinzhu/copier"
+)
+
+func OutboxEventModelToDTO(dataModel *model.OutboxEvent) (*dto.OutboxEvent, error) {
+	dataDTO := &dto.OutboxEvent{}
+	err := copier.CopyWithOption(&dataDTO, &dataModel, copier.Option{DeepCopy: true})
+	if err != nil {
+		return nil, errors.New("error converting outbox event model to dto")
+	}
+	return dataDTO, nil
+}
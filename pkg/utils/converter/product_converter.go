@@ -14,6 +14,11 @@ func ProductModelToDTO(dataModel *model.Product) (*dto.Product, error) {
 	if err != nil {
 		return nil, errors.New("error converting product model to dto")
 	}
+	if dataModel.Rating != nil {
+		if dataDTO.Rating, err = RatingModelToDTO(dataModel.Rating); err != nil {
+			return nil, err
+		}
+	}
 	return dataDTO, nil
 }
 
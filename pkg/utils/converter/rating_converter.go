@@ -0,0 +1,26 @@
+package converter
+
+import (
+	"errors"
+	"math"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/model"
+	"github.com/jinzhu/copier"
+)
+
+// RatingModelToDTO returns an empty rating for sellers without reviews
+func RatingModelToDTO(dataModel *model.Rating) (*dto.Rating, error) {
+	dataDTO := &dto.Rating{}
+	if dataModel == nil {
+		return dataDTO, nil
+	}
+	err := copier.CopyWithOption(&dataDTO, &dataModel, copier.Option{DeepCopy: true})
+	if err != nil {
+		return nil, errors.New("error converting rating model to dto")
+	}
+	if dataDTO.Count > 0 {
+		dataDTO.Average = math.Round(float64(dataDTO.Sum)/float64(dataDTO.Count)*100) / 100
+	}
+	return dataDTO, nil
+}
@@ -14,6 +14,7 @@ func ReviewModelToDTO(dataModel *model.Review) (*dto.Review, error) {
 	if err != nil {
 		return nil, errors.New("error converting review model to dto")
 	}
+	dataDTO.Verified = !dataModel.OrderID.IsZero()
 	return dataDTO, nil
 }
 
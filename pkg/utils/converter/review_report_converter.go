@@ -0,0 +1,18 @@
+package converter
+
+import (
+	"errors"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/model"
+	"github.com/jinzhu/copier"
+)
+
+func ReviewReportModelToDTO(dataModel *model.ReviewReport) (*dto.ReviewReport, error) {
+	dataDTO := &dto.ReviewReport{}
+	err := copier.CopyWithOption(&dataDTO, &dataModel, copier.Option{DeepCopy: true})
+	if err != nil {
+		return nil, errors.New("error converting review report model to dto")
+	}
+	return dataDTO, nil
+}
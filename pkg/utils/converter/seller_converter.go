@@ -14,6 +14,11 @@ func SellerModelToDTO(dataModel *model.Seller) (*dto.Seller, error) {
 	if err != nil {
 		return nil, errors.New("error converting seller model to dto")
 	}
+	if dataModel.Rating != nil {
+		if dataDTO.Rating, err = RatingModelToDTO(dataModel.Rating); err != nil {
+			return nil, err
+		}
+	}
 	return dataDTO, nil
 }
 
@@ -0,0 +1,189 @@
+// Package cron works out when scheduled jobs are due. Schedules are written
+// in the standard five fields, minute hour day-of-month month day-of-week,
+// each a *, a number, a range or a list of those with an optional /step.
+// "@hourly", "@daily", "@weekly", "@monthly" and "@every <duration>" are
+// accepted too.
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidSpec = errors.New("invalid cron schedule")
+
+// Schedule is when a job runs
+type Schedule interface {
+	// Next is the first run strictly after t, or the zero time if there is
+	// none within five years
+	Next(t time.Time) time.Time
+}
+
+// Every runs every d, at multiples of d since the zero time so every
+// replica works out the same runs
+func Every(d time.Duration) Schedule {
+	return every(d)
+}
+
+type every time.Duration
+
+func (e every) Next(t time.Time) time.Time {
+	d := time.Duration(e)
+	return t.Truncate(d).Add(d)
+}
+
+// fields is a parsed five field schedule, a bit per allowed value
+type fields struct {
+	minute, hour, dom, month, dow uint64
+	// Restricted day fields match when either matches, like in cron
+	domAny, dowAny bool
+}
+
+type bounds struct {
+	min, max int
+}
+
+var (
+	minuteBounds = bounds{0, 59}
+	hourBounds   = bounds{0, 23}
+	domBounds    = bounds{1, 31}
+	monthBounds  = bounds{1, 12}
+	// 7 is Sunday as well as 0
+	dowBounds = bounds{0, 7}
+)
+
+var descriptors = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+// Parse reads a schedule spec. Times are matched in the location of the time
+// passed to Next.
+func Parse(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if d, ok := strings.CutPrefix(spec, "@every "); ok {
+		duration, err := time.ParseDuration(strings.TrimSpace(d))
+		if err != nil || duration <= 0 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidSpec, spec)
+		}
+		return Every(duration), nil
+	}
+	if expanded, ok := descriptors[spec]; ok {
+		spec = expanded
+	}
+
+	parts := strings.Fields(spec)
+	if len(parts) != 5 {
+		return nil, fmt.Errorf("%w: %q needs 5 fields", ErrInvalidSpec, spec)
+	}
+	var f fields
+	var err error
+	if f.minute, err = parseField(parts[0], minuteBounds); err != nil {
+		return nil, err
+	}
+	if f.hour, err = parseField(parts[1], hourBounds); err != nil {
+		return nil, err
+	}
+	if f.dom, err = parseField(parts[2], domBounds); err != nil {
+		return nil, err
+	}
+	if f.month, err = parseField(parts[3], monthBounds); err != nil {
+		return nil, err
+	}
+	if f.dow, err = parseField(parts[4], dowBounds); err != nil {
+		return nil, err
+	}
+	if f.dow&(1<<7) != 0 {
+		f.dow |= 1
+	}
+	f.domAny, f.dowAny = parts[2] == "*", parts[4] == "*"
+	return f, nil
+}
+
+// MustParse is Parse for schedules known to be valid
+func MustParse(spec string) Schedule {
+	s, err := Parse(spec)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func parseField(field string, b bounds) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if r, s, ok := strings.Cut(part, "/"); ok {
+			n, err := strconv.Atoi(s)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("%w: bad step in %q", ErrInvalidSpec, field)
+			}
+			rangePart, step = r, n
+		}
+
+		lo, hi := b.min, b.max
+		if rangePart != "*" {
+			from, to, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = strconv.Atoi(from); err != nil {
+				return 0, fmt.Errorf("%w: %q", ErrInvalidSpec, field)
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(to); err != nil {
+					return 0, fmt.Errorf("%w: %q", ErrInvalidSpec, field)
+				}
+			} else if step > 1 {
+				// "5/15" runs from 5 to the end
+				hi = b.max
+			}
+		}
+		if lo < b.min || hi > b.max || lo > hi {
+			return 0, fmt.Errorf("%w: %q is out of range %d-%d", ErrInvalidSpec, field, b.min, b.max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+func (f fields) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		switch {
+		case f.month&(1<<int(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !f.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case f.hour&(1<<t.Hour()) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case f.minute&(1<<t.Minute()) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (f fields) dayMatches(t time.Time) bool {
+	dom := f.dom&(1<<t.Day()) != 0
+	dow := f.dow&(1<<int(t.Weekday())) != 0
+	switch {
+	case f.domAny && f.dowAny:
+		return true
+	case f.domAny:
+		return dow
+	case f.dowAny:
+		return dom
+	}
+	return dom || dow
+}
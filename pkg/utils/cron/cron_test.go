@@ -0,0 +1,55 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNext(t *testing.T) {
+	bangkok := time.FixedZone("ICT", 7*60*60)
+	// A Friday
+	from := time.Date(2025, 1, 31, 10, 7, 30, 0, bangkok)
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2025, 1, 31, 10, 8, 0, 0, bangkok)},
+		{"*/15 * * * *", time.Date(2025, 1, 31, 10, 15, 0, 0, bangkok)},
+		{"5/20 9-17 * * *", time.Date(2025, 1, 31, 10, 25, 0, 0, bangkok)},
+		{"0 3 * * *", time.Date(2025, 2, 1, 3, 0, 0, 0, bangkok)},
+		{"@daily", time.Date(2025, 2, 1, 0, 0, 0, 0, bangkok)},
+		{"30 8 * * 1-5", time.Date(2025, 2, 3, 8, 30, 0, 0, bangkok)},
+		// Sunday as 7
+		{"0 0 * * 7", time.Date(2025, 2, 2, 0, 0, 0, 0, bangkok)},
+		{"0 0 30 * *", time.Date(2025, 3, 30, 0, 0, 0, 0, bangkok)},
+		// Day of month or day of week when both are set
+		{"0 12 15 * 6", time.Date(2025, 2, 1, 12, 0, 0, 0, bangkok)},
+		{"0 0 1,15 3 *", time.Date(2025, 3, 1, 0, 0, 0, 0, bangkok)},
+	}
+	for _, tt := range tests {
+		schedule, err := Parse(tt.spec)
+		assert.NoError(t, err, tt.spec)
+		assert.Equal(t, tt.want, schedule.Next(from), tt.spec)
+	}
+
+	// There is no 31 February
+	assert.True(t, MustParse("0 0 31 2 *").Next(from).IsZero())
+}
+
+func TestEvery(t *testing.T) {
+	schedule, err := Parse("@every 5m")
+	assert.NoError(t, err)
+	from := time.Date(2025, 1, 31, 10, 7, 30, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 1, 31, 10, 10, 0, 0, time.UTC), schedule.Next(from))
+	assert.Equal(t, time.Date(2025, 1, 31, 10, 15, 0, 0, time.UTC), schedule.Next(schedule.Next(from)))
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "0 0 0 * *", "5-1 * * * *", "*/0 * * * *", "a * * * *", "@every", "@every -1m", "@yearly"} {
+		_, err := Parse(spec)
+		assert.ErrorIs(t, err, ErrInvalidSpec, spec)
+	}
+}
@@ -0,0 +1,99 @@
+// Package geo stores locations as GeoJSON points so MongoDB can index and
+// search them with a 2dsphere index.
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// earthRadius is the mean radius of the earth in meters
+const earthRadius = 6371008.8
+
+// Point is a GeoJSON point. Coordinates are longitude then latitude, the
+// order GeoJSON and MongoDB expect.
+type Point struct {
+	Type        string    `json:"type" bson:"type"`
+	Coordinates []float64 `json:"coordinates" bson:"coordinates"`
+}
+
+// NewPoint returns the point at lat, lng
+func NewPoint(lat float64, lng float64) Point {
+	return Point{Type: "Point", Coordinates: []float64{lng, lat}}
+}
+
+// Parse reads "lat,lng" such as "13.7563,100.5018"
+func Parse(s string) (Point, error) {
+	latStr, lngStr, ok := strings.Cut(s, ",")
+	if !ok {
+		return Point{}, fmt.Errorf("invalid location %q, expected lat,lng", s)
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(latStr), 64)
+	if err != nil {
+		return Point{}, fmt.Errorf("invalid latitude %q", latStr)
+	}
+	lng, err := strconv.ParseFloat(strings.TrimSpace(lngStr), 64)
+	if err != nil {
+		return Point{}, fmt.Errorf("invalid longitude %q", lngStr)
+	}
+	p := NewPoint(lat, lng)
+	return p, p.Validate()
+}
+
+func (p Point) Lat() float64 {
+	if len(p.Coordinates) < 2 {
+		return 0
+	}
+	return p.Coordinates[1]
+}
+
+func (p Point) Lng() float64 {
+	if len(p.Coordinates) < 2 {
+		return 0
+	}
+	return p.Coordinates[0]
+}
+
+// Validate reports whether p is a point MongoDB will accept
+func (p Point) Validate() error {
+	if p.Type != "Point" || len(p.Coordinates) != 2 {
+		return errors.New("location must be a GeoJSON point")
+	}
+	if lat := p.Lat(); math.IsNaN(lat) || lat < -90 || lat > 90 {
+		return fmt.Errorf("latitude %v is out of range", lat)
+	}
+	if lng := p.Lng(); math.IsNaN(lng) || lng < -180 || lng > 180 {
+		return fmt.Errorf("longitude %v is out of range", lng)
+	}
+	return nil
+}
+
+// Distance is the great-circle distance between a and b in meters
+func Distance(a Point, b Point) float64 {
+	lat1, lat2 := radians(a.Lat()), radians(b.Lat())
+	dLat, dLng := lat2-lat1, radians(b.Lng()-a.Lng())
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// Midpoint is the point halfway between a and b along the great circle
+func Midpoint(a Point, b Point) Point {
+	lat1, lng1 := radians(a.Lat()), radians(a.Lng())
+	lat2, dLng := radians(b.Lat()), radians(b.Lng()-a.Lng())
+	bx := math.Cos(lat2) * math.Cos(dLng)
+	by := math.Cos(lat2) * math.Sin(dLng)
+	lat := math.Atan2(math.Sin(lat1)+math.Sin(lat2), math.Sqrt((math.Cos(lat1)+bx)*(math.Cos(lat1)+bx)+by*by))
+	lng := lng1 + math.Atan2(by, math.Cos(lat1)+bx)
+	return NewPoint(degrees(lat), math.Mod(degrees(lng)+540, 360)-180)
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
@@ -0,0 +1,45 @@
+package geo
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	p, err := Parse("13.7563, 100.5018")
+	require.NoError(t, err)
+	assert.Equal(t, NewPoint(13.7563, 100.5018), p)
+	assert.Equal(t, []float64{100.5018, 13.7563}, p.Coordinates)
+
+	for _, input := range []string{"", "13.7", "north,east", "91,100", "13,181"} {
+		_, err := Parse(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestDistance(t *testing.T) {
+	siam := NewPoint(13.7460, 100.5340)
+	chatuchak := NewPoint(13.7999, 100.5500)
+
+	// About 6.2 km apart
+	assert.InDelta(t, 6230, Distance(siam, chatuchak), 50)
+	assert.Zero(t, Distance(siam, siam))
+	assert.InDelta(t, Distance(siam, chatuchak), Distance(chatuchak, siam), 1e-6)
+}
+
+func TestMidpoint(t *testing.T) {
+	siam := NewPoint(13.7460, 100.5340)
+	chatuchak := NewPoint(13.7999, 100.5500)
+
+	mid := Midpoint(siam, chatuchak)
+	assert.NoError(t, mid.Validate())
+	assert.InDelta(t, Distance(siam, mid), Distance(mid, chatuchak), 1)
+	assert.InDelta(t, Distance(siam, chatuchak)/2, Distance(siam, mid), 1)
+
+	// Across the antimeridian the longitude wraps around
+	mid = Midpoint(NewPoint(0, 179), NewPoint(0, -179))
+	assert.InDelta(t, 180, math.Abs(mid.Lng()), 1e-6)
+}
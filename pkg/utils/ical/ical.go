@@ -0,0 +1,146 @@
+// Package ical writes RFC 5545 iCalendar files
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Event is a VEVENT. An event without a Start time is an all-day event on
+// Date.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	Date        time.Time
+	Start       time.Time
+	End         time.Time
+	Created     time.Time
+	Modified    time.Time
+	// Revision of the event, calendar apps only take a change to an event
+	// they already have when it is higher
+	Sequence  int
+	Cancelled bool
+}
+
+// Calendar is a VCALENDAR of events
+type Calendar struct {
+	Name   string
+	Events []Event
+}
+
+const (
+	productID     = "-//Dongy's Adventure//Marketplace//EN"
+	utcFormat     = "20060102T150405Z"
+	dateFormat    = "20060102"
+	maxLineOctets = 75
+)
+
+// WriteTo writes the calendar with CRLF line endings and long lines folded
+func (c Calendar) WriteTo(w io.Writer) (int64, error) {
+	lw := &lineWriter{w: w}
+	lw.line("BEGIN:VCALENDAR")
+	lw.line("VERSION:2.0")
+	lw.line("PRODID:" + productID)
+	lw.line("CALSCALE:GREGORIAN")
+	lw.line("METHOD:PUBLISH")
+	if c.Name != "" {
+		lw.line("X-WR-CALNAME:" + escape(c.Name))
+	}
+	for _, event := range c.Events {
+		event.write(lw)
+	}
+	lw.line("END:VCALENDAR")
+	return lw.n, lw.err
+}
+
+func (e Event) write(lw *lineWriter) {
+	stamp := e.Modified
+	if stamp.IsZero() {
+		stamp = e.Created
+	}
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+
+	lw.line("BEGIN:VEVENT")
+	lw.line("UID:" + escape(e.UID))
+	lw.line("DTSTAMP:" + stamp.UTC().Format(utcFormat))
+	if e.Sequence > 0 {
+		lw.line(fmt.Sprintf("SEQUENCE:%d", e.Sequence))
+	}
+	if e.Start.IsZero() {
+		lw.line("DTSTART;VALUE=DATE:" + e.Date.Format(dateFormat))
+		lw.line("DTEND;VALUE=DATE:" + e.Date.AddDate(0, 0, 1).Format(dateFormat))
+	} else {
+		lw.line("DTSTART:" + e.Start.UTC().Format(utcFormat))
+		if !e.End.IsZero() {
+			lw.line("DTEND:" + e.End.UTC().Format(utcFormat))
+		}
+	}
+	lw.line("SUMMARY:" + escape(e.Summary))
+	if e.Description != "" {
+		lw.line("DESCRIPTION:" + escape(e.Description))
+	}
+	if e.Location != "" {
+		lw.line("LOCATION:" + escape(e.Location))
+	}
+	if !e.Created.IsZero() {
+		lw.line("CREATED:" + e.Created.UTC().Format(utcFormat))
+	}
+	if !e.Modified.IsZero() {
+		lw.line("LAST-MODIFIED:" + e.Modified.UTC().Format(utcFormat))
+	}
+	if e.Cancelled {
+		lw.line("STATUS:CANCELLED")
+	} else {
+		lw.line("STATUS:CONFIRMED")
+	}
+	lw.line("END:VEVENT")
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// escape escapes a TEXT value
+func escape(text string) string {
+	return escaper.Replace(text)
+}
+
+type lineWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+// line writes a content line, folding it into lines of at most 75 octets
+// without splitting a UTF-8 character
+func (lw *lineWriter) line(content string) {
+	var b strings.Builder
+	width := 0
+	for _, r := range content {
+		size := len(string(r))
+		if width+size > maxLineOctets {
+			b.WriteString("\r\n ")
+			// The leading space of a continuation line counts
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	b.WriteString("\r\n")
+	lw.write(b.String())
+}
+
+func (lw *lineWriter) write(s string) {
+	if lw.err != nil {
+		return
+	}
+	n, err := io.WriteString(lw.w, s)
+	lw.n += int64(n)
+	if err != nil {
+		lw.err = fmt.Errorf("failed to write calendar: %w", err)
+	}
+}
@@ -0,0 +1,69 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalendar(t *testing.T) {
+	start := time.Date(2025, 3, 3, 10, 0, 0, 0, time.FixedZone("ICT", 7*60*60))
+	calendar := Calendar{
+		Name: "Meet-ups",
+		Events: []Event{
+			{
+				UID:      "a@example.com",
+				Summary:  "Meet-up, order 42",
+				Location: "Siam Paragon; Gate 1",
+				Start:    start,
+				End:      start.Add(30 * time.Minute),
+				Created:  start.AddDate(0, 0, -1),
+			},
+			{UID: "b@example.com", Summary: "All day", Date: time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC), Created: start},
+		},
+	}
+
+	var b strings.Builder
+	_, err := calendar.WriteTo(&b)
+	assert.NoError(t, err)
+	out := b.String()
+
+	assert.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+	assert.Contains(t, out, "DTSTART:20250303T030000Z\r\nDTEND:20250303T033000Z\r\n")
+	assert.Contains(t, out, `SUMMARY:Meet-up\, order 42`)
+	assert.Contains(t, out, `LOCATION:Siam Paragon\; Gate 1`)
+	assert.Contains(t, out, "DTSTART;VALUE=DATE:20250304\r\nDTEND;VALUE=DATE:20250305\r\n")
+	assert.Contains(t, out, "STATUS:CONFIRMED\r\n")
+	assert.NotContains(t, out, "SEQUENCE:")
+}
+
+func TestCancelledEvent(t *testing.T) {
+	start := time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC)
+	calendar := Calendar{Events: []Event{{UID: "a@example.com", Summary: "Meet-up", Start: start, Sequence: 2, Cancelled: true}}}
+
+	var b strings.Builder
+	_, err := calendar.WriteTo(&b)
+	assert.NoError(t, err)
+	out := b.String()
+
+	assert.Contains(t, out, "SEQUENCE:2\r\n")
+	assert.Contains(t, out, "STATUS:CANCELLED\r\n")
+	assert.NotContains(t, out, "STATUS:CONFIRMED")
+}
+
+func TestLineFolding(t *testing.T) {
+	var b strings.Builder
+	lw := &lineWriter{w: &b}
+	lw.line("DESCRIPTION:" + strings.Repeat("ก", 40))
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+	assert.Len(t, lines, 2)
+	for _, line := range lines {
+		assert.LessOrEqual(t, len(line), maxLineOctets)
+	}
+	assert.True(t, strings.HasPrefix(lines[1], " "))
+	assert.Equal(t, "DESCRIPTION:"+strings.Repeat("ก", 40), lines[0]+lines[1][1:])
+}
@@ -0,0 +1,158 @@
+package imageproc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+
+	// Register decoders used by image.Decode
+	_ "image/gif"
+	_ "image/png"
+
+	_ "golang.org/x/image/webp"
+
+	"github.com/HugoSmits86/nativewebp"
+	"golang.org/x/image/draw"
+)
+
+type Format string
+
+const (
+	JPEG Format = "jpeg"
+	WebP Format = "webp"
+)
+
+// Upper bound on decoded pixels so a tiny file can't expand into gigabytes of memory.
+const maxPixels = 50_000_000
+
+// Rendition describes one output size. The longest side of the image is
+// scaled down to MaxDimension; smaller images are never upscaled.
+type Rendition struct {
+	Name         string
+	MaxDimension int
+}
+
+var DefaultRenditions = []Rendition{
+	{Name: "thumbnail", MaxDimension: 200},
+	{Name: "medium", MaxDimension: 640},
+	{Name: "large", MaxDimension: 1280},
+}
+
+type Variant struct {
+	Name        string
+	Data        []byte
+	ContentType string
+	Ext         string
+	Width       int
+	Height      int
+}
+
+type Options struct {
+	Format      Format
+	JPEGQuality int
+	Renditions  []Rendition
+}
+
+// Process decodes an uploaded image and returns one re-encoded variant per
+// rendition. Re-encoding only writes pixel data, so EXIF metadata (GPS
+// location, camera serials, ...) is dropped from every variant. The EXIF
+// orientation is applied to the pixels first so photos stay upright.
+func Process(data []byte, opts Options) ([]Variant, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("unsupported image: %v", err)
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return nil, errors.New("image dimensions are too large")
+	}
+
+	src, kind, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %v", err)
+	}
+	if kind == "jpeg" {
+		src = applyOrientation(src, exifOrientation(data))
+	}
+
+	renditions := opts.Renditions
+	if len(renditions) == 0 {
+		renditions = DefaultRenditions
+	}
+
+	variants := make([]Variant, 0, len(renditions))
+	for _, rendition := range renditions {
+		resized := resize(src, rendition.MaxDimension)
+		encoded, err := encode(resized, opts)
+		if err != nil {
+			return nil, err
+		}
+		encoded.Name = rendition.Name
+		variants = append(variants, *encoded)
+	}
+	return variants, nil
+}
+
+func resize(src image.Image, maxDimension int) image.Image {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if maxDimension <= 0 || (width <= maxDimension && height <= maxDimension) {
+		dst := image.NewRGBA(image.Rect(0, 0, width, height))
+		draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Src)
+		return dst
+	}
+
+	if width >= height {
+		height = max(1, height*maxDimension/width)
+		width = maxDimension
+	} else {
+		width = max(1, width*maxDimension/height)
+		height = maxDimension
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Src, nil)
+	return dst
+}
+
+func encode(img image.Image, opts Options) (*Variant, error) {
+	var buf bytes.Buffer
+	variant := &Variant{
+		Width:  img.Bounds().Dx(),
+		Height: img.Bounds().Dy(),
+	}
+
+	switch opts.Format {
+	case WebP:
+		if err := nativewebp.Encode(&buf, img, nil); err != nil {
+			return nil, fmt.Errorf("failed to encode webp: %v", err)
+		}
+		variant.ContentType = "image/webp"
+		variant.Ext = ".webp"
+	case JPEG, "":
+		quality := opts.JPEGQuality
+		if quality <= 0 || quality > 100 {
+			quality = 82
+		}
+		if err := jpeg.Encode(&buf, flatten(img), &jpeg.Options{Quality: quality}); err != nil {
+			return nil, fmt.Errorf("failed to encode jpeg: %v", err)
+		}
+		variant.ContentType = "image/jpeg"
+		variant.Ext = ".jpg"
+	default:
+		return nil, fmt.Errorf("unsupported output format: %s", opts.Format)
+	}
+
+	variant.Data = buf.Bytes()
+	return variant, nil
+}
+
+// flatten draws the image over a white background, JPEG has no alpha channel
+// and transparent PNGs would otherwise turn black.
+func flatten(img image.Image) image.Image {
+	dst := image.NewRGBA(img.Bounds())
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), img, img.Bounds().Min, draw.Over)
+	return dst
+}
@@ -0,0 +1,105 @@
+package imageproc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testImage(width, height int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), 128, 255})
+		}
+	}
+	return img
+}
+
+// jpegWithOrientation encodes a JPEG and splices in an APP1 EXIF segment
+// holding only the orientation tag.
+func jpegWithOrientation(t *testing.T, img image.Image, orientation uint16) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, nil))
+
+	tiff := []byte("II*\x00")
+	tiff = binary.LittleEndian.AppendUint32(tiff, 8)
+	tiff = binary.LittleEndian.AppendUint16(tiff, 1)
+	tiff = binary.LittleEndian.AppendUint16(tiff, orientationTag)
+	tiff = binary.LittleEndian.AppendUint16(tiff, 3) // SHORT
+	tiff = binary.LittleEndian.AppendUint32(tiff, 1)
+	tiff = binary.LittleEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0)
+	tiff = binary.LittleEndian.AppendUint32(tiff, 0)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	app1 := []byte{0xFF, 0xE1}
+	app1 = binary.BigEndian.AppendUint16(app1, uint16(len(segment)+2))
+	app1 = append(app1, segment...)
+
+	encoded := buf.Bytes()
+	out := append([]byte{}, encoded[:2]...)
+	out = append(out, app1...)
+	return append(out, encoded[2:]...)
+}
+
+func TestProcess_ResizesAndStripsExif(t *testing.T) {
+	data := jpegWithOrientation(t, testImage(400, 300), 6)
+	require.Equal(t, 6, exifOrientation(data))
+
+	variants, err := Process(data, Options{
+		Format: JPEG,
+		Renditions: []Rendition{
+			{Name: "thumbnail", MaxDimension: 100},
+			{Name: "large", MaxDimension: 1000},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, variants, 2)
+
+	// Rotated to portrait, then scaled so the long side fits
+	assert.Equal(t, "thumbnail", variants[0].Name)
+	assert.Equal(t, 75, variants[0].Width)
+	assert.Equal(t, 100, variants[0].Height)
+
+	// Never upscaled
+	assert.Equal(t, 300, variants[1].Width)
+	assert.Equal(t, 400, variants[1].Height)
+
+	for _, v := range variants {
+		assert.Equal(t, "image/jpeg", v.ContentType)
+		assert.False(t, bytes.Contains(v.Data, []byte("Exif")))
+		assert.Equal(t, 1, exifOrientation(v.Data))
+	}
+}
+
+func TestProcess_WebP(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, testImage(64, 32)))
+
+	variants, err := Process(buf.Bytes(), Options{
+		Format:     WebP,
+		Renditions: []Rendition{{Name: "thumbnail", MaxDimension: 32}},
+	})
+	require.NoError(t, err)
+	require.Len(t, variants, 1)
+	assert.Equal(t, ".webp", variants[0].Ext)
+	assert.Equal(t, 32, variants[0].Width)
+	assert.Equal(t, 16, variants[0].Height)
+
+	_, format, err := image.DecodeConfig(bytes.NewReader(variants[0].Data))
+	require.NoError(t, err)
+	assert.Equal(t, "webp", format)
+}
+
+func TestProcess_RejectsNonImage(t *testing.T) {
+	_, err := Process([]byte("definitely not an image"), Options{})
+	assert.Error(t, err)
+}
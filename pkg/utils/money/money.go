@@ -0,0 +1,217 @@
+// Package money stores amounts as integer minor units so sums and balances
+// never pick up floating point rounding errors.
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is the currency of every amount on the platform
+const DefaultCurrency = "THB"
+
+// ErrUnsupportedCurrency rejects amounts decoded in another currency, which
+// would otherwise panic once mixed with the platform's amounts
+var ErrUnsupportedCurrency = errors.New("money: only " + DefaultCurrency + " is supported")
+
+// minorUnits is how many minor units (satang) make one major unit (baht)
+const minorUnits = 100
+
+// Money is an amount in the currency's minor unit, satang for baht. Amounts
+// of different currencies must not be mixed.
+type Money struct {
+	Amount   int64  `json:"amount" bson:"amount"`
+	Currency string `json:"currency" bson:"currency"`
+}
+
+// New returns amount satang in the default currency
+func New(amount int64) Money {
+	return Money{Amount: amount, Currency: DefaultCurrency}
+}
+
+// FromMajor converts an amount in baht, rounding to the nearest satang
+func FromMajor(major float64) Money {
+	return New(int64(math.Round(major * minorUnits)))
+}
+
+// Parse reads a decimal amount in baht such as "1250" or "199.50" exactly
+func Parse(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	whole, fraction, _ := strings.Cut(strings.TrimPrefix(s, "-"), ".")
+	if whole == "" && fraction == "" || len(fraction) > 2 {
+		return Money{}, fmt.Errorf("invalid amount: %q", s)
+	}
+	fraction += strings.Repeat("0", 2-len(fraction))
+
+	var major, minor int64
+	var err error
+	if whole != "" {
+		if major, err = strconv.ParseInt(whole, 10, 64); err != nil {
+			return Money{}, fmt.Errorf("invalid amount: %q", s)
+		}
+	}
+	if minor, err = strconv.ParseInt(fraction, 10, 64); err != nil || minor < 0 {
+		return Money{}, fmt.Errorf("invalid amount: %q", s)
+	}
+	amount := major*minorUnits + minor
+	if negative {
+		amount = -amount
+	}
+	return New(amount), nil
+}
+
+// Major is the amount in baht, for display and ratios only
+func (m Money) Major() float64 {
+	return float64(m.Amount) / minorUnits
+}
+
+func (m Money) Add(other Money) Money {
+	return Money{Amount: m.Amount + other.Amount, Currency: m.currencyWith(other)}
+}
+
+func (m Money) Sub(other Money) Money {
+	return Money{Amount: m.Amount - other.Amount, Currency: m.currencyWith(other)}
+}
+
+func (m Money) Mul(n int) Money {
+	return Money{Amount: m.Amount * int64(n), Currency: m.CurrencyCode()}
+}
+
+func (m Money) Neg() Money {
+	return Money{Amount: -m.Amount, Currency: m.CurrencyCode()}
+}
+
+// Percent is percent of m rounded to the nearest satang
+func (m Money) Percent(percent float64) Money {
+	return Money{Amount: int64(math.Round(float64(m.Amount) * percent / 100)), Currency: m.CurrencyCode()}
+}
+
+// Share is the part of m in proportion part/whole, rounded to the nearest satang
+func (m Money) Share(part Money, whole Money) Money {
+	if whole.Amount == 0 {
+		return Money{Currency: m.CurrencyCode()}
+	}
+	ratio := float64(part.Amount) / float64(whole.Amount)
+	return Money{Amount: int64(math.Round(float64(m.Amount) * ratio)), Currency: m.CurrencyCode()}
+}
+
+// Cmp returns -1, 0 or +1 as m is less than, equal to or greater than other
+func (m Money) Cmp(other Money) int {
+	m.currencyWith(other)
+	switch {
+	case m.Amount < other.Amount:
+		return -1
+	case m.Amount > other.Amount:
+		return 1
+	}
+	return 0
+}
+
+func (m Money) LessThan(other Money) bool {
+	return m.Cmp(other) < 0
+}
+
+// IsZero also lets the bson encoder honour omitempty
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.Amount > 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+func Min(a Money, b Money) Money {
+	if b.LessThan(a) {
+		return b
+	}
+	return a
+}
+
+func Max(a Money, b Money) Money {
+	if a.LessThan(b) {
+		return b
+	}
+	return a
+}
+
+func (m Money) String() string {
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	return fmt.Sprintf("%s%d.%02d %s", sign, amount/minorUnits, amount%minorUnits, m.CurrencyCode())
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	type plain Money
+	return json.Marshal(plain{Amount: m.Amount, Currency: m.CurrencyCode()})
+}
+
+// UnmarshalJSON accepts {"amount": 19950, "currency": "THB"} in satang, or
+// a bare number or string such as 199.5 or "199.50" in baht. Currencies other
+// than DefaultCurrency are rejected.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	trimmed := strings.TrimSpace(string(data))
+	if trimmed == "null" {
+		return nil
+	}
+	if strings.HasPrefix(trimmed, "{") {
+		type plain Money
+		var p plain
+		if err := json.Unmarshal(data, &p); err != nil {
+			return err
+		}
+		*m = Money(p)
+		m.Currency = strings.ToUpper(m.Currency)
+		if m.Currency == "" {
+			m.Currency = DefaultCurrency
+		}
+		if m.Currency != DefaultCurrency {
+			return ErrUnsupportedCurrency
+		}
+		return nil
+	}
+	parsed, err := Parse(strings.Trim(trimmed, `"`))
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// UnmarshalParam binds form and query values in baht
+func (m *Money) UnmarshalParam(param string) error {
+	parsed, err := Parse(param)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// CurrencyCode is the ISO 4217 code, zero values are in the default currency
+func (m Money) CurrencyCode() string {
+	if m.Currency == "" {
+		return DefaultCurrency
+	}
+	return m.Currency
+}
+
+// currencyWith panics on mixed currencies, like adding metres to seconds
+func (m Money) currencyWith(other Money) string {
+	currency, otherCurrency := m.CurrencyCode(), other.CurrencyCode()
+	if currency != otherCurrency {
+		panic(errors.New("money: mixing " + currency + " and " + otherCurrency))
+	}
+	return currency
+}
@@ -0,0 +1,87 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestParse(t *testing.T) {
+	for input, expected := range map[string]int64{
+		"1250":    125000,
+		"199.5":   19950,
+		"199.50":  19950,
+		"0.01":    1,
+		".5":      50,
+		"-12.34":  -1234,
+		" 10.00 ": 1000,
+	} {
+		m, err := Parse(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, New(expected), m, input)
+	}
+
+	for _, input := range []string{"", "abc", "1.234", "1.-5", "1e5"} {
+		_, err := Parse(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	price := FromMajor(0.1)
+	total := New(0)
+	for i := 0; i < 10; i++ {
+		total = total.Add(price)
+	}
+	// Ten 0.1 baht items are exactly one baht, unlike with float64
+	assert.Equal(t, New(100), total)
+
+	assert.Equal(t, New(2997), New(999).Mul(3))
+	assert.Equal(t, New(1485), New(9900).Percent(15))
+	assert.Equal(t, New(333), New(1000).Share(New(100), New(300)))
+	assert.Equal(t, New(-5), New(5).Neg())
+	assert.True(t, New(1).LessThan(New(2)))
+	assert.Equal(t, New(1), Min(New(1), New(2)))
+	assert.Equal(t, "-12.05 THB", New(-1205).String())
+
+	assert.Panics(t, func() { New(1).Add(Money{Amount: 1, Currency: "USD"}) })
+}
+
+func TestJSON(t *testing.T) {
+	data, err := json.Marshal(Money{Amount: 19950})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"amount":19950,"currency":"THB"}`, string(data))
+
+	for input, expected := range map[string]Money{
+		`{"amount":19950,"currency":"THB"}`: New(19950),
+		`{"amount":19950}`:                  New(19950),
+		`{"amount":19950,"currency":"thb"}`: New(19950),
+		`199.5`:                             New(19950),
+		`"199.50"`:                          New(19950),
+	} {
+		var m Money
+		require.NoError(t, json.Unmarshal([]byte(input), &m), input)
+		assert.Equal(t, expected, m, input)
+	}
+
+	var m Money
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"amount":100,"currency":"USD"}`), &m), ErrUnsupportedCurrency)
+}
+
+func TestBSONOmitEmpty(t *testing.T) {
+	type doc struct {
+		Price Money `bson:"price,omitempty"`
+	}
+	data, err := bson.Marshal(doc{})
+	require.NoError(t, err)
+	assert.Equal(t, bson.Raw(data).String(), `{}`)
+
+	data, err = bson.Marshal(doc{Price: New(100)})
+	require.NoError(t, err)
+	var decoded doc
+	require.NoError(t, bson.Unmarshal(data, &decoded))
+	assert.Equal(t, New(100), decoded.Price)
+}
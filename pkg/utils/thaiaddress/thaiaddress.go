@@ -0,0 +1,271 @@
+// Package thaiaddress checks and normalizes Thai addresses against an
+// embedded dataset of provinces, districts, subdistricts and postcodes.
+//
+// data/provinces.json lists every province with its postcode prefixes, and
+// districts and subdistricts are imported into it with cmd/addressdata. A
+// province with districts only accepts the exact postcodes of its
+// subdistricts, and of the chosen district and subdistrict when given. For a
+// province without them, only the postcode prefix is checked.
+package thaiaddress
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrUnknownProvince    = errors.New("unknown province")
+	ErrUnknownDistrict    = errors.New("unknown district")
+	ErrUnknownSubdistrict = errors.New("unknown subdistrict")
+	ErrInvalidZip         = errors.New("zip must be 5 digits")
+	ErrZipMismatch        = errors.New("zip is not in this province")
+)
+
+//go:embed data/provinces.json
+var provincesJSON []byte
+
+var zipPattern = regexp.MustCompile(`^[0-9]{5}$`)
+
+type Province struct {
+	// ISO 3166-2:TH code without the TH- prefix
+	Code        string     `json:"code"`
+	NameTH      string     `json:"nameTH"`
+	NameEN      string     `json:"nameEN"`
+	ZipPrefixes []string   `json:"zipPrefixes"`
+	Aliases     []string   `json:"aliases,omitempty"`
+	Districts   []District `json:"districts,omitempty"`
+}
+
+type District struct {
+	NameTH       string        `json:"nameTH"`
+	NameEN       string        `json:"nameEN"`
+	Subdistricts []Subdistrict `json:"subdistricts,omitempty"`
+}
+
+type Subdistrict struct {
+	NameTH string `json:"nameTH"`
+	NameEN string `json:"nameEN"`
+	Zip    string `json:"zip"`
+}
+
+// Address is the part of an address that is checked. Empty fields are
+// left out of the check.
+type Address struct {
+	Province    string
+	District    string
+	Subdistrict string
+	Zip         string
+}
+
+// Match is a place a postcode belongs to. District and Subdistrict are
+// empty when the dataset doesn't go that deep.
+type Match struct {
+	Province    *Province
+	District    *District
+	Subdistrict *Subdistrict
+}
+
+type Dataset struct {
+	Provinces []Province
+}
+
+var (
+	defaultOnce    sync.Once
+	defaultDataset *Dataset
+)
+
+// Default is the embedded dataset
+func Default() *Dataset {
+	defaultOnce.Do(func() {
+		dataset, err := Load(provincesJSON)
+		if err != nil {
+			panic(fmt.Sprintf("invalid embedded address data: %v", err))
+		}
+		defaultDataset = dataset
+	})
+	return defaultDataset
+}
+
+func Load(data []byte) (*Dataset, error) {
+	var provinces []Province
+	if err := json.Unmarshal(data, &provinces); err != nil {
+		return nil, err
+	}
+	return &Dataset{Provinces: provinces}, nil
+}
+
+// Province finds a province by its Thai or English name or an alias
+func (d *Dataset) Province(name string) (*Province, bool) {
+	key := provinceKey(name)
+	if key == "" {
+		return nil, false
+	}
+	for i := range d.Provinces {
+		p := &d.Provinces[i]
+		for _, candidate := range append([]string{p.NameTH, p.NameEN}, p.Aliases...) {
+			if provinceKey(candidate) == key {
+				return p, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// District finds a district of the province by its Thai or English name
+func (p *Province) District(name string) (*District, bool) {
+	key := placeKey(name, "เขต", "อำเภอ", "อ.", "amphoe", "khet", "district")
+	for i := range p.Districts {
+		d := &p.Districts[i]
+		if placeKey(d.NameTH) == key || placeKey(d.NameEN) == key {
+			return d, true
+		}
+	}
+	return nil, false
+}
+
+// Subdistrict finds a subdistrict of the district by its Thai or English name
+func (d *District) Subdistrict(name string) (*Subdistrict, bool) {
+	key := placeKey(name, "แขวง", "ตำบล", "ต.", "tambon", "khwaeng", "subdistrict")
+	for i := range d.Subdistricts {
+		s := &d.Subdistricts[i]
+		if placeKey(s.NameTH) == key || placeKey(s.NameEN) == key {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+// HasZip reports whether zip can be in the province. Only the prefix is
+// known for provinces without districts.
+func (p *Province) HasZip(zip string) bool {
+	if len(p.Districts) > 0 {
+		for i := range p.Districts {
+			if p.Districts[i].hasZip(zip) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, prefix := range p.ZipPrefixes {
+		if strings.HasPrefix(zip, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// LookupZip returns the places with the postcode, as precise as the
+// dataset allows
+func (d *Dataset) LookupZip(zip string) ([]Match, error) {
+	if !zipPattern.MatchString(zip) {
+		return nil, ErrInvalidZip
+	}
+	var matches []Match
+	for i := range d.Provinces {
+		p := &d.Provinces[i]
+		if !p.HasZip(zip) {
+			continue
+		}
+		if len(p.Districts) == 0 {
+			matches = append(matches, Match{Province: p})
+			continue
+		}
+		for j := range p.Districts {
+			district := &p.Districts[j]
+			for k := range district.Subdistricts {
+				if subdistrict := &district.Subdistricts[k]; subdistrict.Zip == zip {
+					matches = append(matches, Match{Province: p, District: district, Subdistrict: subdistrict})
+				}
+			}
+		}
+	}
+	return matches, nil
+}
+
+// Normalize checks that the parts of the address agree with each other and
+// returns them with the province, district and subdistrict spelled as in
+// the dataset, in Thai
+func (d *Dataset) Normalize(addr Address) (Address, error) {
+	addr.Zip = strings.TrimSpace(addr.Zip)
+	if addr.Zip != "" && !zipPattern.MatchString(addr.Zip) {
+		return addr, ErrInvalidZip
+	}
+	if strings.TrimSpace(addr.Province) == "" {
+		if addr.Zip != "" {
+			if matches, _ := d.LookupZip(addr.Zip); len(matches) == 0 {
+				return addr, fmt.Errorf("%w: no province has zip %s", ErrZipMismatch, addr.Zip)
+			}
+		}
+		return addr, nil
+	}
+
+	province, ok := d.Province(addr.Province)
+	if !ok {
+		return addr, fmt.Errorf("%w: %q", ErrUnknownProvince, addr.Province)
+	}
+	addr.Province = province.NameTH
+	if addr.Zip != "" && !province.HasZip(addr.Zip) {
+		return addr, fmt.Errorf("%w: %s is not in %s", ErrZipMismatch, addr.Zip, province.NameEN)
+	}
+	if len(province.Districts) == 0 || strings.TrimSpace(addr.District) == "" {
+		return addr, nil
+	}
+
+	district, ok := province.District(addr.District)
+	if !ok {
+		return addr, fmt.Errorf("%w: %q in %s", ErrUnknownDistrict, addr.District, province.NameEN)
+	}
+	addr.District = district.NameTH
+	if addr.Zip != "" && len(district.Subdistricts) > 0 && !district.hasZip(addr.Zip) {
+		return addr, fmt.Errorf("%w: %s is not in %s", ErrZipMismatch, addr.Zip, district.NameEN)
+	}
+	if len(district.Subdistricts) == 0 || strings.TrimSpace(addr.Subdistrict) == "" {
+		return addr, nil
+	}
+
+	subdistrict, ok := district.Subdistrict(addr.Subdistrict)
+	if !ok {
+		return addr, fmt.Errorf("%w: %q in %s", ErrUnknownSubdistrict, addr.Subdistrict, district.NameEN)
+	}
+	addr.Subdistrict = subdistrict.NameTH
+	if addr.Zip != "" && subdistrict.Zip != addr.Zip {
+		return addr, fmt.Errorf("%w: %s is not in %s", ErrZipMismatch, addr.Zip, subdistrict.NameEN)
+	}
+	return addr, nil
+}
+
+func (d *District) hasZip(zip string) bool {
+	for _, s := range d.Subdistricts {
+		if s.Zip == zip {
+			return true
+		}
+	}
+	return false
+}
+
+// provinceKey matches "จ.ลพบุรี", "Lop Buri province" and "lopburi"
+func provinceKey(name string) string {
+	return placeKey(name, "จังหวัด", "จ.", "changwat", "province")
+}
+
+// placeKey is name without case, spacing and the first of affixes it starts
+// or ends with, such as "อำเภอ" or "district"
+func placeKey(name string, affixes ...string) string {
+	key := strings.ToLower(strings.TrimSpace(name))
+	for _, affix := range affixes {
+		if strings.HasPrefix(key, affix) {
+			key = strings.TrimPrefix(key, affix)
+			break
+		}
+		if strings.HasSuffix(key, affix) {
+			key = strings.TrimSuffix(key, affix)
+			break
+		}
+	}
+	return strings.NewReplacer(" ", "", "-", "", ".", "", "'", "").Replace(key)
+}
@@ -0,0 +1,88 @@
+package thaiaddress
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testData = `[
+  {"code": "10", "nameTH": "กรุงเทพมหานคร", "nameEN": "Bangkok", "zipPrefixes": ["10"], "aliases": ["กทม"],
+   "districts": [{"nameTH": "ปทุมวัน", "nameEN": "Pathum Wan", "subdistricts": [
+     {"nameTH": "ลุมพินี", "nameEN": "Lumphini", "zip": "10330"}
+   ]}]},
+  {"code": "11", "nameTH": "สมุทรปราการ", "nameEN": "Samut Prakan", "zipPrefixes": ["10"]},
+  {"code": "15", "nameTH": "ลพบุรี", "nameEN": "Lopburi", "zipPrefixes": ["15"]}
+]`
+
+func TestDefault(t *testing.T) {
+	dataset := Default()
+	assert.Len(t, dataset.Provinces, 77)
+	for _, p := range dataset.Provinces {
+		assert.NotEmpty(t, p.ZipPrefixes, p.NameEN)
+	}
+
+	province, ok := dataset.Province("Chiang Mai province")
+	require.True(t, ok)
+	assert.Equal(t, "เชียงใหม่", province.NameTH)
+}
+
+func TestProvince(t *testing.T) {
+	dataset, err := Load([]byte(testData))
+	require.NoError(t, err)
+
+	for _, name := range []string{"ลพบุรี", "จ.ลพบุรี", "จังหวัดลพบุรี", "Lopburi", "lop buri", "LOP-BURI"} {
+		province, ok := dataset.Province(name)
+		if assert.True(t, ok, name) {
+			assert.Equal(t, "15", province.Code)
+		}
+	}
+	_, ok := dataset.Province("Atlantis")
+	assert.False(t, ok)
+}
+
+func TestNormalize(t *testing.T) {
+	dataset, err := Load([]byte(testData))
+	require.NoError(t, err)
+
+	addr, err := dataset.Normalize(Address{Province: "กทม", District: "เขตปทุมวัน", Subdistrict: "Lumphini", Zip: "10330"})
+	require.NoError(t, err)
+	assert.Equal(t, Address{Province: "กรุงเทพมหานคร", District: "ปทุมวัน", Subdistrict: "ลุมพินี", Zip: "10330"}, addr)
+
+	// Provinces without districts only check the postcode prefix
+	_, err = dataset.Normalize(Address{Province: "Samut Prakan", District: "Bang Phli", Zip: "10540"})
+	assert.NoError(t, err)
+	// Districts not in the dataset are let through unchecked
+	_, err = dataset.Normalize(Address{Province: "Lopburi"})
+	assert.NoError(t, err)
+
+	for addr, expected := range map[Address]error{
+		{Province: "Lopburi", Zip: "10330"}:                                   ErrZipMismatch,
+		{Province: "Bangkok", Zip: "10540"}:                                   ErrZipMismatch,
+		{Province: "Bangkok", District: "Pathum Wan", Zip: "10200"}:           ErrZipMismatch,
+		{Province: "Bangkok", Subdistrict: "Lumphini", District: "Sukhumvit"}: ErrUnknownDistrict,
+		{Province: "Bangkok", District: "Pathum Wan", Subdistrict: "Silom"}:   ErrUnknownSubdistrict,
+		{Province: "Narnia"}: ErrUnknownProvince,
+		{Zip: "1033"}:        ErrInvalidZip,
+		{Zip: "99999"}:       ErrZipMismatch,
+	} {
+		_, err := dataset.Normalize(addr)
+		assert.ErrorIs(t, err, expected, addr)
+	}
+}
+
+func TestLookupZip(t *testing.T) {
+	dataset, err := Load([]byte(testData))
+	require.NoError(t, err)
+
+	matches, err := dataset.LookupZip("10330")
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
+	assert.Equal(t, "ลุมพินี", matches[0].Subdistrict.NameTH)
+	assert.Equal(t, "Samut Prakan", matches[1].Province.NameEN)
+	assert.Nil(t, matches[1].District)
+
+	_, err = dataset.LookupZip("abcde")
+	assert.ErrorIs(t, err, ErrInvalidZip)
+}
@@ -19,4 +19,17 @@ Feature: Seller appoints location for order
   Scenario: Seller changes location for an order
     When the seller updates the location for order with ID "67d150af6b6922ff40714555"
     Then the order status for order with ID "67d150af6b6922ff40714555" should not change
+    And the response status should be 201
+
+  Scenario: Buyer counters the seller's proposed location and the seller accepts it
+    When the seller proposes a location for appointment "5a3f3e5a8b15c19c9aebed4e"
+    And the buyer counter-proposes a location for appointment "5a3f3e5a8b15c19c9aebed4e"
+    And the seller accepts the buyer's proposal for appointment "5a3f3e5a8b15c19c9aebed4e"
+    Then the order status for order with ID "67d150af6b6922ff40714555" should be 1
+    And the response status should be 200
+
+  Scenario: Buyer rejects the seller's proposed location
+    When the seller proposes a location for appointment "5a3f3e5a8b15c19c9aebed4e"
+    And the buyer rejects the seller's proposal for appointment "5a3f3e5a8b15c19c9aebed4e"
+    Then the order status for order with ID "67d150af6b6922ff40714555" should be 0
     And the response status should be 200
@@ -0,0 +1,12 @@
+Feature: Seller edits a product
+
+  Background:
+    Given a product with ID "67d151ba6b6922ff40714558" is listed by the seller "67d150af6b6922ff40714556"
+
+  Scenario: Seller edits their own product
+    When the user "67d150af6b6922ff40714556" edits the product "67d151ba6b6922ff40714558"
+    Then the response status should be 200
+
+  Scenario: Another seller can't edit the product
+    When the user "67d150af6b6922ff40714557" edits the product "67d151ba6b6922ff40714558"
+    Then the response status should be 401
@@ -13,6 +13,9 @@ import (
 
 	"github.com/Dongy-s-Advanture/back-end/internal/controller"
 	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/orderstatus"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/proposalkind"
+	"github.com/Dongy-s-Advanture/back-end/internal/enum/proposalstatus"
 	"github.com/cucumber/godog"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
@@ -22,14 +25,22 @@ import (
 var orderID primitive.ObjectID
 var appointmentID primitive.ObjectID
 var orderStatus int16
+var callerID primitive.ObjectID
+var proposalID primitive.ObjectID
 
 func InitializeSellerAppointmentScenario(ctx *godog.ScenarioContext) {
 	router = gin.New()
 	orderController := controller.NewOrderController(mockOrderService, mockPaymentService)
 	appointmentController := controller.NewAppointmentController(mockAppointmentService)
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", callerID.Hex())
+		c.Next()
+	})
 	router.POST("/order", orderController.CreateOrder)
-	router.PUT("/appointment/:appointment_id", appointmentController.UpdateAppointmentPlace)
 	router.PUT("/order/:order_id", orderController.UpdateOrderStatusByOrderID)
+	router.POST("/appointment/:appointment_id/proposal", appointmentController.Propose)
+	router.POST("/appointment/:appointment_id/proposal/:proposal_id/accept", appointmentController.AcceptProposal)
+	router.POST("/appointment/:appointment_id/proposal/:proposal_id/reject", appointmentController.RejectProposal)
 
 	// Bind steps to functions
 	ctx.Step(`^an order with ID "([^"]*)" is created$`, anOrderWithIDIsCreated)
@@ -41,6 +52,10 @@ func InitializeSellerAppointmentScenario(ctx *godog.ScenarioContext) {
 	ctx.Step(`^the response status should be (\d+)$`, theResponseStatusShouldBe)
 	ctx.Step(`^the order status for order with ID "([^"]*)" should not change$`, theOrderStatusForOrderWithIDShouldNotChange)
 	ctx.Step(`^the seller updates the location for order with ID "([^"]*)"$`, theSellerUpdatesTheLocationForOrderWithID)
+	ctx.Step(`^the seller proposes a location for appointment "([^"]*)"$`, theSellerProposesALocation)
+	ctx.Step(`^the buyer counter-proposes a location for appointment "([^"]*)"$`, theBuyerCounterProposesALocation)
+	ctx.Step(`^the seller accepts the buyer's proposal for appointment "([^"]*)"$`, theSellerAcceptsTheBuyersProposal)
+	ctx.Step(`^the buyer rejects the seller's proposal for appointment "([^"]*)"$`, theBuyerRejectsTheSellersProposal)
 }
 
 func anOrderWithIDIsCreated(id string) error {
@@ -251,30 +266,102 @@ func theOrderStatusForOrderWithIDShouldNotChange(orderID string) error {
 }
 
 func theSellerUpdatesTheLocationForOrderWithID(orderID string) error {
-	orderIDPrimitive, err := primitive.ObjectIDFromHex(orderID)
+	// A new place goes through a proposal, the order is untouched until
+	// the buyer answers it
+	return proposeLocation(appointmentID.Hex(), sellerID, "Updated Address")
+}
+
+func proposeLocation(id string, proposer primitive.ObjectID, address string) error {
+	appointmentIDPrimitive, _ := primitive.ObjectIDFromHex(id)
+	callerID = proposer
+	proposalID = primitive.NewObjectID()
+
+	requestBody := dto.AppointmentProposalRequest{
+		Kind:     proposalkind.PLACE,
+		Address:  address,
+		City:     "Pathum Wan",
+		Province: "Bangkok",
+		Zip:      "10330",
+	}
+	mockAppointmentService.EXPECT().
+		Propose(appointmentIDPrimitive, proposer, &requestBody).
+		Return(&dto.Appointment{
+			AppointmentID: appointmentIDPrimitive,
+			OrderID:       orderID,
+			Proposals: []dto.AppointmentProposal{{
+				ProposalID: proposalID,
+				Kind:       proposalkind.PLACE,
+				Status:     proposalstatus.PENDING,
+				ProposedBy: proposer,
+				Address:    address,
+			}},
+		}, nil).Times(1)
+
+	jsonBody, err := json.Marshal(requestBody)
 	if err != nil {
-		return fmt.Errorf("invalid order ID: %v", err)
+		return fmt.Errorf("failed to marshal request body: %v", err)
 	}
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/appointment/%s/proposal", id), bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
 
-	updatedAppointment := &dto.Appointment{
-		AppointmentID: orderIDPrimitive,
-		OrderID:       orderIDPrimitive,
-		Address:       "Updated Address",
-		City:          "Updated City",
-		Province:      "Updated Province",
-		Zip:           "54321",
-		Date:          time.Now(),
-		TimeSlot:      "11:00 AM - 12:00 PM",
-		CreatedAt:     time.Now(),
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	testResponse = recorder.Result()
+
+	if testResponse.StatusCode != http.StatusCreated {
+		return fmt.Errorf("error: expected status 201 but got %d", testResponse.StatusCode)
 	}
+	return nil
+}
+
+func answerProposal(id string, answerer primitive.ObjectID, answer string) error {
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/appointment/%s/proposal/%s/%s", id, proposalID.Hex(), answer), nil)
+	callerID = answerer
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	testResponse = recorder.Result()
+
+	if testResponse.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(testResponse.Body)
+		return fmt.Errorf("error: expected status 200 but got %d. Response: %s", testResponse.StatusCode, string(body))
+	}
+	return nil
+}
 
+func theSellerProposesALocation(id string) error {
+	return proposeLocation(id, sellerID, "Siam Paragon, main entrance")
+}
+
+func theBuyerCounterProposesALocation(id string) error {
+	return proposeLocation(id, buyerID, "MBK Center, Gate 1")
+}
+
+func theSellerAcceptsTheBuyersProposal(id string) error {
+	appointmentIDPrimitive, _ := primitive.ObjectIDFromHex(id)
 	mockAppointmentService.EXPECT().
-		UpdateAppointmentPlace(orderIDPrimitive, gomock.Any()).
-		Return(updatedAppointment, nil).Times(1) // Return updated appointment DTO and no error
+		AcceptProposal(appointmentIDPrimitive, proposalID, sellerID).
+		Return(&dto.Appointment{AppointmentID: appointmentIDPrimitive, OrderID: orderID, Address: "MBK Center, Gate 1"}, nil).
+		Times(1)
 
+	if err := answerProposal(id, sellerID, "accept"); err != nil {
+		return err
+	}
+	// An accepted place leaves the order waiting for a time
+	orderStatus = orderstatus.WAITFORTIME
 	return nil
 }
 
+func theBuyerRejectsTheSellersProposal(id string) error {
+	appointmentIDPrimitive, _ := primitive.ObjectIDFromHex(id)
+	mockAppointmentService.EXPECT().
+		RejectProposal(appointmentIDPrimitive, proposalID, buyerID).
+		Return(&dto.Appointment{AppointmentID: appointmentIDPrimitive, OrderID: orderID}, nil).
+		Times(1)
+
+	return answerProposal(id, buyerID, "reject")
+}
+
 func TestSellerAppointment(t *testing.T) {
 	suite := godog.TestSuite{
 		ScenarioInitializer: InitializeSellerAppointmentScenario,
@@ -0,0 +1,105 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Dongy-s-Advanture/back-end/internal/controller"
+	"github.com/Dongy-s-Advanture/back-end/internal/dto"
+	"github.com/Dongy-s-Advanture/back-end/internal/model"
+	"github.com/cucumber/godog"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var listedProduct *dto.Product
+
+func InitializeSellerProductScenario(ctx *godog.ScenarioContext) {
+	router = gin.New()
+	productController := controller.NewProductController(mockProductService, nil)
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", callerID.Hex())
+		c.Next()
+	})
+	router.PUT("/product/:product_id", productController.UpdateProduct)
+
+	ctx.Step(`^a product with ID "([^"]*)" is listed by the seller "([^"]*)"$`, aProductIsListedByTheSeller)
+	ctx.Step(`^the user "([^"]*)" edits the product "([^"]*)"$`, theUserEditsTheProduct)
+	ctx.Step(`^the response status should be (\d+)$`, theResponseStatusShouldBe)
+}
+
+func aProductIsListedByTheSeller(productIDStr string, sellerIDStr string) error {
+	productID, err := primitive.ObjectIDFromHex(productIDStr)
+	if err != nil {
+		return fmt.Errorf("invalid productID format: %v", err)
+	}
+	ownerID, err := primitive.ObjectIDFromHex(sellerIDStr)
+	if err != nil {
+		return fmt.Errorf("invalid sellerID format: %v", err)
+	}
+	listedProduct = &dto.Product{ProductID: productID, ProductName: "Mug", SellerID: ownerID, Amount: 3}
+
+	mockProductService.EXPECT().
+		GetProductByID(productID).
+		Return(listedProduct, nil).
+		AnyTimes()
+	return nil
+}
+
+func theUserEditsTheProduct(userIDStr string, productIDStr string) error {
+	var err error
+	callerID, err = primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		return fmt.Errorf("invalid userID format: %v", err)
+	}
+
+	// Only the owner gets through to the service, and the product can't be
+	// handed to another seller through the body
+	if callerID == listedProduct.SellerID {
+		mockProductService.EXPECT().
+			UpdateProduct(listedProduct.ProductID, gomock.Any()).
+			DoAndReturn(func(productID primitive.ObjectID, updated *model.Product) (*dto.Product, error) {
+				if updated.SellerID != listedProduct.SellerID {
+					return nil, fmt.Errorf("product moved to seller %s", updated.SellerID.Hex())
+				}
+				return listedProduct, nil
+			}).
+			Times(1)
+	}
+
+	jsonBody, err := json.Marshal(dto.UpdateProductRequest{
+		ProductName: "Blue mug",
+		Amount:      2,
+		SellerID:    primitive.NewObjectID().Hex(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request body: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPut, "/product/"+productIDStr, bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	testResponse = recorder.Result()
+	return nil
+}
+
+func TestSellerProduct(t *testing.T) {
+	suite := godog.TestSuite{
+		ScenarioInitializer: InitializeSellerProductScenario,
+		Options: &godog.Options{
+			Format:   "pretty",
+			Paths:    []string{"feature/seller_product.feature"},
+			TestingT: t,
+		},
+	}
+
+	if suite.Run() != 0 {
+		t.Fatal("Godog tests failed")
+	}
+}